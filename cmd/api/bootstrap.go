@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/admin"
+	adminService "github.com/daniel-vuky/go-blog/internal/service/admin"
+	adminStorage "github.com/daniel-vuky/go-blog/internal/storage/admin"
+	"github.com/daniel-vuky/go-blog/pkg/config"
+	"github.com/daniel-vuky/go-blog/pkg/password"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	bootstrapAdminCommand   = "bootstrap-admin"
+	bootstrapPasswordEnvKey = "BOOTSTRAP_ADMIN_PASSWORD"
+)
+
+// runBootstrapAdmin
+// Create the first super admin from the command line
+// @param ctx context.Context
+// @param args []string
+// @return error
+func runBootstrapAdmin(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet(bootstrapAdminCommand, flag.ContinueOnError)
+	email := flags.String("email", "", "email of the admin")
+	plainPassword := flags.String("password", "", "password of the admin, defaults to $"+bootstrapPasswordEnvKey)
+	firstname := flags.String("firstname", "", "firstname of the admin")
+	lastname := flags.String("lastname", "", "lastname of the admin")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *plainPassword == "" {
+		*plainPassword = os.Getenv(bootstrapPasswordEnvKey)
+	}
+	if *email == "" || *plainPassword == "" || *firstname == "" {
+		flags.Usage()
+		return errors.New("email, password and firstname are required")
+	}
+
+	loadedConfig, err := config.LoadConfig("./")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	connPool, err := loadedConfig.ConnectToPgxPool()
+	if err != nil {
+		return fmt.Errorf("failed to create connection pool: %w", err)
+	}
+	defer connPool.Close()
+	passwordHasher, err := password.NewHasher(loadedConfig.Password)
+	if err != nil {
+		return fmt.Errorf("failed to create password hasher: %w", err)
+	}
+
+	service := adminService.NewService(adminStorage.NewAdminRepository(connPool), passwordHasher, nil)
+	createdAdmin, err := service.BootstrapAdmin(ctx, &model.CreateAdminParams{
+		Email:     *email,
+		Password:  *plainPassword,
+		Firstname: *firstname,
+		Lastname: pgtype.Text{
+			String: *lastname,
+			Valid:  *lastname != "",
+		},
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("created admin %d <%s>\n", createdAdmin.AdminID, createdAdmin.Email)
+	return nil
+}
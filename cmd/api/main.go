@@ -20,6 +20,13 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), interruptSignals...)
 	defer stop()
 
+	if len(os.Args) > 1 && os.Args[1] == bootstrapAdminCommand {
+		if err := runBootstrapAdmin(ctx, os.Args[2:]); err != nil {
+			log.Fatalf("failed to bootstrap admin: %v", err)
+		}
+		return
+	}
+
 	errGroup, ctx := errgroup.WithContext(ctx)
 	server, err := gin.NewServer()
 	if err != nil {
@@ -0,0 +1,8 @@
+CREATE OR REPLACE FUNCTION update_password_changed_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.hashed_password IS DISTINCT FROM OLD.hashed_password THEN NEW.password_changed_at = NOW();
+END IF;
+RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,12 @@
+-- Keep password_changed_at untouched when the application only upgrades
+-- the hash of an unchanged password (transparent rehash on login).
+CREATE OR REPLACE FUNCTION update_password_changed_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.hashed_password IS DISTINCT FROM OLD.hashed_password
+        AND COALESCE(current_setting('go_blog.password_rehash', true), '') <> 'on' THEN
+        NEW.password_changed_at = NOW();
+END IF;
+RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
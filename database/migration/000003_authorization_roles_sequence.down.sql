@@ -0,0 +1 @@
+-- Nothing to revert, the sequence keeps its position
@@ -0,0 +1,5 @@
+-- The Administrator role is seeded with an explicit id, move the sequence past it
+SELECT setval(
+    pg_get_serial_sequence('authorization_roles', 'role_id'),
+    (SELECT MAX(role_id) FROM authorization_roles)
+);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "admin_audit_log";
+DROP TABLE IF EXISTS "login_ip_failures";
+ALTER TABLE "admin"
+    DROP COLUMN IF EXISTS "failed_login_count",
+    DROP COLUMN IF EXISTS "lock_count";
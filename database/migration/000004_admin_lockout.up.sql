@@ -0,0 +1,27 @@
+ALTER TABLE "admin"
+    ADD COLUMN "failed_login_count" integer NOT NULL DEFAULT 0,
+    ADD COLUMN "lock_count" integer NOT NULL DEFAULT 0;
+
+CREATE TABLE "login_ip_failures" (
+    "client_ip" varchar PRIMARY KEY,
+    "failed_count" integer NOT NULL DEFAULT 0,
+    "lock_count" integer NOT NULL DEFAULT 0,
+    "lock_expires" timestamptz,
+    "updated_at" timestamptz NOT NULL DEFAULT 'NOW()'
+);
+
+CREATE TABLE "admin_audit_log" (
+    "audit_id" bigserial PRIMARY KEY,
+    "admin_id" bigint,
+    "actor_id" bigint,
+    "action" varchar(64) NOT NULL,
+    "detail" text,
+    "client_ip" varchar,
+    "created_at" timestamptz NOT NULL DEFAULT 'NOW()'
+);
+
+CREATE INDEX ON "admin_audit_log" ("admin_id");
+
+ALTER TABLE "admin_audit_log" ADD FOREIGN KEY ("admin_id") REFERENCES "admin" ("admin_id") ON DELETE SET NULL ON UPDATE NO ACTION;
+
+ALTER TABLE "admin_audit_log" ADD FOREIGN KEY ("actor_id") REFERENCES "admin" ("admin_id") ON DELETE SET NULL ON UPDATE NO ACTION;
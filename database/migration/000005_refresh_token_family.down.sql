@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS "refresh_tokens_refresh_token_idx";
+DROP INDEX IF EXISTS "refresh_tokens_family_id_idx";
+DROP INDEX IF EXISTS "refresh_tokens_user_id_idx";
+ALTER TABLE "refresh_tokens"
+    DROP COLUMN IF EXISTS "family_id",
+    DROP COLUMN IF EXISTS "used_at";
@@ -0,0 +1,9 @@
+ALTER TABLE "refresh_tokens"
+    ADD COLUMN "family_id" uuid NOT NULL DEFAULT gen_random_uuid(),
+    ADD COLUMN "used_at" timestamptz;
+
+CREATE UNIQUE INDEX ON "refresh_tokens" ("refresh_token");
+
+CREATE INDEX ON "refresh_tokens" ("family_id");
+
+CREATE INDEX ON "refresh_tokens" ("user_id");
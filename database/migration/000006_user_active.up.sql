@@ -0,0 +1 @@
+ALTER TABLE "user" ADD COLUMN "active" bool NOT NULL DEFAULT true;
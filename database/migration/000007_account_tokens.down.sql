@@ -0,0 +1,4 @@
+ALTER TABLE "user" DROP COLUMN IF EXISTS "email_verified_at";
+DROP TABLE IF EXISTS "account_tokens";
+DROP TYPE IF EXISTS "account_token_purpose";
+DROP TYPE IF EXISTS "account_type";
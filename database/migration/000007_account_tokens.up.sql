@@ -0,0 +1,28 @@
+-- 1 - Admin
+-- 2 - User
+CREATE TYPE "account_type" AS ENUM (
+    '1',
+    '2'
+);
+
+-- 1 - Password reset
+-- 2 - Email verification
+CREATE TYPE "account_token_purpose" AS ENUM (
+    '1',
+    '2'
+);
+
+CREATE TABLE "account_tokens" (
+    "token_id" bigserial PRIMARY KEY,
+    "account_type" account_type NOT NULL,
+    "account_id" bigint NOT NULL,
+    "purpose" account_token_purpose NOT NULL,
+    "token_hash" varchar NOT NULL UNIQUE,
+    "expired_at" timestamptz NOT NULL,
+    "used_at" timestamptz,
+    "created_at" timestamptz NOT NULL DEFAULT 'NOW()'
+);
+
+CREATE INDEX ON "account_tokens" ("account_type", "account_id", "purpose");
+
+ALTER TABLE "user" ADD COLUMN "email_verified_at" timestamptz;
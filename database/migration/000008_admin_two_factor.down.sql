@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "admin_recovery_codes";
+DROP TABLE IF EXISTS "admin_two_factor";
+ALTER TABLE "authorization_roles" DROP COLUMN IF EXISTS "require_two_factor";
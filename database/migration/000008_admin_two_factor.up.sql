@@ -0,0 +1,23 @@
+ALTER TABLE "authorization_roles" ADD COLUMN "require_two_factor" bool NOT NULL DEFAULT false;
+
+CREATE TABLE "admin_two_factor" (
+    "admin_id" integer PRIMARY KEY,
+    "encrypted_secret" varchar NOT NULL,
+    "last_used_step" bigint NOT NULL DEFAULT 0,
+    "enabled_at" timestamptz,
+    "created_at" timestamptz NOT NULL DEFAULT 'NOW()'
+);
+
+CREATE TABLE "admin_recovery_codes" (
+    "recovery_code_id" bigserial PRIMARY KEY,
+    "admin_id" integer NOT NULL,
+    "code_hash" varchar NOT NULL,
+    "used_at" timestamptz,
+    "created_at" timestamptz NOT NULL DEFAULT 'NOW()'
+);
+
+CREATE INDEX ON "admin_recovery_codes" ("admin_id");
+
+ALTER TABLE "admin_two_factor" ADD FOREIGN KEY ("admin_id") REFERENCES "admin" ("admin_id") ON DELETE CASCADE;
+
+ALTER TABLE "admin_recovery_codes" ADD FOREIGN KEY ("admin_id") REFERENCES "admin" ("admin_id") ON DELETE CASCADE;
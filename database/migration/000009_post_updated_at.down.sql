@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "post_author_id_idx";
+DROP TRIGGER IF EXISTS post_updated_at_trigger ON "post";
+DROP FUNCTION IF EXISTS update_updated_at();
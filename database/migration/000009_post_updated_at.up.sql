@@ -0,0 +1,14 @@
+CREATE OR REPLACE FUNCTION update_updated_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = NOW();
+RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER post_updated_at_trigger
+    BEFORE UPDATE ON "post"
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at();
+
+CREATE INDEX ON "post" ("author_id");
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "category_parent_id_idx";
+ALTER TABLE "category" DROP CONSTRAINT IF EXISTS "category_parent_id_fkey";
+ALTER TABLE "category" DROP CONSTRAINT IF EXISTS "category_parent_id_check";
+UPDATE "category" SET "parent_id" = 0 WHERE "parent_id" IS NULL;
+ALTER TABLE "category" ALTER COLUMN "parent_id" SET NOT NULL;
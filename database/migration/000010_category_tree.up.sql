@@ -0,0 +1,14 @@
+-- Root categories have no parent
+ALTER TABLE "category" ALTER COLUMN "parent_id" DROP NOT NULL;
+
+UPDATE "category"
+SET "parent_id" = NULL
+WHERE "parent_id" = "category_id"
+   OR "parent_id" NOT IN (SELECT "category_id" FROM "category");
+
+ALTER TABLE "category" ADD CONSTRAINT "category_parent_id_check" CHECK ("parent_id" <> "category_id");
+
+ALTER TABLE "category" ADD CONSTRAINT "category_parent_id_fkey"
+    FOREIGN KEY ("parent_id") REFERENCES "category" ("category_id") ON DELETE RESTRICT;
+
+CREATE INDEX "category_parent_id_idx" ON "category" ("parent_id");
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "post_links_category_id_idx";
+DROP INDEX IF EXISTS "post_links_post_id_category_id_idx";
@@ -0,0 +1,9 @@
+DELETE FROM "post_links" duplicate
+USING "post_links" original
+WHERE duplicate.post_id = original.post_id
+  AND duplicate.category_id = original.category_id
+  AND duplicate.link_id > original.link_id;
+
+CREATE UNIQUE INDEX "post_links_post_id_category_id_idx" ON "post_links" ("post_id", "category_id");
+
+CREATE INDEX "post_links_category_id_idx" ON "post_links" ("category_id");
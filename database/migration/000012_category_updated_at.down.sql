@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS comment_updated_at_trigger ON "comment";
+
+DROP TRIGGER IF EXISTS category_updated_at_trigger ON "category";
+
+ALTER TABLE "category" DROP COLUMN IF EXISTS "updated_at";
@@ -0,0 +1,13 @@
+ALTER TABLE "category" ADD COLUMN "updated_at" timestamptz NOT NULL DEFAULT NOW();
+
+UPDATE "category" SET updated_at = created_at;
+
+CREATE TRIGGER category_updated_at_trigger
+    BEFORE UPDATE ON "category"
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at();
+
+CREATE TRIGGER comment_updated_at_trigger
+    BEFORE UPDATE ON "comment"
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at();
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "comment_parent_id_idx";
+DROP INDEX IF EXISTS "comment_post_id_parent_id_idx";
@@ -0,0 +1,3 @@
+CREATE INDEX "comment_post_id_parent_id_idx" ON "comment" ("post_id", "parent_id", "created_at");
+
+CREATE INDEX "comment_parent_id_idx" ON "comment" ("parent_id", "created_at");
@@ -0,0 +1,9 @@
+ALTER TABLE "post" DROP COLUMN IF EXISTS "comment_mode";
+
+DROP INDEX IF EXISTS "comment_status_created_at_idx";
+
+ALTER TABLE "comment" DROP COLUMN IF EXISTS "status";
+
+DROP TYPE IF EXISTS "comment_mode";
+
+DROP TYPE IF EXISTS "comment_status";
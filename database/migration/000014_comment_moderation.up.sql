@@ -0,0 +1,27 @@
+-- pending  - waiting for a moderator
+-- approved - visible on the blog
+-- spam     - hidden, flagged as spam
+-- rejected - hidden by a moderator
+CREATE TYPE "comment_status" AS ENUM (
+    'pending',
+    'approved',
+    'spam',
+    'rejected'
+);
+
+-- open      - comments are published immediately
+-- moderated - comments wait for a moderator
+-- closed    - new comments are refused
+CREATE TYPE "comment_mode" AS ENUM (
+    'open',
+    'moderated',
+    'closed'
+);
+
+ALTER TABLE "comment" ADD COLUMN "status" comment_status NOT NULL DEFAULT 'pending';
+
+UPDATE "comment" SET status = 'approved';
+
+CREATE INDEX "comment_status_created_at_idx" ON "comment" ("status", "created_at");
+
+ALTER TABLE "post" ADD COLUMN "comment_mode" comment_mode NOT NULL DEFAULT 'open';
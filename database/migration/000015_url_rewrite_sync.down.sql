@@ -0,0 +1,15 @@
+DROP TRIGGER IF EXISTS post_url_rewrite_delete_trigger ON "post";
+DROP TRIGGER IF EXISTS category_url_rewrite_delete_trigger ON "category";
+DROP TRIGGER IF EXISTS post_url_rewrite_trigger ON "post";
+DROP TRIGGER IF EXISTS category_url_rewrite_trigger ON "category";
+DROP FUNCTION IF EXISTS delete_url_rewrite();
+DROP FUNCTION IF EXISTS sync_url_rewrite();
+
+DROP INDEX IF EXISTS "url_rewrite_entity_idx";
+DROP INDEX IF EXISTS "url_rewrite_url_key_idx";
+
+ALTER TABLE "url_rewrite"
+    DROP COLUMN IF EXISTS "is_redirect",
+    ALTER COLUMN "url_key" DROP NOT NULL,
+    ALTER COLUMN "entity_id" DROP NOT NULL,
+    ALTER COLUMN "entity_type" DROP NOT NULL;
@@ -0,0 +1,93 @@
+-- The table was never written by the application, rebuild it from the current url keys
+TRUNCATE "url_rewrite";
+
+ALTER TABLE "url_rewrite"
+    ALTER COLUMN "entity_type" SET NOT NULL,
+    ALTER COLUMN "entity_id" SET NOT NULL,
+    ALTER COLUMN "url_key" SET NOT NULL,
+    ADD COLUMN "is_redirect" bool NOT NULL DEFAULT false;
+
+CREATE UNIQUE INDEX "url_rewrite_url_key_idx" ON "url_rewrite" ("url_key");
+
+CREATE INDEX "url_rewrite_entity_idx" ON "url_rewrite" ("entity_type", "entity_id");
+
+INSERT INTO "url_rewrite" (entity_type, entity_id, url_key)
+SELECT '1', category_id, url_key
+FROM "category"
+WHERE url_key IS NOT NULL;
+
+INSERT INTO "url_rewrite" (entity_type, entity_id, url_key)
+SELECT '2', post_id, url_key
+FROM "post"
+WHERE url_key IS NOT NULL
+ON CONFLICT (url_key) DO NOTHING;
+
+-- Keeps the url rewrites of an entity in line with its url key.
+-- The previous key becomes a permanent redirect and the new key takes over
+-- any redirect left on it, TG_ARGV[0] is the url_rewrite_entity of the table.
+CREATE OR REPLACE FUNCTION sync_url_rewrite()
+RETURNS TRIGGER AS $$
+DECLARE
+    rewrite_entity url_rewrite_entity := TG_ARGV[0]::url_rewrite_entity;
+    rewrite_entity_id bigint;
+BEGIN
+    IF TG_OP = 'UPDATE' AND NEW.url_key IS NOT DISTINCT FROM OLD.url_key THEN
+        RETURN NEW;
+    END IF;
+    IF rewrite_entity = '1' THEN
+        rewrite_entity_id := NEW.category_id;
+    ELSE
+        rewrite_entity_id := NEW.post_id;
+    END IF;
+
+    UPDATE "url_rewrite"
+    SET is_redirect = true
+    WHERE entity_type = rewrite_entity
+      AND entity_id = rewrite_entity_id
+      AND NOT is_redirect;
+
+    IF NEW.url_key IS NOT NULL THEN
+        DELETE FROM "url_rewrite"
+        WHERE url_key = NEW.url_key
+          AND is_redirect;
+        INSERT INTO "url_rewrite" (entity_type, entity_id, url_key)
+        VALUES (rewrite_entity, rewrite_entity_id, NEW.url_key);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Removes the url rewrites of a deleted entity, TG_ARGV[0] is the url_rewrite_entity of the table.
+CREATE OR REPLACE FUNCTION delete_url_rewrite()
+RETURNS TRIGGER AS $$
+DECLARE
+    rewrite_entity url_rewrite_entity := TG_ARGV[0]::url_rewrite_entity;
+BEGIN
+    IF rewrite_entity = '1' THEN
+        DELETE FROM "url_rewrite" WHERE entity_type = rewrite_entity AND entity_id = OLD.category_id;
+    ELSE
+        DELETE FROM "url_rewrite" WHERE entity_type = rewrite_entity AND entity_id = OLD.post_id;
+    END IF;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER category_url_rewrite_trigger
+    AFTER INSERT OR UPDATE OF url_key ON "category"
+    FOR EACH ROW
+    EXECUTE FUNCTION sync_url_rewrite('1');
+
+CREATE TRIGGER post_url_rewrite_trigger
+    AFTER INSERT OR UPDATE OF url_key ON "post"
+    FOR EACH ROW
+    EXECUTE FUNCTION sync_url_rewrite('2');
+
+CREATE TRIGGER category_url_rewrite_delete_trigger
+    AFTER DELETE ON "category"
+    FOR EACH ROW
+    EXECUTE FUNCTION delete_url_rewrite('1');
+
+CREATE TRIGGER post_url_rewrite_delete_trigger
+    AFTER DELETE ON "post"
+    FOR EACH ROW
+    EXECUTE FUNCTION delete_url_rewrite('2');
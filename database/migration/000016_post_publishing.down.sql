@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS "post_status_published_at_idx";
+
+ALTER TABLE "post" DROP CONSTRAINT IF EXISTS "post_published_at_check";
+
+ALTER TABLE "post" DROP COLUMN IF EXISTS "published_at";
+
+ALTER TABLE "post" DROP COLUMN IF EXISTS "status";
+
+DROP TYPE IF EXISTS "post_status";
@@ -0,0 +1,28 @@
+-- draft     - being written, only visible to admins
+-- review    - waiting for an editor
+-- scheduled - published automatically once published_at is reached
+-- published - visible on the blog
+-- archived  - withdrawn from the blog
+CREATE TYPE "post_status" AS ENUM (
+    'draft',
+    'review',
+    'scheduled',
+    'published',
+    'archived'
+);
+
+ALTER TABLE "post"
+    ADD COLUMN "status" post_status NOT NULL DEFAULT 'draft',
+    ADD COLUMN "published_at" timestamptz;
+
+-- Every existing post was public, keep it that way without touching updated_at
+ALTER TABLE "post" DISABLE TRIGGER "post_updated_at_trigger";
+
+UPDATE "post" SET status = 'published', published_at = created_at;
+
+ALTER TABLE "post" ENABLE TRIGGER "post_updated_at_trigger";
+
+ALTER TABLE "post" ADD CONSTRAINT "post_published_at_check"
+    CHECK (status NOT IN ('scheduled', 'published') OR published_at IS NOT NULL);
+
+CREATE INDEX "post_status_published_at_idx" ON "post" ("status", "published_at");
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS "post_revision_immutable_trigger" ON "post_revision";
+
+DROP FUNCTION IF EXISTS reject_post_revision_update();
+
+DROP TABLE IF EXISTS "post_revision";
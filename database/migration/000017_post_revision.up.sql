@@ -0,0 +1,39 @@
+CREATE TABLE "post_revision" (
+    "revision_id" bigserial PRIMARY KEY,
+    "post_id" bigint NOT NULL,
+    "name" varchar(512) NOT NULL,
+    "short_description" text,
+    "description" text,
+    "content" text,
+    "admin_id" bigint,
+    "restored_from" bigint,
+    "created_at" timestamptz NOT NULL DEFAULT 'NOW()'
+);
+
+CREATE INDEX "post_revision_post_id_idx" ON "post_revision" ("post_id", "revision_id");
+
+ALTER TABLE "post_revision" ADD FOREIGN KEY ("post_id") REFERENCES "post" ("post_id") ON DELETE CASCADE;
+
+ALTER TABLE "post_revision" ADD FOREIGN KEY ("admin_id") REFERENCES "admin" ("admin_id") ON DELETE SET NULL ON UPDATE NO ACTION;
+
+ALTER TABLE "post_revision" ADD FOREIGN KEY ("restored_from") REFERENCES "post_revision" ("revision_id") ON DELETE SET NULL;
+
+-- The recorded state of a revision never changes, only the links to deleted admins and revisions are cleared
+CREATE OR REPLACE FUNCTION reject_post_revision_update()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'post revisions are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER post_revision_immutable_trigger
+    BEFORE UPDATE OF post_id, name, short_description, description, content, created_at ON "post_revision"
+    FOR EACH ROW
+    EXECUTE FUNCTION reject_post_revision_update();
+
+-- Start the history of the existing posts from their current state
+INSERT INTO "post_revision" (post_id, name, short_description, description, content, admin_id, created_at)
+SELECT post.post_id, post.name, post.short_description, post.description, post.content, admin.admin_id, post.updated_at
+FROM "post"
+LEFT JOIN "admin" ON admin.admin_id = post.author_id
+ORDER BY post.post_id;
@@ -0,0 +1,18 @@
+DROP INDEX IF EXISTS "post_search_vector_idx";
+
+DROP TRIGGER IF EXISTS "post_search_vector_trigger" ON "post";
+
+DROP TRIGGER IF EXISTS "post_updated_at_trigger" ON "post";
+
+CREATE TRIGGER post_updated_at_trigger
+    BEFORE UPDATE ON "post"
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at();
+
+ALTER TABLE "post" DROP COLUMN IF EXISTS "search_vector";
+
+DROP FUNCTION IF EXISTS update_post_search_vector();
+
+DROP FUNCTION IF EXISTS post_search_vector(regconfig, text, text, text, text);
+
+DROP TABLE IF EXISTS "search_setting";
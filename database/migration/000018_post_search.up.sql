@@ -0,0 +1,63 @@
+-- Single row holding the text search configuration the post vectors are built with,
+-- the application keeps it in line with its configured language
+CREATE TABLE "search_setting" (
+    "setting_id" boolean PRIMARY KEY DEFAULT true CHECK ("setting_id"),
+    "language" regconfig NOT NULL DEFAULT 'english'
+);
+
+INSERT INTO "search_setting" DEFAULT VALUES;
+
+CREATE OR REPLACE FUNCTION post_search_vector(
+    language regconfig,
+    name text,
+    short_description text,
+    description text,
+    content text
+)
+RETURNS tsvector AS $$
+    SELECT setweight(to_tsvector(language, COALESCE(name, '')), 'A')
+        || setweight(to_tsvector(language, COALESCE(short_description, '')), 'B')
+        || setweight(to_tsvector(language, COALESCE(description, '')), 'C')
+        || setweight(to_tsvector(language, COALESCE(content, '')), 'D');
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION update_post_search_vector()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector = post_search_vector(
+        (SELECT language FROM "search_setting"),
+        NEW.name,
+        NEW.short_description,
+        NEW.description,
+        NEW.content
+    );
+RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE "post" ADD COLUMN "search_vector" tsvector;
+
+CREATE TRIGGER post_search_vector_trigger
+    BEFORE INSERT OR UPDATE OF name, short_description, description, content ON "post"
+    FOR EACH ROW
+    EXECUTE FUNCTION update_post_search_vector();
+
+-- Rebuilding the vectors alone is not an edit of the post, keep updated_at as it is
+DROP TRIGGER "post_updated_at_trigger" ON "post";
+
+CREATE TRIGGER post_updated_at_trigger
+    BEFORE UPDATE ON "post"
+    FOR EACH ROW
+    WHEN ((to_jsonb(OLD) - 'search_vector') IS DISTINCT FROM (to_jsonb(NEW) - 'search_vector'))
+    EXECUTE FUNCTION update_updated_at();
+
+UPDATE "post"
+SET search_vector = post_search_vector(
+    (SELECT language FROM "search_setting"),
+    name,
+    short_description,
+    description,
+    content
+);
+
+CREATE INDEX "post_search_vector_idx" ON "post" USING GIN ("search_vector");
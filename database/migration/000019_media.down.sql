@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "post_media_key";
+
+DROP TABLE IF EXISTS "post_media";
+
+DROP TABLE IF EXISTS "media";
@@ -0,0 +1,37 @@
+CREATE TABLE "media" (
+    "media_id" bigserial PRIMARY KEY,
+    "file_name" varchar(255) NOT NULL,
+    "storage_key" varchar(255) UNIQUE NOT NULL,
+    "content_hash" char(64) UNIQUE NOT NULL,
+    "mime_type" varchar(127) NOT NULL,
+    "size" bigint NOT NULL,
+    "admin_id" bigint,
+    "created_at" timestamptz NOT NULL DEFAULT 'NOW()'
+);
+
+ALTER TABLE "media" ADD FOREIGN KEY ("admin_id") REFERENCES "admin" ("admin_id") ON DELETE SET NULL ON UPDATE NO ACTION;
+
+-- Posts using a media file in their thumbnail or their content, a used file can not be deleted
+CREATE TABLE "post_media" (
+    "post_id" bigint NOT NULL,
+    "media_id" bigint NOT NULL,
+    PRIMARY KEY ("post_id", "media_id")
+);
+
+CREATE INDEX "post_media_media_id_idx" ON "post_media" ("media_id");
+
+ALTER TABLE "post_media" ADD FOREIGN KEY ("post_id") REFERENCES "post" ("post_id") ON DELETE CASCADE;
+
+ALTER TABLE "post_media" ADD FOREIGN KEY ("media_id") REFERENCES "media" ("media_id") ON DELETE RESTRICT;
+
+-- Media keys found in the thumbnail and the content of a post, kept for the files not uploaded yet
+-- so an upload links the posts already using its key
+CREATE TABLE "post_media_key" (
+    "post_id" bigint NOT NULL,
+    "storage_key" varchar(255) NOT NULL,
+    PRIMARY KEY ("post_id", "storage_key")
+);
+
+CREATE INDEX "post_media_key_storage_key_idx" ON "post_media_key" ("storage_key");
+
+ALTER TABLE "post_media_key" ADD FOREIGN KEY ("post_id") REFERENCES "post" ("post_id") ON DELETE CASCADE;
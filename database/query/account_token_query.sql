@@ -0,0 +1,36 @@
+-- name: CreateAccountToken :one
+INSERT INTO account_tokens
+    (
+        account_type,
+        account_id,
+        purpose,
+        token_hash,
+        expired_at
+    )
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetLatestAccountToken :one
+SELECT * FROM account_tokens
+WHERE account_type = $1
+  AND account_id = $2
+  AND purpose = $3
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: RevokeAccountTokens :exec
+UPDATE account_tokens
+SET used_at = NOW()
+WHERE account_type = $1
+  AND account_id = $2
+  AND purpose = $3
+  AND used_at IS NULL;
+
+-- name: ConsumeAccountToken :one
+UPDATE account_tokens
+SET used_at = NOW()
+WHERE token_hash = $1
+  AND purpose = $2
+  AND used_at IS NULL
+  AND expired_at > NOW()
+RETURNING *;
@@ -3,6 +3,11 @@ SELECT *
 FROM admin
 WHERE email = $1;
 
+-- name: GetAdminByID :one
+SELECT *
+FROM admin
+WHERE admin_id = $1;
+
 -- name: GetTotalAdmin :one
 SELECT COUNT(*)
 FROM admin
@@ -53,4 +58,17 @@ RETURNING *;
 -- name: DeleteAdmin :one
 DELETE FROM admin
 WHERE email = $1
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: RehashAdminPassword :exec
+UPDATE admin
+SET hashed_password = $2
+WHERE email = $1;
+
+
+-- name: LockAdminTable :exec
+LOCK TABLE admin IN EXCLUSIVE MODE;
+
+-- name: CountAdmin :one
+SELECT COUNT(*)
+FROM admin;
@@ -0,0 +1,11 @@
+-- name: CreateAuditLog :one
+INSERT INTO admin_audit_log
+    (
+        admin_id,
+        actor_id,
+        action,
+        detail,
+        client_ip
+    )
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
@@ -0,0 +1,63 @@
+-- name: GetAuthorizationRole :one
+SELECT *
+FROM authorization_roles
+WHERE role_id = $1;
+
+-- name: GetAuthorizationRules :many
+SELECT *
+FROM authorization_rules
+WHERE role_id = $1
+ORDER BY permission_code;
+
+-- name: GetListAuthorizationRoles :many
+SELECT *
+FROM authorization_roles
+ORDER BY role_id;
+
+-- name: CreateAuthorizationRole :one
+INSERT INTO authorization_roles
+    (
+        role_name,
+        is_administrator,
+        require_two_factor
+    )
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: UpdateAuthorizationRole :one
+UPDATE authorization_roles
+SET role_name = COALESCE(sqlc.narg(role_name), role_name),
+    is_administrator = COALESCE(sqlc.narg(is_administrator), is_administrator),
+    require_two_factor = COALESCE(sqlc.narg(require_two_factor), require_two_factor)
+WHERE role_id = $1
+RETURNING *;
+
+-- name: LockAuthorizationRole :one
+SELECT role_id
+FROM authorization_roles
+WHERE role_id = $1
+FOR UPDATE;
+
+-- name: CountAdminByRole :one
+SELECT COUNT(*)
+FROM admin
+WHERE role_id = $1;
+
+-- name: DeleteAuthorizationRole :one
+DELETE FROM authorization_roles
+WHERE role_id = $1
+RETURNING *;
+
+-- name: DeleteAuthorizationRules :exec
+DELETE FROM authorization_rules
+WHERE role_id = $1;
+
+-- name: CreateAuthorizationRule :one
+INSERT INTO authorization_rules
+    (
+        role_id,
+        permission_code,
+        is_allowed
+    )
+VALUES ($1, $2, $3)
+RETURNING *;
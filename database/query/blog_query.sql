@@ -0,0 +1,96 @@
+-- name: GetPublishedPost :one
+SELECT post.post_id, post.name, post.url_key, post.short_description, post.description, post.content, post.thumbnail,
+       COALESCE(TRIM(CONCAT_WS(' ', admin.firstname, admin.lastname)), '') AS author_name,
+       post.published_at, post.created_at, post.updated_at
+FROM "post"
+LEFT JOIN "admin" ON admin.admin_id = post.author_id
+WHERE post.url_key = $1
+  AND post.url_key IS NOT NULL
+  AND post.status = 'published';
+
+-- name: GetPublishedPostCategories :many
+SELECT category.name, category.url_key, category.updated_at
+FROM "category"
+JOIN "post_links" ON post_links.category_id = category.category_id
+WHERE post_links.post_id = $1
+  AND category.url_key IS NOT NULL
+ORDER BY category.name, category.category_id;
+
+-- name: GetListPublishedPost :many
+WITH RECURSIVE subtree AS (
+    SELECT category_id
+    FROM "category"
+    WHERE category_id = sqlc.narg(category_id)
+    UNION ALL
+    SELECT child.category_id
+    FROM "category" child
+    JOIN subtree ON child.parent_id = subtree.category_id
+    WHERE sqlc.arg(include_descendants)::bool
+)
+SELECT post.name, post.url_key, post.short_description, post.thumbnail,
+       COALESCE(TRIM(CONCAT_WS(' ', admin.firstname, admin.lastname)), '') AS author_name,
+       post.published_at, post.created_at, post.updated_at
+FROM "post"
+LEFT JOIN "admin" ON admin.admin_id = post.author_id
+WHERE post.url_key IS NOT NULL
+  AND post.status = 'published'
+  AND (sqlc.narg(category_id)::bigint IS NULL OR EXISTS (
+    SELECT 1 FROM "post_links"
+    JOIN subtree ON subtree.category_id = post_links.category_id
+    WHERE post_links.post_id = post.post_id
+  ))
+ORDER BY post.published_at DESC, post.post_id DESC
+LIMIT sqlc.arg(page_size) OFFSET sqlc.arg(page_offset);
+
+-- name: GetTotalPublishedPost :one
+WITH RECURSIVE subtree AS (
+    SELECT category_id
+    FROM "category"
+    WHERE category_id = sqlc.narg(category_id)
+    UNION ALL
+    SELECT child.category_id
+    FROM "category" child
+    JOIN subtree ON child.parent_id = subtree.category_id
+    WHERE sqlc.arg(include_descendants)::bool
+)
+SELECT COUNT(*)
+FROM "post"
+WHERE post.url_key IS NOT NULL
+  AND post.status = 'published'
+  AND (sqlc.narg(category_id)::bigint IS NULL OR EXISTS (
+    SELECT 1 FROM "post_links"
+    JOIN subtree ON subtree.category_id = post_links.category_id
+    WHERE post_links.post_id = post.post_id
+  ));
+
+-- name: GetPublicCategory :one
+SELECT category.category_id, category.name, category.url_key, parent.url_key AS parent_url_key,
+       category.short_description, category.description, category.updated_at
+FROM "category"
+LEFT JOIN "category" parent ON parent.category_id = category.parent_id
+WHERE category.url_key = $1;
+
+-- name: GetListPublicCategory :many
+SELECT category.category_id, category.name, category.url_key, parent.url_key AS parent_url_key,
+       category.short_description, category.description, category.updated_at
+FROM "category"
+LEFT JOIN "category" parent ON parent.category_id = category.parent_id
+WHERE category.url_key IS NOT NULL
+ORDER BY category.name, category.category_id;
+
+-- name: GetListPublicComment :many
+SELECT comment.comment_id, comment.parent_id,
+       COALESCE(TRIM(CONCAT_WS(' ', "user".firstname, "user".lastname)), '') AS author_name,
+       comment.comment, comment.created_at, comment.updated_at
+FROM "comment"
+LEFT JOIN "user" ON "user".user_id = comment.user_id
+WHERE comment.post_id = $1
+  AND comment.status = 'approved'
+ORDER BY comment.created_at, comment.comment_id
+LIMIT sqlc.arg(page_size) OFFSET sqlc.arg(page_offset);
+
+-- name: GetTotalPublicComment :one
+SELECT COUNT(*)
+FROM "comment"
+WHERE post_id = $1
+  AND status = 'approved';
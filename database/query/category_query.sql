@@ -0,0 +1,93 @@
+-- name: GetCategory :one
+SELECT *
+FROM "category"
+WHERE category_id = $1;
+
+-- name: GetTotalCategory :one
+SELECT COUNT(*)
+FROM "category"
+WHERE
+    (name ILIKE '%' || $1 || '%' OR $1 IS NULL) AND
+    (parent_id = $2 OR $2 IS NULL);
+
+-- name: GetListCategory :many
+SELECT *
+FROM "category"
+WHERE
+    (name ILIKE '%' || $1 || '%' OR $1 IS NULL) AND
+    (parent_id = $2 OR $2 IS NULL)
+ORDER BY $3 $4
+LIMIT $5 OFFSET $6;
+
+-- name: GetCategoryTree :many
+WITH RECURSIVE tree AS (
+    SELECT category.*, 0 AS depth
+    FROM "category"
+    WHERE (sqlc.narg(root_id)::bigint IS NULL AND parent_id IS NULL)
+       OR category_id = sqlc.narg(root_id)
+    UNION ALL
+    SELECT child.*, tree.depth + 1
+    FROM "category" child
+    JOIN tree ON child.parent_id = tree.category_id
+)
+SELECT category_id, parent_id, name, url_key, short_description, description, created_at, updated_at
+FROM tree
+ORDER BY depth, name, category_id;
+
+-- name: GetCategoryBreadcrumbs :many
+WITH RECURSIVE ancestors AS (
+    SELECT category.*, 0 AS depth
+    FROM "category"
+    WHERE category_id = $1
+    UNION ALL
+    SELECT parent.*, ancestors.depth + 1
+    FROM "category" parent
+    JOIN ancestors ON parent.category_id = ancestors.parent_id
+)
+SELECT category_id, parent_id, name, url_key, short_description, description, created_at, updated_at
+FROM ancestors
+ORDER BY depth DESC;
+
+-- name: IsCategoryInSubtree :one
+WITH RECURSIVE subtree AS (
+    SELECT category_id
+    FROM "category"
+    WHERE category_id = sqlc.arg(category_id)
+    UNION ALL
+    SELECT child.category_id
+    FROM "category" child
+    JOIN subtree ON child.parent_id = subtree.category_id
+)
+SELECT EXISTS (SELECT 1 FROM subtree WHERE category_id = sqlc.arg(target_id));
+
+-- name: CreateCategory :one
+INSERT INTO "category"
+    (
+        parent_id,
+        name,
+        url_key,
+        short_description,
+        description
+    )
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: UpdateCategory :one
+UPDATE "category"
+SET name = COALESCE(sqlc.narg(name), name),
+    url_key = COALESCE(sqlc.narg(url_key), url_key),
+    short_description = COALESCE(sqlc.narg(short_description), short_description),
+    description = COALESCE(sqlc.narg(description), description)
+WHERE category_id = sqlc.arg(category_id)
+RETURNING *;
+
+-- name: MoveCategory :one
+UPDATE "category"
+SET parent_id = $2
+WHERE category_id = $1
+RETURNING *;
+
+-- name: DeleteCategory :one
+DELETE FROM "category"
+WHERE category_id = $1
+RETURNING *;
@@ -0,0 +1,81 @@
+-- name: GetComment :one
+SELECT * FROM "comment"
+WHERE comment_id = $1;
+
+-- name: GetListRootComment :many
+SELECT comment.*,
+       COALESCE(TRIM(CONCAT_WS(' ', "user".firstname, "user".lastname)), '') AS author_name,
+       (SELECT COUNT(*) FROM "comment" reply WHERE reply.parent_id = comment.comment_id AND reply.status = 'approved') AS reply_count
+FROM "comment"
+LEFT JOIN "user" ON "user".user_id = comment.user_id
+WHERE comment.post_id = $1
+  AND comment.parent_id IS NULL
+  AND comment.status = 'approved'
+ORDER BY comment.created_at, comment.comment_id
+LIMIT sqlc.arg(page_size) OFFSET sqlc.arg(page_offset);
+
+-- name: GetTotalRootComment :one
+SELECT COUNT(*)
+FROM "comment"
+WHERE post_id = $1
+  AND parent_id IS NULL
+  AND status = 'approved';
+
+-- name: GetListReplyComment :many
+SELECT reply.comment_id, reply.post_id, reply.user_id, reply.parent_id, reply.comment,
+       reply.status, reply.created_at, reply.updated_at,
+       COALESCE(TRIM(CONCAT_WS(' ', "user".firstname, "user".lastname)), '') AS author_name,
+       (SELECT COUNT(*) FROM "comment" child WHERE child.parent_id = reply.comment_id AND child.status = 'approved') AS reply_count
+FROM (
+    SELECT comment.*,
+           ROW_NUMBER() OVER (PARTITION BY comment.parent_id ORDER BY comment.created_at, comment.comment_id) AS position
+    FROM "comment"
+    WHERE comment.parent_id = ANY(sqlc.arg(parent_ids)::bigint[])
+      AND comment.status = 'approved'
+) reply
+LEFT JOIN "user" ON "user".user_id = reply.user_id
+WHERE reply.position > sqlc.arg(page_offset)
+  AND reply.position <= sqlc.arg(page_size) + sqlc.arg(page_offset)
+ORDER BY reply.parent_id, reply.position;
+
+-- name: CountReplyComment :one
+SELECT COUNT(*)
+FROM "comment"
+WHERE parent_id = $1
+  AND status = 'approved';
+
+-- name: CreateComment :one
+INSERT INTO "comment" (post_id, user_id, parent_id, comment, status)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: UpdateComment :one
+UPDATE "comment"
+SET comment = $2,
+    status = $3
+WHERE comment_id = $1
+RETURNING *;
+
+-- name: GetListComment :many
+SELECT *
+FROM "comment"
+WHERE
+    (status = sqlc.narg(status) OR sqlc.narg(status) IS NULL) AND
+    (post_id = sqlc.narg(post_id) OR sqlc.narg(post_id) IS NULL) AND
+    (user_id = sqlc.narg(user_id) OR sqlc.narg(user_id) IS NULL)
+ORDER BY created_at DESC
+LIMIT sqlc.arg(page_size) OFFSET sqlc.arg(page_offset);
+
+-- name: GetTotalComment :one
+SELECT COUNT(*)
+FROM "comment"
+WHERE
+    (status = sqlc.narg(status) OR sqlc.narg(status) IS NULL) AND
+    (post_id = sqlc.narg(post_id) OR sqlc.narg(post_id) IS NULL) AND
+    (user_id = sqlc.narg(user_id) OR sqlc.narg(user_id) IS NULL);
+
+-- name: UpdateCommentStatus :many
+UPDATE "comment"
+SET status = $2
+WHERE comment_id = ANY(sqlc.arg(comment_ids)::bigint[])
+RETURNING *;
@@ -0,0 +1,54 @@
+-- name: IncrementAdminFailedLogin :one
+UPDATE admin
+SET failed_login_count = failed_login_count + 1
+WHERE email = $1
+  AND (lock_expires IS NULL OR lock_expires <= NOW())
+RETURNING admin_id, failed_login_count, lock_count;
+
+-- name: LockAdmin :exec
+UPDATE admin
+SET lock_expires = $2,
+    lock_count = lock_count + 1,
+    failed_login_count = 0
+WHERE admin_id = $1;
+
+-- name: ResetAdminFailedLogin :exec
+UPDATE admin
+SET failed_login_count = 0,
+    lock_count = 0
+WHERE admin_id = $1;
+
+-- name: UnlockAdmin :one
+UPDATE admin
+SET lock_expires = NULL,
+    failed_login_count = 0,
+    lock_count = 0
+WHERE email = $1
+RETURNING admin_id, role_id, email, hashed_password, firstname, lastname, active, lock_expires, password_changed_at, created_at;
+
+-- name: GetClientIpLockExpires :one
+SELECT lock_expires
+FROM login_ip_failures
+WHERE client_ip = $1;
+
+-- name: IncrementClientIpFailedLogin :one
+INSERT INTO login_ip_failures (client_ip, failed_count, updated_at)
+VALUES ($1, 1, NOW())
+ON CONFLICT (client_ip) DO UPDATE
+SET failed_count = CASE
+        WHEN login_ip_failures.updated_at < NOW() - (sqlc.arg(window_seconds)::bigint * INTERVAL '1 second') THEN 1
+        ELSE login_ip_failures.failed_count + 1
+    END,
+    updated_at = NOW()
+RETURNING failed_count, lock_count;
+
+-- name: LockClientIp :exec
+UPDATE login_ip_failures
+SET lock_expires = $2,
+    lock_count = lock_count + 1,
+    failed_count = 0
+WHERE client_ip = $1;
+
+-- name: ResetClientIpFailedLogin :exec
+DELETE FROM login_ip_failures
+WHERE client_ip = $1;
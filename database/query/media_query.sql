@@ -0,0 +1,36 @@
+-- name: GetMedia :one
+SELECT media_id, file_name, storage_key, content_hash, mime_type, size, admin_id,
+       (SELECT COUNT(*) FROM "post_media" WHERE post_media.media_id = media.media_id) AS usage_count,
+       created_at
+FROM "media"
+WHERE media_id = $1;
+
+-- name: GetMediaByHash :one
+SELECT media_id, file_name, storage_key, content_hash, mime_type, size, admin_id,
+       (SELECT COUNT(*) FROM "post_media" WHERE post_media.media_id = media.media_id) AS usage_count,
+       created_at
+FROM "media"
+WHERE content_hash = $1;
+
+-- name: GetListMediaPost :many
+SELECT post.post_id, post.name
+FROM "post_media"
+JOIN "post" ON post.post_id = post_media.post_id
+WHERE post_media.media_id = $1
+ORDER BY post.post_id;
+
+-- name: CreateMedia :one
+INSERT INTO "media" (file_name, storage_key, content_hash, mime_type, size, admin_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING media_id;
+
+-- name: LinkMediaPosts :exec
+INSERT INTO "post_media" (post_id, media_id)
+SELECT post_id, $1
+FROM "post_media_key"
+WHERE storage_key = $2;
+
+-- name: DeleteMedia :one
+DELETE FROM "media"
+WHERE media_id = $1
+RETURNING media_id, file_name, storage_key, content_hash, mime_type, size, admin_id, 0::bigint AS usage_count, created_at;
@@ -0,0 +1,32 @@
+-- name: GetPostCategories :many
+SELECT category.*
+FROM "category"
+JOIN "post_links" ON post_links.category_id = category.category_id
+WHERE post_links.post_id = $1
+ORDER BY category.name, category.category_id;
+
+-- name: DeletePostLinks :exec
+DELETE FROM "post_links"
+WHERE post_id = $1;
+
+-- name: AddPostLinks :exec
+INSERT INTO "post_links" (post_id, category_id)
+SELECT $1, unnest(sqlc.arg(category_ids)::bigint[])
+ON CONFLICT (post_id, category_id) DO NOTHING;
+
+-- name: RemovePostLinks :exec
+DELETE FROM "post_links"
+WHERE post_id = $1
+  AND category_id = ANY(sqlc.arg(category_ids)::bigint[]);
+
+-- name: ReassignPostLinks :exec
+INSERT INTO "post_links" (post_id, category_id)
+SELECT post_id, sqlc.arg(target_id)
+FROM "post_links"
+WHERE category_id = sqlc.arg(source_id)
+ON CONFLICT (post_id, category_id) DO NOTHING;
+
+-- name: MoveChildCategories :exec
+UPDATE "category"
+SET parent_id = sqlc.arg(target_id)
+WHERE parent_id = sqlc.arg(source_id);
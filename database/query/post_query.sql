@@ -0,0 +1,146 @@
+-- name: GetPost :one
+SELECT *
+FROM "post"
+WHERE post_id = $1;
+
+-- name: GetTotalPost :one
+SELECT COUNT(*)
+FROM "post"
+WHERE
+    (name ILIKE '%' || $1 || '%' OR $1 IS NULL) AND
+    (url_key = $2 OR $2 IS NULL) AND
+    (author_id = $3 OR $3 IS NULL) AND
+    (status = $4 OR $4 IS NULL);
+
+-- name: GetListPost :many
+SELECT *
+FROM "post"
+WHERE
+    (name ILIKE '%' || $1 || '%' OR $1 IS NULL) AND
+    (url_key = $2 OR $2 IS NULL) AND
+    (author_id = $3 OR $3 IS NULL) AND
+    (status = $4 OR $4 IS NULL)
+ORDER BY $5 $6
+LIMIT $7 OFFSET $8;
+
+-- name: CreatePost :one
+INSERT INTO "post"
+    (
+        name,
+        short_description,
+        description,
+        content,
+        url_key,
+        thumbnail,
+        author_id,
+        comment_mode
+    )
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: DeletePostMediaKey :exec
+DELETE FROM "post_media_key"
+WHERE post_id = $1;
+
+-- name: CreatePostMediaKey :exec
+INSERT INTO "post_media_key" (post_id, storage_key)
+SELECT $1, UNNEST($2::varchar[]);
+
+-- name: DeletePostMedia :exec
+DELETE FROM "post_media"
+WHERE post_id = $1;
+
+-- name: CreatePostMedia :exec
+INSERT INTO "post_media" (post_id, media_id)
+SELECT $1, media_id
+FROM "media"
+WHERE storage_key = ANY($2::varchar[]);
+
+-- name: UpdatePost :one
+UPDATE "post"
+SET name = COALESCE(sqlc.narg(name), name),
+    short_description = COALESCE(sqlc.narg(short_description), short_description),
+    description = COALESCE(sqlc.narg(description), description),
+    content = COALESCE(sqlc.narg(content), content),
+    url_key = COALESCE(sqlc.narg(url_key), url_key),
+    thumbnail = COALESCE(sqlc.narg(thumbnail), thumbnail),
+    comment_mode = COALESCE(sqlc.narg(comment_mode), comment_mode)
+WHERE post_id = sqlc.arg(post_id)
+RETURNING *;
+
+-- name: DeletePost :one
+DELETE FROM "post"
+WHERE post_id = $1
+RETURNING *;
+
+-- name: UpdatePostStatus :one
+UPDATE "post"
+SET status = $2,
+    published_at = $3
+WHERE post_id = $1
+RETURNING *;
+
+-- name: PublishScheduledPost :many
+UPDATE "post"
+SET status = 'published'
+WHERE status = 'scheduled'
+  AND published_at <= $1
+RETURNING *;
+
+-- name: CreatePostRevision :exec
+INSERT INTO "post_revision" (post_id, name, short_description, description, content, admin_id, restored_from)
+SELECT post_id, name, short_description, description, content, NULLIF(sqlc.arg(admin_id)::bigint, 0), sqlc.narg(restored_from)
+FROM "post"
+WHERE post_id = sqlc.arg(post_id);
+
+-- name: CreateChangedPostRevision :exec
+INSERT INTO "post_revision" (post_id, name, short_description, description, content, admin_id)
+SELECT post.post_id, post.name, post.short_description, post.description, post.content, NULLIF(sqlc.arg(admin_id)::bigint, 0)
+FROM "post"
+LEFT JOIN LATERAL (
+    SELECT name, short_description, description, content
+    FROM "post_revision"
+    WHERE post_revision.post_id = post.post_id
+    ORDER BY revision_id DESC
+    LIMIT 1
+) latest ON true
+WHERE post.post_id = sqlc.arg(post_id)
+  AND (latest.name IS NULL
+    OR latest.name IS DISTINCT FROM post.name
+    OR latest.short_description IS DISTINCT FROM post.short_description
+    OR latest.description IS DISTINCT FROM post.description
+    OR latest.content IS DISTINCT FROM post.content);
+
+-- name: GetPostRevision :one
+SELECT post_revision.*,
+       COALESCE(TRIM(CONCAT_WS(' ', admin.firstname, admin.lastname)), '') AS admin_name
+FROM "post_revision"
+LEFT JOIN "admin" ON admin.admin_id = post_revision.admin_id
+WHERE post_revision.post_id = $1
+  AND post_revision.revision_id = $2;
+
+-- name: GetListPostRevision :many
+SELECT post_revision.*,
+       COALESCE(TRIM(CONCAT_WS(' ', admin.firstname, admin.lastname)), '') AS admin_name
+FROM "post_revision"
+LEFT JOIN "admin" ON admin.admin_id = post_revision.admin_id
+WHERE post_revision.post_id = $1
+ORDER BY post_revision.revision_id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: GetTotalPostRevision :one
+SELECT COUNT(*)
+FROM "post_revision"
+WHERE post_id = $1;
+
+-- name: RestorePostRevision :one
+UPDATE "post"
+SET name = post_revision.name,
+    short_description = post_revision.short_description,
+    description = post_revision.description,
+    content = post_revision.content
+FROM "post_revision"
+WHERE post.post_id = sqlc.arg(post_id)
+  AND post_revision.post_id = post.post_id
+  AND post_revision.revision_id = sqlc.arg(revision_id)
+RETURNING post.*;
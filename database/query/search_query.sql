@@ -0,0 +1,40 @@
+-- name: SearchPublishedPost :many
+SELECT ranked.name, ranked.url_key, ranked.short_description, ranked.thumbnail, ranked.author_name,
+       ts_headline(
+         sqlc.arg(language)::regconfig,
+         CONCAT_WS(' ', ranked.short_description, ranked.description, ranked.content),
+         ranked.query,
+         sqlc.arg(headline_options)
+       ) AS headline,
+       ranked.rank, ranked.published_at, ranked.updated_at
+FROM (
+  SELECT post.post_id, post.name, post.url_key, post.short_description, post.description, post.content, post.thumbnail,
+         COALESCE(TRIM(CONCAT_WS(' ', admin.firstname, admin.lastname)), '') AS author_name,
+         ts_rank_cd(post.search_vector, search.query) AS rank, search.query,
+         post.published_at, post.updated_at
+  FROM "post"
+  CROSS JOIN to_tsquery(sqlc.arg(language)::regconfig, sqlc.arg(query)) AS search(query)
+  LEFT JOIN "admin" ON admin.admin_id = post.author_id
+  WHERE post.url_key IS NOT NULL
+    AND post.status = 'published'
+    AND post.search_vector @@ search.query
+  ORDER BY rank DESC, post.published_at DESC, post.post_id DESC
+  LIMIT sqlc.arg(page_size) OFFSET sqlc.arg(page_offset)
+) ranked
+ORDER BY ranked.rank DESC, ranked.published_at DESC, ranked.post_id DESC;
+
+-- name: GetTotalSearchPublishedPost :one
+SELECT COUNT(*)
+FROM "post"
+WHERE post.url_key IS NOT NULL
+  AND post.status = 'published'
+  AND post.search_vector @@ to_tsquery(sqlc.arg(language)::regconfig, sqlc.arg(query));
+
+-- name: UpdateSearchLanguage :execrows
+UPDATE "search_setting"
+SET language = sqlc.arg(language)::regconfig
+WHERE language <> sqlc.arg(language)::regconfig;
+
+-- name: RebuildPostSearchVector :exec
+UPDATE "post"
+SET search_vector = post_search_vector(sqlc.arg(language)::regconfig, name, short_description, description, content);
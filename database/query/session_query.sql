@@ -0,0 +1,44 @@
+-- name: CreateRefreshToken :one
+INSERT INTO refresh_tokens
+    (
+        user_id,
+        refresh_token,
+        user_agent,
+        client_ip,
+        expired_at,
+        family_id
+    )
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetRefreshToken :one
+SELECT *
+FROM refresh_tokens
+WHERE refresh_token = $1;
+
+-- name: MarkRefreshTokenUsed :one
+UPDATE refresh_tokens
+SET used_at = NOW()
+WHERE refresh_token_id = $1
+  AND used_at IS NULL
+  AND is_blocked = false
+RETURNING refresh_token_id;
+
+-- name: BlockRefreshTokenFamily :exec
+UPDATE refresh_tokens
+SET is_blocked = true
+WHERE family_id = $1;
+
+-- name: BlockUserRefreshTokens :exec
+UPDATE refresh_tokens
+SET is_blocked = true
+WHERE user_id = $1;
+
+-- name: GetListActiveRefreshTokens :many
+SELECT *
+FROM refresh_tokens
+WHERE user_id = $1
+  AND is_blocked = false
+  AND used_at IS NULL
+  AND expired_at > NOW()
+ORDER BY created_at DESC;
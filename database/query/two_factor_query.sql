@@ -0,0 +1,50 @@
+-- name: GetAdminTwoFactor :one
+SELECT *
+FROM admin_two_factor
+WHERE admin_id = $1;
+
+-- name: SaveAdminTwoFactorSecret :one
+INSERT INTO admin_two_factor (admin_id, encrypted_secret)
+VALUES ($1, $2)
+ON CONFLICT (admin_id) DO UPDATE
+SET encrypted_secret = EXCLUDED.encrypted_secret,
+    last_used_step = 0,
+    created_at = NOW()
+WHERE admin_two_factor.enabled_at IS NULL
+RETURNING *;
+
+-- name: EnableAdminTwoFactor :exec
+UPDATE admin_two_factor
+SET enabled_at = NOW()
+WHERE admin_id = $1;
+
+-- name: UseAdminTwoFactorStep :execrows
+UPDATE admin_two_factor
+SET last_used_step = $2
+WHERE admin_id = $1
+  AND last_used_step < $2;
+
+-- name: DeleteAdminTwoFactor :exec
+DELETE FROM admin_two_factor
+WHERE admin_id = $1;
+
+-- name: DeleteAdminRecoveryCodes :exec
+DELETE FROM admin_recovery_codes
+WHERE admin_id = $1;
+
+-- name: CreateAdminRecoveryCode :exec
+INSERT INTO admin_recovery_codes (admin_id, code_hash)
+VALUES ($1, $2);
+
+-- name: UseAdminRecoveryCode :execrows
+UPDATE admin_recovery_codes
+SET used_at = NOW()
+WHERE admin_id = $1
+  AND code_hash = $2
+  AND used_at IS NULL;
+
+-- name: CountAdminRecoveryCodes :one
+SELECT COUNT(*)
+FROM admin_recovery_codes
+WHERE admin_id = $1
+  AND used_at IS NULL;
@@ -0,0 +1,27 @@
+-- name: GetUrlRewrite :one
+SELECT *
+FROM "url_rewrite"
+WHERE url_key = $1;
+
+-- name: GetCanonicalUrlRewrite :one
+SELECT *
+FROM "url_rewrite"
+WHERE entity_type = $1
+  AND entity_id = $2
+  AND NOT is_redirect;
+
+-- name: GetListTakenUrlKey :many
+SELECT url_key
+FROM "url_rewrite"
+WHERE (url_key = $1 OR url_key LIKE $1 || '-%')
+  AND NOT (entity_type = $2 AND entity_id = $3)
+UNION
+SELECT url_key
+FROM "post"
+WHERE (url_key = $1 OR url_key LIKE $1 || '-%')
+  AND NOT ($2 = '2' AND post_id = $3)
+UNION
+SELECT url_key
+FROM "category"
+WHERE (url_key = $1 OR url_key LIKE $1 || '-%')
+  AND NOT ($2 = '1' AND category_id = $3);
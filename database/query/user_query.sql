@@ -0,0 +1,66 @@
+-- name: GetUser :one
+SELECT *
+FROM "user"
+WHERE email = $1;
+
+-- name: GetUserByID :one
+SELECT *
+FROM "user"
+WHERE user_id = $1;
+
+-- name: GetTotalUser :one
+SELECT COUNT(*)
+FROM "user"
+WHERE
+    (email ILIKE '%' || $1 || '%' OR $1 IS NULL) AND
+    (firstname ILIKE '%' || $2 || '%' OR $2 IS NULL) AND
+    (lastname ILIKE '%' || $3 || '%' OR $3 IS NULL) AND
+    (active = $4 OR $4 IS NULL);
+
+-- name: GetListUser :many
+SELECT *
+FROM "user"
+WHERE
+    (email ILIKE '%' || $1 || '%' OR $1 IS NULL) AND
+    (firstname ILIKE '%' || $2 || '%' OR $2 IS NULL) AND
+    (lastname ILIKE '%' || $3 || '%' OR $3 IS NULL) AND
+    (active = $4 OR $4 IS NULL)
+ORDER BY $5 $6
+LIMIT $7 OFFSET $8;
+
+-- name: CreateUser :one
+INSERT INTO "user"
+    (
+        email,
+        hashed_password,
+        firstname,
+        lastname,
+        subscribe,
+        gender,
+        dob
+    )
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: UpdateUser :one
+UPDATE "user"
+SET hashed_password = COALESCE(sqlc.narg(hashed_password), hashed_password),
+    firstname = COALESCE(sqlc.narg(firstname), firstname),
+    lastname = COALESCE(sqlc.narg(lastname), lastname),
+    subscribe = COALESCE(sqlc.narg(subscribe), subscribe),
+    gender = COALESCE(sqlc.narg(gender), gender),
+    dob = COALESCE(sqlc.narg(dob), dob),
+    active = COALESCE(sqlc.narg(active), active)
+WHERE user_id = sqlc.arg(user_id)
+RETURNING *;
+
+-- name: RehashUserPassword :exec
+UPDATE "user"
+SET hashed_password = $2
+WHERE user_id = $1;
+
+-- name: VerifyUserEmail :one
+UPDATE "user"
+SET email_verified_at = COALESCE(email_verified_at, NOW())
+WHERE user_id = $1
+RETURNING *;
@@ -4,6 +4,15 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Matching modes selected with the `filter` tag of a filter field
+const (
+	FilterEqual    = "eq"
+	FilterLike     = "like"
+	FilterContains = "contains"
 )
 
 type FilterParams struct {
@@ -11,6 +20,8 @@ type FilterParams struct {
 
 // BuildFilterConditions
 // Dynamically builds the WHERE clause and arguments based on non-nil struct fields.
+// The column is taken from the `db` tag, falling back to the lowercase field name.
+// Text fields are matched with ILIKE and other fields with =, unless the `filter` tag says otherwise.
 func (f *FilterParams) BuildFilterConditions(filters interface{}) (string, []interface{}) {
 	var conditions []string
 	var args []interface{}
@@ -29,12 +40,24 @@ func (f *FilterParams) BuildFilterConditions(filters interface{}) (string, []int
 
 		// Skip if the field is nil or has zero value
 		if !isZeroValue(field) {
-			columnName := strings.ToLower(fieldName)
+			columnName := fieldType.Tag.Get("db")
+			if columnName == "" {
+				columnName = strings.ToLower(fieldName)
+			}
+			arg := field.Interface()
+			operator := "="
+			switch filterMode(fieldType.Tag.Get("filter"), arg) {
+			case FilterLike:
+				operator = "ILIKE"
+			case FilterContains:
+				operator = "ILIKE"
+				arg = containsPattern(arg)
+			}
 			conditions = append(
 				conditions,
-				fmt.Sprintf("%s ILIKE $%d", columnName, len(args)+1), // Correctly format the placeholder
+				fmt.Sprintf("%s %s $%d", columnName, operator, len(args)+1), // Correctly format the placeholder
 			)
-			args = append(args, field.Interface())
+			args = append(args, arg)
 		}
 	}
 
@@ -62,3 +85,38 @@ func isZeroValue(v reflect.Value) bool {
 		return v.Interface() == reflect.Zero(v.Type()).Interface()
 	}
 }
+
+// filterMode
+// Returns the matching mode of a field, text values default to ILIKE.
+func filterMode(tag string, value interface{}) string {
+	if tag != "" {
+		return tag
+	}
+	switch value.(type) {
+	case string, pgtype.Text:
+		return FilterLike
+	default:
+		return FilterEqual
+	}
+}
+
+// containsPattern
+// Wraps a text value into an ILIKE pattern matching it anywhere, escaping wildcards of the value.
+func containsPattern(value interface{}) interface{} {
+	var text string
+	switch v := value.(type) {
+	case string:
+		text = v
+	case pgtype.Text:
+		text = v.String
+	default:
+		return value
+	}
+	return "%" + EscapeLike(text) + "%"
+}
+
+// EscapeLike
+// Escapes the LIKE wildcards of a value so it is matched literally.
+func EscapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
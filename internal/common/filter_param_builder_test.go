@@ -0,0 +1,38 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+type testFilter struct {
+	Email    pgtype.Text `filter:"contains"`
+	Name     pgtype.Text `db:"firstname"`
+	Active   pgtype.Bool
+	RoleID   int64 `db:"role_id"`
+	Nickname string
+}
+
+// TestFilterParams_BuildFilterConditions test building conditions of every kind of field
+func TestFilterParams_BuildFilterConditions(t *testing.T) {
+	var params FilterParams
+	conditions, args := params.BuildFilterConditions(&testFilter{
+		Email:  pgtype.Text{String: "50%_off", Valid: true},
+		Name:   pgtype.Text{String: "Daniel", Valid: true},
+		Active: pgtype.Bool{Bool: false, Valid: true},
+		RoleID: 2,
+	})
+	require.Equal(t, " AND email ILIKE $1 AND firstname ILIKE $2 AND active = $3 AND role_id = $4", conditions)
+	require.Equal(t, []interface{}{
+		`%50\%\_off%`,
+		pgtype.Text{String: "Daniel", Valid: true},
+		pgtype.Bool{Bool: false, Valid: true},
+		int64(2),
+	}, args)
+
+	conditions, args = params.BuildFilterConditions(&testFilter{})
+	require.Empty(t, conditions)
+	require.Empty(t, args)
+}
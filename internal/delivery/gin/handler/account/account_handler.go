@@ -0,0 +1,139 @@
+package account
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/daniel-vuky/go-blog/internal/delivery/gin/middleware"
+	model "github.com/daniel-vuky/go-blog/internal/models/account"
+	"github.com/daniel-vuky/go-blog/internal/service/account"
+	"github.com/gin-gonic/gin"
+)
+
+// accountTypes
+// Account types accepted by the password recovery routes
+var accountTypes = map[string]model.AccountType{
+	"admin": model.AccountTypeAdmin,
+	"user":  model.AccountTypeUser,
+}
+
+type Handler struct {
+	service *account.Service
+}
+
+// NewHandler create a new handler
+func NewHandler(s *account.Service) *Handler {
+	return &Handler{
+		service: s,
+	}
+}
+
+// forgotPasswordParams
+type forgotPasswordParams struct {
+	AccountType string `json:"account_type" binding:"omitempty,oneof=admin user"`
+	Email       string `json:"email" binding:"required,email,max=255"`
+}
+
+// ForgotPassword Send a password reset link to an admin or a site user
+// @Param forgotPasswordParams
+// @Success 200 {object} gin.H{"message": "..."}
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /auth/forgot-password [post]
+func (s *Handler) ForgotPassword(ctx *gin.Context) {
+	var arg forgotPasswordParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	accountType := model.AccountTypeUser
+	if arg.AccountType != "" {
+		accountType = accountTypes[arg.AccountType]
+	}
+	if err := s.service.ForgotPassword(ctx, accountType, arg.Email); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "if the account exists, a reset link has been sent to its email"})
+}
+
+// resetPasswordParams
+type resetPasswordParams struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8,max=72"`
+}
+
+// ResetPassword Choose a new password with a reset token
+// @Param resetPasswordParams
+// @Success 200 {object} gin.H{"message": "password has been reset"}
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /auth/reset-password [post]
+func (s *Handler) ResetPassword(ctx *gin.Context) {
+	var arg resetPasswordParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := s.service.ResetPassword(ctx, arg.Token, arg.Password); err != nil {
+		if errors.Is(err, account.ErrInvalidAccountToken) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "password has been reset"})
+}
+
+// verifyEmailParams
+type verifyEmailParams struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// VerifyEmail Confirm the email of a site user with a verification token
+// @Param verifyEmailParams
+// @Success 200 {object} model.User
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /auth/verify-email [post]
+func (s *Handler) VerifyEmail(ctx *gin.Context) {
+	var arg verifyEmailParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	verifiedUser, err := s.service.VerifyEmail(ctx, arg.Token)
+	if err != nil {
+		if errors.Is(err, account.ErrInvalidAccountToken) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, verifiedUser)
+}
+
+// ResendEmailVerification Send a new verification link to the authenticated site user
+// @Success 200 {object} gin.H{"message": "verification email has been sent"}
+// @Failure 401 {object} gin.H{"error": "Unauthorized"}
+// @Failure 409 {object} gin.H{"error": "Conflict"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /users/me/verify-email [post]
+func (s *Handler) ResendEmailVerification(ctx *gin.Context) {
+	authorizedUser, ok := middleware.GetAuthorizedUser(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user is not authenticated"})
+		return
+	}
+	if err := s.service.SendEmailVerification(ctx, &authorizedUser); err != nil {
+		if errors.Is(err, account.ErrEmailAlreadyVerified) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "verification email has been sent"})
+}
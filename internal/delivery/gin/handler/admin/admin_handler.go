@@ -1,10 +1,14 @@
 package admin
 
 import (
+	"crypto/subtle"
 	"errors"
-	"fmt"
+	"github.com/daniel-vuky/go-blog/internal/delivery/gin/middleware"
 	model "github.com/daniel-vuky/go-blog/internal/models/admin"
 	"github.com/daniel-vuky/go-blog/internal/service/admin"
+	"github.com/daniel-vuky/go-blog/internal/service/twofactor"
+	"github.com/daniel-vuky/go-blog/pkg/config"
+	"github.com/daniel-vuky/go-blog/pkg/token"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -12,14 +16,27 @@ import (
 	"time"
 )
 
+const setupTokenHeaderKey = "X-Setup-Token"
+
 type Handler struct {
-	service *admin.Service
+	service          *admin.Service
+	twoFactorService *twofactor.Service
+	tokenMaker       token.Maker
+	config           *config.Config
 }
 
 // NewHandler create a new handler
-func NewHandler(s *admin.Service) *Handler {
+func NewHandler(
+	s *admin.Service,
+	twoFactorService *twofactor.Service,
+	tokenMaker token.Maker,
+	cfg *config.Config,
+) *Handler {
 	return &Handler{
-		service: s,
+		service:          s,
+		twoFactorService: twoFactorService,
+		tokenMaker:       tokenMaker,
+		config:           cfg,
 	}
 }
 
@@ -47,10 +64,23 @@ func (s *Handler) GetAdmin(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, loadedAdmin)
 }
 
+// GetCurrentAdmin Get the authenticated admin
+// @Success 200 {object} model.Admin
+// @Failure 401 {object} gin.H{"error": "Unauthorized"}
+// @Router /admin/me [get]
+func (s *Handler) GetCurrentAdmin(ctx *gin.Context) {
+	authorizedAdmin, ok := middleware.GetAuthorizedAdmin(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "admin is not authenticated"})
+		return
+	}
+	ctx.JSON(http.StatusOK, authorizedAdmin)
+}
+
 // getListAdminParams
 type getListAdminParams struct {
 	Email          string `json:"email" form:"email" binding:"omitempty,max=255"`
-	Active         bool   `json:"active" form:"active" binding:"omitempty"`
+	Active         *bool  `json:"active" form:"active" binding:"omitempty"`
 	Firstname      string `json:"firstname" form:"firstname" binding:"omitempty,max=32"`
 	Lastname       string `json:"lastname" form:"lastname" binding:"omitempty,max=32"`
 	OrderBy        string `json:"order_by" form:"order_by" binding:"omitempty"`
@@ -71,12 +101,16 @@ func (s *Handler) GetListAdmin(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	fmt.Print(arg)
+	filter := &model.GetListAdminFilterParams{
+		Email:     pgtype.Text{String: arg.Email, Valid: arg.Email != ""},
+		Firstname: pgtype.Text{String: arg.Firstname, Valid: arg.Firstname != ""},
+		Lastname:  pgtype.Text{String: arg.Lastname, Valid: arg.Lastname != ""},
+	}
+	if arg.Active != nil {
+		filter.Active = pgtype.Bool{Bool: *arg.Active, Valid: true}
+	}
 	admins, err := s.service.GetListAdmin(ctx, &model.GetListAdminParams{
-		Email:          pgtype.Text{String: arg.Email, Valid: arg.Email != ""},
-		Active:         pgtype.Bool{Bool: arg.Active, Valid: true},
-		Firstname:      pgtype.Text{String: arg.Firstname, Valid: arg.Firstname != ""},
-		Lastname:       pgtype.Text{String: arg.Lastname, Valid: arg.Lastname != ""},
+		Filter:         filter,
 		OrderBy:        arg.OrderBy,
 		OrderDirection: arg.OrderDirection,
 		PageSize:       arg.PageSize,
@@ -113,10 +147,10 @@ func (s *Handler) CreateAdmin(ctx *gin.Context) {
 		return
 	}
 	createdAdmin, err := s.service.CreateAdmin(ctx, &model.CreateAdminParams{
-		RoleID:         arg.RoleID,
-		Email:          arg.Email,
-		HashedPassword: arg.Password,
-		Firstname:      arg.Firstname,
+		RoleID:    arg.RoleID,
+		Email:     arg.Email,
+		Password:  arg.Password,
+		Firstname: arg.Firstname,
 		Lastname: pgtype.Text{
 			String: arg.Lastname,
 			Valid:  arg.Lastname != "",
@@ -137,6 +171,55 @@ func (s *Handler) CreateAdmin(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, createdAdmin)
 }
 
+// bootstrapAdminParams
+type bootstrapAdminParams struct {
+	Email     string `json:"email" binding:"required,email,max=255"`
+	Password  string `json:"password" binding:"required"`
+	Firstname string `json:"firstname" binding:"required,max=32"`
+	Lastname  string `json:"lastname" binding:"max=32"`
+}
+
+// BootstrapAdmin Create the first super admin while no admin exists
+// @Param X-Setup-Token header, required when setup.token is configured
+// @Param bootstrapAdminParams
+// @Success 200 {object} model.Admin
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 403 {object} gin.H{"error": "Forbidden"}
+// @Failure 409 {object} gin.H{"error": "Conflict"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /setup/admin [post]
+func (s *Handler) BootstrapAdmin(ctx *gin.Context) {
+	setupToken := s.config.Setup.Token
+	if setupToken != "" &&
+		subtle.ConstantTimeCompare([]byte(ctx.GetHeader(setupTokenHeaderKey)), []byte(setupToken)) != 1 {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "invalid setup token"})
+		return
+	}
+	var arg bootstrapAdminParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	createdAdmin, err := s.service.BootstrapAdmin(ctx, &model.CreateAdminParams{
+		Email:     arg.Email,
+		Password:  arg.Password,
+		Firstname: arg.Firstname,
+		Lastname: pgtype.Text{
+			String: arg.Lastname,
+			Valid:  arg.Lastname != "",
+		},
+	})
+	if err != nil {
+		if errors.Is(err, admin.ErrAdminAlreadyExists) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, createdAdmin)
+}
+
 type updateAdminParams struct {
 	Email       string    `json:"email" binding:"required,email,max=255"`
 	RoleID      int64     `json:"role_id" binding:"required,gt=0"`
@@ -165,7 +248,7 @@ func (s *Handler) UpdateAdmin(ctx *gin.Context) {
 			Int64: arg.RoleID,
 			Valid: true,
 		},
-		HashedPassword: pgtype.Text{
+		Password: pgtype.Text{
 			String: arg.Password,
 			Valid:  arg.Password != "",
 		},
@@ -220,3 +303,208 @@ func (s *Handler) DeleteAdmin(ctx *gin.Context) {
 	}
 	ctx.JSON(http.StatusOK, deletedAdmin)
 }
+
+// loginAdminParams
+type loginAdminParams struct {
+	Email    string `json:"email" binding:"required,email,max=255"`
+	Password string `json:"password" binding:"required"`
+}
+
+// loginAdminResponse
+type loginAdminResponse struct {
+	AccessToken          string      `json:"access_token"`
+	AccessTokenExpiresAt time.Time   `json:"access_token_expires_at"`
+	Admin                model.Admin `json:"admin"`
+}
+
+// twoFactorChallengeResponse
+type twoFactorChallengeResponse struct {
+	TwoFactorRequired       bool      `json:"two_factor_required"`
+	ChallengeToken          string    `json:"challenge_token"`
+	ChallengeTokenExpiresAt time.Time `json:"challenge_token_expires_at"`
+}
+
+// Login Authenticate an admin and issue an access token,
+// or a challenge token when the admin enabled two-factor authentication
+// @Param loginAdminParams
+// @Success 200 {object} loginAdminResponse
+// @Success 202 {object} twoFactorChallengeResponse
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 401 {object} gin.H{"error": "Unauthorized"}
+// @Failure 403 {object} gin.H{"error": "Forbidden"}
+// @Failure 423 {object} gin.H{"error": "Locked"}
+// @Failure 429 {object} gin.H{"error": "Too Many Requests"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/login [post]
+func (s *Handler) Login(ctx *gin.Context) {
+	var arg loginAdminParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	loggedAdmin, err := s.service.Authenticate(ctx, arg.Email, arg.Password, ctx.ClientIP())
+	if err != nil {
+		respondLoginError(ctx, err)
+		return
+	}
+	isEnabled, err := s.twoFactorService.IsEnabled(ctx, loggedAdmin.AdminID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if isEnabled {
+		payload, err := token.NewAdminChallengePayload(
+			loggedAdmin.AdminID,
+			loggedAdmin.RoleID,
+			s.config.TwoFactor.ChallengeDuration,
+		)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		challengeToken, err := s.tokenMaker.CreateToken(payload)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusAccepted, twoFactorChallengeResponse{
+			TwoFactorRequired:       true,
+			ChallengeToken:          challengeToken,
+			ChallengeTokenExpiresAt: payload.ExpiredAt,
+		})
+		return
+	}
+	s.service.CompleteLogin(ctx, loggedAdmin.AdminID, ctx.ClientIP())
+	s.respondAccessToken(ctx, loggedAdmin)
+}
+
+// loginTwoFactorParams
+type loginTwoFactorParams struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required,max=32"`
+}
+
+// LoginTwoFactor Exchange a challenge token and a TOTP or recovery code for an access token
+// @Param loginTwoFactorParams
+// @Success 200 {object} loginAdminResponse
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 401 {object} gin.H{"error": "Unauthorized"}
+// @Failure 403 {object} gin.H{"error": "Forbidden"}
+// @Failure 423 {object} gin.H{"error": "Locked"}
+// @Failure 429 {object} gin.H{"error": "Too Many Requests"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/login/2fa [post]
+func (s *Handler) LoginTwoFactor(ctx *gin.Context) {
+	var arg loginTwoFactorParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	payload, err := s.tokenMaker.VerifyToken(arg.ChallengeToken)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	if payload.Audience != token.AudienceAdminTwoFactor {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": token.ErrInvalidToken.Error()})
+		return
+	}
+	loggedAdmin, err := s.service.GetAdminByID(ctx, payload.AdminID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": token.ErrInvalidToken.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if payload.IssuedAt.Before(loggedAdmin.PasswordChangedAt) {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "token was issued before the last password change"})
+		return
+	}
+	if err = s.service.CheckAdminStatus(&loggedAdmin); err != nil {
+		respondLoginError(ctx, err)
+		return
+	}
+	if err = s.twoFactorService.Verify(ctx, &loggedAdmin, arg.Code, ctx.ClientIP()); err != nil {
+		switch {
+		case errors.Is(err, twofactor.ErrInvalidTwoFactorCode), errors.Is(err, twofactor.ErrTwoFactorNotEnabled):
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		default:
+			respondLoginError(ctx, err)
+		}
+		return
+	}
+	s.respondAccessToken(ctx, loggedAdmin)
+}
+
+// respondAccessToken
+// Issue an access token for an admin who completed every login step
+// @param ctx *gin.Context
+// @param loggedAdmin model.Admin
+func (s *Handler) respondAccessToken(ctx *gin.Context, loggedAdmin model.Admin) {
+	payload, err := token.NewAdminPayload(loggedAdmin.AdminID, loggedAdmin.RoleID, s.config.Token.AccessTokenDuration)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	accessToken, err := s.tokenMaker.CreateToken(payload)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, loginAdminResponse{
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: payload.ExpiredAt,
+		Admin:                loggedAdmin,
+	})
+}
+
+// respondLoginError
+// Map the errors of a login step to their status codes
+// @param ctx *gin.Context
+// @param err error
+func respondLoginError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, admin.ErrInvalidCredentials):
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, admin.ErrAdminInactive):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, admin.ErrAdminLocked):
+		ctx.JSON(http.StatusLocked, gin.H{"error": err.Error()})
+	case errors.Is(err, admin.ErrClientIpLocked):
+		ctx.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// UnlockAdmin Remove the lock of an admin after repeated failed logins
+// @Param email
+// @Success 200 {object} model.Admin
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/{email}/unlock [post]
+func (s *Handler) UnlockAdmin(ctx *gin.Context) {
+	email := ctx.Param("email")
+	if email == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "email is required"})
+		return
+	}
+	authorizedAdmin, ok := middleware.GetAuthorizedAdmin(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "admin is not authenticated"})
+		return
+	}
+	unlockedAdmin, err := s.service.UnlockAdmin(ctx, email, authorizedAdmin.AdminID, ctx.ClientIP())
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "admin not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, unlockedAdmin)
+}
@@ -0,0 +1,265 @@
+package authorization
+
+import (
+	"errors"
+	"net/http"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/admin"
+	"github.com/daniel-vuky/go-blog/internal/service/authorization"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Handler struct {
+	service *authorization.Service
+}
+
+// NewHandler create a new handler
+func NewHandler(s *authorization.Service) *Handler {
+	return &Handler{
+		service: s,
+	}
+}
+
+// roleUri
+type roleUri struct {
+	RoleID int64 `uri:"id" binding:"required,gt=0"`
+}
+
+// GetListPermissions Get all known permission codes
+// @Success 200 {object} []authorization.Permission
+// @Router /admin/permissions [get]
+func (s *Handler) GetListPermissions(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, authorization.ListPermissions())
+}
+
+// GetListRoles Get all roles
+// @Success 200 {object} []model.AuthorizationRole
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/roles [get]
+func (s *Handler) GetListRoles(ctx *gin.Context) {
+	roles, err := s.service.GetListRoles(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, roles)
+}
+
+// GetRole Get role by id
+// @Param id
+// @Success 200 {object} model.AuthorizationRole
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/roles/{id} [get]
+func (s *Handler) GetRole(ctx *gin.Context) {
+	var uri roleUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	role, err := s.service.GetRole(ctx, uri.RoleID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "role not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, role)
+}
+
+// createRoleParams
+type createRoleParams struct {
+	RoleName         string `json:"role_name" binding:"required,max=255"`
+	IsAdministrator  bool   `json:"is_administrator"`
+	RequireTwoFactor bool   `json:"require_two_factor"`
+}
+
+// CreateRole Create a new role
+// @Param createRoleParams
+// @Success 200 {object} model.AuthorizationRole
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/roles [post]
+func (s *Handler) CreateRole(ctx *gin.Context) {
+	var arg createRoleParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	createdRole, err := s.service.CreateRole(ctx, &model.CreateAuthorizationRoleParams{
+		RoleName:         arg.RoleName,
+		IsAdministrator:  arg.IsAdministrator,
+		RequireTwoFactor: arg.RequireTwoFactor,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, createdRole)
+}
+
+// updateRoleParams
+type updateRoleParams struct {
+	RoleName         string `json:"role_name" binding:"max=255"`
+	IsAdministrator  *bool  `json:"is_administrator"`
+	RequireTwoFactor *bool  `json:"require_two_factor"`
+}
+
+// UpdateRole Update role params
+// @Param id
+// @Param updateRoleParams
+// @Success 200 {object} model.AuthorizationRole
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/roles/{id} [put]
+func (s *Handler) UpdateRole(ctx *gin.Context) {
+	var uri roleUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var arg updateRoleParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	params := &model.UpdateAuthorizationRoleParams{
+		RoleID: uri.RoleID,
+		RoleName: pgtype.Text{
+			String: arg.RoleName,
+			Valid:  arg.RoleName != "",
+		},
+	}
+	if arg.IsAdministrator != nil {
+		params.IsAdministrator = pgtype.Bool{Bool: *arg.IsAdministrator, Valid: true}
+	}
+	if arg.RequireTwoFactor != nil {
+		params.RequireTwoFactor = pgtype.Bool{Bool: *arg.RequireTwoFactor, Valid: true}
+	}
+	updatedRole, err := s.service.UpdateRole(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "role not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, updatedRole)
+}
+
+// DeleteRole Delete a role which is not assigned to any admin
+// @Param id
+// @Success 200 {object} model.AuthorizationRole
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 409 {object} gin.H{"error": "Conflict"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/roles/{id} [delete]
+func (s *Handler) DeleteRole(ctx *gin.Context) {
+	var uri roleUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	deletedRole, err := s.service.DeleteRole(ctx, uri.RoleID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "role not found"})
+		case errors.Is(err, authorization.ErrRoleInUse):
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, deletedRole)
+}
+
+// GetRules Get all rules of a role
+// @Param id
+// @Success 200 {object} []model.AuthorizationRule
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/roles/{id}/rules [get]
+func (s *Handler) GetRules(ctx *gin.Context) {
+	var uri roleUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	rules, err := s.service.GetRules(ctx, uri.RoleID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "role not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, rules)
+}
+
+// ruleParams
+type ruleParams struct {
+	PermissionCode string `json:"permission_code" binding:"required,max=128"`
+	IsAllowed      bool   `json:"is_allowed"`
+}
+
+// replaceRulesParams
+type replaceRulesParams struct {
+	Rules []ruleParams `json:"rules" binding:"omitempty,dive"`
+}
+
+// ReplaceRules Replace all rules of a role
+// @Param id
+// @Param replaceRulesParams
+// @Success 200 {object} []model.AuthorizationRule
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/roles/{id}/rules [put]
+func (s *Handler) ReplaceRules(ctx *gin.Context) {
+	var uri roleUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var arg replaceRulesParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	params := &model.ReplaceAuthorizationRulesParams{
+		RoleID: uri.RoleID,
+		Rules:  make([]model.AuthorizationRuleParams, 0, len(arg.Rules)),
+	}
+	for _, rule := range arg.Rules {
+		params.Rules = append(params.Rules, model.AuthorizationRuleParams{
+			PermissionCode: rule.PermissionCode,
+			IsAllowed:      rule.IsAllowed,
+		})
+	}
+	rules, err := s.service.ReplaceRules(ctx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "role not found"})
+			return
+		}
+		if errors.Is(err, authorization.ErrUnknownPermission) || errors.Is(err, authorization.ErrDuplicatedPermission) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, rules)
+}
@@ -0,0 +1,224 @@
+package blog
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/blog"
+	"github.com/daniel-vuky/go-blog/internal/service/blog"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type Handler struct {
+	service *blog.Service
+}
+
+// NewHandler create a new handler
+func NewHandler(s *blog.Service) *Handler {
+	return &Handler{
+		service: s,
+	}
+}
+
+// urlKeyUri
+type urlKeyUri struct {
+	UrlKey string `uri:"url_key" binding:"required,max=255"`
+}
+
+// pageParams
+type pageParams struct {
+	PageSize    int32 `json:"page_size" form:"page_size" binding:"omitempty,gt=0,max=100"`
+	CurrentPage int32 `json:"current_page" form:"current_page" binding:"omitempty,gt=0"`
+}
+
+// withDefaults fill the missing page parameters
+func (p pageParams) withDefaults() pageParams {
+	if p.PageSize == 0 {
+		p.PageSize = 20
+	}
+	if p.CurrentPage == 0 {
+		p.CurrentPage = 1
+	}
+	return p
+}
+
+// GetListPost Get the published posts, newest first
+// @Param pageParams
+// @Success 200 {object} blog.ListPostResponse
+// @Success 304
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /blog/posts [get]
+func (s *Handler) GetListPost(ctx *gin.Context) {
+	var arg pageParams
+	if err := ctx.ShouldBindQuery(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	arg = arg.withDefaults()
+	posts, err := s.service.GetListPost(ctx, &model.GetListPostParams{
+		PageSize:    arg.PageSize,
+		CurrentPage: arg.CurrentPage,
+	})
+	if err != nil {
+		respondBlogError(ctx, err, "")
+		return
+	}
+	respondCacheable(ctx, newValidator(ctx.Request.URL.RequestURI(), posts.Totals, postTimes(posts.Posts)...), posts)
+}
+
+// GetPost Get a published post by url key
+// @Param url_key
+// @Success 200 {object} model.Post
+// @Success 304
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /blog/posts/{url_key} [get]
+func (s *Handler) GetPost(ctx *gin.Context) {
+	var uri urlKeyUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	post, err := s.service.GetPost(ctx, uri.UrlKey)
+	if err != nil {
+		respondBlogError(ctx, err, "post not found")
+		return
+	}
+	updatedAt := []time.Time{post.UpdatedAt}
+	for _, category := range post.Categories {
+		updatedAt = append(updatedAt, category.UpdatedAt)
+	}
+	respondCacheable(ctx, newValidator(ctx.Request.URL.RequestURI(), int64(len(post.Categories)), updatedAt...), post)
+}
+
+// GetListComment Get the comments of a published post, oldest first
+// @Param url_key
+// @Param pageParams
+// @Success 200 {object} blog.ListCommentResponse
+// @Success 304
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /blog/posts/{url_key}/comments [get]
+func (s *Handler) GetListComment(ctx *gin.Context) {
+	var uri urlKeyUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var arg pageParams
+	if err := ctx.ShouldBindQuery(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	arg = arg.withDefaults()
+	_, comments, err := s.service.GetListComment(ctx, uri.UrlKey, arg.PageSize, arg.CurrentPage)
+	if err != nil {
+		respondBlogError(ctx, err, "post not found")
+		return
+	}
+	updatedAt := make([]time.Time, 0, len(comments.Comments))
+	for _, comment := range comments.Comments {
+		updatedAt = append(updatedAt, comment.UpdatedAt)
+	}
+	respondCacheable(ctx, newValidator(ctx.Request.URL.RequestURI(), comments.Totals, updatedAt...), comments)
+}
+
+// GetListCategory Get every public category
+// @Success 200 {object} []model.Category
+// @Success 304
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /blog/categories [get]
+func (s *Handler) GetListCategory(ctx *gin.Context) {
+	categories, err := s.service.GetListCategory(ctx)
+	if err != nil {
+		respondBlogError(ctx, err, "")
+		return
+	}
+	updatedAt := make([]time.Time, 0, len(categories))
+	for _, category := range categories {
+		updatedAt = append(updatedAt, category.UpdatedAt)
+	}
+	respondCacheable(ctx, newValidator(ctx.Request.URL.RequestURI(), int64(len(categories)), updatedAt...), categories)
+}
+
+// GetCategory Get a category by url key
+// @Param url_key
+// @Success 200 {object} model.Category
+// @Success 304
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /blog/categories/{url_key} [get]
+func (s *Handler) GetCategory(ctx *gin.Context) {
+	var uri urlKeyUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	category, err := s.service.GetCategory(ctx, uri.UrlKey)
+	if err != nil {
+		respondBlogError(ctx, err, "category not found")
+		return
+	}
+	respondCacheable(ctx, newValidator(ctx.Request.URL.RequestURI(), 1, category.UpdatedAt), category)
+}
+
+// GetListCategoryPost Get the published posts of a category, newest first
+// @Param url_key
+// @Param pageParams
+// @Success 200 {object} blog.ListPostResponse
+// @Success 304
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /blog/categories/{url_key}/posts [get]
+func (s *Handler) GetListCategoryPost(ctx *gin.Context) {
+	var uri urlKeyUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var arg pageParams
+	if err := ctx.ShouldBindQuery(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	arg = arg.withDefaults()
+	category, posts, err := s.service.GetListCategoryPost(ctx, uri.UrlKey, arg.PageSize, arg.CurrentPage)
+	if err != nil {
+		respondBlogError(ctx, err, "category not found")
+		return
+	}
+	updatedAt := append(postTimes(posts.Posts), category.UpdatedAt)
+	respondCacheable(ctx, newValidator(ctx.Request.URL.RequestURI(), posts.Totals, updatedAt...), posts)
+}
+
+// postTimes
+// Collect the update times of the posts of a listing
+// @param posts []model.PostSummary
+// @return []time.Time
+func postTimes(posts []model.PostSummary) []time.Time {
+	updatedAt := make([]time.Time, 0, len(posts)+1)
+	for _, post := range posts {
+		updatedAt = append(updatedAt, post.UpdatedAt)
+	}
+	return updatedAt
+}
+
+// respondBlogError
+// Map the errors of the blog service to their status codes
+// @param ctx *gin.Context
+// @param err error
+// @param notFoundMessage string
+func respondBlogError(ctx *gin.Context, err error, notFoundMessage string) {
+	if notFoundMessage != "" && errors.Is(err, pgx.ErrNoRows) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": notFoundMessage})
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,88 @@
+package blog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// validator
+// Cache validators of a public response, derived from the updated_at of the resources it contains
+type validator struct {
+	etag         string
+	lastModified time.Time
+}
+
+// newValidator
+// Build the validators of a response. The key identifies the response (the request uri),
+// the totals detect removed resources and the update times detect modified ones.
+// @param key string
+// @param totals int64
+// @param updatedAt ...time.Time
+// @return validator
+func newValidator(key string, totals int64, updatedAt ...time.Time) validator {
+	hash := sha256.New()
+	hash.Write([]byte(key))
+	hash.Write([]byte{0})
+	hash.Write([]byte(strconv.FormatInt(totals, 10)))
+	var lastModified time.Time
+	for _, t := range updatedAt {
+		hash.Write([]byte{0})
+		hash.Write([]byte(strconv.FormatInt(t.UnixNano(), 10)))
+		if t.After(lastModified) {
+			lastModified = t
+		}
+	}
+
+	return validator{
+		etag:         `"` + hex.EncodeToString(hash.Sum(nil))[:32] + `"`,
+		lastModified: lastModified.UTC().Truncate(time.Second),
+	}
+}
+
+// notModified
+// Evaluate the conditional headers of the request, If-None-Match taking precedence over If-Modified-Since
+// @param r *http.Request
+// @return bool
+func (v validator) notModified(r *http.Request) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, etag := range strings.Split(ifNoneMatch, ",") {
+			etag = strings.TrimPrefix(strings.TrimSpace(etag), "W/")
+			if etag == "*" || etag == v.etag {
+				return true
+			}
+		}
+		return false
+	}
+	if ifModifiedSince := r.Header.Get("If-Modified-Since"); ifModifiedSince != "" && !v.lastModified.IsZero() {
+		since, err := http.ParseTime(ifModifiedSince)
+		if err != nil {
+			return false
+		}
+		return !v.lastModified.After(since)
+	}
+	return false
+}
+
+// respondCacheable
+// Write the validators and the body, or 304 Not Modified when the client copy is still fresh
+// @param ctx *gin.Context
+// @param v validator
+// @param body any
+func respondCacheable(ctx *gin.Context, v validator, body any) {
+	ctx.Header("ETag", v.etag)
+	if !v.lastModified.IsZero() {
+		ctx.Header("Last-Modified", v.lastModified.Format(http.TimeFormat))
+	}
+	ctx.Header("Cache-Control", "public, no-cache")
+	if v.notModified(ctx.Request) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+	ctx.JSON(http.StatusOK, body)
+}
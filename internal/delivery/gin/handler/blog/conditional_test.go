@@ -0,0 +1,78 @@
+package blog
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// newConditionalRouter
+// Create a router answering with the validators of a resource updated at updatedAt
+func newConditionalRouter(updatedAt time.Time) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/resource", func(ctx *gin.Context) {
+		respondCacheable(ctx, newValidator(ctx.Request.URL.RequestURI(), 1, updatedAt), gin.H{"name": "resource"})
+	})
+	return router
+}
+
+// TestRespondCacheable test the validators are sent and conditional requests get 304
+func TestRespondCacheable(t *testing.T) {
+	updatedAt := time.Date(2024, 5, 1, 10, 30, 15, 500, time.UTC)
+	router := newConditionalRouter(updatedAt)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/resource", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	etag := recorder.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	require.Equal(t, "Wed, 01 May 2024 10:30:15 GMT", recorder.Header().Get("Last-Modified"))
+
+	testCases := []struct {
+		name   string
+		header map[string]string
+		code   int
+	}{
+		{"matching etag", map[string]string{"If-None-Match": etag}, http.StatusNotModified},
+		{"weak etag in a list", map[string]string{"If-None-Match": `"other", W/` + etag}, http.StatusNotModified},
+		{"other etag", map[string]string{"If-None-Match": `"other"`}, http.StatusOK},
+		{"etag takes precedence", map[string]string{
+			"If-None-Match":     `"other"`,
+			"If-Modified-Since": updatedAt.Add(time.Hour).Format(http.TimeFormat),
+		}, http.StatusOK},
+		{"not modified since", map[string]string{"If-Modified-Since": updatedAt.Format(http.TimeFormat)}, http.StatusNotModified},
+		{"modified since", map[string]string{"If-Modified-Since": updatedAt.Add(-time.Second).Format(http.TimeFormat)}, http.StatusOK},
+		{"invalid date", map[string]string{"If-Modified-Since": "yesterday"}, http.StatusOK},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/resource", nil)
+			for key, value := range tc.header {
+				request.Header.Set(key, value)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.code, recorder.Code)
+			if tc.code == http.StatusNotModified {
+				require.Empty(t, recorder.Body.String())
+			}
+		})
+	}
+}
+
+// TestNewValidator test the etag changes with the update times, the totals and the key
+func TestNewValidator(t *testing.T) {
+	updatedAt := time.Now()
+	v := newValidator("/blog/posts", 2, updatedAt, updatedAt.Add(-time.Hour))
+
+	require.Equal(t, v, newValidator("/blog/posts", 2, updatedAt, updatedAt.Add(-time.Hour)))
+	require.NotEqual(t, v.etag, newValidator("/blog/posts", 2, updatedAt, updatedAt.Add(time.Nanosecond)).etag)
+	require.NotEqual(t, v.etag, newValidator("/blog/posts", 1, updatedAt, updatedAt.Add(-time.Hour)).etag)
+	require.NotEqual(t, v.etag, newValidator("/blog/posts?current_page=2", 2, updatedAt, updatedAt.Add(-time.Hour)).etag)
+	require.True(t, newValidator("/blog/posts", 0).lastModified.IsZero())
+}
@@ -0,0 +1,345 @@
+package category
+
+import (
+	"errors"
+	"net/http"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/category"
+	"github.com/daniel-vuky/go-blog/internal/service/category"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Handler struct {
+	service *category.Service
+}
+
+// NewHandler create a new handler
+func NewHandler(s *category.Service) *Handler {
+	return &Handler{
+		service: s,
+	}
+}
+
+// categoryUri
+type categoryUri struct {
+	CategoryID int64 `uri:"id" binding:"required,gt=0"`
+}
+
+// getTreeParams
+type getTreeParams struct {
+	RootID int64 `json:"root_id" form:"root_id" binding:"omitempty,gt=0"`
+}
+
+// GetTree Get the nested category hierarchy, or the subtree of root_id
+// @Param getTreeParams
+// @Success 200 {object} []model.CategoryNode
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /categories/tree [get]
+func (s *Handler) GetTree(ctx *gin.Context) {
+	var arg getTreeParams
+	if err := ctx.ShouldBindQuery(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	tree, err := s.service.GetTree(ctx, pgtype.Int8{Int64: arg.RootID, Valid: arg.RootID != 0})
+	if err != nil {
+		respondCategoryError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, tree)
+}
+
+// GetBreadcrumbs Get the path from the root to a category
+// @Param id
+// @Success 200 {object} []model.Category
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /categories/{id}/breadcrumbs [get]
+func (s *Handler) GetBreadcrumbs(ctx *gin.Context) {
+	var uri categoryUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	breadcrumbs, err := s.service.GetBreadcrumbs(ctx, uri.CategoryID)
+	if err != nil {
+		respondCategoryError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, breadcrumbs)
+}
+
+// GetCategory Get category by id
+// @Param id
+// @Success 200 {object} model.Category
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/categories/{id} [get]
+func (s *Handler) GetCategory(ctx *gin.Context) {
+	var uri categoryUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	loadedCategory, err := s.service.GetCategory(ctx, uri.CategoryID)
+	if err != nil {
+		respondCategoryError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, loadedCategory)
+}
+
+// getListCategoryParams
+type getListCategoryParams struct {
+	Name           string `json:"name" form:"name" binding:"omitempty,max=255"`
+	ParentID       int64  `json:"parent_id" form:"parent_id" binding:"omitempty,gt=0"`
+	OrderBy        string `json:"order_by" form:"order_by" binding:"omitempty,oneof=category_id parent_id name created_at updated_at"`
+	OrderDirection string `json:"order_direction" form:"order_direction" binding:"omitempty,oneof=asc desc"`
+	PageSize       int32  `json:"page_size" form:"page_size" binding:"required,gt=0"`
+	CurrentPage    int32  `json:"current_page" form:"current_page" binding:"required,gt=0"`
+}
+
+// GetListCategory Get a flat list of categories
+// @Param getListCategoryParams
+// @Success 200 {object} category.ListCategoryResponse
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/categories [get]
+func (s *Handler) GetListCategory(ctx *gin.Context) {
+	var arg getListCategoryParams
+	if err := ctx.ShouldBindQuery(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	categories, err := s.service.GetListCategory(ctx, &model.GetListCategoryParams{
+		Filter: &model.GetListCategoryFilterParams{
+			Name:     pgtype.Text{String: arg.Name, Valid: arg.Name != ""},
+			ParentID: pgtype.Int8{Int64: arg.ParentID, Valid: arg.ParentID != 0},
+		},
+		OrderBy:        arg.OrderBy,
+		OrderDirection: arg.OrderDirection,
+		PageSize:       arg.PageSize,
+		CurrentPage:    arg.CurrentPage,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, categories)
+}
+
+// createCategoryParams
+type createCategoryParams struct {
+	ParentID         int64  `json:"parent_id" binding:"omitempty,gt=0"`
+	Name             string `json:"name" binding:"required,max=255"`
+	UrlKey           string `json:"url_key"`
+	ShortDescription string `json:"short_description"`
+	Description      string `json:"description"`
+}
+
+// CreateCategory Create a new category, a missing parent_id creates a root category
+// @Param createCategoryParams
+// @Success 200 {object} model.Category
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 409 {object} gin.H{"error": "Conflict"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/categories [post]
+func (s *Handler) CreateCategory(ctx *gin.Context) {
+	var arg createCategoryParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	createdCategory, err := s.service.CreateCategory(ctx, &model.CreateCategoryParams{
+		ParentID:         pgtype.Int8{Int64: arg.ParentID, Valid: arg.ParentID != 0},
+		Name:             arg.Name,
+		UrlKey:           pgtype.Text{String: arg.UrlKey, Valid: arg.UrlKey != ""},
+		ShortDescription: pgtype.Text{String: arg.ShortDescription, Valid: arg.ShortDescription != ""},
+		Description:      pgtype.Text{String: arg.Description, Valid: arg.Description != ""},
+	})
+	if err != nil {
+		respondCategoryError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, createdCategory)
+}
+
+// updateCategoryParams
+type updateCategoryParams struct {
+	Name             string `json:"name" binding:"max=255"`
+	UrlKey           string `json:"url_key"`
+	ShortDescription string `json:"short_description"`
+	Description      string `json:"description"`
+	RegenerateUrlKey bool   `json:"regenerate_url_key"`
+}
+
+// UpdateCategory Update category params
+// @Param id
+// @Param updateCategoryParams
+// @Success 200 {object} model.Category
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 409 {object} gin.H{"error": "Conflict"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/categories/{id} [put]
+func (s *Handler) UpdateCategory(ctx *gin.Context) {
+	var uri categoryUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var arg updateCategoryParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	updatedCategory, err := s.service.UpdateCategory(ctx, &model.UpdateCategoryParams{
+		CategoryID:       uri.CategoryID,
+		Name:             pgtype.Text{String: arg.Name, Valid: arg.Name != ""},
+		UrlKey:           pgtype.Text{String: arg.UrlKey, Valid: arg.UrlKey != ""},
+		ShortDescription: pgtype.Text{String: arg.ShortDescription, Valid: arg.ShortDescription != ""},
+		Description:      pgtype.Text{String: arg.Description, Valid: arg.Description != ""},
+		RegenerateUrlKey: arg.RegenerateUrlKey,
+	})
+	if err != nil {
+		respondCategoryError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, updatedCategory)
+}
+
+// moveCategoryParams
+type moveCategoryParams struct {
+	ParentID int64 `json:"parent_id" binding:"omitempty,gt=0"`
+}
+
+// MoveCategory Move a category with its subtree under another parent, a missing parent_id moves it to the root
+// @Param id
+// @Param moveCategoryParams
+// @Success 200 {object} model.Category
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 409 {object} gin.H{"error": "Conflict"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/categories/{id}/move [post]
+func (s *Handler) MoveCategory(ctx *gin.Context) {
+	var uri categoryUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var arg moveCategoryParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	movedCategory, err := s.service.MoveCategory(
+		ctx,
+		uri.CategoryID,
+		pgtype.Int8{Int64: arg.ParentID, Valid: arg.ParentID != 0},
+	)
+	if err != nil {
+		respondCategoryError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, movedCategory)
+}
+
+// deleteCategoryParams
+type deleteCategoryParams struct {
+	ReassignTo int64 `json:"reassign_to" form:"reassign_to" binding:"omitempty,gt=0"`
+}
+
+// DeleteCategory Delete a category without child categories,
+// its posts are assigned to reassign_to first when it is given
+// @Param id
+// @Param deleteCategoryParams
+// @Success 200 {object} model.Category
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 409 {object} gin.H{"error": "Conflict"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/categories/{id} [delete]
+func (s *Handler) DeleteCategory(ctx *gin.Context) {
+	var uri categoryUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var arg deleteCategoryParams
+	if err := ctx.ShouldBindQuery(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	deletedCategory, err := s.service.DeleteCategory(
+		ctx,
+		uri.CategoryID,
+		pgtype.Int8{Int64: arg.ReassignTo, Valid: arg.ReassignTo != 0},
+	)
+	if err != nil {
+		respondCategoryError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, deletedCategory)
+}
+
+// mergeCategoryParams
+type mergeCategoryParams struct {
+	TargetID int64 `json:"target_id" binding:"required,gt=0"`
+}
+
+// MergeCategory Move the posts and child categories of a category to the target category, then delete it
+// @Param id
+// @Param mergeCategoryParams
+// @Success 200 {object} model.Category
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 409 {object} gin.H{"error": "Conflict"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/categories/{id}/merge [post]
+func (s *Handler) MergeCategory(ctx *gin.Context) {
+	var uri categoryUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var arg mergeCategoryParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	mergedCategory, err := s.service.MergeCategory(ctx, uri.CategoryID, arg.TargetID)
+	if err != nil {
+		respondCategoryError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, mergedCategory)
+}
+
+// respondCategoryError
+// Map the errors of the category service to their status codes
+// @param ctx *gin.Context
+// @param err error
+func respondCategoryError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+	case errors.Is(err, category.ErrParentNotFound), errors.Is(err, category.ErrTargetCategoryNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, category.ErrInvalidTargetCategory), errors.Is(err, category.ErrUrlKeyInvalid):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, category.ErrUrlKeyAlreadyExists),
+		errors.Is(err, category.ErrUrlKeyReserved),
+		errors.Is(err, category.ErrCategoryCycle),
+		errors.Is(err, category.ErrCategoryHasChildren):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
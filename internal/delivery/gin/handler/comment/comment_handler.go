@@ -0,0 +1,291 @@
+package comment
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/daniel-vuky/go-blog/internal/delivery/gin/middleware"
+	model "github.com/daniel-vuky/go-blog/internal/models/comment"
+	"github.com/daniel-vuky/go-blog/internal/service/comment"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Handler struct {
+	service *comment.Service
+}
+
+// NewHandler create a new handler
+func NewHandler(s *comment.Service) *Handler {
+	return &Handler{
+		service: s,
+	}
+}
+
+// idUri
+type idUri struct {
+	ID int64 `uri:"id" binding:"required,gt=0"`
+}
+
+// getCommentTreeParams
+type getCommentTreeParams struct {
+	Depth         int32 `json:"depth" form:"depth" binding:"omitempty,gt=0,max=10"`
+	PageSize      int32 `json:"page_size" form:"page_size" binding:"omitempty,gt=0,max=100"`
+	CurrentPage   int32 `json:"current_page" form:"current_page" binding:"omitempty,gt=0"`
+	ReplyPageSize int32 `json:"reply_page_size" form:"reply_page_size" binding:"omitempty,gt=0,max=50"`
+}
+
+// toModel fill the missing parameters with their defaults
+func (p getCommentTreeParams) toModel() *model.GetCommentTreeParams {
+	arg := &model.GetCommentTreeParams{
+		Depth:         3,
+		PageSize:      20,
+		CurrentPage:   1,
+		ReplyPageSize: 5,
+	}
+	if p.Depth > 0 {
+		arg.Depth = p.Depth
+	}
+	if p.PageSize > 0 {
+		arg.PageSize = p.PageSize
+	}
+	if p.CurrentPage > 0 {
+		arg.CurrentPage = p.CurrentPage
+	}
+	if p.ReplyPageSize > 0 {
+		arg.ReplyPageSize = p.ReplyPageSize
+	}
+	return arg
+}
+
+// GetCommentTree Get a page of the comments of a post with their nested replies
+// @Param id
+// @Param getCommentTreeParams
+// @Success 200 {object} comment.ListCommentResponse
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /posts/{id}/comments [get]
+func (s *Handler) GetCommentTree(ctx *gin.Context) {
+	var uri idUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var arg getCommentTreeParams
+	if err := ctx.ShouldBindQuery(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	comments, err := s.service.GetCommentTree(ctx, uri.ID, arg.toModel())
+	if err != nil {
+		respondCommentError(ctx, err, "post not found")
+		return
+	}
+	ctx.JSON(http.StatusOK, comments)
+}
+
+// GetReplyTree Get a page of the replies of a comment with their nested replies
+// @Param id
+// @Param getCommentTreeParams
+// @Success 200 {object} comment.ListCommentResponse
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /comments/{id}/replies [get]
+func (s *Handler) GetReplyTree(ctx *gin.Context) {
+	var uri idUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var arg getCommentTreeParams
+	if err := ctx.ShouldBindQuery(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	replies, err := s.service.GetReplyTree(ctx, uri.ID, arg.toModel())
+	if err != nil {
+		respondCommentError(ctx, err, "comment not found")
+		return
+	}
+	ctx.JSON(http.StatusOK, replies)
+}
+
+// createCommentParams
+type createCommentParams struct {
+	ParentID int64  `json:"parent_id" binding:"omitempty,gt=0"`
+	Comment  string `json:"comment" binding:"required,max=5000"`
+}
+
+// CreateComment Comment a post as the authenticated user, or reply to one of its comments
+// @Param id
+// @Param createCommentParams
+// @Success 201 {object} model.Comment
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 401 {object} gin.H{"error": "Unauthorized"}
+// @Failure 403 {object} gin.H{"error": "Forbidden"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /posts/{id}/comments [post]
+func (s *Handler) CreateComment(ctx *gin.Context) {
+	authorizedUserID, ok := middleware.GetAuthorizedUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user is not authenticated"})
+		return
+	}
+	var uri idUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var arg createCommentParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	createdComment, err := s.service.CreateComment(ctx, &model.CreateCommentParams{
+		PostID:   uri.ID,
+		UserID:   authorizedUserID,
+		ParentID: pgtype.Int8{Int64: arg.ParentID, Valid: arg.ParentID > 0},
+		Comment:  arg.Comment,
+	})
+	if err != nil {
+		respondCommentError(ctx, err, "post not found")
+		return
+	}
+	ctx.JSON(http.StatusCreated, createdComment)
+}
+
+// updateCommentParams
+type updateCommentParams struct {
+	Comment string `json:"comment" binding:"required,max=5000"`
+}
+
+// UpdateComment Edit a comment of the authenticated user
+// @Param id
+// @Param updateCommentParams
+// @Success 200 {object} model.Comment
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 401 {object} gin.H{"error": "Unauthorized"}
+// @Failure 403 {object} gin.H{"error": "Forbidden"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /comments/{id} [put]
+func (s *Handler) UpdateComment(ctx *gin.Context) {
+	authorizedUserID, ok := middleware.GetAuthorizedUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user is not authenticated"})
+		return
+	}
+	var uri idUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var arg updateCommentParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	updatedComment, err := s.service.UpdateComment(ctx, authorizedUserID, &model.UpdateCommentParams{
+		CommentID: uri.ID,
+		Comment:   arg.Comment,
+	})
+	if err != nil {
+		respondCommentError(ctx, err, "comment not found")
+		return
+	}
+	ctx.JSON(http.StatusOK, updatedComment)
+}
+
+// getListCommentParams
+type getListCommentParams struct {
+	Status         string `json:"status" form:"status" binding:"omitempty,oneof=pending approved spam rejected"`
+	PostID         int64  `json:"post_id" form:"post_id" binding:"omitempty,gt=0"`
+	UserID         int64  `json:"user_id" form:"user_id" binding:"omitempty,gt=0"`
+	OrderBy        string `json:"order_by" form:"order_by" binding:"omitempty,oneof=comment_id post_id user_id created_at updated_at"`
+	OrderDirection string `json:"order_direction" form:"order_direction" binding:"omitempty,oneof=asc desc"`
+	PageSize       int32  `json:"page_size" form:"page_size" binding:"required,gt=0"`
+	CurrentPage    int32  `json:"current_page" form:"current_page" binding:"required,gt=0"`
+}
+
+// GetListComment Get list of comments, filtered by status for the moderation queue
+// @Param getListCommentParams
+// @Success 200 {object} comment.AdminListCommentResponse
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/comments [get]
+func (s *Handler) GetListComment(ctx *gin.Context) {
+	var arg getListCommentParams
+	if err := ctx.ShouldBindQuery(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	comments, err := s.service.GetListComment(ctx, &model.GetListCommentParams{
+		Filter: &model.GetListCommentFilterParams{
+			Status: pgtype.Text{String: arg.Status, Valid: arg.Status != ""},
+			PostID: pgtype.Int8{Int64: arg.PostID, Valid: arg.PostID != 0},
+			UserID: pgtype.Int8{Int64: arg.UserID, Valid: arg.UserID != 0},
+		},
+		OrderBy:        arg.OrderBy,
+		OrderDirection: arg.OrderDirection,
+		PageSize:       arg.PageSize,
+		CurrentPage:    arg.CurrentPage,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, comments)
+}
+
+// moderateCommentsParams
+type moderateCommentsParams struct {
+	CommentIDs []int64 `json:"comment_ids" binding:"required,min=1,max=100,dive,gt=0"`
+	Status     string  `json:"status" binding:"required,oneof=pending approved spam rejected"`
+}
+
+// ModerateComments Approve, reject or mark as spam several comments at once
+// @Param moderateCommentsParams
+// @Success 200 {object} []model.Comment
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/comments/moderate [post]
+func (s *Handler) ModerateComments(ctx *gin.Context) {
+	var arg moderateCommentsParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	moderatedComments, err := s.service.ModerateComments(ctx, arg.CommentIDs, model.CommentStatus(arg.Status))
+	if err != nil {
+		respondCommentError(ctx, err, "comment not found")
+		return
+	}
+	ctx.JSON(http.StatusOK, moderatedComments)
+}
+
+// respondCommentError
+// Map the errors of the comment service to their status codes
+// @param ctx *gin.Context
+// @param err error
+// @param notFoundMessage string
+func respondCommentError(ctx *gin.Context, err error, notFoundMessage string) {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": notFoundMessage})
+	case errors.Is(err, comment.ErrCommentsNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, comment.ErrParentNotFound),
+		errors.Is(err, comment.ErrParentOtherPost),
+		errors.Is(err, comment.ErrInvalidStatus):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, comment.ErrNotCommentOwner), errors.Is(err, comment.ErrCommentsClosed):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,194 @@
+package links
+
+import (
+	"errors"
+	"net/http"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/links"
+	"github.com/daniel-vuky/go-blog/internal/service/links"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type Handler struct {
+	service *links.Service
+}
+
+// NewHandler create a new handler
+func NewHandler(s *links.Service) *Handler {
+	return &Handler{
+		service: s,
+	}
+}
+
+// idUri
+type idUri struct {
+	ID int64 `uri:"id" binding:"required,gt=0"`
+}
+
+// setCategoriesParams
+type setCategoriesParams struct {
+	CategoryIDs []int64 `json:"category_ids" binding:"max=100,dive,gt=0"`
+}
+
+// changeCategoriesParams
+type changeCategoriesParams struct {
+	CategoryIDs []int64 `json:"category_ids" form:"category_ids" binding:"required,min=1,max=100,dive,gt=0"`
+}
+
+// GetPostCategories Get the categories of a post
+// @Param id
+// @Success 200 {object} []model.Category
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/posts/{id}/categories [get]
+func (s *Handler) GetPostCategories(ctx *gin.Context) {
+	var uri idUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	categories, err := s.service.GetPostCategories(ctx, uri.ID)
+	if err != nil {
+		respondLinksError(ctx, err, "post not found")
+		return
+	}
+	ctx.JSON(http.StatusOK, categories)
+}
+
+// SetPostCategories Replace every category of a post, an empty list removes them all
+// @Param id
+// @Param setCategoriesParams
+// @Success 200 {object} []model.Category
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/posts/{id}/categories [put]
+func (s *Handler) SetPostCategories(ctx *gin.Context) {
+	var uri idUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var arg setCategoriesParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	categories, err := s.service.SetPostCategories(ctx, uri.ID, arg.CategoryIDs)
+	if err != nil {
+		respondLinksError(ctx, err, "post not found")
+		return
+	}
+	ctx.JSON(http.StatusOK, categories)
+}
+
+// AddPostCategories Assign a post to more categories
+// @Param id
+// @Param changeCategoriesParams
+// @Success 200 {object} []model.Category
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/posts/{id}/categories [post]
+func (s *Handler) AddPostCategories(ctx *gin.Context) {
+	var uri idUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var arg changeCategoriesParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	categories, err := s.service.AddPostCategories(ctx, uri.ID, arg.CategoryIDs)
+	if err != nil {
+		respondLinksError(ctx, err, "post not found")
+		return
+	}
+	ctx.JSON(http.StatusOK, categories)
+}
+
+// RemovePostCategories Remove a post from the categories given as category_ids query values
+// @Param id
+// @Param changeCategoriesParams
+// @Success 200 {object} []model.Category
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/posts/{id}/categories [delete]
+func (s *Handler) RemovePostCategories(ctx *gin.Context) {
+	var uri idUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var arg changeCategoriesParams
+	if err := ctx.ShouldBindQuery(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	categories, err := s.service.RemovePostCategories(ctx, uri.ID, arg.CategoryIDs)
+	if err != nil {
+		respondLinksError(ctx, err, "post not found")
+		return
+	}
+	ctx.JSON(http.StatusOK, categories)
+}
+
+// getListCategoryPostParams
+type getListCategoryPostParams struct {
+	IncludeDescendants bool  `json:"include_descendants" form:"include_descendants"`
+	PageSize           int32 `json:"page_size" form:"page_size" binding:"required,gt=0,max=100"`
+	CurrentPage        int32 `json:"current_page" form:"current_page" binding:"required,gt=0"`
+}
+
+// GetListCategoryPost Get the published posts of a category, optionally with the posts of its descendants
+// @Param id
+// @Param getListCategoryPostParams
+// @Success 200 {object} blog.ListPostResponse
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /categories/{id}/posts [get]
+func (s *Handler) GetListCategoryPost(ctx *gin.Context) {
+	var uri idUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var arg getListCategoryPostParams
+	if err := ctx.ShouldBindQuery(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	posts, err := s.service.GetListCategoryPost(ctx, &model.GetListCategoryPostParams{
+		CategoryID:         uri.ID,
+		IncludeDescendants: arg.IncludeDescendants,
+		PageSize:           arg.PageSize,
+		CurrentPage:        arg.CurrentPage,
+	})
+	if err != nil {
+		respondLinksError(ctx, err, "category not found")
+		return
+	}
+	ctx.JSON(http.StatusOK, posts)
+}
+
+// respondLinksError
+// Map the errors of the links service to their status codes
+// @param ctx *gin.Context
+// @param err error
+// @param notFoundMessage string
+func respondLinksError(ctx *gin.Context, err error, notFoundMessage string) {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": notFoundMessage})
+	case errors.Is(err, links.ErrCategoryNotFound):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
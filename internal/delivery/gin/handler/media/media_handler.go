@@ -0,0 +1,260 @@
+package media
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/daniel-vuky/go-blog/internal/delivery/gin/middleware"
+	model "github.com/daniel-vuky/go-blog/internal/models/media"
+	"github.com/daniel-vuky/go-blog/internal/service/media"
+	"github.com/daniel-vuky/go-blog/pkg/filestore"
+	"github.com/daniel-vuky/go-blog/pkg/imaging"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// multipartOverhead
+// Room left in the request body for the multipart headers around the file
+const multipartOverhead = 1 << 20
+
+type Handler struct {
+	service *media.Service
+}
+
+// NewHandler create a new handler
+func NewHandler(s *media.Service) *Handler {
+	return &Handler{
+		service: s,
+	}
+}
+
+// mediaUri
+type mediaUri struct {
+	MediaID int64 `uri:"id" binding:"required,gt=0"`
+}
+
+// variantParams
+type variantParams struct {
+	Width  int    `form:"w" binding:"omitempty,gte=0,max=10000"`
+	Height int    `form:"h" binding:"omitempty,gte=0,max=10000"`
+	Fit    string `form:"fit" binding:"omitempty,oneof=cover contain"`
+	Format string `form:"format" binding:"omitempty,oneof=jpeg png webp"`
+}
+
+// getListMediaParams
+type getListMediaParams struct {
+	FileName       string `json:"file_name" form:"file_name" binding:"omitempty,max=255"`
+	MimeType       string `json:"mime_type" form:"mime_type" binding:"omitempty,max=127"`
+	OrderBy        string `json:"order_by" form:"order_by" binding:"omitempty,oneof=media_id file_name size created_at"`
+	OrderDirection string `json:"order_direction" form:"order_direction" binding:"omitempty,oneof=asc desc"`
+	PageSize       int32  `json:"page_size" form:"page_size" binding:"required,gt=0"`
+	CurrentPage    int32  `json:"current_page" form:"current_page" binding:"required,gt=0"`
+}
+
+// GetListMedia Get a list of media files, newest first
+// @Param getListMediaParams
+// @Success 200 {object} media.ListMediaResponse
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/media [get]
+func (s *Handler) GetListMedia(ctx *gin.Context) {
+	var arg getListMediaParams
+	if err := ctx.ShouldBindQuery(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	listMedia, err := s.service.GetListMedia(ctx, &model.GetListMediaParams{
+		Filter: &model.GetListMediaFilterParams{
+			FileName: pgtype.Text{String: arg.FileName, Valid: arg.FileName != ""},
+			MimeType: pgtype.Text{String: arg.MimeType, Valid: arg.MimeType != ""},
+		},
+		OrderBy:        arg.OrderBy,
+		OrderDirection: arg.OrderDirection,
+		PageSize:       arg.PageSize,
+		CurrentPage:    arg.CurrentPage,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, listMedia)
+}
+
+// GetMedia Get a media file with the posts using it
+// @Param id
+// @Success 200 {object} model.Media
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/media/{id} [get]
+func (s *Handler) GetMedia(ctx *gin.Context) {
+	var uri mediaUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	file, err := s.service.GetMedia(ctx, uri.MediaID)
+	if err != nil {
+		respondMediaError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, file)
+}
+
+// UploadMedia Upload an image as the multipart field file, an image already
+// in the library is returned with 200 instead of being stored again
+// @Param file
+// @Success 201 {object} model.Media
+// @Success 200 {object} model.Media
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 401 {object} gin.H{"error": "Unauthorized"}
+// @Failure 413 {object} gin.H{"error": "Request Entity Too Large"}
+// @Failure 415 {object} gin.H{"error": "Unsupported Media Type"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/media [post]
+func (s *Handler) UploadMedia(ctx *gin.Context) {
+	authorizedAdmin, ok := middleware.GetAuthorizedAdmin(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "admin is not authenticated"})
+		return
+	}
+	maxSize := s.service.MaxSize()
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxSize+multipartOverhead)
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			respondMediaError(ctx, media.ErrFileTooLarge)
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if fileHeader.Size > maxSize {
+		respondMediaError(ctx, media.ErrFileTooLarge)
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+	content, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uploaded, created, err := s.service.UploadMedia(ctx, &model.UploadMediaParams{
+		FileName: fileHeader.Filename,
+		Content:  content,
+		AdminID:  pgtype.Int8{Int64: int64(authorizedAdmin.AdminID), Valid: true},
+	})
+	if err != nil {
+		respondMediaError(ctx, err)
+		return
+	}
+	if created {
+		ctx.JSON(http.StatusCreated, uploaded)
+		return
+	}
+	ctx.JSON(http.StatusOK, uploaded)
+}
+
+// DeleteMedia Delete a media file no post uses
+// @Param id
+// @Success 200 {object} model.Media
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 409 {object} gin.H{"error": "Conflict"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/media/{id} [delete]
+func (s *Handler) DeleteMedia(ctx *gin.Context) {
+	var uri mediaUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	deleted, err := s.service.DeleteMedia(ctx, uri.MediaID)
+	if err != nil {
+		respondMediaError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, deleted)
+}
+
+// ServeFile Serve a stored file or one of its resized variants, the content of a key never changes
+// @Param key
+// @Param w query int false "Width of the variant"
+// @Param h query int false "Height of the variant"
+// @Param fit query string false "cover or contain"
+// @Param format query string false "jpeg, png or webp"
+// @Success 200
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /media/{key} [get]
+func (s *Handler) ServeFile(ctx *gin.Context) {
+	var arg variantParams
+	if err := ctx.ShouldBindQuery(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	storageKey := strings.TrimPrefix(ctx.Param("key"), "/")
+	var (
+		file        io.ReadCloser
+		contentType string
+		err         error
+	)
+	if arg == (variantParams{}) {
+		file, contentType, err = s.service.OpenFile(ctx, storageKey)
+	} else {
+		// The request context stops the wait for a variant when the client goes away
+		file, contentType, err = s.service.OpenVariant(ctx.Request.Context(), storageKey, media.Variant{
+			Width:  arg.Width,
+			Height: arg.Height,
+			Fit:    imaging.Fit(arg.Fit),
+			Format: imaging.Format(arg.Format),
+		})
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, filestore.ErrNotFound) || errors.Is(err, filestore.ErrInvalidKey):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
+		case errors.Is(err, media.ErrVariantNotAllowed):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	defer file.Close()
+	ctx.DataFromReader(http.StatusOK, -1, contentType, file, map[string]string{
+		"Cache-Control":          "public, max-age=31536000, immutable",
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// respondMediaError
+// Map the errors of the media service to their status codes
+// @param ctx *gin.Context
+// @param err error
+func respondMediaError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "media not found"})
+	case errors.Is(err, media.ErrEmptyFile):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, media.ErrFileTooLarge):
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, media.ErrUnsupportedMediaType):
+		ctx.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case errors.Is(err, media.ErrMediaInUse):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
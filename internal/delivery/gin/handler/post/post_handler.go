@@ -0,0 +1,414 @@
+package post
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/daniel-vuky/go-blog/internal/delivery/gin/middleware"
+	model "github.com/daniel-vuky/go-blog/internal/models/post"
+	"github.com/daniel-vuky/go-blog/internal/service/post"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Handler struct {
+	service *post.Service
+}
+
+// NewHandler create a new handler
+func NewHandler(s *post.Service) *Handler {
+	return &Handler{
+		service: s,
+	}
+}
+
+// postUri
+type postUri struct {
+	PostID int64 `uri:"id" binding:"required,gt=0"`
+}
+
+// GetPost Get post by id
+// @Param id
+// @Success 200 {object} model.Post
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/posts/{id} [get]
+func (s *Handler) GetPost(ctx *gin.Context) {
+	var uri postUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	loadedPost, err := s.service.GetPost(ctx, uri.PostID)
+	if err != nil {
+		respondPostError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, loadedPost)
+}
+
+// getListPostParams
+type getListPostParams struct {
+	Name           string `json:"name" form:"name" binding:"omitempty,max=512"`
+	UrlKey         string `json:"url_key" form:"url_key" binding:"omitempty"`
+	AuthorID       int64  `json:"author_id" form:"author_id" binding:"omitempty,gt=0"`
+	Status         string `json:"status" form:"status" binding:"omitempty,oneof=draft review scheduled published archived"`
+	OrderBy        string `json:"order_by" form:"order_by" binding:"omitempty,oneof=post_id name published_at created_at updated_at"`
+	OrderDirection string `json:"order_direction" form:"order_direction" binding:"omitempty,oneof=asc desc"`
+	PageSize       int32  `json:"page_size" form:"page_size" binding:"required,gt=0"`
+	CurrentPage    int32  `json:"current_page" form:"current_page" binding:"required,gt=0"`
+}
+
+// GetListPost Get list of posts
+// @Param getListPostParams
+// @Success 200 {object} post.ListPostResponse
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/posts [get]
+func (s *Handler) GetListPost(ctx *gin.Context) {
+	var arg getListPostParams
+	if err := ctx.ShouldBindQuery(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	posts, err := s.service.GetListPost(ctx, &model.GetListPostParams{
+		Filter: &model.GetListPostFilterParams{
+			Name:     pgtype.Text{String: arg.Name, Valid: arg.Name != ""},
+			UrlKey:   pgtype.Text{String: arg.UrlKey, Valid: arg.UrlKey != ""},
+			AuthorID: pgtype.Int8{Int64: arg.AuthorID, Valid: arg.AuthorID != 0},
+			Status:   pgtype.Text{String: arg.Status, Valid: arg.Status != ""},
+		},
+		OrderBy:        arg.OrderBy,
+		OrderDirection: arg.OrderDirection,
+		PageSize:       arg.PageSize,
+		CurrentPage:    arg.CurrentPage,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, posts)
+}
+
+// createPostParams
+type createPostParams struct {
+	Name             string `json:"name" binding:"required,max=512"`
+	ShortDescription string `json:"short_description"`
+	Description      string `json:"description"`
+	Content          string `json:"content"`
+	UrlKey           string `json:"url_key"`
+	Thumbnail        string `json:"thumbnail"`
+	CommentMode      string `json:"comment_mode" binding:"omitempty,oneof=open moderated closed"`
+}
+
+// CreatePost Create a new post written by the authenticated admin
+// @Param createPostParams
+// @Success 200 {object} model.Post
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 401 {object} gin.H{"error": "Unauthorized"}
+// @Failure 409 {object} gin.H{"error": "Conflict"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/posts [post]
+func (s *Handler) CreatePost(ctx *gin.Context) {
+	authorizedAdmin, ok := middleware.GetAuthorizedAdmin(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "admin is not authenticated"})
+		return
+	}
+	var arg createPostParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	createdPost, err := s.service.CreatePost(ctx, &model.CreatePostParams{
+		Name:             arg.Name,
+		ShortDescription: pgtype.Text{String: arg.ShortDescription, Valid: arg.ShortDescription != ""},
+		Description:      pgtype.Text{String: arg.Description, Valid: arg.Description != ""},
+		Content:          pgtype.Text{String: arg.Content, Valid: arg.Content != ""},
+		UrlKey:           pgtype.Text{String: arg.UrlKey, Valid: arg.UrlKey != ""},
+		Thumbnail:        pgtype.Text{String: arg.Thumbnail, Valid: arg.Thumbnail != ""},
+		AuthorID:         int64(authorizedAdmin.AdminID),
+		CommentMode:      model.CommentMode(arg.CommentMode),
+	})
+	if err != nil {
+		respondPostError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, createdPost)
+}
+
+// updatePostParams
+type updatePostParams struct {
+	Name             string `json:"name" binding:"max=512"`
+	ShortDescription string `json:"short_description"`
+	Description      string `json:"description"`
+	Content          string `json:"content"`
+	UrlKey           string `json:"url_key"`
+	Thumbnail        string `json:"thumbnail"`
+	CommentMode      string `json:"comment_mode" binding:"omitempty,oneof=open moderated closed"`
+	RegenerateUrlKey bool   `json:"regenerate_url_key"`
+}
+
+// UpdatePost Update post params, a revision is recorded when the name, the descriptions or the content changed
+// @Param id
+// @Param updatePostParams
+// @Success 200 {object} model.Post
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 401 {object} gin.H{"error": "Unauthorized"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 409 {object} gin.H{"error": "Conflict"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/posts/{id} [put]
+func (s *Handler) UpdatePost(ctx *gin.Context) {
+	authorizedAdmin, ok := middleware.GetAuthorizedAdmin(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "admin is not authenticated"})
+		return
+	}
+	var uri postUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var arg updatePostParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	updatedPost, err := s.service.UpdatePost(ctx, &model.UpdatePostParams{
+		PostID:           uri.PostID,
+		Name:             pgtype.Text{String: arg.Name, Valid: arg.Name != ""},
+		ShortDescription: pgtype.Text{String: arg.ShortDescription, Valid: arg.ShortDescription != ""},
+		Description:      pgtype.Text{String: arg.Description, Valid: arg.Description != ""},
+		Content:          pgtype.Text{String: arg.Content, Valid: arg.Content != ""},
+		UrlKey:           pgtype.Text{String: arg.UrlKey, Valid: arg.UrlKey != ""},
+		Thumbnail:        pgtype.Text{String: arg.Thumbnail, Valid: arg.Thumbnail != ""},
+		CommentMode:      pgtype.Text{String: arg.CommentMode, Valid: arg.CommentMode != ""},
+		RegenerateUrlKey: arg.RegenerateUrlKey,
+		EditorID:         int64(authorizedAdmin.AdminID),
+	})
+	if err != nil {
+		respondPostError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, updatedPost)
+}
+
+// DeletePost Delete a post
+// @Param id
+// @Success 200 {object} model.Post
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/posts/{id} [delete]
+func (s *Handler) DeletePost(ctx *gin.Context) {
+	var uri postUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	deletedPost, err := s.service.DeletePost(ctx, uri.PostID)
+	if err != nil {
+		respondPostError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, deletedPost)
+}
+
+// updatePostStatusParams
+type updatePostStatusParams struct {
+	Status      string    `json:"status" binding:"required,oneof=draft review scheduled published archived"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// UpdatePostStatus Move a post in the publishing workflow
+// @Param id
+// @Param updatePostStatusParams
+// @Success 200 {object} model.Post
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 409 {object} gin.H{"error": "Conflict"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/posts/{id}/status [put]
+func (s *Handler) UpdatePostStatus(ctx *gin.Context) {
+	var uri postUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var arg updatePostStatusParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	updatedPost, err := s.service.UpdatePostStatus(ctx, &model.UpdatePostStatusParams{
+		PostID: uri.PostID,
+		Status: model.PostStatus(arg.Status),
+		PublishedAt: pgtype.Timestamptz{
+			Time:  arg.PublishedAt,
+			Valid: arg.PublishedAt != time.Time{},
+		},
+	})
+	if err != nil {
+		respondPostError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, updatedPost)
+}
+
+// postRevisionUri
+type postRevisionUri struct {
+	PostID     int64 `uri:"id" binding:"required,gt=0"`
+	RevisionID int64 `uri:"revision_id" binding:"required,gt=0"`
+}
+
+// previewPostParams
+type previewPostParams struct {
+	Content string `json:"content"`
+}
+
+// PreviewPost Render a markdown content the way the blog shows it, without saving it
+// @Param previewPostParams
+// @Success 200 {object} markdown.Document
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/posts/preview [post]
+func (s *Handler) PreviewPost(ctx *gin.Context) {
+	var arg previewPostParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	document, err := s.service.PreviewContent(arg.Content)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, document)
+}
+
+// getListPostRevisionParams
+type getListPostRevisionParams struct {
+	PageSize    int32 `json:"page_size" form:"page_size" binding:"required,gt=0,max=100"`
+	CurrentPage int32 `json:"current_page" form:"current_page" binding:"required,gt=0"`
+}
+
+// GetListPostRevision Get the revisions of a post, newest first
+// @Param id
+// @Param getListPostRevisionParams
+// @Success 200 {object} post.ListPostRevisionResponse
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/posts/{id}/revisions [get]
+func (s *Handler) GetListPostRevision(ctx *gin.Context) {
+	var uri postUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var arg getListPostRevisionParams
+	if err := ctx.ShouldBindQuery(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	revisions, err := s.service.GetListPostRevision(ctx, &model.GetListPostRevisionParams{
+		PostID:      uri.PostID,
+		PageSize:    arg.PageSize,
+		CurrentPage: arg.CurrentPage,
+	})
+	if err != nil {
+		respondPostError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, revisions)
+}
+
+// getPostRevisionDiffParams
+type getPostRevisionDiffParams struct {
+	From int64 `json:"from" form:"from" binding:"required,gt=0"`
+	To   int64 `json:"to" form:"to" binding:"required,gt=0"`
+}
+
+// GetPostRevisionDiff Get the line-level changes between two revisions of a post
+// @Param id
+// @Param getPostRevisionDiffParams
+// @Success 200 {object} post.PostRevisionDiff
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/posts/{id}/revisions/diff [get]
+func (s *Handler) GetPostRevisionDiff(ctx *gin.Context) {
+	var uri postUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var arg getPostRevisionDiffParams
+	if err := ctx.ShouldBindQuery(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	revisionDiff, err := s.service.GetPostRevisionDiff(ctx, uri.PostID, arg.From, arg.To)
+	if err != nil {
+		respondPostError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, revisionDiff)
+}
+
+// RestorePostRevision Restore a revision of a post, recorded as a new revision
+// @Param id
+// @Param revision_id
+// @Success 200 {object} model.Post
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 401 {object} gin.H{"error": "Unauthorized"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/posts/{id}/revisions/{revision_id}/restore [post]
+func (s *Handler) RestorePostRevision(ctx *gin.Context) {
+	authorizedAdmin, ok := middleware.GetAuthorizedAdmin(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "admin is not authenticated"})
+		return
+	}
+	var uri postRevisionUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	restoredPost, err := s.service.RestorePostRevision(ctx, uri.PostID, uri.RevisionID, int64(authorizedAdmin.AdminID))
+	if err != nil {
+		respondPostError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, restoredPost)
+}
+
+// respondPostError
+// Map the errors of the post service to their status codes
+// @param ctx *gin.Context
+// @param err error
+func respondPostError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "post not found"})
+	case errors.Is(err, post.ErrRevisionNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, post.ErrUrlKeyAlreadyExists),
+		errors.Is(err, post.ErrUrlKeyReserved),
+		errors.Is(err, post.ErrInvalidStatusTransition):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, post.ErrInvalidStatus),
+		errors.Is(err, post.ErrInvalidPublishedAt),
+		errors.Is(err, post.ErrPublishedAtNotAllowed),
+		errors.Is(err, post.ErrUrlKeyRequired),
+		errors.Is(err, post.ErrUrlKeyInvalid):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
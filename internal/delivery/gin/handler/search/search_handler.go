@@ -0,0 +1,57 @@
+package search
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/daniel-vuky/go-blog/internal/service/search"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	service *search.Service
+}
+
+// NewHandler create a new handler
+func NewHandler(s *search.Service) *Handler {
+	return &Handler{
+		service: s,
+	}
+}
+
+// searchParams
+type searchParams struct {
+	Query       string `json:"q" form:"q" binding:"required,max=255"`
+	PageSize    int32  `json:"page_size" form:"page_size" binding:"omitempty,gt=0,max=100"`
+	CurrentPage int32  `json:"current_page" form:"current_page" binding:"omitempty,gt=0"`
+}
+
+// SearchPost Search the published posts, best match first
+// @Param searchParams
+// @Success 200 {object} search.ListPostResponse
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /search [get]
+func (s *Handler) SearchPost(ctx *gin.Context) {
+	var arg searchParams
+	if err := ctx.ShouldBindQuery(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if arg.PageSize == 0 {
+		arg.PageSize = 20
+	}
+	if arg.CurrentPage == 0 {
+		arg.CurrentPage = 1
+	}
+	posts, err := s.service.SearchPost(ctx, arg.Query, arg.PageSize, arg.CurrentPage)
+	if err != nil {
+		if errors.Is(err, search.ErrEmptyQuery) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, posts)
+}
@@ -0,0 +1,148 @@
+package session
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/daniel-vuky/go-blog/internal/delivery/gin/middleware"
+	"github.com/daniel-vuky/go-blog/internal/service/session"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	service *session.Service
+}
+
+// NewHandler create a new handler
+func NewHandler(s *session.Service) *Handler {
+	return &Handler{
+		service: s,
+	}
+}
+
+// refreshTokenParams
+type refreshTokenParams struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// userUri
+type userUri struct {
+	UserID int64 `uri:"id" binding:"required,gt=0"`
+}
+
+// Refresh Exchange a refresh token for a new access token and refresh token
+// @Param refreshTokenParams
+// @Success 200 {object} session.Tokens
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 401 {object} gin.H{"error": "Unauthorized"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /auth/refresh [post]
+func (s *Handler) Refresh(ctx *gin.Context) {
+	var arg refreshTokenParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	tokens, err := s.service.Refresh(ctx, arg.RefreshToken, ctx.Request.UserAgent(), ctx.ClientIP())
+	if err != nil {
+		if isRefreshTokenError(err) {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, tokens)
+}
+
+// Logout End the session of a refresh token
+// @Param refreshTokenParams
+// @Success 200 {object} gin.H{"message": "logged out"}
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 401 {object} gin.H{"error": "Unauthorized"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /auth/logout [post]
+func (s *Handler) Logout(ctx *gin.Context) {
+	var arg refreshTokenParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := s.service.Logout(ctx, arg.RefreshToken); err != nil {
+		if isRefreshTokenError(err) {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "logged out"})
+}
+
+// GetListSessions Get the active sessions of the authenticated user
+// @Success 200 {object} []model.RefreshToken
+// @Failure 401 {object} gin.H{"error": "Unauthorized"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /auth/sessions [get]
+func (s *Handler) GetListSessions(ctx *gin.Context) {
+	userID, ok := middleware.GetAuthorizedUserID(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user is not authenticated"})
+		return
+	}
+	sessions, err := s.service.GetListSessions(ctx, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, sessions)
+}
+
+// GetListUserSessions Get the active sessions of a user
+// @Param id
+// @Success 200 {object} []model.RefreshToken
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/users/{id}/sessions [get]
+func (s *Handler) GetListUserSessions(ctx *gin.Context) {
+	var uri userUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sessions, err := s.service.GetListSessions(ctx, uri.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, sessions)
+}
+
+// RevokeUserSessions Revoke every session of a user
+// @Param id
+// @Success 200 {object} gin.H{"message": "sessions revoked"}
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/users/{id}/sessions [delete]
+func (s *Handler) RevokeUserSessions(ctx *gin.Context) {
+	var uri userUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := s.service.RevokeUserSessions(ctx, uri.UserID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "sessions revoked"})
+}
+
+// isRefreshTokenError
+// Check if the error is caused by the refresh token sent by the client
+// @param err error
+// @return bool
+func isRefreshTokenError(err error) bool {
+	return errors.Is(err, session.ErrInvalidRefreshToken) ||
+		errors.Is(err, session.ErrExpiredRefreshToken) ||
+		errors.Is(err, session.ErrRefreshTokenReused)
+}
@@ -0,0 +1,202 @@
+package twofactor
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/daniel-vuky/go-blog/internal/delivery/gin/middleware"
+	"github.com/daniel-vuky/go-blog/internal/service/admin"
+	"github.com/daniel-vuky/go-blog/internal/service/twofactor"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type Handler struct {
+	service      *twofactor.Service
+	adminService *admin.Service
+}
+
+// NewHandler create a new handler
+func NewHandler(s *twofactor.Service, adminService *admin.Service) *Handler {
+	return &Handler{
+		service:      s,
+		adminService: adminService,
+	}
+}
+
+// codeParams
+type codeParams struct {
+	Code string `json:"code" binding:"required,max=32"`
+}
+
+// recoveryCodesResponse
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// GetStatus Get the two-factor state of the authenticated admin
+// @Success 200 {object} twofactor.Status
+// @Failure 401 {object} gin.H{"error": "Unauthorized"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/me/2fa [get]
+func (s *Handler) GetStatus(ctx *gin.Context) {
+	authorizedAdmin, ok := middleware.GetAuthorizedAdmin(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "admin is not authenticated"})
+		return
+	}
+	status, err := s.service.GetStatus(ctx, authorizedAdmin.AdminID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, status)
+}
+
+// Setup Start the enrollment of the authenticated admin with a new secret
+// @Success 200 {object} twofactor.SetupResponse
+// @Failure 401 {object} gin.H{"error": "Unauthorized"}
+// @Failure 409 {object} gin.H{"error": "Conflict"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/me/2fa/setup [post]
+func (s *Handler) Setup(ctx *gin.Context) {
+	authorizedAdmin, ok := middleware.GetAuthorizedAdmin(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "admin is not authenticated"})
+		return
+	}
+	setup, err := s.service.Setup(ctx, &authorizedAdmin)
+	if err != nil {
+		respondTwoFactorError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, setup)
+}
+
+// Enable Confirm the enrollment with a code of the new secret
+// @Param codeParams
+// @Success 200 {object} recoveryCodesResponse
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 401 {object} gin.H{"error": "Unauthorized"}
+// @Failure 409 {object} gin.H{"error": "Conflict"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/me/2fa/enable [post]
+func (s *Handler) Enable(ctx *gin.Context) {
+	authorizedAdmin, ok := middleware.GetAuthorizedAdmin(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "admin is not authenticated"})
+		return
+	}
+	var arg codeParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	recoveryCodes, err := s.service.Enable(ctx, authorizedAdmin.AdminID, arg.Code)
+	if err != nil {
+		respondTwoFactorError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, recoveryCodesResponse{RecoveryCodes: recoveryCodes})
+}
+
+// Disable Turn two-factor authentication off for the authenticated admin
+// @Param codeParams
+// @Success 204
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 401 {object} gin.H{"error": "Unauthorized"}
+// @Failure 403 {object} gin.H{"error": "Forbidden"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/me/2fa/disable [post]
+func (s *Handler) Disable(ctx *gin.Context) {
+	authorizedAdmin, ok := middleware.GetAuthorizedAdmin(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "admin is not authenticated"})
+		return
+	}
+	var arg codeParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := s.service.Disable(ctx, &authorizedAdmin, arg.Code); err != nil {
+		respondTwoFactorError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// RegenerateRecoveryCodes Replace the recovery codes of the authenticated admin
+// @Param codeParams
+// @Success 200 {object} recoveryCodesResponse
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 401 {object} gin.H{"error": "Unauthorized"}
+// @Failure 409 {object} gin.H{"error": "Conflict"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/me/2fa/recovery-codes [post]
+func (s *Handler) RegenerateRecoveryCodes(ctx *gin.Context) {
+	authorizedAdmin, ok := middleware.GetAuthorizedAdmin(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "admin is not authenticated"})
+		return
+	}
+	var arg codeParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	recoveryCodes, err := s.service.RegenerateRecoveryCodes(ctx, authorizedAdmin.AdminID, arg.Code)
+	if err != nil {
+		respondTwoFactorError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, recoveryCodesResponse{RecoveryCodes: recoveryCodes})
+}
+
+// ResetAdmin Remove the two-factor settings of an admin who lost its device
+// @Param email
+// @Success 204
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/{email}/2fa [delete]
+func (s *Handler) ResetAdmin(ctx *gin.Context) {
+	email := ctx.Param("email")
+	if email == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "email is required"})
+		return
+	}
+	loadedAdmin, err := s.adminService.GetAdmin(ctx, email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "admin not found"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err = s.service.Reset(ctx, loadedAdmin.AdminID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// respondTwoFactorError
+// Map the errors of the enrollment to their status codes
+// @param ctx *gin.Context
+// @param err error
+func respondTwoFactorError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, twofactor.ErrInvalidTwoFactorCode):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, twofactor.ErrTwoFactorRequired):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, twofactor.ErrTwoFactorAlreadyEnabled),
+		errors.Is(err, twofactor.ErrTwoFactorNotEnabled),
+		errors.Is(err, twofactor.ErrTwoFactorNotSetUp):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
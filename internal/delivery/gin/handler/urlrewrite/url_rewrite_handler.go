@@ -0,0 +1,90 @@
+package urlrewrite
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/daniel-vuky/go-blog/internal/service/urlrewrite"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type Handler struct {
+	service *urlrewrite.Service
+}
+
+// NewHandler create a new handler
+func NewHandler(s *urlrewrite.Service) *Handler {
+	return &Handler{
+		service: s,
+	}
+}
+
+// resolveParams
+type resolveParams struct {
+	Path string `json:"path" form:"path" binding:"required,max=2048"`
+}
+
+// Resolve Resolve a path to the post or the category it belongs to
+// @Param resolveParams
+// @Success 200 {object} urlrewrite.Resolution
+// @Success 301 {object} urlrewrite.Resolution
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /resolve [get]
+func (s *Handler) Resolve(ctx *gin.Context) {
+	var arg resolveParams
+	if err := ctx.ShouldBindQuery(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	resolution, err := s.service.Resolve(ctx, arg.Path)
+	if err != nil {
+		respondResolveError(ctx, err)
+		return
+	}
+	if resolution.RedirectTo != "" {
+		ctx.Header("Location", "/resolve?path="+url.QueryEscape(resolution.RedirectTo))
+		ctx.JSON(http.StatusMovedPermanently, resolution)
+		return
+	}
+	ctx.JSON(http.StatusOK, resolution)
+}
+
+// ResolveRoute Resolve the path of a request which matched no other route
+// @Success 200 {object} urlrewrite.Resolution
+// @Success 301 {object} urlrewrite.Resolution
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /{path} [get]
+func (s *Handler) ResolveRoute(ctx *gin.Context) {
+	if ctx.Request.Method != http.MethodGet && ctx.Request.Method != http.MethodHead {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "route not found"})
+		return
+	}
+	resolution, err := s.service.Resolve(ctx, ctx.Request.URL.Path)
+	if err != nil {
+		respondResolveError(ctx, err)
+		return
+	}
+	if resolution.RedirectTo != "" {
+		ctx.Header("Location", (&url.URL{Path: "/" + resolution.RedirectTo}).EscapedPath())
+		ctx.JSON(http.StatusMovedPermanently, resolution)
+		return
+	}
+	ctx.JSON(http.StatusOK, resolution)
+}
+
+// respondResolveError
+// Map the errors of the resolver to their status codes
+// @param ctx *gin.Context
+// @param err error
+func respondResolveError(ctx *gin.Context, err error) {
+	if errors.Is(err, pgx.ErrNoRows) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "route not found"})
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,358 @@
+package user
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/daniel-vuky/go-blog/internal/delivery/gin/middleware"
+	model "github.com/daniel-vuky/go-blog/internal/models/user"
+	"github.com/daniel-vuky/go-blog/internal/service/account"
+	"github.com/daniel-vuky/go-blog/internal/service/session"
+	"github.com/daniel-vuky/go-blog/internal/service/user"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// dobLayout
+// Layout of the date of birth sent by clients
+const dobLayout = "2006-01-02"
+
+type Handler struct {
+	service        *user.Service
+	sessionService *session.Service
+	accountService *account.Service
+}
+
+// NewHandler create a new handler
+func NewHandler(s *user.Service, sessionService *session.Service, accountService *account.Service) *Handler {
+	return &Handler{
+		service:        s,
+		sessionService: sessionService,
+		accountService: accountService,
+	}
+}
+
+// userUri
+type userUri struct {
+	UserID int64 `uri:"id" binding:"required,gt=0"`
+}
+
+// registerUserParams
+type registerUserParams struct {
+	Email     string `json:"email" binding:"required,email,max=255"`
+	Password  string `json:"password" binding:"required,min=8,max=72"`
+	Firstname string `json:"firstname" binding:"required,max=32"`
+	Lastname  string `json:"lastname" binding:"required,max=32"`
+	Subscribe bool   `json:"subscribe"`
+	Gender    string `json:"gender" binding:"omitempty,oneof=1 2 3"`
+	Dob       string `json:"dob" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// Register Register a new site user and send the email verification link
+// @Param registerUserParams
+// @Success 200 {object} model.User
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 409 {object} gin.H{"error": "Conflict"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /users/register [post]
+func (s *Handler) Register(ctx *gin.Context) {
+	var arg registerUserParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	dob, err := parseDob(arg.Dob)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	createdUser, err := s.service.Register(ctx, &model.CreateUserParams{
+		Email:     arg.Email,
+		Password:  arg.Password,
+		Firstname: arg.Firstname,
+		Lastname:  arg.Lastname,
+		Subscribe: pgtype.Bool{Bool: arg.Subscribe, Valid: true},
+		Gender:    parseGender(arg.Gender),
+		Dob:       dob,
+	})
+	if err != nil {
+		respondUserError(ctx, err)
+		return
+	}
+	if err = s.accountService.SendEmailVerification(ctx, &createdUser); err != nil {
+		log.Printf("failed to send the email verification of user %d: %v", createdUser.UserID, err)
+	}
+	ctx.JSON(http.StatusOK, createdUser)
+}
+
+// loginUserParams
+type loginUserParams struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+// loginUserResponse
+type loginUserResponse struct {
+	session.Tokens
+	User model.User `json:"user"`
+}
+
+// Login Authenticate a site user and start a new session
+// @Param loginUserParams
+// @Success 200 {object} loginUserResponse
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 401 {object} gin.H{"error": "Unauthorized"}
+// @Failure 403 {object} gin.H{"error": "Forbidden"}
+// @Failure 429 {object} gin.H{"error": "Too Many Requests"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /users/login [post]
+func (s *Handler) Login(ctx *gin.Context) {
+	var arg loginUserParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	loggedUser, err := s.service.Login(ctx, arg.Email, arg.Password, ctx.ClientIP())
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrInvalidCredentials):
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case errors.Is(err, user.ErrUserDisabled):
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, user.ErrClientIpLocked):
+			ctx.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	tokens, err := s.sessionService.CreateSession(ctx, loggedUser.UserID, ctx.Request.UserAgent(), ctx.ClientIP())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, loginUserResponse{
+		Tokens: tokens,
+		User:   loggedUser,
+	})
+}
+
+// GetCurrentUser Get the authenticated site user
+// @Success 200 {object} model.User
+// @Failure 401 {object} gin.H{"error": "Unauthorized"}
+// @Router /users/me [get]
+func (s *Handler) GetCurrentUser(ctx *gin.Context) {
+	authorizedUser, ok := middleware.GetAuthorizedUser(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user is not authenticated"})
+		return
+	}
+	ctx.JSON(http.StatusOK, authorizedUser)
+}
+
+// updateUserParams
+type updateUserParams struct {
+	Firstname       string `json:"firstname" binding:"max=32"`
+	Lastname        string `json:"lastname" binding:"max=32"`
+	Subscribe       *bool  `json:"subscribe"`
+	Gender          string `json:"gender" binding:"omitempty,oneof=1 2 3"`
+	Dob             string `json:"dob" binding:"omitempty,datetime=2006-01-02"`
+	Password        string `json:"password" binding:"omitempty,min=8,max=72"`
+	CurrentPassword string `json:"current_password" binding:"required_with=Password"`
+}
+
+// UpdateCurrentUser Update the profile of the authenticated site user
+// @Param updateUserParams
+// @Success 200 {object} model.User
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 401 {object} gin.H{"error": "Unauthorized"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /users/me [put]
+func (s *Handler) UpdateCurrentUser(ctx *gin.Context) {
+	authorizedUser, ok := middleware.GetAuthorizedUser(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "user is not authenticated"})
+		return
+	}
+	var arg updateUserParams
+	if err := ctx.ShouldBindJSON(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	dob, err := parseDob(arg.Dob)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	params := &model.UpdateUserParams{
+		UserID:    authorizedUser.UserID,
+		Password:  pgtype.Text{String: arg.Password, Valid: arg.Password != ""},
+		Firstname: pgtype.Text{String: arg.Firstname, Valid: arg.Firstname != ""},
+		Lastname:  pgtype.Text{String: arg.Lastname, Valid: arg.Lastname != ""},
+		Gender:    parseGender(arg.Gender),
+		Dob:       dob,
+	}
+	if arg.Subscribe != nil {
+		params.Subscribe = pgtype.Bool{Bool: *arg.Subscribe, Valid: true}
+	}
+	updatedUser, err := s.service.UpdateProfile(ctx, params, arg.CurrentPassword)
+	if err != nil {
+		if errors.Is(err, user.ErrInvalidPassword) {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		respondUserError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, updatedUser)
+}
+
+// getListUserParams
+type getListUserParams struct {
+	Search         string `json:"search" form:"search" binding:"omitempty,max=255"`
+	Email          string `json:"email" form:"email" binding:"omitempty,max=255"`
+	Firstname      string `json:"firstname" form:"firstname" binding:"omitempty,max=32"`
+	Lastname       string `json:"lastname" form:"lastname" binding:"omitempty,max=32"`
+	Gender         string `json:"gender" form:"gender" binding:"omitempty,oneof=1 2 3"`
+	Subscribe      *bool  `json:"subscribe" form:"subscribe" binding:"omitempty"`
+	Active         *bool  `json:"active" form:"active" binding:"omitempty"`
+	OrderBy        string `json:"order_by" form:"order_by" binding:"omitempty,oneof=user_id email firstname lastname created_at"`
+	OrderDirection string `json:"order_direction" form:"order_direction" binding:"omitempty,oneof=asc desc"`
+	PageSize       int32  `json:"page_size" form:"page_size" binding:"required,gt=0"`
+	CurrentPage    int32  `json:"current_page" form:"current_page" binding:"required,gt=0"`
+}
+
+// GetListUser Get list of site users
+// @Param getListUserParams
+// @Success 200 {object} user.ListUserResponse
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/users [get]
+func (s *Handler) GetListUser(ctx *gin.Context) {
+	var arg getListUserParams
+	if err := ctx.ShouldBindQuery(&arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter := &model.GetListUserFilterParams{
+		Email:     pgtype.Text{String: arg.Email, Valid: arg.Email != ""},
+		Firstname: pgtype.Text{String: arg.Firstname, Valid: arg.Firstname != ""},
+		Lastname:  pgtype.Text{String: arg.Lastname, Valid: arg.Lastname != ""},
+		Gender:    parseGender(arg.Gender),
+	}
+	if arg.Subscribe != nil {
+		filter.Subscribe = pgtype.Bool{Bool: *arg.Subscribe, Valid: true}
+	}
+	if arg.Active != nil {
+		filter.Active = pgtype.Bool{Bool: *arg.Active, Valid: true}
+	}
+	users, err := s.service.GetListUser(ctx, &model.GetListUserParams{
+		Filter:         filter,
+		Search:         arg.Search,
+		OrderBy:        arg.OrderBy,
+		OrderDirection: arg.OrderDirection,
+		PageSize:       arg.PageSize,
+		CurrentPage:    arg.CurrentPage,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, users)
+}
+
+// GetUser Get site user by id
+// @Param id
+// @Success 200 {object} model.User
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/users/{id} [get]
+func (s *Handler) GetUser(ctx *gin.Context) {
+	var uri userUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	siteUser, err := s.service.GetUserByID(ctx, uri.UserID)
+	if err != nil {
+		respondUserError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, siteUser)
+}
+
+// DisableUser Disable a site user and revoke its sessions
+// @Param id
+// @Success 200 {object} model.User
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/users/{id}/disable [post]
+func (s *Handler) DisableUser(ctx *gin.Context) {
+	s.setUserActive(ctx, false)
+}
+
+// EnableUser Enable a disabled site user
+// @Param id
+// @Success 200 {object} model.User
+// @Failure 400 {object} gin.H{"error": "Bad Request"}
+// @Failure 404 {object} gin.H{"error": "Not Found"}
+// @Failure 500 {object} gin.H{"error": "Internal Server Error"}
+// @Router /admin/users/{id}/enable [post]
+func (s *Handler) EnableUser(ctx *gin.Context) {
+	s.setUserActive(ctx, true)
+}
+
+// setUserActive
+// Shared implementation of DisableUser and EnableUser
+func (s *Handler) setUserActive(ctx *gin.Context, active bool) {
+	var uri userUri
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	updatedUser, err := s.service.SetUserActive(ctx, uri.UserID, active)
+	if err != nil {
+		respondUserError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, updatedUser)
+}
+
+// respondUserError
+// Map the errors of the user service to a response
+func respondUserError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+	case errors.Is(err, user.ErrEmailAlreadyExists):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, user.ErrInvalidGender), errors.Is(err, user.ErrInvalidDob):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// parseGender
+// Convert the gender sent by clients, an empty value leaves it unset
+func parseGender(gender string) model.NullGender {
+	return model.NullGender{Gender: model.Gender(gender), Valid: gender != ""}
+}
+
+// parseDob
+// Convert the date of birth sent by clients, an empty value leaves it unset
+func parseDob(dob string) (pgtype.Timestamptz, error) {
+	if dob == "" {
+		return pgtype.Timestamptz{}, nil
+	}
+	parsed, err := time.Parse(dobLayout, dob)
+	if err != nil {
+		return pgtype.Timestamptz{}, user.ErrInvalidDob
+	}
+	return pgtype.Timestamptz{Time: parsed, Valid: true}, nil
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/admin"
+	"github.com/daniel-vuky/go-blog/internal/service/admin"
+	"github.com/daniel-vuky/go-blog/pkg/token"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	authorizationHeaderKey  = "authorization"
+	authorizationTypeBearer = "bearer"
+	AuthorizationPayloadKey = "authorization_payload"
+	AuthorizedAdminKey      = "authorized_admin"
+)
+
+// verifyBearerToken
+// Extract the bearer token from the request and verify it
+// @param ctx *gin.Context
+// @param tokenMaker token.Maker
+// @return *token.Payload, error
+func verifyBearerToken(ctx *gin.Context, tokenMaker token.Maker) (*token.Payload, error) {
+	authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
+	if authorizationHeader == "" {
+		return nil, errors.New("authorization header is not provided")
+	}
+	fields := strings.Fields(authorizationHeader)
+	if len(fields) != 2 {
+		return nil, errors.New("invalid authorization header format")
+	}
+	if strings.ToLower(fields[0]) != authorizationTypeBearer {
+		return nil, errors.New("unsupported authorization type")
+	}
+	return tokenMaker.VerifyToken(fields[1])
+}
+
+// AdminAuth
+// Authenticate the admin sending the request with a bearer access token
+// @param tokenMaker token.Maker
+// @param adminService *admin.Service
+// @return gin.HandlerFunc
+func AdminAuth(tokenMaker token.Maker, adminService *admin.Service) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload, err := verifyBearerToken(ctx, tokenMaker)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if payload.Audience != token.AudienceAdminAccess {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": token.ErrInvalidToken.Error()})
+			return
+		}
+		authorizedAdmin, err := adminService.GetAdminByID(ctx, payload.AdminID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "admin not found"})
+				return
+			}
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if payload.IssuedAt.Before(authorizedAdmin.PasswordChangedAt) {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token was issued before the last password change"})
+			return
+		}
+		isActive, err := adminService.IsAdminActive(ctx, authorizedAdmin.Email)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if !isActive {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin account is inactive or locked"})
+			return
+		}
+
+		ctx.Set(AuthorizationPayloadKey, payload)
+		ctx.Set(AuthorizedAdminKey, authorizedAdmin)
+		ctx.Next()
+	}
+}
+
+// GetAuthorizedAdmin
+// Return the admin injected by AdminAuth
+// @param ctx *gin.Context
+// @return model.Admin, bool
+func GetAuthorizedAdmin(ctx *gin.Context) (model.Admin, bool) {
+	value, exists := ctx.Get(AuthorizedAdminKey)
+	if !exists {
+		return model.Admin{}, false
+	}
+	authorizedAdmin, ok := value.(model.Admin)
+	return authorizedAdmin, ok
+}
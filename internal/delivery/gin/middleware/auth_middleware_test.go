@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/admin"
+	"github.com/daniel-vuky/go-blog/internal/service/admin"
+	"github.com/daniel-vuky/go-blog/pkg/config"
+	"github.com/daniel-vuky/go-blog/pkg/password"
+	"github.com/daniel-vuky/go-blog/pkg/token"
+	goRandom "github.com/daniel-vuky/go-random"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryAdminRepository
+// In-memory admin repository used to exercise middlewares without a database
+type memoryAdminRepository struct {
+	admins map[int32]model.Admin
+}
+
+func (repo *memoryAdminRepository) Get(_ context.Context, email string) (model.Admin, error) {
+	for _, a := range repo.admins {
+		if a.Email == email {
+			return a, nil
+		}
+	}
+	return model.Admin{}, pgx.ErrNoRows
+}
+
+func (repo *memoryAdminRepository) GetByID(_ context.Context, adminID int32) (model.Admin, error) {
+	a, ok := repo.admins[adminID]
+	if !ok {
+		return model.Admin{}, pgx.ErrNoRows
+	}
+	return a, nil
+}
+
+func (repo *memoryAdminRepository) GetList(context.Context, *model.GetListAdminParams) ([]model.Admin, int64, error) {
+	return nil, 0, nil
+}
+
+func (repo *memoryAdminRepository) Create(context.Context, *model.CreateAdminParams) (model.Admin, error) {
+	return model.Admin{}, nil
+}
+
+func (repo *memoryAdminRepository) CreateFirst(context.Context, *model.CreateAdminParams) (model.Admin, error) {
+	return model.Admin{}, nil
+}
+
+func (repo *memoryAdminRepository) Delete(context.Context, string) (model.Admin, error) {
+	return model.Admin{}, nil
+}
+
+func (repo *memoryAdminRepository) Update(context.Context, *model.UpdateAdminParams) (model.Admin, error) {
+	return model.Admin{}, nil
+}
+
+func (repo *memoryAdminRepository) Rehash(context.Context, string, string) error {
+	return nil
+}
+
+// setupAuthRouter
+// Create a router with one route protected by AdminAuth
+func setupAuthRouter(t *testing.T, admins ...model.Admin) (*gin.Engine, token.Maker) {
+	gin.SetMode(gin.TestMode)
+	tokenMaker, err := token.NewJWTMaker(goRandom.RandomString(32))
+	require.NoError(t, err)
+	hasher, err := password.NewHasher(&config.Password{Algorithm: password.AlgorithmBcrypt, BcryptCost: 4})
+	require.NoError(t, err)
+	repo := &memoryAdminRepository{admins: map[int32]model.Admin{}}
+	for _, a := range admins {
+		repo.admins[a.AdminID] = a
+	}
+
+	router := gin.New()
+	router.GET("/protected", AdminAuth(tokenMaker, admin.NewService(repo, hasher, nil)), func(ctx *gin.Context) {
+		authorizedAdmin, ok := GetAuthorizedAdmin(ctx)
+		require.True(t, ok)
+		ctx.JSON(http.StatusOK, authorizedAdmin)
+	})
+	return router, tokenMaker
+}
+
+// newTestAdmin
+// Create an active admin with the given id
+func newTestAdmin(adminID int32) model.Admin {
+	return model.Admin{
+		AdminID:           adminID,
+		RoleID:            1,
+		Email:             goRandom.RandomEmail(),
+		Active:            pgtype.Bool{Bool: true, Valid: true},
+		PasswordChangedAt: time.Now().Add(-time.Hour),
+	}
+}
+
+// createBearer
+// Create an authorization header value for the admin
+func createBearer(t *testing.T, tokenMaker token.Maker, adminID int32, duration time.Duration) string {
+	payload, err := token.NewAdminPayload(adminID, 1, duration)
+	require.NoError(t, err)
+	accessToken, err := tokenMaker.CreateToken(payload)
+	require.NoError(t, err)
+	return fmt.Sprintf("Bearer %s", accessToken)
+}
+
+// TestAdminAuth test the authentication middleware
+func TestAdminAuth(t *testing.T) {
+	activeAdmin := newTestAdmin(1)
+	inactiveAdmin := newTestAdmin(2)
+	inactiveAdmin.Active = pgtype.Bool{Bool: false, Valid: true}
+	lockedAdmin := newTestAdmin(3)
+	lockedAdmin.LockExpires = pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true}
+	changedAdmin := newTestAdmin(4)
+	changedAdmin.PasswordChangedAt = time.Now().Add(time.Minute)
+
+	router, tokenMaker := setupAuthRouter(t, activeAdmin, inactiveAdmin, lockedAdmin, changedAdmin)
+
+	testCases := []struct {
+		name          string
+		authorization string
+		expectedCode  int
+	}{
+		{"OK", createBearer(t, tokenMaker, activeAdmin.AdminID, time.Minute), http.StatusOK},
+		{"NoAuthorization", "", http.StatusUnauthorized},
+		{"UnsupportedType", "Basic abc", http.StatusUnauthorized},
+		{"InvalidFormat", "Bearer", http.StatusUnauthorized},
+		{"ExpiredToken", createBearer(t, tokenMaker, activeAdmin.AdminID, -time.Minute), http.StatusUnauthorized},
+		{"UnknownAdmin", createBearer(t, tokenMaker, 99, time.Minute), http.StatusUnauthorized},
+		{"InactiveAdmin", createBearer(t, tokenMaker, inactiveAdmin.AdminID, time.Minute), http.StatusForbidden},
+		{"LockedAdmin", createBearer(t, tokenMaker, lockedAdmin.AdminID, time.Minute), http.StatusForbidden},
+		{"PasswordChanged", createBearer(t, tokenMaker, changedAdmin.AdminID, time.Minute), http.StatusUnauthorized},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/protected", nil)
+			if tc.authorization != "" {
+				request.Header.Set(authorizationHeaderKey, tc.authorization)
+			}
+			router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.expectedCode, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/daniel-vuky/go-blog/internal/service/authorization"
+	"github.com/gin-gonic/gin"
+)
+
+// PermissionChecker
+// Builds middlewares restricting routes to roles granted a permission
+type PermissionChecker struct {
+	service *authorization.Service
+}
+
+// NewPermissionChecker
+// Create new permission checker
+// @param s *authorization.Service
+// @return *PermissionChecker
+func NewPermissionChecker(s *authorization.Service) *PermissionChecker {
+	return &PermissionChecker{service: s}
+}
+
+// RequirePermission
+// Reject the request unless the role of the authenticated admin is granted the permission.
+// Must run after AdminAuth.
+// @param permissionCode string
+// @return gin.HandlerFunc
+func (p *PermissionChecker) RequirePermission(permissionCode string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authorizedAdmin, ok := GetAuthorizedAdmin(ctx)
+		if !ok {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "admin is not authenticated"})
+			return
+		}
+		isAllowed, err := p.service.IsAllowed(ctx, authorizedAdmin.RoleID, permissionCode)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if !isAllowed {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "permission denied: " + permissionCode})
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/daniel-vuky/go-blog/internal/service/twofactor"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireTwoFactor
+// Reject the request while the role of the authenticated admin requires two-factor
+// authentication and the admin did not enable it yet. Must run after AdminAuth.
+// @param twoFactorService *twofactor.Service
+// @return gin.HandlerFunc
+func RequireTwoFactor(twoFactorService *twofactor.Service) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authorizedAdmin, ok := GetAuthorizedAdmin(ctx)
+		if !ok {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "admin is not authenticated"})
+			return
+		}
+		isRequired, err := twoFactorService.IsRequired(ctx, authorizedAdmin.RoleID)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if !isRequired {
+			ctx.Next()
+			return
+		}
+		isEnabled, err := twoFactorService.IsEnabled(ctx, authorizedAdmin.AdminID)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if !isEnabled {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": twofactor.ErrTwoFactorRequired.Error()})
+			return
+		}
+		ctx.Next()
+	}
+}
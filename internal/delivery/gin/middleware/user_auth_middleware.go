@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/user"
+	"github.com/daniel-vuky/go-blog/internal/service/user"
+	"github.com/daniel-vuky/go-blog/pkg/token"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+const AuthorizedUserKey = "authorized_user"
+
+// UserAuth
+// Authenticate the site user sending the request with a bearer access token
+// @param tokenMaker token.Maker
+// @param userService *user.Service
+// @return gin.HandlerFunc
+func UserAuth(tokenMaker token.Maker, userService *user.Service) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload, err := verifyBearerToken(ctx, tokenMaker)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if payload.Audience != token.AudienceUserAccess {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": token.ErrInvalidToken.Error()})
+			return
+		}
+		authorizedUser, err := userService.GetUserByID(ctx, payload.UserID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+				return
+			}
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if payload.IssuedAt.Before(authorizedUser.PasswordChangedAt) {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token was issued before the last password change"})
+			return
+		}
+		if !authorizedUser.Active {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": user.ErrUserDisabled.Error()})
+			return
+		}
+
+		ctx.Set(AuthorizationPayloadKey, payload)
+		ctx.Set(AuthorizedUserKey, authorizedUser)
+		ctx.Next()
+	}
+}
+
+// GetAuthorizedUser
+// Return the site user injected by UserAuth
+// @param ctx *gin.Context
+// @return model.User, bool
+func GetAuthorizedUser(ctx *gin.Context) (model.User, bool) {
+	value, exists := ctx.Get(AuthorizedUserKey)
+	if !exists {
+		return model.User{}, false
+	}
+	authorizedUser, ok := value.(model.User)
+	return authorizedUser, ok
+}
+
+// GetAuthorizedUserID
+// Return the id of the site user authenticated by UserAuth
+// @param ctx *gin.Context
+// @return int64, bool
+func GetAuthorizedUserID(ctx *gin.Context) (int64, bool) {
+	authorizedUser, ok := GetAuthorizedUser(ctx)
+	if !ok {
+		return 0, false
+	}
+	return authorizedUser.UserID, true
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/user"
+	"github.com/daniel-vuky/go-blog/internal/service/user"
+	"github.com/daniel-vuky/go-blog/pkg/token"
+	goRandom "github.com/daniel-vuky/go-random"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryUserRepository
+// In-memory user repository used to exercise middlewares without a database
+type memoryUserRepository struct {
+	users map[int64]model.User
+}
+
+func (repo *memoryUserRepository) Get(context.Context, string) (model.User, error) {
+	return model.User{}, pgx.ErrNoRows
+}
+
+func (repo *memoryUserRepository) GetByID(_ context.Context, userID int64) (model.User, error) {
+	u, ok := repo.users[userID]
+	if !ok {
+		return model.User{}, pgx.ErrNoRows
+	}
+	return u, nil
+}
+
+func (repo *memoryUserRepository) GetList(context.Context, *model.GetListUserParams) ([]model.User, int64, error) {
+	return nil, 0, nil
+}
+
+func (repo *memoryUserRepository) Create(context.Context, *model.CreateUserParams) (model.User, error) {
+	return model.User{}, nil
+}
+
+func (repo *memoryUserRepository) Update(context.Context, *model.UpdateUserParams) (model.User, error) {
+	return model.User{}, nil
+}
+
+func (repo *memoryUserRepository) Rehash(context.Context, int64, string) error {
+	return nil
+}
+
+func (repo *memoryUserRepository) VerifyEmail(context.Context, int64) (model.User, error) {
+	return model.User{}, nil
+}
+
+// createUserBearer
+// Create an authorization header value for the site user
+func createUserBearer(t *testing.T, tokenMaker token.Maker, userID int64) string {
+	payload, err := token.NewUserPayload(userID, time.Minute)
+	require.NoError(t, err)
+	accessToken, err := tokenMaker.CreateToken(payload)
+	require.NoError(t, err)
+	return fmt.Sprintf("Bearer %s", accessToken)
+}
+
+// TestUserAuth test the site user authentication middleware
+func TestUserAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tokenMaker, err := token.NewJWTMaker(goRandom.RandomString(32))
+	require.NoError(t, err)
+	repo := &memoryUserRepository{users: map[int64]model.User{
+		1: {UserID: 1, Active: true, PasswordChangedAt: time.Now().Add(-time.Hour)},
+		2: {UserID: 2, Active: false},
+		3: {UserID: 3, Active: true, PasswordChangedAt: time.Now().Add(time.Minute)},
+	}}
+	router := gin.New()
+	router.GET("/protected", UserAuth(tokenMaker, user.NewService(repo, nil, nil, nil)), func(ctx *gin.Context) {
+		userID, ok := GetAuthorizedUserID(ctx)
+		require.True(t, ok)
+		ctx.JSON(http.StatusOK, gin.H{"user_id": userID})
+	})
+
+	testCases := []struct {
+		name          string
+		authorization string
+		expectedCode  int
+	}{
+		{"OK", createUserBearer(t, tokenMaker, 1), http.StatusOK},
+		{"AdminToken", createBearer(t, tokenMaker, 1, time.Minute), http.StatusUnauthorized},
+		{"UnknownUser", createUserBearer(t, tokenMaker, 99), http.StatusUnauthorized},
+		{"DisabledUser", createUserBearer(t, tokenMaker, 2), http.StatusForbidden},
+		{"PasswordChanged", createUserBearer(t, tokenMaker, 3), http.StatusUnauthorized},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/protected", nil)
+			request.Header.Set(authorizationHeaderKey, tc.authorization)
+			router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.expectedCode, recorder.Code)
+		})
+	}
+}
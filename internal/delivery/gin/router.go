@@ -1,27 +1,437 @@
 package gin
 
+import (
+	"github.com/daniel-vuky/go-blog/internal/service/authorization"
+)
+
 // loadRoutes
 // Load all routes of application
 func (s *Server) loadRoutes() {
-	LoadDefaultAdminRoutes(s)
+	LoadSetupRoutes(s)
 	LoadAdminRoutes(s)
+	LoadAuthorizationRoutes(s)
+	LoadSessionRoutes(s)
+	LoadUserRoutes(s)
+	LoadAccountRoutes(s)
+	LoadTwoFactorRoutes(s)
+	LoadPostRoutes(s)
+	LoadCategoryRoutes(s)
+	LoadLinksRoutes(s)
+	LoadBlogRoutes(s)
+	LoadSearchRoutes(s)
+	LoadCommentRoutes(s)
+	LoadMediaRoutes(s)
+	LoadUrlRewriteRoutes(s)
 }
 
-// LoadDefaultAdminRoutes
-// Provide the way to create default super admin
-func LoadDefaultAdminRoutes(s *Server) {
-	s.router.POST("/default_admin", s.handler.adminHandler.CreateAdmin)
+// LoadSetupRoutes
+// Provide the way to create the first super admin, only usable while no admin exists
+func LoadSetupRoutes(s *Server) {
+	s.router.POST("/setup/admin", s.handler.adminHandler.BootstrapAdmin)
 }
 
 // LoadAdminRoutes
 // Load all admin routes
 func LoadAdminRoutes(s *Server) {
-	adminGroup := s.router.Group("/admin")
+	s.router.POST("/admin/login", s.handler.adminHandler.Login)
+	s.router.POST("/admin/login/2fa", s.handler.adminHandler.LoginTwoFactor)
+
+	adminGroup := s.router.Group("/admin", s.middleware.adminAuth, s.middleware.twoFactor)
+	{
+		adminGroup.GET("/me", s.handler.adminHandler.GetCurrentAdmin)
+		adminGroup.GET(
+			"/:email",
+			s.middleware.permission.RequirePermission(authorization.PermissionAdminView),
+			s.handler.adminHandler.GetAdmin,
+		)
+		adminGroup.GET(
+			"/",
+			s.middleware.permission.RequirePermission(authorization.PermissionAdminView),
+			s.handler.adminHandler.GetListAdmin,
+		)
+		adminGroup.POST(
+			"/",
+			s.middleware.permission.RequirePermission(authorization.PermissionAdminCreate),
+			s.handler.adminHandler.CreateAdmin,
+		)
+		adminGroup.PUT(
+			"/",
+			s.middleware.permission.RequirePermission(authorization.PermissionAdminUpdate),
+			s.handler.adminHandler.UpdateAdmin,
+		)
+		adminGroup.DELETE(
+			"/:email",
+			s.middleware.permission.RequirePermission(authorization.PermissionAdminDelete),
+			s.handler.adminHandler.DeleteAdmin,
+		)
+		adminGroup.POST(
+			"/:email/unlock",
+			s.middleware.permission.RequirePermission(authorization.PermissionAdminUpdate),
+			s.handler.adminHandler.UnlockAdmin,
+		)
+	}
+}
+
+// LoadAuthorizationRoutes
+// Load all role, rule and permission routes
+func LoadAuthorizationRoutes(s *Server) {
+	adminGroup := s.router.Group("/admin", s.middleware.adminAuth, s.middleware.twoFactor)
+	{
+		adminGroup.GET(
+			"/permissions",
+			s.middleware.permission.RequirePermission(authorization.PermissionRoleView),
+			s.handler.authorizationHandler.GetListPermissions,
+		)
+		adminGroup.GET(
+			"/roles",
+			s.middleware.permission.RequirePermission(authorization.PermissionRoleView),
+			s.handler.authorizationHandler.GetListRoles,
+		)
+		adminGroup.GET(
+			"/roles/:id",
+			s.middleware.permission.RequirePermission(authorization.PermissionRoleView),
+			s.handler.authorizationHandler.GetRole,
+		)
+		adminGroup.POST(
+			"/roles",
+			s.middleware.permission.RequirePermission(authorization.PermissionRoleManage),
+			s.handler.authorizationHandler.CreateRole,
+		)
+		adminGroup.PUT(
+			"/roles/:id",
+			s.middleware.permission.RequirePermission(authorization.PermissionRoleManage),
+			s.handler.authorizationHandler.UpdateRole,
+		)
+		adminGroup.DELETE(
+			"/roles/:id",
+			s.middleware.permission.RequirePermission(authorization.PermissionRoleManage),
+			s.handler.authorizationHandler.DeleteRole,
+		)
+		adminGroup.GET(
+			"/roles/:id/rules",
+			s.middleware.permission.RequirePermission(authorization.PermissionRoleView),
+			s.handler.authorizationHandler.GetRules,
+		)
+		adminGroup.PUT(
+			"/roles/:id/rules",
+			s.middleware.permission.RequirePermission(authorization.PermissionRoleManage),
+			s.handler.authorizationHandler.ReplaceRules,
+		)
+	}
+}
+
+// LoadSessionRoutes
+// Load the session routes of site users and the admin routes revoking them
+func LoadSessionRoutes(s *Server) {
+	authGroup := s.router.Group("/auth")
+	{
+		authGroup.POST("/refresh", s.handler.sessionHandler.Refresh)
+		authGroup.POST("/logout", s.handler.sessionHandler.Logout)
+		authGroup.GET("/sessions", s.middleware.userAuth, s.handler.sessionHandler.GetListSessions)
+	}
+
+	adminGroup := s.router.Group("/admin", s.middleware.adminAuth, s.middleware.twoFactor)
+	{
+		adminGroup.GET(
+			"/users/:id/sessions",
+			s.middleware.permission.RequirePermission(authorization.PermissionUserView),
+			s.handler.sessionHandler.GetListUserSessions,
+		)
+		adminGroup.DELETE(
+			"/users/:id/sessions",
+			s.middleware.permission.RequirePermission(authorization.PermissionUserManage),
+			s.handler.sessionHandler.RevokeUserSessions,
+		)
+	}
+}
+
+// LoadUserRoutes
+// Load the public site user routes and the admin routes managing site users
+func LoadUserRoutes(s *Server) {
+	userGroup := s.router.Group("/users")
+	{
+		userGroup.POST("/register", s.handler.userHandler.Register)
+		userGroup.POST("/login", s.handler.userHandler.Login)
+		userGroup.GET("/me", s.middleware.userAuth, s.handler.userHandler.GetCurrentUser)
+		userGroup.PUT("/me", s.middleware.userAuth, s.handler.userHandler.UpdateCurrentUser)
+	}
+
+	adminGroup := s.router.Group("/admin", s.middleware.adminAuth, s.middleware.twoFactor)
+	{
+		adminGroup.GET(
+			"/users",
+			s.middleware.permission.RequirePermission(authorization.PermissionUserView),
+			s.handler.userHandler.GetListUser,
+		)
+		adminGroup.GET(
+			"/users/:id",
+			s.middleware.permission.RequirePermission(authorization.PermissionUserView),
+			s.handler.userHandler.GetUser,
+		)
+		adminGroup.POST(
+			"/users/:id/disable",
+			s.middleware.permission.RequirePermission(authorization.PermissionUserManage),
+			s.handler.userHandler.DisableUser,
+		)
+		adminGroup.POST(
+			"/users/:id/enable",
+			s.middleware.permission.RequirePermission(authorization.PermissionUserManage),
+			s.handler.userHandler.EnableUser,
+		)
+	}
+}
+
+// LoadAccountRoutes
+// Load the password recovery and email verification routes
+func LoadAccountRoutes(s *Server) {
+	authGroup := s.router.Group("/auth")
+	{
+		authGroup.POST("/forgot-password", s.handler.accountHandler.ForgotPassword)
+		authGroup.POST("/reset-password", s.handler.accountHandler.ResetPassword)
+		authGroup.POST("/verify-email", s.handler.accountHandler.VerifyEmail)
+	}
+
+	s.router.POST("/users/me/verify-email", s.middleware.userAuth, s.handler.accountHandler.ResendEmailVerification)
+}
+
+// LoadTwoFactorRoutes
+// Load the two-factor enrollment routes, which stay reachable before the enrollment
+// required by the role of the admin is completed
+func LoadTwoFactorRoutes(s *Server) {
+	enrollmentGroup := s.router.Group("/admin/me/2fa", s.middleware.adminAuth)
+	{
+		enrollmentGroup.GET("", s.handler.twoFactorHandler.GetStatus)
+		enrollmentGroup.POST("/setup", s.handler.twoFactorHandler.Setup)
+		enrollmentGroup.POST("/enable", s.handler.twoFactorHandler.Enable)
+		enrollmentGroup.POST("/disable", s.handler.twoFactorHandler.Disable)
+		enrollmentGroup.POST("/recovery-codes", s.handler.twoFactorHandler.RegenerateRecoveryCodes)
+	}
+
+	adminGroup := s.router.Group("/admin", s.middleware.adminAuth, s.middleware.twoFactor)
+	{
+		adminGroup.DELETE(
+			"/:email/2fa",
+			s.middleware.permission.RequirePermission(authorization.PermissionAdminUpdate),
+			s.handler.twoFactorHandler.ResetAdmin,
+		)
+	}
+}
+
+// LoadPostRoutes
+// Load the admin routes managing posts
+func LoadPostRoutes(s *Server) {
+	adminGroup := s.router.Group("/admin", s.middleware.adminAuth, s.middleware.twoFactor)
 	{
-		adminGroup.GET("/:email", s.handler.adminHandler.GetAdmin)
-		adminGroup.GET("/", s.handler.adminHandler.GetListAdmin)
-		adminGroup.POST("/", s.handler.adminHandler.CreateAdmin)
-		adminGroup.PUT("/", s.handler.adminHandler.UpdateAdmin)
-		adminGroup.DELETE("/:email", s.handler.adminHandler.DeleteAdmin)
+		adminGroup.GET(
+			"/posts",
+			s.middleware.permission.RequirePermission(authorization.PermissionPostView),
+			s.handler.postHandler.GetListPost,
+		)
+		adminGroup.GET(
+			"/posts/:id",
+			s.middleware.permission.RequirePermission(authorization.PermissionPostView),
+			s.handler.postHandler.GetPost,
+		)
+		adminGroup.POST(
+			"/posts",
+			s.middleware.permission.RequirePermission(authorization.PermissionPostCreate),
+			s.handler.postHandler.CreatePost,
+		)
+		adminGroup.POST(
+			"/posts/preview",
+			s.middleware.permission.RequirePermission(authorization.PermissionPostView),
+			s.handler.postHandler.PreviewPost,
+		)
+		adminGroup.PUT(
+			"/posts/:id",
+			s.middleware.permission.RequirePermission(authorization.PermissionPostUpdate),
+			s.handler.postHandler.UpdatePost,
+		)
+		adminGroup.GET(
+			"/posts/:id/revisions",
+			s.middleware.permission.RequirePermission(authorization.PermissionPostView),
+			s.handler.postHandler.GetListPostRevision,
+		)
+		adminGroup.GET(
+			"/posts/:id/revisions/diff",
+			s.middleware.permission.RequirePermission(authorization.PermissionPostView),
+			s.handler.postHandler.GetPostRevisionDiff,
+		)
+		adminGroup.POST(
+			"/posts/:id/revisions/:revision_id/restore",
+			s.middleware.permission.RequirePermission(authorization.PermissionPostUpdate),
+			s.handler.postHandler.RestorePostRevision,
+		)
+		adminGroup.PUT(
+			"/posts/:id/status",
+			s.middleware.permission.RequirePermission(authorization.PermissionPostPublish),
+			s.handler.postHandler.UpdatePostStatus,
+		)
+		adminGroup.DELETE(
+			"/posts/:id",
+			s.middleware.permission.RequirePermission(authorization.PermissionPostDelete),
+			s.handler.postHandler.DeletePost,
+		)
 	}
 }
+
+// LoadCategoryRoutes
+// Load the public category navigation routes and the admin routes managing categories
+func LoadCategoryRoutes(s *Server) {
+	categoryGroup := s.router.Group("/categories")
+	{
+		categoryGroup.GET("/tree", s.handler.categoryHandler.GetTree)
+		categoryGroup.GET("/:id/breadcrumbs", s.handler.categoryHandler.GetBreadcrumbs)
+	}
+
+	adminGroup := s.router.Group("/admin", s.middleware.adminAuth, s.middleware.twoFactor)
+	{
+		adminGroup.GET(
+			"/categories",
+			s.middleware.permission.RequirePermission(authorization.PermissionCategoryView),
+			s.handler.categoryHandler.GetListCategory,
+		)
+		adminGroup.GET(
+			"/categories/:id",
+			s.middleware.permission.RequirePermission(authorization.PermissionCategoryView),
+			s.handler.categoryHandler.GetCategory,
+		)
+		adminGroup.POST(
+			"/categories",
+			s.middleware.permission.RequirePermission(authorization.PermissionCategoryManage),
+			s.handler.categoryHandler.CreateCategory,
+		)
+		adminGroup.PUT(
+			"/categories/:id",
+			s.middleware.permission.RequirePermission(authorization.PermissionCategoryManage),
+			s.handler.categoryHandler.UpdateCategory,
+		)
+		adminGroup.POST(
+			"/categories/:id/move",
+			s.middleware.permission.RequirePermission(authorization.PermissionCategoryManage),
+			s.handler.categoryHandler.MoveCategory,
+		)
+		adminGroup.POST(
+			"/categories/:id/merge",
+			s.middleware.permission.RequirePermission(authorization.PermissionCategoryManage),
+			s.handler.categoryHandler.MergeCategory,
+		)
+		adminGroup.DELETE(
+			"/categories/:id",
+			s.middleware.permission.RequirePermission(authorization.PermissionCategoryManage),
+			s.handler.categoryHandler.DeleteCategory,
+		)
+	}
+}
+
+// LoadLinksRoutes
+// Load the routes assigning posts to categories and listing the posts of a category
+func LoadLinksRoutes(s *Server) {
+	s.router.GET("/categories/:id/posts", s.handler.linksHandler.GetListCategoryPost)
+
+	adminGroup := s.router.Group("/admin", s.middleware.adminAuth, s.middleware.twoFactor)
+	{
+		adminGroup.GET(
+			"/posts/:id/categories",
+			s.middleware.permission.RequirePermission(authorization.PermissionPostView),
+			s.handler.linksHandler.GetPostCategories,
+		)
+		adminGroup.PUT(
+			"/posts/:id/categories",
+			s.middleware.permission.RequirePermission(authorization.PermissionPostUpdate),
+			s.handler.linksHandler.SetPostCategories,
+		)
+		adminGroup.POST(
+			"/posts/:id/categories",
+			s.middleware.permission.RequirePermission(authorization.PermissionPostUpdate),
+			s.handler.linksHandler.AddPostCategories,
+		)
+		adminGroup.DELETE(
+			"/posts/:id/categories",
+			s.middleware.permission.RequirePermission(authorization.PermissionPostUpdate),
+			s.handler.linksHandler.RemovePostCategories,
+		)
+	}
+}
+
+// LoadBlogRoutes
+// Load the public read-only routes of the blog
+func LoadBlogRoutes(s *Server) {
+	blogGroup := s.router.Group("/blog")
+	{
+		blogGroup.GET("/posts", s.handler.blogHandler.GetListPost)
+		blogGroup.GET("/posts/:url_key", s.handler.blogHandler.GetPost)
+		blogGroup.GET("/posts/:url_key/comments", s.handler.blogHandler.GetListComment)
+		blogGroup.GET("/categories", s.handler.blogHandler.GetListCategory)
+		blogGroup.GET("/categories/:url_key", s.handler.blogHandler.GetCategory)
+		blogGroup.GET("/categories/:url_key/posts", s.handler.blogHandler.GetListCategoryPost)
+	}
+}
+
+// LoadSearchRoutes
+// Load the public full-text search of the published posts
+func LoadSearchRoutes(s *Server) {
+	s.router.GET("/search", s.handler.searchHandler.SearchPost)
+}
+
+// LoadCommentRoutes
+// Load the routes reading the comment threads, letting site users comment the posts
+// and letting admins moderate the comments
+func LoadCommentRoutes(s *Server) {
+	s.router.GET("/posts/:id/comments", s.handler.commentHandler.GetCommentTree)
+	s.router.POST("/posts/:id/comments", s.middleware.userAuth, s.handler.commentHandler.CreateComment)
+	s.router.GET("/comments/:id/replies", s.handler.commentHandler.GetReplyTree)
+	s.router.PUT("/comments/:id", s.middleware.userAuth, s.handler.commentHandler.UpdateComment)
+
+	adminGroup := s.router.Group("/admin", s.middleware.adminAuth, s.middleware.twoFactor)
+	{
+		adminGroup.GET(
+			"/comments",
+			s.middleware.permission.RequirePermission(authorization.PermissionCommentView),
+			s.handler.commentHandler.GetListComment,
+		)
+		adminGroup.POST(
+			"/comments/moderate",
+			s.middleware.permission.RequirePermission(authorization.PermissionCommentModerate),
+			s.handler.commentHandler.ModerateComments,
+		)
+	}
+}
+
+// LoadMediaRoutes
+// Load the routes managing the media library and serving the stored files
+func LoadMediaRoutes(s *Server) {
+	s.router.GET("/media/*key", s.handler.mediaHandler.ServeFile)
+
+	adminGroup := s.router.Group("/admin", s.middleware.adminAuth, s.middleware.twoFactor)
+	{
+		adminGroup.GET(
+			"/media",
+			s.middleware.permission.RequirePermission(authorization.PermissionMediaView),
+			s.handler.mediaHandler.GetListMedia,
+		)
+		adminGroup.GET(
+			"/media/:id",
+			s.middleware.permission.RequirePermission(authorization.PermissionMediaView),
+			s.handler.mediaHandler.GetMedia,
+		)
+		adminGroup.POST(
+			"/media",
+			s.middleware.permission.RequirePermission(authorization.PermissionMediaManage),
+			s.handler.mediaHandler.UploadMedia,
+		)
+		adminGroup.DELETE(
+			"/media/:id",
+			s.middleware.permission.RequirePermission(authorization.PermissionMediaManage),
+			s.handler.mediaHandler.DeleteMedia,
+		)
+	}
+}
+
+// LoadUrlRewriteRoutes
+// Load the resolver of the public paths, also answering every path no other route matched.
+// The first segment of a new route must be added to the reserved url keys of the urlrewrite service.
+func LoadUrlRewriteRoutes(s *Server) {
+	s.router.GET("/resolve", s.handler.urlRewriteHandler.Resolve)
+	s.router.NoRoute(s.handler.urlRewriteHandler.ResolveRoute)
+}
@@ -0,0 +1,28 @@
+package gin
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/daniel-vuky/go-blog/internal/delivery/gin/middleware"
+	"github.com/daniel-vuky/go-blog/internal/service/urlrewrite"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRouter_ReservedUrlKeys test the first segment of every route is a reserved url key,
+// otherwise a post or a category could take a path the resolver never receives
+func TestRouter_ReservedUrlKeys(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := &Server{
+		router:     gin.New(),
+		handler:    &handlers{},
+		middleware: &middlewares{permission: &middleware.PermissionChecker{}},
+	}
+	s.loadRoutes()
+
+	for _, route := range s.router.Routes() {
+		segment, _, _ := strings.Cut(strings.TrimPrefix(route.Path, "/"), "/")
+		require.True(t, urlrewrite.IsReservedUrlKey(segment), "%s %s", route.Method, route.Path)
+	}
+}
@@ -3,10 +3,58 @@ package gin
 import (
 	"context"
 	"fmt"
+	accountHandler "github.com/daniel-vuky/go-blog/internal/delivery/gin/handler/account"
 	adminHandler "github.com/daniel-vuky/go-blog/internal/delivery/gin/handler/admin"
+	authorizationHandler "github.com/daniel-vuky/go-blog/internal/delivery/gin/handler/authorization"
+	blogHandler "github.com/daniel-vuky/go-blog/internal/delivery/gin/handler/blog"
+	categoryHandler "github.com/daniel-vuky/go-blog/internal/delivery/gin/handler/category"
+	commentHandler "github.com/daniel-vuky/go-blog/internal/delivery/gin/handler/comment"
+	linksHandler "github.com/daniel-vuky/go-blog/internal/delivery/gin/handler/links"
+	mediaHandler "github.com/daniel-vuky/go-blog/internal/delivery/gin/handler/media"
+	postHandler "github.com/daniel-vuky/go-blog/internal/delivery/gin/handler/post"
+	searchHandler "github.com/daniel-vuky/go-blog/internal/delivery/gin/handler/search"
+	sessionHandler "github.com/daniel-vuky/go-blog/internal/delivery/gin/handler/session"
+	twoFactorHandler "github.com/daniel-vuky/go-blog/internal/delivery/gin/handler/twofactor"
+	urlRewriteHandler "github.com/daniel-vuky/go-blog/internal/delivery/gin/handler/urlrewrite"
+	userHandler "github.com/daniel-vuky/go-blog/internal/delivery/gin/handler/user"
+	"github.com/daniel-vuky/go-blog/internal/delivery/gin/middleware"
+	accountService "github.com/daniel-vuky/go-blog/internal/service/account"
 	adminService "github.com/daniel-vuky/go-blog/internal/service/admin"
+	auditService "github.com/daniel-vuky/go-blog/internal/service/audit"
+	authorizationService "github.com/daniel-vuky/go-blog/internal/service/authorization"
+	blogService "github.com/daniel-vuky/go-blog/internal/service/blog"
+	categoryService "github.com/daniel-vuky/go-blog/internal/service/category"
+	commentService "github.com/daniel-vuky/go-blog/internal/service/comment"
+	linksService "github.com/daniel-vuky/go-blog/internal/service/links"
+	lockoutService "github.com/daniel-vuky/go-blog/internal/service/lockout"
+	mediaService "github.com/daniel-vuky/go-blog/internal/service/media"
+	postService "github.com/daniel-vuky/go-blog/internal/service/post"
+	searchService "github.com/daniel-vuky/go-blog/internal/service/search"
+	sessionService "github.com/daniel-vuky/go-blog/internal/service/session"
+	twoFactorService "github.com/daniel-vuky/go-blog/internal/service/twofactor"
+	urlRewriteService "github.com/daniel-vuky/go-blog/internal/service/urlrewrite"
+	userService "github.com/daniel-vuky/go-blog/internal/service/user"
+	accountStorage "github.com/daniel-vuky/go-blog/internal/storage/account"
 	adminStorage "github.com/daniel-vuky/go-blog/internal/storage/admin"
+	auditStorage "github.com/daniel-vuky/go-blog/internal/storage/audit"
+	authorizationStorage "github.com/daniel-vuky/go-blog/internal/storage/authorization"
+	blogStorage "github.com/daniel-vuky/go-blog/internal/storage/blog"
+	categoryStorage "github.com/daniel-vuky/go-blog/internal/storage/category"
+	commentStorage "github.com/daniel-vuky/go-blog/internal/storage/comment"
+	linksStorage "github.com/daniel-vuky/go-blog/internal/storage/links"
+	lockoutStorage "github.com/daniel-vuky/go-blog/internal/storage/lockout"
+	mediaStorage "github.com/daniel-vuky/go-blog/internal/storage/media"
+	postStorage "github.com/daniel-vuky/go-blog/internal/storage/post"
+	searchStorage "github.com/daniel-vuky/go-blog/internal/storage/search"
+	sessionStorage "github.com/daniel-vuky/go-blog/internal/storage/session"
+	twoFactorStorage "github.com/daniel-vuky/go-blog/internal/storage/twofactor"
+	urlRewriteStorage "github.com/daniel-vuky/go-blog/internal/storage/urlrewrite"
+	userStorage "github.com/daniel-vuky/go-blog/internal/storage/user"
 	"github.com/daniel-vuky/go-blog/pkg/config"
+	"github.com/daniel-vuky/go-blog/pkg/filestore"
+	"github.com/daniel-vuky/go-blog/pkg/mail"
+	"github.com/daniel-vuky/go-blog/pkg/password"
+	"github.com/daniel-vuky/go-blog/pkg/token"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/sync/errgroup"
 	"net/http"
@@ -15,15 +63,39 @@ import (
 // service
 // Struct to hold all application services
 type handlers struct {
-	adminHandler *adminHandler.Handler
+	accountHandler       *accountHandler.Handler
+	adminHandler         *adminHandler.Handler
+	authorizationHandler *authorizationHandler.Handler
+	blogHandler          *blogHandler.Handler
+	categoryHandler      *categoryHandler.Handler
+	commentHandler       *commentHandler.Handler
+	linksHandler         *linksHandler.Handler
+	mediaHandler         *mediaHandler.Handler
+	postHandler          *postHandler.Handler
+	searchHandler        *searchHandler.Handler
+	sessionHandler       *sessionHandler.Handler
+	twoFactorHandler     *twoFactorHandler.Handler
+	urlRewriteHandler    *urlRewriteHandler.Handler
+	userHandler          *userHandler.Handler
+}
+
+// middlewares
+// Struct to hold all application middlewares
+type middlewares struct {
+	adminAuth  gin.HandlerFunc
+	twoFactor  gin.HandlerFunc
+	userAuth   gin.HandlerFunc
+	permission *middleware.PermissionChecker
 }
 
 // Server
 // Struct to hold all server configuration
 type Server struct {
-	config  *config.Config
-	router  *gin.Engine
-	handler *handlers
+	config     *config.Config
+	router     *gin.Engine
+	handler    *handlers
+	middleware *middlewares
+	scheduler  *postService.Service
 }
 
 // NewServer
@@ -38,23 +110,130 @@ func NewServer() (*Server, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create connection pool: %w", err)
 	}
+	passwordHasher, err := password.NewHasher(loadedConfig.Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create password hasher: %w", err)
+	}
+	tokenMaker, err := token.NewMaker(loadedConfig.Token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token maker: %w", err)
+	}
+	mailer, err := mail.NewMailer(loadedConfig.Mail)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mailer: %w", err)
+	}
+	fileStorage, err := filestore.NewStorage(loadedConfig.Media)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create media storage: %w", err)
+	}
+	auditSvc := auditService.NewService(auditStorage.NewAuditRepository(connPool))
+	lockoutSvc := lockoutService.NewService(
+		lockoutStorage.NewLockoutRepository(connPool),
+		auditSvc,
+		loadedConfig.Lockout,
+	)
+	adminSvc := adminService.NewService(
+		adminStorage.NewAdminRepository(connPool),
+		passwordHasher,
+		lockoutSvc,
+	)
+	authorizationSvc := authorizationService.NewService(
+		authorizationStorage.NewAuthorizationRepository(connPool),
+	)
+	twoFactorSvc, err := twoFactorService.NewService(
+		twoFactorStorage.NewTwoFactorRepository(connPool),
+		authorizationSvc,
+		lockoutSvc,
+		loadedConfig.TwoFactor,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create two-factor service: %w", err)
+	}
+	sessionSvc := sessionService.NewService(
+		sessionStorage.NewSessionRepository(connPool),
+		tokenMaker,
+		loadedConfig.Token,
+	)
+	userSvc := userService.NewService(
+		userStorage.NewUserRepository(connPool),
+		passwordHasher,
+		lockoutSvc,
+		sessionSvc,
+	)
+	mediaSvc, err := mediaService.NewService(mediaStorage.NewMediaRepository(connPool), fileStorage, loadedConfig.Media)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create media service: %w", err)
+	}
+	blogSvc := blogService.NewService(blogStorage.NewBlogRepository(connPool), mediaSvc)
+	urlRewriteSvc := urlRewriteService.NewService(urlRewriteStorage.NewUrlRewriteRepository(connPool), blogSvc)
+	postSvc := postService.NewService(postStorage.NewPostRepository(connPool), urlRewriteSvc)
+	categorySvc := categoryService.NewService(categoryStorage.NewCategoryRepository(connPool), urlRewriteSvc)
+	searchSvc := searchService.NewService(searchStorage.NewSearchRepository(connPool), loadedConfig.Search)
+	if err = searchSvc.SyncLanguage(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to set the search language: %w", err)
+	}
+	commentSvc := commentService.NewService(commentStorage.NewCommentRepository(connPool), postSvc)
+	linksSvc := linksService.NewService(linksStorage.NewLinksRepository(connPool), postSvc, categorySvc, blogSvc)
+	accountSvc, err := accountService.NewService(
+		accountStorage.NewAccountTokenRepository(connPool),
+		adminSvc,
+		userSvc,
+		mailer,
+		loadedConfig.AccountToken,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create account service: %w", err)
+	}
 	listHandlers := &handlers{
-		adminHandler: adminHandler.NewHandler(
-			adminService.NewService(
-				adminStorage.NewAdminRepository(connPool),
-			),
-		),
+		accountHandler:       accountHandler.NewHandler(accountSvc),
+		adminHandler:         adminHandler.NewHandler(adminSvc, twoFactorSvc, tokenMaker, loadedConfig),
+		authorizationHandler: authorizationHandler.NewHandler(authorizationSvc),
+		blogHandler:          blogHandler.NewHandler(blogSvc),
+		categoryHandler:      categoryHandler.NewHandler(categorySvc),
+		commentHandler:       commentHandler.NewHandler(commentSvc),
+		linksHandler:         linksHandler.NewHandler(linksSvc),
+		mediaHandler:         mediaHandler.NewHandler(mediaSvc),
+		postHandler:          postHandler.NewHandler(postSvc),
+		searchHandler:        searchHandler.NewHandler(searchSvc),
+		sessionHandler:       sessionHandler.NewHandler(sessionSvc),
+		twoFactorHandler:     twoFactorHandler.NewHandler(twoFactorSvc, adminSvc),
+		urlRewriteHandler:    urlRewriteHandler.NewHandler(urlRewriteSvc),
+		userHandler:          userHandler.NewHandler(userSvc, sessionSvc, accountSvc),
+	}
+	listMiddlewares := &middlewares{
+		adminAuth:  middleware.AdminAuth(tokenMaker, adminSvc),
+		twoFactor:  middleware.RequireTwoFactor(twoFactorSvc),
+		userAuth:   middleware.UserAuth(tokenMaker, userSvc),
+		permission: middleware.NewPermissionChecker(authorizationSvc),
+	}
+	router, err := newRouter(loadedConfig.Server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create router: %w", err)
 	}
 	newServer := &Server{
-		config:  loadedConfig,
-		router:  gin.Default(),
-		handler: listHandlers,
+		config:     loadedConfig,
+		router:     router,
+		handler:    listHandlers,
+		middleware: listMiddlewares,
+		scheduler:  postSvc,
 	}
 	newServer.loadRoutes()
 
 	return newServer, nil
 }
 
+// newRouter
+// Create the gin engine, reading the client ip from forwarded headers only behind the trusted proxies
+// @param cfg *config.Server
+// @return *gin.Engine, error
+func newRouter(cfg *config.Server) (*gin.Engine, error) {
+	router := gin.Default()
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return nil, err
+	}
+	return router, nil
+}
+
 // Start
 // Starting the server with graceful shutdown
 // @param ctx context.Context
@@ -74,5 +253,8 @@ func (s *Server) Start(ctx context.Context, waitGroup *errgroup.Group) error {
 		err := server.Shutdown(ctx)
 		return err
 	})
+	waitGroup.Go(func() error {
+		return s.scheduler.RunScheduler(ctx, s.config.Publishing.SchedulerInterval)
+	})
 	return nil
 }
@@ -0,0 +1,56 @@
+package gin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/daniel-vuky/go-blog/pkg/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// countFailedLogins
+// Sends one request per forwarded ip from remoteAddr and returns the failed logins counted per client ip,
+// the way the lockout counts them
+func countFailedLogins(t *testing.T, cfg *config.Server, remoteAddr string, forwardedIps ...string) map[string]int {
+	gin.SetMode(gin.TestMode)
+	router, err := newRouter(cfg)
+	require.NoError(t, err)
+
+	failedLogins := map[string]int{}
+	router.POST("/auth/login", func(c *gin.Context) {
+		failedLogins[c.ClientIP()]++
+		c.Status(http.StatusUnauthorized)
+	})
+	for _, forwardedIp := range forwardedIps {
+		req := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", forwardedIp)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	return failedLogins
+}
+
+// TestNewRouter_ForgedForwardedFor test a forged X-Forwarded-For header does not start a new client ip counter
+func TestNewRouter_ForgedForwardedFor(t *testing.T) {
+	failedLogins := countFailedLogins(t, &config.Server{}, "192.0.2.1:4000", "198.51.100.1", "198.51.100.2", "198.51.100.3")
+	require.Equal(t, map[string]int{"192.0.2.1": 3}, failedLogins)
+}
+
+// TestNewRouter_TrustedProxy test the forwarded client ip is used behind a trusted proxy
+func TestNewRouter_TrustedProxy(t *testing.T) {
+	failedLogins := countFailedLogins(
+		t,
+		&config.Server{TrustedProxies: []string{"192.0.2.0/24"}},
+		"192.0.2.1:4000",
+		"198.51.100.1", "198.51.100.1", "198.51.100.2",
+	)
+	require.Equal(t, map[string]int{"198.51.100.1": 2, "198.51.100.2": 1}, failedLogins)
+}
+
+// TestNewRouter_InvalidTrustedProxy test an invalid trusted proxy is rejected
+func TestNewRouter_InvalidTrustedProxy(t *testing.T) {
+	_, err := newRouter(&config.Server{TrustedProxies: []string{"not-an-ip"}})
+	require.Error(t, err)
+}
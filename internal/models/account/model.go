@@ -0,0 +1,44 @@
+package account
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// AccountType
+// Kind of account a token belongs to
+type AccountType string
+
+const (
+	AccountTypeAdmin AccountType = "1"
+	AccountTypeUser  AccountType = "2"
+)
+
+// TokenPurpose
+// What an account token can be exchanged for
+type TokenPurpose string
+
+const (
+	TokenPurposePasswordReset     TokenPurpose = "1"
+	TokenPurposeEmailVerification TokenPurpose = "2"
+)
+
+type AccountToken struct {
+	TokenID     int64              `json:"token_id"`
+	AccountType AccountType        `json:"account_type"`
+	AccountID   int64              `json:"account_id"`
+	Purpose     TokenPurpose       `json:"purpose"`
+	TokenHash   string             `json:"-"`
+	ExpiredAt   time.Time          `json:"expired_at"`
+	UsedAt      pgtype.Timestamptz `json:"used_at"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+type CreateAccountTokenParams struct {
+	AccountType AccountType  `json:"account_type"`
+	AccountID   int64        `json:"account_id"`
+	Purpose     TokenPurpose `json:"purpose"`
+	TokenHash   string       `json:"token_hash"`
+	ExpiredAt   time.Time    `json:"expired_at"`
+}
@@ -20,11 +20,31 @@ type Admin struct {
 	CreatedAt         time.Time          `json:"created_at"`
 }
 
+type AdminTwoFactor struct {
+	AdminID         int32              `json:"admin_id"`
+	EncryptedSecret string             `json:"-"`
+	LastUsedStep    int64              `json:"-"`
+	EnabledAt       pgtype.Timestamptz `json:"enabled_at"`
+	CreatedAt       time.Time          `json:"created_at"`
+}
+
+type LoginFailureCounter struct {
+	AdminID          int32 `json:"admin_id"`
+	FailedLoginCount int32 `json:"failed_login_count"`
+	LockCount        int32 `json:"lock_count"`
+}
+
+type ClientIpFailureCounter struct {
+	FailedCount int32 `json:"failed_count"`
+	LockCount   int32 `json:"lock_count"`
+}
+
 type AuthorizationRole struct {
-	RoleID          int32     `json:"role_id"`
-	RoleName        string    `json:"role_name"`
-	IsAdministrator bool      `json:"is_administrator"`
-	CreatedAt       time.Time `json:"created_at"`
+	RoleID           int32     `json:"role_id"`
+	RoleName         string    `json:"role_name"`
+	IsAdministrator  bool      `json:"is_administrator"`
+	RequireTwoFactor bool      `json:"require_two_factor"`
+	CreatedAt        time.Time `json:"created_at"`
 }
 
 type AuthorizationRule struct {
@@ -35,9 +55,33 @@ type AuthorizationRule struct {
 	CreatedAt      time.Time `json:"created_at"`
 }
 
+type CreateAuthorizationRoleParams struct {
+	RoleName         string `json:"role_name"`
+	IsAdministrator  bool   `json:"is_administrator"`
+	RequireTwoFactor bool   `json:"require_two_factor"`
+}
+
+type UpdateAuthorizationRoleParams struct {
+	RoleID           int64       `json:"role_id"`
+	RoleName         pgtype.Text `json:"role_name"`
+	IsAdministrator  pgtype.Bool `json:"is_administrator"`
+	RequireTwoFactor pgtype.Bool `json:"require_two_factor"`
+}
+
+type AuthorizationRuleParams struct {
+	PermissionCode string `json:"permission_code"`
+	IsAllowed      bool   `json:"is_allowed"`
+}
+
+type ReplaceAuthorizationRulesParams struct {
+	RoleID int64                     `json:"role_id"`
+	Rules  []AuthorizationRuleParams `json:"rules"`
+}
+
 type CreateAdminParams struct {
 	RoleID            int64              `json:"role_id"`
 	Email             string             `json:"email"`
+	Password          string             `json:"-"`
 	HashedPassword    string             `json:"hashed_password"`
 	Firstname         string             `json:"firstname"`
 	Lastname          pgtype.Text        `json:"lastname"`
@@ -65,6 +109,7 @@ type GetListAdminFilterParams struct {
 type UpdateAdminParams struct {
 	Email             string             `json:"email"`
 	RoleID            pgtype.Int8        `json:"role_id"`
+	Password          pgtype.Text        `json:"-"`
 	HashedPassword    pgtype.Text        `json:"hashed_password"`
 	Firstname         pgtype.Text        `json:"firstname"`
 	Lastname          pgtype.Text        `json:"lastname"`
@@ -0,0 +1,25 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type AuditLog struct {
+	AuditID   int64       `json:"audit_id"`
+	AdminID   pgtype.Int8 `json:"admin_id"`
+	ActorID   pgtype.Int8 `json:"actor_id"`
+	Action    string      `json:"action"`
+	Detail    pgtype.Text `json:"detail"`
+	ClientIp  pgtype.Text `json:"client_ip"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type CreateAuditLogParams struct {
+	AdminID  pgtype.Int8 `json:"admin_id"`
+	ActorID  pgtype.Int8 `json:"actor_id"`
+	Action   string      `json:"action"`
+	Detail   pgtype.Text `json:"detail"`
+	ClientIp pgtype.Text `json:"client_ip"`
+}
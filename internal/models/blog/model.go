@@ -0,0 +1,90 @@
+package blog
+
+import (
+	"time"
+
+	"github.com/daniel-vuky/go-blog/pkg/markdown"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Post
+// A published post as exposed to anonymous readers, Content is the markdown source
+// and ContentHtml its sanitized rendering. ThumbnailSrcset offers resized thumbnails of the media library.
+type Post struct {
+	PostID           int64              `json:"-"`
+	Name             string             `json:"name"`
+	UrlKey           string             `json:"url_key"`
+	ShortDescription pgtype.Text        `json:"short_description"`
+	Description      pgtype.Text        `json:"description"`
+	Content          pgtype.Text        `json:"content"`
+	ContentHtml      string             `json:"content_html"`
+	TableOfContents  []markdown.Heading `json:"table_of_contents"`
+	WordCount        int                `json:"word_count"`
+	ReadingTime      int                `json:"reading_time"`
+	Thumbnail        pgtype.Text        `json:"thumbnail"`
+	ThumbnailSrcset  string             `json:"thumbnail_srcset,omitempty"`
+	AuthorName       string             `json:"author_name"`
+	Categories       []CategorySummary  `json:"categories"`
+	PublishedAt      time.Time          `json:"published_at"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        time.Time          `json:"updated_at"`
+}
+
+// PostSummary
+// A published post without its content, used by the listings.
+type PostSummary struct {
+	Name             string      `json:"name"`
+	UrlKey           string      `json:"url_key"`
+	ShortDescription pgtype.Text `json:"short_description"`
+	Thumbnail        pgtype.Text `json:"thumbnail"`
+	ThumbnailSrcset  string      `json:"thumbnail_srcset,omitempty"`
+	AuthorName       string      `json:"author_name"`
+	PublishedAt      time.Time   `json:"published_at"`
+	CreatedAt        time.Time   `json:"created_at"`
+	UpdatedAt        time.Time   `json:"updated_at"`
+}
+
+// Category
+// A category as exposed to anonymous readers, linked to its parent by url key.
+type Category struct {
+	CategoryID       int64       `json:"-"`
+	Name             string      `json:"name"`
+	UrlKey           string      `json:"url_key"`
+	ParentUrlKey     pgtype.Text `json:"parent_url_key"`
+	ShortDescription pgtype.Text `json:"short_description"`
+	Description      pgtype.Text `json:"description"`
+	UpdatedAt        time.Time   `json:"updated_at"`
+}
+
+// CategorySummary
+// The name and url key of a category a post belongs to.
+type CategorySummary struct {
+	Name      string    `json:"name"`
+	UrlKey    string    `json:"url_key"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+// Comment
+// A comment of a published post, the id is kept to thread the replies.
+type Comment struct {
+	CommentID  int64       `json:"comment_id"`
+	ParentID   pgtype.Int8 `json:"parent_id"`
+	AuthorName string      `json:"author_name"`
+	Comment    string      `json:"comment"`
+	CreatedAt  time.Time   `json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+}
+
+type GetListPostParams struct {
+	CategoryID pgtype.Int8 `json:"category_id"`
+	// IncludeDescendants also lists the posts of the descendants of the category
+	IncludeDescendants bool  `json:"include_descendants"`
+	PageSize           int32 `json:"page_size"`
+	CurrentPage        int32 `json:"current_page"`
+}
+
+type GetListCommentParams struct {
+	PostID      int64 `json:"post_id"`
+	PageSize    int32 `json:"page_size"`
+	CurrentPage int32 `json:"current_page"`
+}
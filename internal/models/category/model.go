@@ -3,15 +3,56 @@ package admin
 import (
 	"time"
 
+	"github.com/daniel-vuky/go-blog/internal/common"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Category struct {
 	CategoryID       int64       `json:"category_id"`
-	ParentID         int64       `json:"parent_id"`
+	ParentID         pgtype.Int8 `json:"parent_id"`
 	Name             string      `json:"name"`
 	UrlKey           pgtype.Text `json:"url_key"`
 	ShortDescription pgtype.Text `json:"short_description"`
 	Description      pgtype.Text `json:"description"`
 	CreatedAt        time.Time   `json:"created_at"`
+	UpdatedAt        time.Time   `json:"updated_at"`
+}
+
+// CategoryNode
+// A category with its nested children.
+type CategoryNode struct {
+	Category
+	Children []*CategoryNode `json:"children"`
+}
+
+type CreateCategoryParams struct {
+	ParentID         pgtype.Int8 `json:"parent_id"`
+	Name             string      `json:"name"`
+	UrlKey           pgtype.Text `json:"url_key"`
+	ShortDescription pgtype.Text `json:"short_description"`
+	Description      pgtype.Text `json:"description"`
+}
+
+type UpdateCategoryParams struct {
+	CategoryID       int64       `json:"category_id"`
+	Name             pgtype.Text `json:"name"`
+	UrlKey           pgtype.Text `json:"url_key"`
+	ShortDescription pgtype.Text `json:"short_description"`
+	Description      pgtype.Text `json:"description"`
+	// RegenerateUrlKey replaces the url key with one generated from the name when UrlKey is not set
+	RegenerateUrlKey bool `json:"regenerate_url_key"`
+}
+
+type GetListCategoryParams struct {
+	common.FilterParams
+	Filter         *GetListCategoryFilterParams
+	OrderBy        string `json:"order_by"`
+	OrderDirection string `json:"order_direction"`
+	PageSize       int32  `json:"page_size"`
+	CurrentPage    int32  `json:"current_page"`
+}
+
+type GetListCategoryFilterParams struct {
+	Name     pgtype.Text `json:"name" filter:"contains"`
+	ParentID pgtype.Int8 `json:"parent_id" db:"parent_id"`
 }
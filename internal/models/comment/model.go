@@ -1,17 +1,99 @@
 package admin
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/daniel-vuky/go-blog/internal/common"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type CommentStatus string
+
+const (
+	CommentStatusPending  CommentStatus = "pending"
+	CommentStatusApproved CommentStatus = "approved"
+	CommentStatusSpam     CommentStatus = "spam"
+	CommentStatusRejected CommentStatus = "rejected"
+)
+
+// IsValid reports whether the value is one of the comment_status enum values.
+func (e CommentStatus) IsValid() bool {
+	switch e {
+	case CommentStatusPending, CommentStatusApproved, CommentStatusSpam, CommentStatusRejected:
+		return true
+	}
+	return false
+}
+
+func (e *CommentStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = CommentStatus(s)
+	case string:
+		*e = CommentStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for CommentStatus: %T", src)
+	}
+	return nil
+}
+
 type Comment struct {
-	CommentID int64       `json:"comment_id"`
-	PostID    int64       `json:"post_id"`
-	UserID    int64       `json:"user_id"`
-	ParentID  pgtype.Int8 `json:"parent_id"`
-	Comment   string      `json:"comment"`
-	CreatedAt time.Time   `json:"created_at"`
-	UpdatedAt time.Time   `json:"updated_at"`
+	CommentID int64         `json:"comment_id"`
+	PostID    int64         `json:"post_id"`
+	UserID    int64         `json:"user_id"`
+	ParentID  pgtype.Int8   `json:"parent_id"`
+	Comment   string        `json:"comment"`
+	Status    CommentStatus `json:"status"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// CommentNode
+// A comment with its author, its number of direct replies and the loaded page of those replies.
+// Replies is null when the level below the comment was not requested.
+type CommentNode struct {
+	Comment
+	AuthorName string         `json:"author_name"`
+	ReplyCount int64          `json:"reply_count"`
+	Replies    []*CommentNode `json:"replies"`
+}
+
+type CreateCommentParams struct {
+	PostID   int64         `json:"post_id"`
+	UserID   int64         `json:"user_id"`
+	ParentID pgtype.Int8   `json:"parent_id"`
+	Comment  string        `json:"comment"`
+	Status   CommentStatus `json:"status"`
+}
+
+type UpdateCommentParams struct {
+	CommentID int64         `json:"comment_id"`
+	Comment   string        `json:"comment"`
+	Status    CommentStatus `json:"status"`
+}
+
+// GetCommentTreeParams
+// Depth is the number of levels returned, the first level being paginated with PageSize and CurrentPage
+// and every deeper level holding at most ReplyPageSize replies per comment.
+type GetCommentTreeParams struct {
+	Depth         int32 `json:"depth"`
+	PageSize      int32 `json:"page_size"`
+	CurrentPage   int32 `json:"current_page"`
+	ReplyPageSize int32 `json:"reply_page_size"`
+}
+
+type GetListCommentParams struct {
+	common.FilterParams
+	Filter         *GetListCommentFilterParams
+	OrderBy        string `json:"order_by"`
+	OrderDirection string `json:"order_direction"`
+	PageSize       int32  `json:"page_size"`
+	CurrentPage    int32  `json:"current_page"`
+}
+
+type GetListCommentFilterParams struct {
+	Status pgtype.Text `json:"status" db:"status" filter:"eq"`
+	PostID pgtype.Int8 `json:"post_id" db:"post_id"`
+	UserID pgtype.Int8 `json:"user_id" db:"user_id"`
 }
@@ -10,3 +10,10 @@ type PostLink struct {
 	PostID     int64     `json:"post_id"`
 	CreatedAt  time.Time `json:"created_at"`
 }
+
+type GetListCategoryPostParams struct {
+	CategoryID         int64 `json:"category_id"`
+	IncludeDescendants bool  `json:"include_descendants"`
+	PageSize           int32 `json:"page_size"`
+	CurrentPage        int32 `json:"current_page"`
+}
@@ -0,0 +1,85 @@
+package media
+
+import (
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/daniel-vuky/go-blog/internal/common"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// storageKeyPattern
+// Key of a stored file, the first two characters of the content hash, the hash and the extension
+var storageKeyPattern = regexp.MustCompile(`[0-9a-f]{2}/[0-9a-f]{64}\.(?:jpg|png|gif|webp)`)
+
+// ExtractStorageKeys
+// Returns the sorted distinct media keys found in the texts.
+// @param texts ...string
+// @return []string
+func ExtractStorageKeys(texts ...string) []string {
+	keys := []string{}
+	for _, text := range texts {
+		for _, key := range storageKeyPattern.FindAllString(text, -1) {
+			// The directory is the start of the hash in the keys of the library
+			if key[:2] == key[3:5] {
+				keys = append(keys, key)
+			}
+		}
+	}
+	slices.Sort(keys)
+	return slices.Compact(keys)
+}
+
+// Media
+// An uploaded file, stored once per content under a key derived from its hash.
+// Url is where the file is served from, UsageCount the number of posts using it.
+type Media struct {
+	MediaID     int64       `json:"media_id"`
+	FileName    string      `json:"file_name"`
+	StorageKey  string      `json:"storage_key"`
+	ContentHash string      `json:"content_hash"`
+	MimeType    string      `json:"mime_type"`
+	Size        int64       `json:"size"`
+	AdminID     pgtype.Int8 `json:"admin_id"`
+	UsageCount  int64       `json:"usage_count"`
+	Url         string      `json:"url"`
+	Posts       []MediaPost `json:"posts,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+// MediaPost
+// A post using a media file in its thumbnail or its content.
+type MediaPost struct {
+	PostID int64  `json:"post_id"`
+	Name   string `json:"name"`
+}
+
+type CreateMediaParams struct {
+	FileName    string      `json:"file_name"`
+	StorageKey  string      `json:"storage_key"`
+	ContentHash string      `json:"content_hash"`
+	MimeType    string      `json:"mime_type"`
+	Size        int64       `json:"size"`
+	AdminID     pgtype.Int8 `json:"admin_id"`
+}
+
+type UploadMediaParams struct {
+	FileName string      `json:"file_name"`
+	Content  []byte      `json:"-"`
+	AdminID  pgtype.Int8 `json:"admin_id"`
+}
+
+type GetListMediaParams struct {
+	common.FilterParams
+	Filter         *GetListMediaFilterParams
+	OrderBy        string `json:"order_by"`
+	OrderDirection string `json:"order_direction"`
+	PageSize       int32  `json:"page_size"`
+	CurrentPage    int32  `json:"current_page"`
+}
+
+type GetListMediaFilterParams struct {
+	FileName pgtype.Text `json:"file_name" db:"file_name" filter:"contains"`
+	MimeType pgtype.Text `json:"mime_type" db:"mime_type" filter:"eq"`
+}
@@ -1,13 +1,90 @@
 package admin
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/daniel-vuky/go-blog/internal/common"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type CommentMode string
+
+const (
+	CommentModeOpen      CommentMode = "open"
+	CommentModeModerated CommentMode = "moderated"
+	CommentModeClosed    CommentMode = "closed"
+)
+
+// IsValid reports whether the value is one of the comment_mode enum values.
+func (e CommentMode) IsValid() bool {
+	switch e {
+	case CommentModeOpen, CommentModeModerated, CommentModeClosed:
+		return true
+	}
+	return false
+}
+
+func (e *CommentMode) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = CommentMode(s)
+	case string:
+		*e = CommentMode(s)
+	default:
+		return fmt.Errorf("unsupported scan type for CommentMode: %T", src)
+	}
+	return nil
+}
+
+type PostStatus string
+
+const (
+	PostStatusDraft     PostStatus = "draft"
+	PostStatusReview    PostStatus = "review"
+	PostStatusScheduled PostStatus = "scheduled"
+	PostStatusPublished PostStatus = "published"
+	PostStatusArchived  PostStatus = "archived"
+)
+
+// IsValid reports whether the value is one of the post_status enum values.
+func (e PostStatus) IsValid() bool {
+	switch e {
+	case PostStatusDraft, PostStatusReview, PostStatusScheduled, PostStatusPublished, PostStatusArchived:
+		return true
+	}
+	return false
+}
+
+func (e *PostStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PostStatus(s)
+	case string:
+		*e = PostStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PostStatus: %T", src)
+	}
+	return nil
+}
+
 type Post struct {
-	PostID           int64       `json:"post_id"`
+	PostID           int64              `json:"post_id"`
+	Name             string             `json:"name"`
+	ShortDescription pgtype.Text        `json:"short_description"`
+	Description      pgtype.Text        `json:"description"`
+	Content          pgtype.Text        `json:"content"`
+	UrlKey           pgtype.Text        `json:"url_key"`
+	Thumbnail        pgtype.Text        `json:"thumbnail"`
+	AuthorID         int64              `json:"author_id"`
+	CommentMode      CommentMode        `json:"comment_mode"`
+	Status           PostStatus         `json:"status"`
+	PublishedAt      pgtype.Timestamptz `json:"published_at"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        time.Time          `json:"updated_at"`
+}
+
+type CreatePostParams struct {
 	Name             string      `json:"name"`
 	ShortDescription pgtype.Text `json:"short_description"`
 	Description      pgtype.Text `json:"description"`
@@ -15,6 +92,66 @@ type Post struct {
 	UrlKey           pgtype.Text `json:"url_key"`
 	Thumbnail        pgtype.Text `json:"thumbnail"`
 	AuthorID         int64       `json:"author_id"`
+	CommentMode      CommentMode `json:"comment_mode"`
+}
+
+type UpdatePostParams struct {
+	PostID           int64       `json:"post_id"`
+	Name             pgtype.Text `json:"name"`
+	ShortDescription pgtype.Text `json:"short_description"`
+	Description      pgtype.Text `json:"description"`
+	Content          pgtype.Text `json:"content"`
+	UrlKey           pgtype.Text `json:"url_key"`
+	Thumbnail        pgtype.Text `json:"thumbnail"`
+	CommentMode      pgtype.Text `json:"comment_mode"`
+	// RegenerateUrlKey replaces the url key with one generated from the name when UrlKey is not set
+	RegenerateUrlKey bool `json:"regenerate_url_key"`
+	// EditorID is the admin recorded on the revision of the update
+	EditorID int64 `json:"editor_id"`
+}
+
+type GetListPostParams struct {
+	common.FilterParams
+	Filter         *GetListPostFilterParams
+	OrderBy        string `json:"order_by"`
+	OrderDirection string `json:"order_direction"`
+	PageSize       int32  `json:"page_size"`
+	CurrentPage    int32  `json:"current_page"`
+}
+
+type GetListPostFilterParams struct {
+	Name     pgtype.Text `json:"name" filter:"contains"`
+	UrlKey   pgtype.Text `json:"url_key" db:"url_key" filter:"eq"`
+	AuthorID pgtype.Int8 `json:"author_id" db:"author_id"`
+	Status   pgtype.Text `json:"status" db:"status" filter:"eq"`
+}
+
+// UpdatePostStatusParams
+// Moves a post in the publishing workflow, PublishedAt is required to schedule a post.
+type UpdatePostStatusParams struct {
+	PostID      int64              `json:"post_id"`
+	Status      PostStatus         `json:"status"`
+	PublishedAt pgtype.Timestamptz `json:"published_at"`
+}
+
+// PostRevision
+// The name, descriptions and content of a post as saved by an admin, RestoredFrom is set
+// when the revision restores an older one.
+type PostRevision struct {
+	RevisionID       int64       `json:"revision_id"`
+	PostID           int64       `json:"post_id"`
+	Name             string      `json:"name"`
+	ShortDescription pgtype.Text `json:"short_description"`
+	Description      pgtype.Text `json:"description"`
+	Content          pgtype.Text `json:"content"`
+	AdminID          pgtype.Int8 `json:"admin_id"`
+	AdminName        string      `json:"admin_name"`
+	RestoredFrom     pgtype.Int8 `json:"restored_from"`
 	CreatedAt        time.Time   `json:"created_at"`
-	UpdatedAt        time.Time   `json:"updated_at"`
+}
+
+type GetListPostRevisionParams struct {
+	PostID      int64 `json:"post_id"`
+	PageSize    int32 `json:"page_size"`
+	CurrentPage int32 `json:"current_page"`
 }
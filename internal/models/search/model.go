@@ -0,0 +1,35 @@
+package search
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// HighlightStart and HighlightStop
+// Markers wrapped around the matches of a headline by the database, replaced once the headline is escaped
+const (
+	HighlightStart = "\uE000"
+	HighlightStop  = "\uE001"
+)
+
+// Post
+// A published post matching a search, with the passage of its text the terms were found in.
+type Post struct {
+	Name             string      `json:"name"`
+	UrlKey           string      `json:"url_key"`
+	ShortDescription pgtype.Text `json:"short_description"`
+	Thumbnail        pgtype.Text `json:"thumbnail"`
+	AuthorName       string      `json:"author_name"`
+	Headline         string      `json:"headline"`
+	Rank             float32     `json:"rank"`
+	PublishedAt      time.Time   `json:"published_at"`
+	UpdatedAt        time.Time   `json:"updated_at"`
+}
+
+type SearchPostParams struct {
+	Language    string `json:"language"`
+	Query       string `json:"query"`
+	PageSize    int32  `json:"page_size"`
+	CurrentPage int32  `json:"current_page"`
+}
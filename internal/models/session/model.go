@@ -0,0 +1,30 @@
+package session
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type RefreshToken struct {
+	RefreshTokenID int64              `json:"refresh_token_id"`
+	UserID         int64              `json:"user_id"`
+	RefreshToken   string             `json:"-"`
+	UserAgent      string             `json:"user_agent"`
+	ClientIp       string             `json:"client_ip"`
+	IsBlocked      bool               `json:"is_blocked"`
+	ExpiredAt      time.Time          `json:"expired_at"`
+	CreatedAt      time.Time          `json:"created_at"`
+	FamilyID       uuid.UUID          `json:"family_id"`
+	UsedAt         pgtype.Timestamptz `json:"used_at"`
+}
+
+type CreateRefreshTokenParams struct {
+	UserID       int64     `json:"user_id"`
+	RefreshToken string    `json:"refresh_token"`
+	UserAgent    string    `json:"user_agent"`
+	ClientIp     string    `json:"client_ip"`
+	ExpiredAt    time.Time `json:"expired_at"`
+	FamilyID     uuid.UUID `json:"family_id"`
+}
@@ -10,6 +10,7 @@ import (
 
 type UrlRewriteEntity string
 
+// UrlRewriteEntity1 is a category and UrlRewriteEntity2 is a post
 const (
 	UrlRewriteEntity1 UrlRewriteEntity = "1"
 	UrlRewriteEntity2 UrlRewriteEntity = "2"
@@ -56,4 +57,5 @@ type UrlRewrite struct {
 	EntityID     pgtype.Int8          `json:"entity_id"`
 	UrlKey       pgtype.Text          `json:"url_key"`
 	CreatedAt    time.Time            `json:"created_at"`
+	IsRedirect   bool                 `json:"is_redirect"`
 }
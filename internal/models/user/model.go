@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/daniel-vuky/go-blog/internal/common"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
@@ -16,6 +17,15 @@ const (
 	Gender3 Gender = "3"
 )
 
+// IsValid reports whether the value is one of the gender enum values.
+func (e Gender) IsValid() bool {
+	switch e {
+	case Gender1, Gender2, Gender3:
+		return true
+	}
+	return false
+}
+
 func (e *Gender) Scan(src interface{}) error {
 	switch s := src.(type) {
 	case []byte:
@@ -62,4 +72,48 @@ type User struct {
 	HashedPassword    string             `json:"hashed_password"`
 	PasswordChangedAt time.Time          `json:"password_changed_at"`
 	CreatedAt         time.Time          `json:"created_at"`
+	Active            bool               `json:"active"`
+	EmailVerifiedAt   pgtype.Timestamptz `json:"email_verified_at"`
+}
+
+type CreateUserParams struct {
+	Email          string             `json:"email"`
+	Password       string             `json:"-"`
+	HashedPassword string             `json:"hashed_password"`
+	Firstname      string             `json:"firstname"`
+	Lastname       string             `json:"lastname"`
+	Subscribe      pgtype.Bool        `json:"subscribe"`
+	Gender         NullGender         `json:"gender"`
+	Dob            pgtype.Timestamptz `json:"dob"`
+}
+
+type UpdateUserParams struct {
+	UserID         int64              `json:"user_id"`
+	Password       pgtype.Text        `json:"-"`
+	HashedPassword pgtype.Text        `json:"hashed_password"`
+	Firstname      pgtype.Text        `json:"firstname"`
+	Lastname       pgtype.Text        `json:"lastname"`
+	Subscribe      pgtype.Bool        `json:"subscribe"`
+	Gender         NullGender         `json:"gender"`
+	Dob            pgtype.Timestamptz `json:"dob"`
+	Active         pgtype.Bool        `json:"active"`
+}
+
+type GetListUserParams struct {
+	common.FilterParams
+	Filter         *GetListUserFilterParams
+	Search         string `json:"search"`
+	OrderBy        string `json:"order_by"`
+	OrderDirection string `json:"order_direction"`
+	PageSize       int32  `json:"page_size"`
+	CurrentPage    int32  `json:"current_page"`
+}
+
+type GetListUserFilterParams struct {
+	Email     pgtype.Text `json:"email" filter:"contains"`
+	Firstname pgtype.Text `json:"firstname" filter:"contains"`
+	Lastname  pgtype.Text `json:"lastname" filter:"contains"`
+	Gender    NullGender  `json:"gender"`
+	Subscribe pgtype.Bool `json:"subscribe"`
+	Active    pgtype.Bool `json:"active"`
 }
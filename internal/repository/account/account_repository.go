@@ -0,0 +1,21 @@
+package account
+
+import (
+	"context"
+
+	accountModel "github.com/daniel-vuky/go-blog/internal/models/account"
+)
+
+type Reader interface {
+	GetLatest(ctx context.Context, accountType accountModel.AccountType, accountID int64, purpose accountModel.TokenPurpose) (accountModel.AccountToken, error)
+}
+
+type Writer interface {
+	Create(ctx context.Context, arg *accountModel.CreateAccountTokenParams) (accountModel.AccountToken, error)
+	Consume(ctx context.Context, tokenHash string, purpose accountModel.TokenPurpose) (accountModel.AccountToken, error)
+}
+
+type Repository interface {
+	Reader
+	Writer
+}
@@ -2,18 +2,25 @@ package admin
 
 import (
 	"context"
+	"errors"
+
 	adminModel "github.com/daniel-vuky/go-blog/internal/models/admin"
 )
 
+var ErrAdminAlreadyExists = errors.New("an admin already exists")
+
 type Reader interface {
 	Get(ctx context.Context, email string) (adminModel.Admin, error)
-	GetList(ctx context.Context, arg *adminModel.GetListAdminParams, filterParams *adminModel.GetListAdminFilterParams) ([]adminModel.Admin, int64, error)
+	GetByID(ctx context.Context, adminID int32) (adminModel.Admin, error)
+	GetList(ctx context.Context, arg *adminModel.GetListAdminParams) ([]adminModel.Admin, int64, error)
 }
 
 type Writer interface {
 	Create(ctx context.Context, arg *adminModel.CreateAdminParams) (adminModel.Admin, error)
+	CreateFirst(ctx context.Context, arg *adminModel.CreateAdminParams) (adminModel.Admin, error)
 	Delete(ctx context.Context, email string) (adminModel.Admin, error)
 	Update(ctx context.Context, arg *adminModel.UpdateAdminParams) (adminModel.Admin, error)
+	Rehash(ctx context.Context, email string, hashedPassword string) error
 }
 
 type Repository interface {
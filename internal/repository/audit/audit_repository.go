@@ -0,0 +1,14 @@
+package audit
+
+import (
+	"context"
+	auditModel "github.com/daniel-vuky/go-blog/internal/models/audit"
+)
+
+type Writer interface {
+	Create(ctx context.Context, arg *auditModel.CreateAuditLogParams) (auditModel.AuditLog, error)
+}
+
+type Repository interface {
+	Writer
+}
@@ -0,0 +1,28 @@
+package authorization
+
+import (
+	"context"
+	"errors"
+
+	adminModel "github.com/daniel-vuky/go-blog/internal/models/admin"
+)
+
+var ErrRoleInUse = errors.New("role is still assigned to admins")
+
+type Reader interface {
+	GetRole(ctx context.Context, roleID int64) (adminModel.AuthorizationRole, error)
+	GetListRoles(ctx context.Context) ([]adminModel.AuthorizationRole, error)
+	GetRules(ctx context.Context, roleID int64) ([]adminModel.AuthorizationRule, error)
+}
+
+type Writer interface {
+	CreateRole(ctx context.Context, arg *adminModel.CreateAuthorizationRoleParams) (adminModel.AuthorizationRole, error)
+	UpdateRole(ctx context.Context, arg *adminModel.UpdateAuthorizationRoleParams) (adminModel.AuthorizationRole, error)
+	DeleteRole(ctx context.Context, roleID int64) (adminModel.AuthorizationRole, error)
+	ReplaceRules(ctx context.Context, arg *adminModel.ReplaceAuthorizationRulesParams) ([]adminModel.AuthorizationRule, error)
+}
+
+type Repository interface {
+	Reader
+	Writer
+}
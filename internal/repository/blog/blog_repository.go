@@ -0,0 +1,20 @@
+package blog
+
+import (
+	"context"
+
+	blogModel "github.com/daniel-vuky/go-blog/internal/models/blog"
+)
+
+type Reader interface {
+	GetPost(ctx context.Context, urlKey string) (blogModel.Post, error)
+	GetPostCategories(ctx context.Context, postID int64) ([]blogModel.CategorySummary, error)
+	GetListPost(ctx context.Context, arg *blogModel.GetListPostParams) ([]blogModel.PostSummary, int64, error)
+	GetCategory(ctx context.Context, urlKey string) (blogModel.Category, error)
+	GetListCategory(ctx context.Context) ([]blogModel.Category, error)
+	GetListComment(ctx context.Context, arg *blogModel.GetListCommentParams) ([]blogModel.Comment, int64, error)
+}
+
+type Repository interface {
+	Reader
+}
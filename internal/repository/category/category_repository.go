@@ -0,0 +1,32 @@
+package category
+
+import (
+	"context"
+	"errors"
+
+	categoryModel "github.com/daniel-vuky/go-blog/internal/models/category"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrCategoryCycle = errors.New("a category cannot be moved into its own subtree")
+
+type Reader interface {
+	Get(ctx context.Context, categoryID int64) (categoryModel.Category, error)
+	GetList(ctx context.Context, arg *categoryModel.GetListCategoryParams) ([]categoryModel.Category, int64, error)
+	GetTree(ctx context.Context, rootID pgtype.Int8) ([]categoryModel.Category, error)
+	GetBreadcrumbs(ctx context.Context, categoryID int64) ([]categoryModel.Category, error)
+}
+
+type Writer interface {
+	Create(ctx context.Context, arg *categoryModel.CreateCategoryParams) (categoryModel.Category, error)
+	Update(ctx context.Context, arg *categoryModel.UpdateCategoryParams) (categoryModel.Category, error)
+	Move(ctx context.Context, categoryID int64, parentID pgtype.Int8) (categoryModel.Category, error)
+	Delete(ctx context.Context, categoryID int64) (categoryModel.Category, error)
+	DeleteAndReassign(ctx context.Context, categoryID int64, targetID int64) (categoryModel.Category, error)
+	Merge(ctx context.Context, sourceID int64, targetID int64) (categoryModel.Category, error)
+}
+
+type Repository interface {
+	Reader
+	Writer
+}
@@ -0,0 +1,26 @@
+package comment
+
+import (
+	"context"
+
+	commentModel "github.com/daniel-vuky/go-blog/internal/models/comment"
+)
+
+type Reader interface {
+	Get(ctx context.Context, commentID int64) (commentModel.Comment, error)
+	GetListRoot(ctx context.Context, postID int64, limit int32, offset int32) ([]*commentModel.CommentNode, int64, error)
+	GetListReply(ctx context.Context, parentIDs []int64, limit int32, offset int32) ([]*commentModel.CommentNode, error)
+	CountReply(ctx context.Context, commentID int64) (int64, error)
+	GetList(ctx context.Context, arg *commentModel.GetListCommentParams) ([]commentModel.Comment, int64, error)
+}
+
+type Writer interface {
+	Create(ctx context.Context, arg *commentModel.CreateCommentParams) (commentModel.Comment, error)
+	Update(ctx context.Context, arg *commentModel.UpdateCommentParams) (commentModel.Comment, error)
+	UpdateStatus(ctx context.Context, commentIDs []int64, status commentModel.CommentStatus) ([]commentModel.Comment, error)
+}
+
+type Repository interface {
+	Reader
+	Writer
+}
@@ -0,0 +1,22 @@
+package links
+
+import (
+	"context"
+
+	categoryModel "github.com/daniel-vuky/go-blog/internal/models/category"
+)
+
+type Reader interface {
+	GetPostCategories(ctx context.Context, postID int64) ([]categoryModel.Category, error)
+}
+
+type Writer interface {
+	SetPostCategories(ctx context.Context, postID int64, categoryIDs []int64) error
+	AddPostCategories(ctx context.Context, postID int64, categoryIDs []int64) error
+	RemovePostCategories(ctx context.Context, postID int64, categoryIDs []int64) error
+}
+
+type Repository interface {
+	Reader
+	Writer
+}
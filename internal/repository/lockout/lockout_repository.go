@@ -0,0 +1,28 @@
+package lockout
+
+import (
+	"context"
+	"time"
+
+	adminModel "github.com/daniel-vuky/go-blog/internal/models/admin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Reader interface {
+	GetClientIpLockExpires(ctx context.Context, clientIp string) (pgtype.Timestamptz, error)
+}
+
+type Writer interface {
+	IncrementAdminFailedLogin(ctx context.Context, email string) (adminModel.LoginFailureCounter, error)
+	LockAdmin(ctx context.Context, adminID int32, lockExpires time.Time) error
+	ResetAdminFailedLogin(ctx context.Context, adminID int32) error
+	UnlockAdmin(ctx context.Context, email string) (adminModel.Admin, error)
+	IncrementClientIpFailedLogin(ctx context.Context, clientIp string, window time.Duration) (adminModel.ClientIpFailureCounter, error)
+	LockClientIp(ctx context.Context, clientIp string, lockExpires time.Time) error
+	ResetClientIpFailedLogin(ctx context.Context, clientIp string) error
+}
+
+type Repository interface {
+	Reader
+	Writer
+}
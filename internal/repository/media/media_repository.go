@@ -0,0 +1,24 @@
+package media
+
+import (
+	"context"
+
+	mediaModel "github.com/daniel-vuky/go-blog/internal/models/media"
+)
+
+type Reader interface {
+	Get(ctx context.Context, mediaID int64) (mediaModel.Media, error)
+	GetByHash(ctx context.Context, contentHash string) (mediaModel.Media, error)
+	GetList(ctx context.Context, arg *mediaModel.GetListMediaParams) ([]mediaModel.Media, int64, error)
+	GetListPost(ctx context.Context, mediaID int64) ([]mediaModel.MediaPost, error)
+}
+
+type Writer interface {
+	Create(ctx context.Context, arg *mediaModel.CreateMediaParams) (mediaModel.Media, error)
+	Delete(ctx context.Context, mediaID int64) (mediaModel.Media, error)
+}
+
+type Repository interface {
+	Reader
+	Writer
+}
@@ -0,0 +1,29 @@
+package post
+
+import (
+	"context"
+	"time"
+
+	postModel "github.com/daniel-vuky/go-blog/internal/models/post"
+)
+
+type Reader interface {
+	Get(ctx context.Context, postID int64) (postModel.Post, error)
+	GetList(ctx context.Context, arg *postModel.GetListPostParams) ([]postModel.Post, int64, error)
+	GetRevision(ctx context.Context, postID int64, revisionID int64) (postModel.PostRevision, error)
+	GetListRevision(ctx context.Context, arg *postModel.GetListPostRevisionParams) ([]postModel.PostRevision, int64, error)
+}
+
+type Writer interface {
+	Create(ctx context.Context, arg *postModel.CreatePostParams) (postModel.Post, error)
+	Update(ctx context.Context, arg *postModel.UpdatePostParams) (postModel.Post, error)
+	Delete(ctx context.Context, postID int64) (postModel.Post, error)
+	UpdateStatus(ctx context.Context, arg *postModel.UpdatePostStatusParams) (postModel.Post, error)
+	PublishScheduled(ctx context.Context, now time.Time) ([]postModel.Post, error)
+	RestoreRevision(ctx context.Context, postID int64, revisionID int64, editorID int64) (postModel.Post, error)
+}
+
+type Repository interface {
+	Reader
+	Writer
+}
@@ -0,0 +1,20 @@
+package search
+
+import (
+	"context"
+
+	searchModel "github.com/daniel-vuky/go-blog/internal/models/search"
+)
+
+type Reader interface {
+	SearchPost(ctx context.Context, arg *searchModel.SearchPostParams) ([]searchModel.Post, int64, error)
+}
+
+type Writer interface {
+	UpdateLanguage(ctx context.Context, language string) (bool, error)
+}
+
+type Repository interface {
+	Reader
+	Writer
+}
@@ -0,0 +1,28 @@
+package session
+
+import (
+	"context"
+	"errors"
+
+	sessionModel "github.com/daniel-vuky/go-blog/internal/models/session"
+	"github.com/google/uuid"
+)
+
+var ErrRefreshTokenUsed = errors.New("refresh token was already used")
+
+type Reader interface {
+	Get(ctx context.Context, hashedToken string) (sessionModel.RefreshToken, error)
+	GetListActive(ctx context.Context, userID int64) ([]sessionModel.RefreshToken, error)
+}
+
+type Writer interface {
+	Create(ctx context.Context, arg *sessionModel.CreateRefreshTokenParams) (sessionModel.RefreshToken, error)
+	Rotate(ctx context.Context, refreshTokenID int64, arg *sessionModel.CreateRefreshTokenParams) (sessionModel.RefreshToken, error)
+	BlockFamily(ctx context.Context, familyID uuid.UUID) error
+	BlockUser(ctx context.Context, userID int64) error
+}
+
+type Repository interface {
+	Reader
+	Writer
+}
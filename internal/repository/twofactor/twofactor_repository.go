@@ -0,0 +1,29 @@
+package twofactor
+
+import (
+	"context"
+	"errors"
+
+	adminModel "github.com/daniel-vuky/go-blog/internal/models/admin"
+)
+
+var ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+
+type Reader interface {
+	Get(ctx context.Context, adminID int32) (adminModel.AdminTwoFactor, error)
+	CountRecoveryCodes(ctx context.Context, adminID int32) (int64, error)
+}
+
+type Writer interface {
+	SaveSecret(ctx context.Context, adminID int32, encryptedSecret string) (adminModel.AdminTwoFactor, error)
+	Enable(ctx context.Context, adminID int32, step int64, recoveryCodeHashes []string) error
+	UseStep(ctx context.Context, adminID int32, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, adminID int32, codeHash string) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, adminID int32, recoveryCodeHashes []string) error
+	Delete(ctx context.Context, adminID int32) error
+}
+
+type Repository interface {
+	Reader
+	Writer
+}
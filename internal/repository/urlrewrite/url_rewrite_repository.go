@@ -0,0 +1,17 @@
+package urlrewrite
+
+import (
+	"context"
+
+	urlRewriteModel "github.com/daniel-vuky/go-blog/internal/models/url_rewrite"
+)
+
+type Reader interface {
+	Get(ctx context.Context, urlKey string) (urlRewriteModel.UrlRewrite, error)
+	GetCanonical(ctx context.Context, entityType urlRewriteModel.UrlRewriteEntity, entityID int64) (urlRewriteModel.UrlRewrite, error)
+	GetListTakenUrlKey(ctx context.Context, base string, entityType urlRewriteModel.UrlRewriteEntity, entityID int64) ([]string, error)
+}
+
+type Repository interface {
+	Reader
+}
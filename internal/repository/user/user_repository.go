@@ -0,0 +1,25 @@
+package user
+
+import (
+	"context"
+
+	userModel "github.com/daniel-vuky/go-blog/internal/models/user"
+)
+
+type Reader interface {
+	Get(ctx context.Context, email string) (userModel.User, error)
+	GetByID(ctx context.Context, userID int64) (userModel.User, error)
+	GetList(ctx context.Context, arg *userModel.GetListUserParams) ([]userModel.User, int64, error)
+}
+
+type Writer interface {
+	Create(ctx context.Context, arg *userModel.CreateUserParams) (userModel.User, error)
+	Update(ctx context.Context, arg *userModel.UpdateUserParams) (userModel.User, error)
+	Rehash(ctx context.Context, userID int64, hashedPassword string) error
+	VerifyEmail(ctx context.Context, userID int64) (userModel.User, error)
+}
+
+type Repository interface {
+	Reader
+	Writer
+}
@@ -0,0 +1,322 @@
+package account
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/account"
+	adminModel "github.com/daniel-vuky/go-blog/internal/models/admin"
+	userModel "github.com/daniel-vuky/go-blog/internal/models/user"
+	"github.com/daniel-vuky/go-blog/internal/repository/account"
+	"github.com/daniel-vuky/go-blog/internal/service/admin"
+	"github.com/daniel-vuky/go-blog/internal/service/user"
+	"github.com/daniel-vuky/go-blog/pkg/config"
+	"github.com/daniel-vuky/go-blog/pkg/mail"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// accountTokenBytes
+	// Amount of random bytes of an account token
+	accountTokenBytes = 32
+	// minSigningKeyLength
+	// Shortest key accepted to sign the account tokens
+	minSigningKeyLength = 32
+)
+
+var (
+	ErrInvalidAccountToken  = errors.New("token is invalid or has expired")
+	ErrEmailAlreadyVerified = errors.New("email is already verified")
+	ErrInvalidSigningKey    = fmt.Errorf("account token signing key must be at least %d characters", minSigningKeyLength)
+)
+
+// Service
+// Issues the password reset and email verification tokens and sends them by email.
+type Service struct {
+	AccountTokenRepo account.Repository
+	AdminService     *admin.Service
+	UserService      *user.Service
+	Mailer           mail.Mailer
+	signingKey       []byte
+	config           *config.AccountToken
+}
+
+// NewService
+// Returns a new instance of Service signing the tokens with the key of the account token configuration.
+func NewService(
+	repo account.Repository,
+	adminService *admin.Service,
+	userService *user.Service,
+	mailer mail.Mailer,
+	cfg *config.AccountToken,
+) (*Service, error) {
+	if len(cfg.SigningKey) < minSigningKeyLength {
+		return nil, ErrInvalidSigningKey
+	}
+	return &Service{
+		AccountTokenRepo: repo,
+		AdminService:     adminService,
+		UserService:      userService,
+		Mailer:           mailer,
+		signingKey:       []byte(cfg.SigningKey),
+		config:           cfg,
+	}, nil
+}
+
+// ForgotPassword
+// Sends a password reset link to the account owning the email.
+// Unknown and inactive accounts are ignored so the response does not reveal which emails exist,
+// as are the requests repeated within the reset cooldown.
+// @param c context.Context
+// @param accountType model.AccountType
+// @param email string
+// @return error
+func (s *Service) ForgotPassword(c context.Context, accountType model.AccountType, email string) error {
+	var accountID int64
+	switch accountType {
+	case model.AccountTypeAdmin:
+		existedAdmin, err := s.AdminService.GetAdmin(c, email)
+		if err != nil {
+			return ignoreNoRows(err)
+		}
+		if !existedAdmin.Active.Bool {
+			return nil
+		}
+		accountID = int64(existedAdmin.AdminID)
+	case model.AccountTypeUser:
+		existedUser, err := s.UserService.GetUserByEmail(c, email)
+		if err != nil {
+			return ignoreNoRows(err)
+		}
+		if !existedUser.Active {
+			return nil
+		}
+		accountID = existedUser.UserID
+	default:
+		return fmt.Errorf("unknown account type %q", accountType)
+	}
+
+	coolingDown, err := s.isCoolingDown(c, accountType, accountID, model.TokenPurposePasswordReset, s.config.ResetCooldown)
+	if err != nil || coolingDown {
+		return err
+	}
+	plainToken, err := s.createToken(c, accountType, accountID, model.TokenPurposePasswordReset, s.config.ResetDuration)
+	if err != nil {
+		return err
+	}
+	return s.Mailer.Send(c, &mail.Message{
+		To:      email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"We received a request to reset your password.\n\n"+
+				"Open the link below within %s to choose a new password:\n%s\n\n"+
+				"If you did not ask for it, you can ignore this email.\n",
+			s.config.ResetDuration,
+			s.buildLink("/auth/reset-password", plainToken),
+		),
+	})
+}
+
+// ResetPassword
+// Exchanges a password reset token for a new password.
+// @param c context.Context
+// @param plainToken string
+// @param newPassword string
+// @return error
+func (s *Service) ResetPassword(c context.Context, plainToken string, newPassword string) error {
+	accountToken, err := s.consumeToken(c, plainToken, model.TokenPurposePasswordReset)
+	if err != nil {
+		return err
+	}
+	switch accountToken.AccountType {
+	case model.AccountTypeAdmin:
+		existedAdmin, err := s.AdminService.GetAdminByID(c, int32(accountToken.AccountID))
+		if err != nil {
+			return convertNoRows(err)
+		}
+		_, err = s.AdminService.UpdateAdmin(c, &adminModel.UpdateAdminParams{
+			Email:    existedAdmin.Email,
+			Password: pgtype.Text{String: newPassword, Valid: true},
+		})
+		return err
+	case model.AccountTypeUser:
+		_, err = s.UserService.ResetPassword(c, accountToken.AccountID, newPassword)
+		return convertNoRows(err)
+	default:
+		return ErrInvalidAccountToken
+	}
+}
+
+// SendEmailVerification
+// Sends an email verification link to a user.
+// @param c context.Context
+// @param siteUser *userModel.User
+// @return error
+func (s *Service) SendEmailVerification(c context.Context, siteUser *userModel.User) error {
+	if siteUser.EmailVerifiedAt.Valid {
+		return ErrEmailAlreadyVerified
+	}
+	plainToken, err := s.createToken(
+		c,
+		model.AccountTypeUser,
+		siteUser.UserID,
+		model.TokenPurposeEmailVerification,
+		s.config.VerificationDuration,
+	)
+	if err != nil {
+		return err
+	}
+	return s.Mailer.Send(c, &mail.Message{
+		To:      siteUser.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm your email address by opening the link below within %s:\n%s\n",
+			siteUser.Firstname,
+			s.config.VerificationDuration,
+			s.buildLink("/auth/verify-email", plainToken),
+		),
+	})
+}
+
+// VerifyEmail
+// Exchanges an email verification token and marks the email of the user as verified.
+// @param c context.Context
+// @param plainToken string
+// @return userModel.User, error
+func (s *Service) VerifyEmail(c context.Context, plainToken string) (userModel.User, error) {
+	accountToken, err := s.consumeToken(c, plainToken, model.TokenPurposeEmailVerification)
+	if err != nil {
+		return userModel.User{}, err
+	}
+	if accountToken.AccountType != model.AccountTypeUser {
+		return userModel.User{}, ErrInvalidAccountToken
+	}
+	verifiedUser, err := s.UserService.VerifyEmail(c, accountToken.AccountID)
+	return verifiedUser, convertNoRows(err)
+}
+
+// createToken
+// Stores a new token of the account and returns its plain value.
+// @param c context.Context
+// @param accountType model.AccountType
+// @param accountID int64
+// @param purpose model.TokenPurpose
+// @param duration time.Duration
+// @return string, error
+func (s *Service) createToken(
+	c context.Context,
+	accountType model.AccountType,
+	accountID int64,
+	purpose model.TokenPurpose,
+	duration time.Duration,
+) (string, error) {
+	buffer := make([]byte, accountTokenBytes)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	plainToken := base64.RawURLEncoding.EncodeToString(buffer)
+	_, err := s.AccountTokenRepo.Create(c, &model.CreateAccountTokenParams{
+		AccountType: accountType,
+		AccountID:   accountID,
+		Purpose:     purpose,
+		TokenHash:   s.signToken(plainToken),
+		ExpiredAt:   time.Now().Add(duration),
+	})
+	if err != nil {
+		return "", err
+	}
+	return plainToken, nil
+}
+
+// isCoolingDown
+// Checks whether a token of the account was issued for the purpose less than cooldown ago.
+// @param c context.Context
+// @param accountType model.AccountType
+// @param accountID int64
+// @param purpose model.TokenPurpose
+// @param cooldown time.Duration
+// @return bool, error
+func (s *Service) isCoolingDown(
+	c context.Context,
+	accountType model.AccountType,
+	accountID int64,
+	purpose model.TokenPurpose,
+	cooldown time.Duration,
+) (bool, error) {
+	if cooldown <= 0 {
+		return false, nil
+	}
+	latestToken, err := s.AccountTokenRepo.GetLatest(c, accountType, accountID, purpose)
+	if err != nil {
+		return false, ignoreNoRows(err)
+	}
+	return time.Since(latestToken.CreatedAt) < cooldown, nil
+}
+
+// consumeToken
+// Marks a token as used, failing when it is unknown, used or expired.
+// @param c context.Context
+// @param plainToken string
+// @param purpose model.TokenPurpose
+// @return model.AccountToken, error
+func (s *Service) consumeToken(c context.Context, plainToken string, purpose model.TokenPurpose) (model.AccountToken, error) {
+	if plainToken == "" {
+		return model.AccountToken{}, ErrInvalidAccountToken
+	}
+	accountToken, err := s.AccountTokenRepo.Consume(c, s.signToken(plainToken), purpose)
+	if err != nil {
+		return model.AccountToken{}, convertNoRows(err)
+	}
+	return accountToken, nil
+}
+
+// signToken
+// Signs a plain token with the application key, only the signature is stored.
+// @param plainToken string
+// @return string
+func (s *Service) signToken(plainToken string) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(plainToken))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// buildLink
+// Builds the link sent by email for a token.
+// @param path string
+// @param plainToken string
+// @return string
+func (s *Service) buildLink(path string, plainToken string) string {
+	return strings.TrimRight(s.config.BaseUrl, "/") + path + "?token=" + url.QueryEscape(plainToken)
+}
+
+// ignoreNoRows
+// Swallows pgx.ErrNoRows.
+// @param err error
+// @return error
+func ignoreNoRows(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	return err
+}
+
+// convertNoRows
+// Maps pgx.ErrNoRows to ErrInvalidAccountToken.
+// @param err error
+// @return error
+func convertNoRows(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrInvalidAccountToken
+	}
+	return err
+}
@@ -0,0 +1,230 @@
+package account
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/account"
+	userModel "github.com/daniel-vuky/go-blog/internal/models/user"
+	"github.com/daniel-vuky/go-blog/internal/service/user"
+	"github.com/daniel-vuky/go-blog/pkg/config"
+	"github.com/daniel-vuky/go-blog/pkg/mail"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryAccountTokenRepository
+// In-memory account token repository
+type memoryAccountTokenRepository struct {
+	tokens []*model.AccountToken
+}
+
+func (repo *memoryAccountTokenRepository) GetLatest(_ context.Context, accountType model.AccountType, accountID int64, purpose model.TokenPurpose) (model.AccountToken, error) {
+	for i := len(repo.tokens) - 1; i >= 0; i-- {
+		t := repo.tokens[i]
+		if t.AccountType == accountType && t.AccountID == accountID && t.Purpose == purpose {
+			return *t, nil
+		}
+	}
+	return model.AccountToken{}, pgx.ErrNoRows
+}
+
+func (repo *memoryAccountTokenRepository) Create(_ context.Context, arg *model.CreateAccountTokenParams) (model.AccountToken, error) {
+	for _, t := range repo.tokens {
+		if t.AccountType == arg.AccountType && t.AccountID == arg.AccountID && t.Purpose == arg.Purpose && !t.UsedAt.Valid {
+			t.UsedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+		}
+	}
+	t := &model.AccountToken{
+		TokenID:     int64(len(repo.tokens) + 1),
+		AccountType: arg.AccountType,
+		AccountID:   arg.AccountID,
+		Purpose:     arg.Purpose,
+		TokenHash:   arg.TokenHash,
+		ExpiredAt:   arg.ExpiredAt,
+		CreatedAt:   time.Now(),
+	}
+	repo.tokens = append(repo.tokens, t)
+	return *t, nil
+}
+
+func (repo *memoryAccountTokenRepository) Consume(_ context.Context, tokenHash string, purpose model.TokenPurpose) (model.AccountToken, error) {
+	for _, t := range repo.tokens {
+		if t.TokenHash == tokenHash && t.Purpose == purpose && !t.UsedAt.Valid && t.ExpiredAt.After(time.Now()) {
+			t.UsedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+			return *t, nil
+		}
+	}
+	return model.AccountToken{}, pgx.ErrNoRows
+}
+
+// memoryUserRepository
+// In-memory user repository only supporting the lookups and the email verification
+type memoryUserRepository struct {
+	users map[int64]*userModel.User
+}
+
+func (repo *memoryUserRepository) Get(_ context.Context, email string) (userModel.User, error) {
+	for _, u := range repo.users {
+		if u.Email == email {
+			return *u, nil
+		}
+	}
+	return userModel.User{}, pgx.ErrNoRows
+}
+
+func (repo *memoryUserRepository) GetByID(_ context.Context, userID int64) (userModel.User, error) {
+	u, ok := repo.users[userID]
+	if !ok {
+		return userModel.User{}, pgx.ErrNoRows
+	}
+	return *u, nil
+}
+
+func (repo *memoryUserRepository) GetList(context.Context, *userModel.GetListUserParams) ([]userModel.User, int64, error) {
+	return nil, 0, nil
+}
+
+func (repo *memoryUserRepository) Create(context.Context, *userModel.CreateUserParams) (userModel.User, error) {
+	return userModel.User{}, nil
+}
+
+func (repo *memoryUserRepository) Update(context.Context, *userModel.UpdateUserParams) (userModel.User, error) {
+	return userModel.User{}, nil
+}
+
+func (repo *memoryUserRepository) Rehash(context.Context, int64, string) error {
+	return nil
+}
+
+func (repo *memoryUserRepository) VerifyEmail(_ context.Context, userID int64) (userModel.User, error) {
+	u, ok := repo.users[userID]
+	if !ok {
+		return userModel.User{}, pgx.ErrNoRows
+	}
+	u.EmailVerifiedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	return *u, nil
+}
+
+// memoryMailer
+// Mailer keeping the sent messages
+type memoryMailer struct {
+	messages []*mail.Message
+}
+
+func (mailer *memoryMailer) Send(_ context.Context, message *mail.Message) error {
+	mailer.messages = append(mailer.messages, message)
+	return nil
+}
+
+var tokenPattern = regexp.MustCompile(`token=(\S+)`)
+
+// extractToken
+// Extract the token from the link of the last sent message
+func extractToken(t *testing.T, mailer *memoryMailer) string {
+	require.NotEmpty(t, mailer.messages)
+	matches := tokenPattern.FindStringSubmatch(mailer.messages[len(mailer.messages)-1].Body)
+	require.Len(t, matches, 2)
+	plainToken, err := url.QueryUnescape(matches[1])
+	require.NoError(t, err)
+	return plainToken
+}
+
+// TestService_EmailVerification test sending and exchanging verification tokens
+func TestService_EmailVerification(t *testing.T) {
+	siteUser := &userModel.User{UserID: 1, Email: "reader@example.com", Firstname: "Reader", Active: true}
+	userRepo := &memoryUserRepository{users: map[int64]*userModel.User{1: siteUser}}
+	mailer := &memoryMailer{}
+	service, err := NewService(
+		&memoryAccountTokenRepository{},
+		nil,
+		user.NewService(userRepo, nil, nil, nil),
+		mailer,
+		&config.AccountToken{
+			SigningKey:           "12345678901234567890123456789012",
+			VerificationDuration: time.Hour,
+			BaseUrl:              "https://blog.example.com/",
+		},
+	)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, service.SendEmailVerification(ctx, siteUser))
+	require.Equal(t, siteUser.Email, mailer.messages[0].To)
+	require.Contains(t, mailer.messages[0].Body, "https://blog.example.com/auth/verify-email?token=")
+	staleToken := extractToken(t, mailer)
+
+	// Sending a new link revokes the previous one
+	require.NoError(t, service.SendEmailVerification(ctx, siteUser))
+	plainToken := extractToken(t, mailer)
+	_, err = service.VerifyEmail(ctx, staleToken)
+	require.ErrorIs(t, err, ErrInvalidAccountToken)
+
+	verifiedUser, err := service.VerifyEmail(ctx, plainToken)
+	require.NoError(t, err)
+	require.True(t, verifiedUser.EmailVerifiedAt.Valid)
+
+	// Tokens are single use
+	_, err = service.VerifyEmail(ctx, plainToken)
+	require.ErrorIs(t, err, ErrInvalidAccountToken)
+
+	err = service.SendEmailVerification(ctx, &verifiedUser)
+	require.ErrorIs(t, err, ErrEmailAlreadyVerified)
+}
+
+// TestService_ForgotPassword_UnknownEmail test unknown emails are silently ignored
+func TestService_ForgotPassword_UnknownEmail(t *testing.T) {
+	mailer := &memoryMailer{}
+	service, err := NewService(
+		&memoryAccountTokenRepository{},
+		nil,
+		user.NewService(&memoryUserRepository{users: map[int64]*userModel.User{}}, nil, nil, nil),
+		mailer,
+		&config.AccountToken{SigningKey: "12345678901234567890123456789012", ResetDuration: time.Hour},
+	)
+	require.NoError(t, err)
+	require.NoError(t, service.ForgotPassword(context.Background(), model.AccountTypeUser, "nobody@example.com"))
+	require.Empty(t, mailer.messages)
+}
+
+// TestNewService_InvalidSigningKey test a missing or short signing key is refused
+func TestNewService_InvalidSigningKey(t *testing.T) {
+	for _, signingKey := range []string{"", "too-short"} {
+		_, err := NewService(&memoryAccountTokenRepository{}, nil, nil, &memoryMailer{}, &config.AccountToken{SigningKey: signingKey})
+		require.ErrorIs(t, err, ErrInvalidSigningKey)
+	}
+}
+
+// TestService_ForgotPassword_Cooldown test a reset link is not sent again within the cooldown
+func TestService_ForgotPassword_Cooldown(t *testing.T) {
+	siteUser := &userModel.User{UserID: 1, Email: "reader@example.com", Firstname: "Reader", Active: true}
+	tokenRepo := &memoryAccountTokenRepository{}
+	mailer := &memoryMailer{}
+	service, err := NewService(
+		tokenRepo,
+		nil,
+		user.NewService(&memoryUserRepository{users: map[int64]*userModel.User{1: siteUser}}, nil, nil, nil),
+		mailer,
+		&config.AccountToken{
+			SigningKey:    "12345678901234567890123456789012",
+			ResetDuration: time.Hour,
+			ResetCooldown: 5 * time.Minute,
+		},
+	)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, service.ForgotPassword(ctx, model.AccountTypeUser, siteUser.Email))
+	require.NoError(t, service.ForgotPassword(ctx, model.AccountTypeUser, siteUser.Email))
+	require.Len(t, mailer.messages, 1)
+	require.Len(t, tokenRepo.tokens, 1)
+
+	// Once the cooldown elapsed a new link is sent
+	tokenRepo.tokens[0].CreatedAt = time.Now().Add(-6 * time.Minute)
+	require.NoError(t, service.ForgotPassword(ctx, model.AccountTypeUser, siteUser.Email))
+	require.Len(t, mailer.messages, 2)
+}
@@ -2,20 +2,40 @@ package admin
 
 import (
 	"context"
+	"errors"
+	"log"
+	"time"
+
 	model "github.com/daniel-vuky/go-blog/internal/models/admin"
 	"github.com/daniel-vuky/go-blog/internal/repository/admin"
+	"github.com/daniel-vuky/go-blog/internal/service/lockout"
+	"github.com/daniel-vuky/go-blog/pkg/password"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const defaultAdministratorRoleID = 1
+
+var (
+	ErrAdminAlreadyExists = admin.ErrAdminAlreadyExists
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrAdminInactive      = errors.New("admin account is inactive")
+	ErrAdminLocked        = errors.New("admin account is locked")
+	ErrClientIpLocked     = lockout.ErrClientIpLocked
 )
 
 // Service
 // Wraps the Repository struct from the repository package.
 type Service struct {
 	AdminRepo admin.Repository
+	Hasher    password.Hasher
+	Lockout   *lockout.Service
 }
 
 // NewService
 // Returns a new instance of Service.
-func NewService(repo admin.Repository) *Service {
-	return &Service{AdminRepo: repo}
+func NewService(repo admin.Repository, hasher password.Hasher, lockoutService *lockout.Service) *Service {
+	return &Service{AdminRepo: repo, Hasher: hasher, Lockout: lockoutService}
 }
 
 // convertAdminToModel
@@ -42,6 +62,11 @@ func convertAdminToModel(adminUser *model.Admin) model.Admin {
 // @param arg *model.CreateAdminParams
 // @return model.Admin
 func (s *Service) CreateAdmin(c context.Context, arg *model.CreateAdminParams) (model.Admin, error) {
+	hashedPassword, err := s.Hasher.Hash(arg.Password)
+	if err != nil {
+		return model.Admin{}, err
+	}
+	arg.HashedPassword = hashedPassword
 	createdAdmin, err := s.AdminRepo.Create(c, arg)
 	if err != nil {
 		return createdAdmin, err
@@ -50,6 +75,28 @@ func (s *Service) CreateAdmin(c context.Context, arg *model.CreateAdminParams) (
 	return convertAdminToModel(&createdAdmin), nil
 }
 
+// BootstrapAdmin
+// Creates the first super admin while the admin table is still empty.
+// @param c context.Context
+// @param arg *model.CreateAdminParams
+// @return model.Admin
+func (s *Service) BootstrapAdmin(c context.Context, arg *model.CreateAdminParams) (model.Admin, error) {
+	hashedPassword, err := s.Hasher.Hash(arg.Password)
+	if err != nil {
+		return model.Admin{}, err
+	}
+	arg.HashedPassword = hashedPassword
+	arg.RoleID = defaultAdministratorRoleID
+	arg.Active = pgtype.Bool{Bool: true, Valid: true}
+	arg.LockExpires = pgtype.Timestamptz{}
+	createdAdmin, err := s.AdminRepo.CreateFirst(c, arg)
+	if err != nil {
+		return createdAdmin, err
+	}
+
+	return convertAdminToModel(&createdAdmin), nil
+}
+
 // DeleteAdmin
 // Deletes an admin.
 // @param c context.Context
@@ -78,6 +125,20 @@ func (s *Service) GetAdmin(c context.Context, email string) (model.Admin, error)
 	return convertAdminToModel(&existedAdmin), err
 }
 
+// GetAdminByID
+// Returns an admin by id.
+// @param c context.Context
+// @param adminID int32
+// @return model.Admin
+func (s *Service) GetAdminByID(c context.Context, adminID int32) (model.Admin, error) {
+	existedAdmin, err := s.AdminRepo.GetByID(c, adminID)
+	if err != nil {
+		return existedAdmin, err
+	}
+
+	return convertAdminToModel(&existedAdmin), err
+}
+
 type ListAdminResponse struct {
 	Totals int64         `json:"totals"`
 	Admins []model.Admin `json:"admins"`
@@ -100,9 +161,13 @@ func (s *Service) GetListAdmin(c context.Context, arg *model.GetListAdminParams)
 	if err != nil {
 		return rsp, err
 	}
+	admins := make([]model.Admin, 0, len(listAdmin))
+	for i := range listAdmin {
+		admins = append(admins, convertAdminToModel(&listAdmin[i]))
+	}
 	rsp = ListAdminResponse{
 		Totals: totalAdmin,
-		Admins: listAdmin,
+		Admins: admins,
 	}
 
 	return rsp, nil
@@ -114,6 +179,14 @@ func (s *Service) GetListAdmin(c context.Context, arg *model.GetListAdminParams)
 // @param arg *model.UpdateAdminParams
 // @return model.Admin
 func (s *Service) UpdateAdmin(c context.Context, arg *model.UpdateAdminParams) (model.Admin, error) {
+	arg.HashedPassword = pgtype.Text{}
+	if arg.Password.Valid {
+		hashedPassword, err := s.Hasher.Hash(arg.Password.String)
+		if err != nil {
+			return model.Admin{}, err
+		}
+		arg.HashedPassword = pgtype.Text{String: hashedPassword, Valid: true}
+	}
 	updatedAdmin, err := s.AdminRepo.Update(c, arg)
 	if err != nil {
 		return updatedAdmin, err
@@ -123,7 +196,7 @@ func (s *Service) UpdateAdmin(c context.Context, arg *model.UpdateAdminParams) (
 }
 
 // IsAdminActive
-// Checks if an admin is active.
+// Checks if an admin is active and not locked.
 // @param c context.Context
 // @param email string
 // @return bool
@@ -132,5 +205,173 @@ func (s *Service) IsAdminActive(c context.Context, email string) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	return adminUser.Active.Bool, nil
+	return checkAdminStatus(&adminUser) == nil, nil
+}
+
+// VerifyAdminPassword
+// Checks the password of an admin and upgrades the stored hash when the hashing parameters changed.
+// @param c context.Context
+// @param email string
+// @param plainPassword string
+// @return model.Admin
+func (s *Service) VerifyAdminPassword(c context.Context, email string, plainPassword string) (model.Admin, error) {
+	adminUser, err := s.getAdminForLogin(c, email)
+	if err != nil {
+		return model.Admin{}, err
+	}
+	return s.verifyPassword(c, &adminUser, plainPassword)
+}
+
+// Login
+// Authenticates an admin by email and password, locking the account
+// and the client ip out after repeated failures.
+// @param c context.Context
+// @param email string
+// @param plainPassword string
+// @param clientIp string
+// @return model.Admin
+func (s *Service) Login(c context.Context, email string, plainPassword string, clientIp string) (model.Admin, error) {
+	adminUser, err := s.Authenticate(c, email, plainPassword, clientIp)
+	if err != nil {
+		return model.Admin{}, err
+	}
+	s.CompleteLogin(c, adminUser.AdminID, clientIp)
+
+	return adminUser, nil
+}
+
+// Authenticate
+// Checks the password step of a login without clearing the failed login counters,
+// so they keep counting until a pending second factor is verified. A locked admin
+// is rejected before its password is checked.
+// @param c context.Context
+// @param email string
+// @param plainPassword string
+// @param clientIp string
+// @return model.Admin
+func (s *Service) Authenticate(c context.Context, email string, plainPassword string, clientIp string) (model.Admin, error) {
+	if err := s.Lockout.CheckClientIp(c, clientIp); err != nil {
+		return model.Admin{}, err
+	}
+	adminUser, err := s.getAdminForLogin(c, email)
+	if err == nil && isAdminLocked(&adminUser) {
+		// The password is not checked during a lock, guessing it gives the same answer either way
+		return model.Admin{}, ErrAdminLocked
+	}
+	if err == nil {
+		adminUser, err = s.verifyPassword(c, &adminUser, plainPassword)
+	}
+	if err != nil {
+		if errors.Is(err, ErrInvalidCredentials) {
+			if lockErr := s.Lockout.RegisterFailedLogin(c, email, clientIp); lockErr != nil {
+				log.Printf("failed to register failed login of %s: %v", clientIp, lockErr)
+			}
+		}
+		return model.Admin{}, err
+	}
+	if err = checkAdminStatus(&adminUser); err != nil {
+		return model.Admin{}, err
+	}
+
+	return adminUser, nil
+}
+
+// CompleteLogin
+// Clears the failed login counters once every step of a login succeeded.
+// @param c context.Context
+// @param adminID int32
+// @param clientIp string
+func (s *Service) CompleteLogin(c context.Context, adminID int32, clientIp string) {
+	if err := s.Lockout.RegisterSuccessfulLogin(c, adminID, clientIp); err != nil {
+		log.Printf("failed to reset failed logins of admin %d: %v", adminID, err)
+	}
+}
+
+// CheckAdminStatus
+// Returns ErrAdminInactive or ErrAdminLocked when the admin may not log in.
+// @param adminUser *model.Admin
+// @return error
+func (s *Service) CheckAdminStatus(adminUser *model.Admin) error {
+	return checkAdminStatus(adminUser)
+}
+
+// UnlockAdmin
+// Removes the lock of an admin.
+// @param c context.Context
+// @param email string
+// @param actorID int32
+// @param clientIp string
+// @return model.Admin
+func (s *Service) UnlockAdmin(c context.Context, email string, actorID int32, clientIp string) (model.Admin, error) {
+	unlockedAdmin, err := s.Lockout.UnlockAdmin(c, email, actorID, clientIp)
+	if err != nil {
+		return unlockedAdmin, err
+	}
+
+	return convertAdminToModel(&unlockedAdmin), nil
+}
+
+// checkAdminStatus
+// Returns an error when the admin is inactive or still locked.
+// @param adminUser *model.Admin
+// @return error
+func checkAdminStatus(adminUser *model.Admin) error {
+	if !adminUser.Active.Bool {
+		return ErrAdminInactive
+	}
+	if isAdminLocked(adminUser) {
+		return ErrAdminLocked
+	}
+	return nil
+}
+
+// isAdminLocked
+// Checks whether the lock of an admin has not expired yet.
+// @param adminUser *model.Admin
+// @return bool
+func isAdminLocked(adminUser *model.Admin) bool {
+	return adminUser.LockExpires.Valid && adminUser.LockExpires.Time.After(time.Now())
+}
+
+// getAdminForLogin
+// Returns the admin of an email, ErrInvalidCredentials when there is none.
+// @param c context.Context
+// @param email string
+// @return model.Admin
+func (s *Service) getAdminForLogin(c context.Context, email string) (model.Admin, error) {
+	adminUser, err := s.AdminRepo.Get(c, email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Admin{}, ErrInvalidCredentials
+		}
+		return model.Admin{}, err
+	}
+	return adminUser, nil
+}
+
+// verifyPassword
+// Checks the password of a loaded admin and upgrades the stored hash when the hashing parameters changed.
+// @param c context.Context
+// @param adminUser *model.Admin
+// @param plainPassword string
+// @return model.Admin
+func (s *Service) verifyPassword(c context.Context, adminUser *model.Admin, plainPassword string) (model.Admin, error) {
+	ok, err := s.Hasher.Verify(adminUser.HashedPassword, plainPassword)
+	if err != nil && !errors.Is(err, password.ErrUnknownHashFormat) {
+		return model.Admin{}, err
+	}
+	if !ok {
+		return model.Admin{}, ErrInvalidCredentials
+	}
+	if s.Hasher.NeedsRehash(adminUser.HashedPassword) {
+		hashedPassword, err := s.Hasher.Hash(plainPassword)
+		if err == nil {
+			err = s.AdminRepo.Rehash(c, adminUser.Email, hashedPassword)
+		}
+		if err != nil {
+			log.Printf("failed to rehash password of admin %d: %v", adminUser.AdminID, err)
+		}
+	}
+
+	return convertAdminToModel(adminUser), nil
 }
@@ -0,0 +1,170 @@
+package admin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/admin"
+	"github.com/daniel-vuky/go-blog/internal/service/audit"
+	"github.com/daniel-vuky/go-blog/internal/service/lockout"
+	"github.com/daniel-vuky/go-blog/pkg/config"
+	"github.com/daniel-vuky/go-blog/pkg/password"
+	goRandom "github.com/daniel-vuky/go-random"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryAdminRepository
+// In-memory admin repository
+type memoryAdminRepository struct {
+	admins map[string]*model.Admin
+}
+
+func (repo *memoryAdminRepository) Get(_ context.Context, email string) (model.Admin, error) {
+	if a, ok := repo.admins[email]; ok {
+		return *a, nil
+	}
+	return model.Admin{}, pgx.ErrNoRows
+}
+
+func (repo *memoryAdminRepository) GetByID(_ context.Context, adminID int32) (model.Admin, error) {
+	for _, a := range repo.admins {
+		if a.AdminID == adminID {
+			return *a, nil
+		}
+	}
+	return model.Admin{}, pgx.ErrNoRows
+}
+
+func (repo *memoryAdminRepository) GetList(_ context.Context, _ *model.GetListAdminParams) ([]model.Admin, int64, error) {
+	var items []model.Admin
+	for _, a := range repo.admins {
+		items = append(items, *a)
+	}
+	return items, int64(len(items)), nil
+}
+
+func (repo *memoryAdminRepository) Create(_ context.Context, _ *model.CreateAdminParams) (model.Admin, error) {
+	return model.Admin{}, nil
+}
+
+func (repo *memoryAdminRepository) CreateFirst(_ context.Context, _ *model.CreateAdminParams) (model.Admin, error) {
+	return model.Admin{}, nil
+}
+
+func (repo *memoryAdminRepository) Delete(_ context.Context, email string) (model.Admin, error) {
+	a, ok := repo.admins[email]
+	if !ok {
+		return model.Admin{}, pgx.ErrNoRows
+	}
+	delete(repo.admins, email)
+	return *a, nil
+}
+
+func (repo *memoryAdminRepository) Update(_ context.Context, _ *model.UpdateAdminParams) (model.Admin, error) {
+	return model.Admin{}, nil
+}
+
+func (repo *memoryAdminRepository) Rehash(_ context.Context, email string, hashedPassword string) error {
+	if a, ok := repo.admins[email]; ok {
+		a.HashedPassword = hashedPassword
+	}
+	return nil
+}
+
+// memoryLockoutRepository
+// In-memory lockout repository without any locked client ip
+type memoryLockoutRepository struct{}
+
+func (repo *memoryLockoutRepository) GetClientIpLockExpires(_ context.Context, _ string) (pgtype.Timestamptz, error) {
+	return pgtype.Timestamptz{}, pgx.ErrNoRows
+}
+
+func (repo *memoryLockoutRepository) IncrementAdminFailedLogin(_ context.Context, _ string) (model.LoginFailureCounter, error) {
+	return model.LoginFailureCounter{}, pgx.ErrNoRows
+}
+
+func (repo *memoryLockoutRepository) LockAdmin(_ context.Context, _ int32, _ time.Time) error {
+	return nil
+}
+
+func (repo *memoryLockoutRepository) ResetAdminFailedLogin(_ context.Context, _ int32) error {
+	return nil
+}
+
+func (repo *memoryLockoutRepository) UnlockAdmin(_ context.Context, _ string) (model.Admin, error) {
+	return model.Admin{}, pgx.ErrNoRows
+}
+
+func (repo *memoryLockoutRepository) IncrementClientIpFailedLogin(_ context.Context, _ string, _ time.Duration) (model.ClientIpFailureCounter, error) {
+	return model.ClientIpFailureCounter{}, nil
+}
+
+func (repo *memoryLockoutRepository) LockClientIp(_ context.Context, _ string, _ time.Time) error {
+	return nil
+}
+
+func (repo *memoryLockoutRepository) ResetClientIpFailedLogin(_ context.Context, _ string) error {
+	return nil
+}
+
+// newTestService
+// Returns a service with one active admin and its plain password.
+func newTestService(t *testing.T, lockExpires pgtype.Timestamptz) (*Service, string, string) {
+	hasher, err := password.NewHasher(&config.Password{Algorithm: password.AlgorithmBcrypt, BcryptCost: 4})
+	require.NoError(t, err)
+	plain := goRandom.RandomString(12)
+	hashed, err := hasher.Hash(plain)
+	require.NoError(t, err)
+
+	email := goRandom.RandomString(8) + "@example.com"
+	repo := &memoryAdminRepository{admins: map[string]*model.Admin{
+		email: {
+			AdminID:        1,
+			Email:          email,
+			HashedPassword: hashed,
+			Active:         pgtype.Bool{Bool: true, Valid: true},
+			LockExpires:    lockExpires,
+		},
+	}}
+	lockoutService := lockout.NewService(&memoryLockoutRepository{}, audit.NewService(nil), &config.Lockout{})
+	return NewService(repo, hasher, lockoutService), email, plain
+}
+
+// TestService_Authenticate
+// Tests the password is checked for an admin without lock.
+func TestService_Authenticate(t *testing.T) {
+	service, email, plain := newTestService(t, pgtype.Timestamptz{})
+
+	authenticated, err := service.Authenticate(context.Background(), email, plain, "127.0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, email, authenticated.Email)
+
+	_, err = service.Authenticate(context.Background(), email, goRandom.RandomString(12), "127.0.0.1")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = service.Authenticate(context.Background(), "unknown@example.com", plain, "127.0.0.1")
+	require.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+// TestService_Authenticate_Locked
+// Tests a locked admin gets the same answer for the right and the wrong password.
+func TestService_Authenticate_Locked(t *testing.T) {
+	service, email, plain := newTestService(t, pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true})
+
+	_, rightErr := service.Authenticate(context.Background(), email, plain, "127.0.0.1")
+	_, wrongErr := service.Authenticate(context.Background(), email, goRandom.RandomString(12), "127.0.0.1")
+	require.ErrorIs(t, rightErr, ErrAdminLocked)
+	require.Equal(t, rightErr, wrongErr)
+}
+
+// TestService_Authenticate_LockExpired
+// Tests an expired lock does not reject the admin.
+func TestService_Authenticate_LockExpired(t *testing.T) {
+	service, email, plain := newTestService(t, pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true})
+
+	_, err := service.Authenticate(context.Background(), email, plain, "127.0.0.1")
+	require.NoError(t, err)
+}
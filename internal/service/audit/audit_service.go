@@ -0,0 +1,36 @@
+package audit
+
+import (
+	"context"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/audit"
+	"github.com/daniel-vuky/go-blog/internal/repository/audit"
+)
+
+// Audit actions
+const (
+	ActionAdminLocked    = "admin.locked"
+	ActionAdminUnlocked  = "admin.unlocked"
+	ActionClientIpLocked = "client_ip.locked"
+)
+
+// Service
+// Wraps the Repository struct from the repository package.
+type Service struct {
+	AuditRepo audit.Repository
+}
+
+// NewService
+// Returns a new instance of Service.
+func NewService(repo audit.Repository) *Service {
+	return &Service{AuditRepo: repo}
+}
+
+// Record
+// Writes an audit log entry.
+// @param c context.Context
+// @param arg *model.CreateAuditLogParams
+// @return model.AuditLog
+func (s *Service) Record(c context.Context, arg *model.CreateAuditLogParams) (model.AuditLog, error) {
+	return s.AuditRepo.Create(c, arg)
+}
@@ -0,0 +1,226 @@
+package authorization
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/admin"
+	"github.com/daniel-vuky/go-blog/internal/repository/authorization"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrUnknownPermission    = errors.New("unknown permission code")
+	ErrDuplicatedPermission = errors.New("duplicated permission code")
+	ErrRoleInUse            = authorization.ErrRoleInUse
+)
+
+// rolePermissions
+// Cached view of a role and its rules.
+type rolePermissions struct {
+	isAdministrator  bool
+	requireTwoFactor bool
+	rules            map[string]bool
+}
+
+// Service
+// Resolves permissions of authorization roles and caches their rule sets.
+type Service struct {
+	AuthorizationRepo authorization.Repository
+	mu                sync.RWMutex
+	cache             map[int64]*rolePermissions
+	generation        uint64
+}
+
+// NewService
+// Returns a new instance of Service.
+func NewService(repo authorization.Repository) *Service {
+	return &Service{
+		AuthorizationRepo: repo,
+		cache:             map[int64]*rolePermissions{},
+	}
+}
+
+// IsAllowed
+// Checks if a role is granted a permission.
+// Administrator roles are granted every permission.
+// @param c context.Context
+// @param roleID int64
+// @param permissionCode string
+// @return bool
+func (s *Service) IsAllowed(c context.Context, roleID int64, permissionCode string) (bool, error) {
+	permissions, err := s.loadRolePermissions(c, roleID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	if permissions.isAdministrator {
+		return true, nil
+	}
+
+	return permissions.rules[permissionCode], nil
+}
+
+// IsTwoFactorRequired
+// Checks if the admins of a role must use two-factor authentication.
+// @param c context.Context
+// @param roleID int64
+// @return bool
+func (s *Service) IsTwoFactorRequired(c context.Context, roleID int64) (bool, error) {
+	permissions, err := s.loadRolePermissions(c, roleID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return permissions.requireTwoFactor, nil
+}
+
+// InvalidateRole
+// Drops the cached rule set of a role.
+// @param roleID int64
+func (s *Service) InvalidateRole(roleID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.cache, roleID)
+	s.generation++
+}
+
+// InvalidateAll
+// Drops every cached rule set.
+func (s *Service) InvalidateAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache = map[int64]*rolePermissions{}
+	s.generation++
+}
+
+// loadRolePermissions
+// Returns the cached rule set of a role, loading it from the repository on a miss.
+// @param c context.Context
+// @param roleID int64
+// @return *rolePermissions
+func (s *Service) loadRolePermissions(c context.Context, roleID int64) (*rolePermissions, error) {
+	s.mu.RLock()
+	permissions, ok := s.cache[roleID]
+	generation := s.generation
+	s.mu.RUnlock()
+	if ok {
+		return permissions, nil
+	}
+
+	role, err := s.AuthorizationRepo.GetRole(c, roleID)
+	if err != nil {
+		return nil, err
+	}
+	permissions = &rolePermissions{
+		isAdministrator:  role.IsAdministrator,
+		requireTwoFactor: role.RequireTwoFactor,
+		rules:            map[string]bool{},
+	}
+	if !role.IsAdministrator {
+		rules, err := s.AuthorizationRepo.GetRules(c, roleID)
+		if err != nil {
+			return nil, err
+		}
+		for _, rule := range rules {
+			permissions.rules[rule.PermissionCode] = rule.IsAllowed
+		}
+	}
+
+	// Skip caching when the role was invalidated while it was being loaded
+	s.mu.Lock()
+	if s.generation == generation {
+		s.cache[roleID] = permissions
+	}
+	s.mu.Unlock()
+
+	return permissions, nil
+}
+
+// GetRole
+// Returns an authorization role.
+// @param c context.Context
+// @param roleID int64
+// @return model.AuthorizationRole
+func (s *Service) GetRole(c context.Context, roleID int64) (model.AuthorizationRole, error) {
+	return s.AuthorizationRepo.GetRole(c, roleID)
+}
+
+// GetListRoles
+// Returns all authorization roles.
+// @param c context.Context
+// @return []model.AuthorizationRole
+func (s *Service) GetListRoles(c context.Context) ([]model.AuthorizationRole, error) {
+	return s.AuthorizationRepo.GetListRoles(c)
+}
+
+// CreateRole
+// Creates a new authorization role.
+// @param c context.Context
+// @param arg *model.CreateAuthorizationRoleParams
+// @return model.AuthorizationRole
+func (s *Service) CreateRole(c context.Context, arg *model.CreateAuthorizationRoleParams) (model.AuthorizationRole, error) {
+	return s.AuthorizationRepo.CreateRole(c, arg)
+}
+
+// UpdateRole
+// Updates an authorization role and drops its cached rule set.
+// @param c context.Context
+// @param arg *model.UpdateAuthorizationRoleParams
+// @return model.AuthorizationRole
+func (s *Service) UpdateRole(c context.Context, arg *model.UpdateAuthorizationRoleParams) (model.AuthorizationRole, error) {
+	updatedRole, err := s.AuthorizationRepo.UpdateRole(c, arg)
+	s.InvalidateRole(arg.RoleID)
+	return updatedRole, err
+}
+
+// DeleteRole
+// Deletes an authorization role which is not assigned to any admin.
+// @param c context.Context
+// @param roleID int64
+// @return model.AuthorizationRole
+func (s *Service) DeleteRole(c context.Context, roleID int64) (model.AuthorizationRole, error) {
+	deletedRole, err := s.AuthorizationRepo.DeleteRole(c, roleID)
+	s.InvalidateRole(roleID)
+	return deletedRole, err
+}
+
+// GetRules
+// Returns all rules of an authorization role.
+// @param c context.Context
+// @param roleID int64
+// @return []model.AuthorizationRule
+func (s *Service) GetRules(c context.Context, roleID int64) ([]model.AuthorizationRule, error) {
+	if _, err := s.AuthorizationRepo.GetRole(c, roleID); err != nil {
+		return nil, err
+	}
+	return s.AuthorizationRepo.GetRules(c, roleID)
+}
+
+// ReplaceRules
+// Replaces all rules of an authorization role and drops its cached rule set.
+// @param c context.Context
+// @param arg *model.ReplaceAuthorizationRulesParams
+// @return []model.AuthorizationRule
+func (s *Service) ReplaceRules(c context.Context, arg *model.ReplaceAuthorizationRulesParams) ([]model.AuthorizationRule, error) {
+	seen := map[string]bool{}
+	for _, rule := range arg.Rules {
+		if !IsKnownPermission(rule.PermissionCode) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownPermission, rule.PermissionCode)
+		}
+		if seen[rule.PermissionCode] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicatedPermission, rule.PermissionCode)
+		}
+		seen[rule.PermissionCode] = true
+	}
+	rules, err := s.AuthorizationRepo.ReplaceRules(c, arg)
+	s.InvalidateRole(arg.RoleID)
+	return rules, err
+}
@@ -0,0 +1,151 @@
+package authorization
+
+import (
+	"context"
+	"testing"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/admin"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryAuthorizationRepository
+// In-memory authorization repository counting the loads of rule sets
+type memoryAuthorizationRepository struct {
+	roles     map[int64]model.AuthorizationRole
+	rules     map[int64][]model.AuthorizationRule
+	ruleLoads int
+}
+
+func (repo *memoryAuthorizationRepository) GetRole(_ context.Context, roleID int64) (model.AuthorizationRole, error) {
+	role, ok := repo.roles[roleID]
+	if !ok {
+		return model.AuthorizationRole{}, pgx.ErrNoRows
+	}
+	return role, nil
+}
+
+func (repo *memoryAuthorizationRepository) GetRules(_ context.Context, roleID int64) ([]model.AuthorizationRule, error) {
+	repo.ruleLoads++
+	return repo.rules[roleID], nil
+}
+
+func (repo *memoryAuthorizationRepository) GetListRoles(context.Context) ([]model.AuthorizationRole, error) {
+	return nil, nil
+}
+
+func (repo *memoryAuthorizationRepository) CreateRole(context.Context, *model.CreateAuthorizationRoleParams) (model.AuthorizationRole, error) {
+	return model.AuthorizationRole{}, nil
+}
+
+func (repo *memoryAuthorizationRepository) UpdateRole(context.Context, *model.UpdateAuthorizationRoleParams) (model.AuthorizationRole, error) {
+	return model.AuthorizationRole{}, nil
+}
+
+func (repo *memoryAuthorizationRepository) DeleteRole(context.Context, int64) (model.AuthorizationRole, error) {
+	return model.AuthorizationRole{}, nil
+}
+
+func (repo *memoryAuthorizationRepository) ReplaceRules(_ context.Context, arg *model.ReplaceAuthorizationRulesParams) ([]model.AuthorizationRule, error) {
+	var rules []model.AuthorizationRule
+	for _, rule := range arg.Rules {
+		rules = append(rules, model.AuthorizationRule{
+			RoleID:         arg.RoleID,
+			PermissionCode: rule.PermissionCode,
+			IsAllowed:      rule.IsAllowed,
+		})
+	}
+	repo.rules[arg.RoleID] = rules
+	return rules, nil
+}
+
+// newMemoryAuthorizationRepository
+// Create a repository holding one administrator role and one restricted role
+func newMemoryAuthorizationRepository() *memoryAuthorizationRepository {
+	return &memoryAuthorizationRepository{
+		roles: map[int64]model.AuthorizationRole{
+			1: {RoleID: 1, RoleName: "Administrator", IsAdministrator: true},
+			2: {RoleID: 2, RoleName: "Editor", IsAdministrator: false},
+		},
+		rules: map[int64][]model.AuthorizationRule{
+			2: {
+				{RoleID: 2, PermissionCode: PermissionAdminView, IsAllowed: true},
+				{RoleID: 2, PermissionCode: PermissionAdminDelete, IsAllowed: false},
+			},
+		},
+	}
+}
+
+// TestService_IsAllowed test resolving permissions of roles
+func TestService_IsAllowed(t *testing.T) {
+	service := NewService(newMemoryAuthorizationRepository())
+	testCases := []struct {
+		name           string
+		roleID         int64
+		permissionCode string
+		expected       bool
+	}{
+		{"Administrator", 1, PermissionAdminDelete, true},
+		{"Allowed", 2, PermissionAdminView, true},
+		{"Denied", 2, PermissionAdminDelete, false},
+		{"MissingRule", 2, PermissionAdminCreate, false},
+		{"UnknownRole", 3, PermissionAdminView, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			isAllowed, err := service.IsAllowed(context.Background(), tc.roleID, tc.permissionCode)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, isAllowed)
+		})
+	}
+}
+
+// TestService_IsAllowed_Cache test caching and invalidation of rule sets
+func TestService_IsAllowed_Cache(t *testing.T) {
+	repo := newMemoryAuthorizationRepository()
+	service := NewService(repo)
+
+	for i := 0; i < 3; i++ {
+		isAllowed, err := service.IsAllowed(context.Background(), 2, PermissionAdminDelete)
+		require.NoError(t, err)
+		require.False(t, isAllowed)
+	}
+	require.Equal(t, 1, repo.ruleLoads)
+
+	repo.rules[2][1].IsAllowed = true
+	service.InvalidateRole(2)
+	isAllowed, err := service.IsAllowed(context.Background(), 2, PermissionAdminDelete)
+	require.NoError(t, err)
+	require.True(t, isAllowed)
+	require.Equal(t, 2, repo.ruleLoads)
+
+	service.InvalidateAll()
+	_, err = service.IsAllowed(context.Background(), 2, PermissionAdminDelete)
+	require.NoError(t, err)
+	require.Equal(t, 3, repo.ruleLoads)
+}
+
+// TestService_ReplaceRules test replacing rules invalidates the cache and rejects unknown codes
+func TestService_ReplaceRules(t *testing.T) {
+	repo := newMemoryAuthorizationRepository()
+	service := NewService(repo)
+
+	isAllowed, err := service.IsAllowed(context.Background(), 2, PermissionRoleManage)
+	require.NoError(t, err)
+	require.False(t, isAllowed)
+
+	_, err = service.ReplaceRules(context.Background(), &model.ReplaceAuthorizationRulesParams{
+		RoleID: 2,
+		Rules:  []model.AuthorizationRuleParams{{PermissionCode: "post.publish_everything", IsAllowed: true}},
+	})
+	require.ErrorIs(t, err, ErrUnknownPermission)
+
+	_, err = service.ReplaceRules(context.Background(), &model.ReplaceAuthorizationRulesParams{
+		RoleID: 2,
+		Rules:  []model.AuthorizationRuleParams{{PermissionCode: PermissionRoleManage, IsAllowed: true}},
+	})
+	require.NoError(t, err)
+	isAllowed, err = service.IsAllowed(context.Background(), 2, PermissionRoleManage)
+	require.NoError(t, err)
+	require.True(t, isAllowed)
+}
@@ -0,0 +1,77 @@
+package authorization
+
+// Permission codes checked by the admin routes
+const (
+	PermissionAdminView       = "admin.view"
+	PermissionAdminCreate     = "admin.create"
+	PermissionAdminUpdate     = "admin.update"
+	PermissionAdminDelete     = "admin.delete"
+	PermissionRoleView        = "role.view"
+	PermissionRoleManage      = "role.manage"
+	PermissionUserView        = "user.view"
+	PermissionUserManage      = "user.manage"
+	PermissionPostView        = "post.view"
+	PermissionPostCreate      = "post.create"
+	PermissionPostUpdate      = "post.update"
+	PermissionPostDelete      = "post.delete"
+	PermissionPostPublish     = "post.publish"
+	PermissionCategoryView    = "category.view"
+	PermissionCategoryManage  = "category.manage"
+	PermissionCommentView     = "comment.view"
+	PermissionCommentModerate = "comment.moderate"
+	PermissionMediaView       = "media.view"
+	PermissionMediaManage     = "media.manage"
+)
+
+// Permission
+// Describes a permission code which can be granted to a role.
+type Permission struct {
+	Code  string `json:"code"`
+	Label string `json:"label"`
+}
+
+// permissions
+// Registry of every permission code known by the application.
+var permissions = []Permission{
+	{Code: PermissionAdminView, Label: "View admins"},
+	{Code: PermissionAdminCreate, Label: "Create admins"},
+	{Code: PermissionAdminUpdate, Label: "Update admins"},
+	{Code: PermissionAdminDelete, Label: "Delete admins"},
+	{Code: PermissionRoleView, Label: "View roles and rules"},
+	{Code: PermissionRoleManage, Label: "Create, update and delete roles and rules"},
+	{Code: PermissionUserView, Label: "View site users and their sessions"},
+	{Code: PermissionUserManage, Label: "Manage site users and revoke their sessions"},
+	{Code: PermissionPostView, Label: "View posts"},
+	{Code: PermissionPostCreate, Label: "Create posts"},
+	{Code: PermissionPostUpdate, Label: "Update posts"},
+	{Code: PermissionPostDelete, Label: "Delete posts"},
+	{Code: PermissionPostPublish, Label: "Submit, schedule, publish and archive posts"},
+	{Code: PermissionCategoryView, Label: "View categories"},
+	{Code: PermissionCategoryManage, Label: "Create, update, move and delete categories"},
+	{Code: PermissionCommentView, Label: "View comments and the moderation queue"},
+	{Code: PermissionCommentModerate, Label: "Approve, reject and mark comments as spam"},
+	{Code: PermissionMediaView, Label: "View the media library"},
+	{Code: PermissionMediaManage, Label: "Upload and delete media files"},
+}
+
+// ListPermissions
+// Returns every registered permission.
+// @return []Permission
+func ListPermissions() []Permission {
+	list := make([]Permission, len(permissions))
+	copy(list, permissions)
+	return list
+}
+
+// IsKnownPermission
+// Checks if the permission code is registered.
+// @param permissionCode string
+// @return bool
+func IsKnownPermission(permissionCode string) bool {
+	for _, permission := range permissions {
+		if permission.Code == permissionCode {
+			return true
+		}
+	}
+	return false
+}
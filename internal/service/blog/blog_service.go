@@ -0,0 +1,198 @@
+package blog
+
+import (
+	"context"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/blog"
+	"github.com/daniel-vuky/go-blog/internal/repository/blog"
+	"github.com/daniel-vuky/go-blog/pkg/markdown"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ImageSrcset
+// Builds the srcset of the resized versions of an image.
+type ImageSrcset interface {
+	Srcset(imageUrl string) string
+}
+
+// Service
+// Serves the published content of the blog to anonymous readers.
+type Service struct {
+	BlogRepo    blog.Repository
+	ImageSrcset ImageSrcset
+}
+
+// NewService
+// Returns a new instance of Service, imageSrcset may be nil when no thumbnail is resized.
+func NewService(repo blog.Repository, imageSrcset ImageSrcset) *Service {
+	return &Service{BlogRepo: repo, ImageSrcset: imageSrcset}
+}
+
+// GetPost
+// Returns a published post by url key with its categories and its rendered content.
+// @param c context.Context
+// @param urlKey string
+// @return model.Post
+func (s *Service) GetPost(c context.Context, urlKey string) (model.Post, error) {
+	post, err := s.BlogRepo.GetPost(c, urlKey)
+	if err != nil {
+		return model.Post{}, err
+	}
+	categories, err := s.BlogRepo.GetPostCategories(c, post.PostID)
+	if err != nil {
+		return model.Post{}, err
+	}
+	if categories == nil {
+		categories = []model.CategorySummary{}
+	}
+	post.Categories = categories
+	document, err := markdown.Render(post.Content.String)
+	if err != nil {
+		return model.Post{}, err
+	}
+	post.ContentHtml = document.Html
+	post.TableOfContents = document.TableOfContents
+	post.WordCount = document.WordCount
+	post.ReadingTime = document.ReadingTime
+	post.ThumbnailSrcset = s.thumbnailSrcset(post.Thumbnail)
+
+	return post, nil
+}
+
+// ListPostResponse
+// Struct to hold the response of the GetListPost method.
+type ListPostResponse struct {
+	Totals int64               `json:"totals"`
+	Posts  []model.PostSummary `json:"posts"`
+}
+
+// GetListPost
+// Returns the published posts, newest first.
+// @param c context.Context
+// @param arg *model.GetListPostParams
+// @return ListPostResponse
+func (s *Service) GetListPost(c context.Context, arg *model.GetListPostParams) (ListPostResponse, error) {
+	var rsp ListPostResponse
+	listPost, totalPost, err := s.BlogRepo.GetListPost(c, arg)
+	if err != nil {
+		return rsp, err
+	}
+	if listPost == nil {
+		listPost = []model.PostSummary{}
+	}
+	for i := range listPost {
+		listPost[i].ThumbnailSrcset = s.thumbnailSrcset(listPost[i].Thumbnail)
+	}
+	rsp = ListPostResponse{
+		Totals: totalPost,
+		Posts:  listPost,
+	}
+
+	return rsp, nil
+}
+
+// GetCategory
+// Returns a category by url key.
+// @param c context.Context
+// @param urlKey string
+// @return model.Category
+func (s *Service) GetCategory(c context.Context, urlKey string) (model.Category, error) {
+	return s.BlogRepo.GetCategory(c, urlKey)
+}
+
+// GetListCategory
+// Returns every public category.
+// @param c context.Context
+// @return []model.Category
+func (s *Service) GetListCategory(c context.Context) ([]model.Category, error) {
+	categories, err := s.BlogRepo.GetListCategory(c)
+	if err != nil {
+		return nil, err
+	}
+	if categories == nil {
+		categories = []model.Category{}
+	}
+
+	return categories, nil
+}
+
+// GetListCategoryPost
+// Returns the category found by url key and a page of its published posts.
+// @param c context.Context
+// @param urlKey string
+// @param pageSize int32
+// @param currentPage int32
+// @return model.Category
+// @return ListPostResponse
+func (s *Service) GetListCategoryPost(
+	c context.Context,
+	urlKey string,
+	pageSize int32,
+	currentPage int32,
+) (model.Category, ListPostResponse, error) {
+	category, err := s.BlogRepo.GetCategory(c, urlKey)
+	if err != nil {
+		return model.Category{}, ListPostResponse{}, err
+	}
+	rsp, err := s.GetListPost(c, &model.GetListPostParams{
+		CategoryID:  pgtype.Int8{Int64: category.CategoryID, Valid: true},
+		PageSize:    pageSize,
+		CurrentPage: currentPage,
+	})
+	if err != nil {
+		return model.Category{}, ListPostResponse{}, err
+	}
+
+	return category, rsp, nil
+}
+
+// ListCommentResponse
+// Struct to hold the response of the GetListComment method.
+type ListCommentResponse struct {
+	Totals   int64           `json:"totals"`
+	Comments []model.Comment `json:"comments"`
+}
+
+// GetListComment
+// Returns the post found by url key and a page of its comments, oldest first.
+// @param c context.Context
+// @param urlKey string
+// @param pageSize int32
+// @param currentPage int32
+// @return model.Post
+// @return ListCommentResponse
+func (s *Service) GetListComment(
+	c context.Context,
+	urlKey string,
+	pageSize int32,
+	currentPage int32,
+) (model.Post, ListCommentResponse, error) {
+	post, err := s.BlogRepo.GetPost(c, urlKey)
+	if err != nil {
+		return model.Post{}, ListCommentResponse{}, err
+	}
+	listComment, totalComment, err := s.BlogRepo.GetListComment(c, &model.GetListCommentParams{
+		PostID:      post.PostID,
+		PageSize:    pageSize,
+		CurrentPage: currentPage,
+	})
+	if err != nil {
+		return model.Post{}, ListCommentResponse{}, err
+	}
+	if listComment == nil {
+		listComment = []model.Comment{}
+	}
+
+	return post, ListCommentResponse{Totals: totalComment, Comments: listComment}, nil
+}
+
+// thumbnailSrcset
+// Returns the srcset of a post thumbnail, empty without thumbnail or when it cannot be resized.
+// @param thumbnail pgtype.Text
+// @return string
+func (s *Service) thumbnailSrcset(thumbnail pgtype.Text) string {
+	if s.ImageSrcset == nil || !thumbnail.Valid || thumbnail.String == "" {
+		return ""
+	}
+	return s.ImageSrcset.Srcset(thumbnail.String)
+}
@@ -0,0 +1,350 @@
+package category
+
+import (
+	"context"
+	"errors"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/category"
+	urlRewriteModel "github.com/daniel-vuky/go-blog/internal/models/url_rewrite"
+	"github.com/daniel-vuky/go-blog/internal/repository/category"
+	"github.com/daniel-vuky/go-blog/internal/service/urlrewrite"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// uniqueViolationCode
+	// Postgres error code raised when a unique constraint is violated
+	uniqueViolationCode = "23505"
+	// foreignKeyViolationCode
+	// Postgres error code raised when a foreign key constraint is violated
+	foreignKeyViolationCode = "23503"
+	// maxUrlKeyAttempts
+	// Number of times a generated url key is generated again when another request took it meanwhile
+	maxUrlKeyAttempts = 3
+)
+
+var (
+	ErrUrlKeyReserved         = urlrewrite.ErrUrlKeyReserved
+	ErrUrlKeyInvalid          = urlrewrite.ErrUrlKeyInvalid
+	ErrUrlKeyAlreadyExists    = errors.New("url key is already used by another post or category")
+	ErrParentNotFound         = errors.New("parent category not found")
+	ErrCategoryHasChildren    = errors.New("category still has child categories")
+	ErrCategoryCycle          = category.ErrCategoryCycle
+	ErrInvalidTargetCategory  = errors.New("target category must differ from the category")
+	ErrTargetCategoryNotFound = errors.New("target category not found")
+)
+
+// Service
+// Manages the category tree of the blog.
+type Service struct {
+	CategoryRepo    category.Repository
+	UrlKeyGenerator urlrewrite.UrlKeyGenerator
+}
+
+// NewService
+// Returns a new instance of Service.
+func NewService(repo category.Repository, urlKeyGenerator urlrewrite.UrlKeyGenerator) *Service {
+	return &Service{CategoryRepo: repo, UrlKeyGenerator: urlKeyGenerator}
+}
+
+// GetCategory
+// Returns a category by id.
+// @param c context.Context
+// @param categoryID int64
+// @return model.Category
+func (s *Service) GetCategory(c context.Context, categoryID int64) (model.Category, error) {
+	return s.CategoryRepo.Get(c, categoryID)
+}
+
+// ListCategoryResponse
+// Struct to hold the response of the GetListCategory method.
+type ListCategoryResponse struct {
+	Totals     int64            `json:"totals"`
+	Categories []model.Category `json:"categories"`
+}
+
+// GetListCategory
+// Returns a flat list of categories.
+// @param c context.Context
+// @param arg *model.GetListCategoryParams
+// @return ListCategoryResponse
+func (s *Service) GetListCategory(c context.Context, arg *model.GetListCategoryParams) (ListCategoryResponse, error) {
+	var rsp ListCategoryResponse
+	if arg.OrderBy == "" {
+		arg.OrderBy = "category_id"
+	}
+	if arg.OrderDirection == "" {
+		arg.OrderDirection = "asc"
+	}
+	listCategory, totalCategory, err := s.CategoryRepo.GetList(c, arg)
+	if err != nil {
+		return rsp, err
+	}
+	if listCategory == nil {
+		listCategory = []model.Category{}
+	}
+	rsp = ListCategoryResponse{
+		Totals:     totalCategory,
+		Categories: listCategory,
+	}
+
+	return rsp, nil
+}
+
+// GetTree
+// Returns the nested hierarchy of every root category, or only of the subtree of rootID.
+// @param c context.Context
+// @param rootID pgtype.Int8
+// @return []*model.CategoryNode
+func (s *Service) GetTree(c context.Context, rootID pgtype.Int8) ([]*model.CategoryNode, error) {
+	categories, err := s.CategoryRepo.GetTree(c, rootID)
+	if err != nil {
+		return nil, err
+	}
+	if rootID.Valid && len(categories) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+
+	return buildTree(categories, rootID), nil
+}
+
+// GetBreadcrumbs
+// Returns the path from the root to a category, the category being the last element.
+// @param c context.Context
+// @param categoryID int64
+// @return []model.Category
+func (s *Service) GetBreadcrumbs(c context.Context, categoryID int64) ([]model.Category, error) {
+	breadcrumbs, err := s.CategoryRepo.GetBreadcrumbs(c, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	if len(breadcrumbs) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+
+	return breadcrumbs, nil
+}
+
+// CreateCategory
+// Creates a new category under an existing parent, or as a root when the parent is not valid.
+// The url key is generated from the name when it is not set.
+// @param c context.Context
+// @param arg *model.CreateCategoryParams
+// @return model.Category
+func (s *Service) CreateCategory(c context.Context, arg *model.CreateCategoryParams) (model.Category, error) {
+	generateUrlKey := !arg.UrlKey.Valid
+	if !generateUrlKey {
+		if err := urlrewrite.CheckUrlKey(arg.UrlKey.String); err != nil {
+			return model.Category{}, err
+		}
+	}
+	for attempt := 1; ; attempt++ {
+		if generateUrlKey {
+			urlKey, err := s.generateUrlKey(c, arg.Name, 0)
+			if err != nil {
+				return model.Category{}, err
+			}
+			arg.UrlKey = urlKey
+		}
+		createdCategory, err := s.CategoryRepo.Create(c, arg)
+		if err == nil {
+			return createdCategory, nil
+		}
+		err = convertConstraintViolation(err)
+		if !generateUrlKey || !errors.Is(err, ErrUrlKeyAlreadyExists) || attempt == maxUrlKeyAttempts {
+			return model.Category{}, err
+		}
+	}
+}
+
+// UpdateCategory
+// Updates a category, MoveCategory changes its parent. The url key is kept unless it is set,
+// or RegenerateUrlKey asks for a new one generated from the name.
+// @param c context.Context
+// @param arg *model.UpdateCategoryParams
+// @return model.Category
+func (s *Service) UpdateCategory(c context.Context, arg *model.UpdateCategoryParams) (model.Category, error) {
+	generateUrlKey := arg.RegenerateUrlKey && !arg.UrlKey.Valid
+	if arg.UrlKey.Valid {
+		if err := urlrewrite.CheckUrlKey(arg.UrlKey.String); err != nil {
+			return model.Category{}, err
+		}
+	}
+	name := arg.Name.String
+	if generateUrlKey && !arg.Name.Valid {
+		currentCategory, err := s.CategoryRepo.Get(c, arg.CategoryID)
+		if err != nil {
+			return model.Category{}, err
+		}
+		name = currentCategory.Name
+	}
+	for attempt := 1; ; attempt++ {
+		if generateUrlKey {
+			urlKey, err := s.generateUrlKey(c, name, arg.CategoryID)
+			if err != nil {
+				return model.Category{}, err
+			}
+			arg.UrlKey = urlKey
+		}
+		updatedCategory, err := s.CategoryRepo.Update(c, arg)
+		if err == nil {
+			return updatedCategory, nil
+		}
+		err = convertConstraintViolation(err)
+		if !generateUrlKey || !errors.Is(err, ErrUrlKeyAlreadyExists) || attempt == maxUrlKeyAttempts {
+			return model.Category{}, err
+		}
+	}
+}
+
+// MoveCategory
+// Moves a category with its subtree under another parent, or to the root when the parent is not valid.
+// @param c context.Context
+// @param categoryID int64
+// @param parentID pgtype.Int8
+// @return model.Category
+func (s *Service) MoveCategory(c context.Context, categoryID int64, parentID pgtype.Int8) (model.Category, error) {
+	if parentID.Valid && parentID.Int64 == categoryID {
+		return model.Category{}, ErrCategoryCycle
+	}
+	if _, err := s.CategoryRepo.Get(c, categoryID); err != nil {
+		return model.Category{}, err
+	}
+	if parentID.Valid {
+		if _, err := s.CategoryRepo.Get(c, parentID.Int64); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return model.Category{}, ErrParentNotFound
+			}
+			return model.Category{}, err
+		}
+	}
+	movedCategory, err := s.CategoryRepo.Move(c, categoryID, parentID)
+	if err != nil {
+		return model.Category{}, convertConstraintViolation(err)
+	}
+
+	return movedCategory, nil
+}
+
+// DeleteCategory
+// Deletes a category which has no child categories. When reassignTo is valid,
+// the posts of the category are assigned to that category first.
+// @param c context.Context
+// @param categoryID int64
+// @param reassignTo pgtype.Int8
+// @return model.Category
+func (s *Service) DeleteCategory(c context.Context, categoryID int64, reassignTo pgtype.Int8) (model.Category, error) {
+	var deletedCategory model.Category
+	var err error
+	if reassignTo.Valid {
+		if reassignTo.Int64 == categoryID {
+			return model.Category{}, ErrInvalidTargetCategory
+		}
+		if err = s.checkTargetExists(c, reassignTo.Int64); err != nil {
+			return model.Category{}, err
+		}
+		deletedCategory, err = s.CategoryRepo.DeleteAndReassign(c, categoryID, reassignTo.Int64)
+	} else {
+		deletedCategory, err = s.CategoryRepo.Delete(c, categoryID)
+	}
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode {
+			return model.Category{}, ErrCategoryHasChildren
+		}
+		return model.Category{}, err
+	}
+
+	return deletedCategory, nil
+}
+
+// MergeCategory
+// Moves the posts and the child categories of a category to the target category, then deletes it.
+// @param c context.Context
+// @param sourceID int64
+// @param targetID int64
+// @return model.Category
+func (s *Service) MergeCategory(c context.Context, sourceID int64, targetID int64) (model.Category, error) {
+	if sourceID == targetID {
+		return model.Category{}, ErrInvalidTargetCategory
+	}
+	if _, err := s.CategoryRepo.Get(c, sourceID); err != nil {
+		return model.Category{}, err
+	}
+	if err := s.checkTargetExists(c, targetID); err != nil {
+		return model.Category{}, err
+	}
+
+	return s.CategoryRepo.Merge(c, sourceID, targetID)
+}
+
+// checkTargetExists
+// Returns ErrTargetCategoryNotFound when the category receiving posts does not exist.
+// @param c context.Context
+// @param targetID int64
+// @return error
+func (s *Service) checkTargetExists(c context.Context, targetID int64) error {
+	if _, err := s.CategoryRepo.Get(c, targetID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrTargetCategoryNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// generateUrlKey
+// Returns a free url key generated from the name of a category.
+// @param c context.Context
+// @param name string
+// @param categoryID int64 0 for a new category
+// @return pgtype.Text
+func (s *Service) generateUrlKey(c context.Context, name string, categoryID int64) (pgtype.Text, error) {
+	urlKey, err := s.UrlKeyGenerator.GenerateUrlKey(c, name, urlRewriteModel.UrlRewriteEntity1, categoryID)
+	if err != nil {
+		return pgtype.Text{}, err
+	}
+	return pgtype.Text{String: urlKey, Valid: true}, nil
+}
+
+// buildTree
+// Nests categories ordered parents first under their parents.
+// @param categories []model.Category
+// @param rootID pgtype.Int8
+// @return []*model.CategoryNode
+func buildTree(categories []model.Category, rootID pgtype.Int8) []*model.CategoryNode {
+	roots := make([]*model.CategoryNode, 0)
+	nodes := make(map[int64]*model.CategoryNode, len(categories))
+	for _, c := range categories {
+		node := &model.CategoryNode{Category: c, Children: make([]*model.CategoryNode, 0)}
+		nodes[c.CategoryID] = node
+		isRoot := !c.ParentID.Valid || (rootID.Valid && c.CategoryID == rootID.Int64)
+		if isRoot {
+			roots = append(roots, node)
+			continue
+		}
+		if parent, ok := nodes[c.ParentID.Int64]; ok {
+			parent.Children = append(parent.Children, node)
+		}
+	}
+	return roots
+}
+
+// convertConstraintViolation
+// Converts the violations of the url_key and parent_id constraints to their errors.
+// @param err error
+// @return error
+func convertConstraintViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case uniqueViolationCode:
+			return ErrUrlKeyAlreadyExists
+		case foreignKeyViolationCode:
+			return ErrParentNotFound
+		}
+	}
+	return err
+}
@@ -0,0 +1,243 @@
+package category
+
+import (
+	"context"
+	"testing"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/category"
+	urlRewriteModel "github.com/daniel-vuky/go-blog/internal/models/url_rewrite"
+	"github.com/daniel-vuky/go-blog/pkg/slug"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryCategoryRepository
+// In-memory category repository, the tree rows and the errors of the tree operations are set by the tests
+// since the recursive queries are covered by the storage tests
+type memoryCategoryRepository struct {
+	categories map[int64]model.Category
+	order      []int64
+	treeErr    error
+	deleteErr  error
+}
+
+func (repo *memoryCategoryRepository) Get(_ context.Context, categoryID int64) (model.Category, error) {
+	c, ok := repo.categories[categoryID]
+	if !ok {
+		return model.Category{}, pgx.ErrNoRows
+	}
+	return c, nil
+}
+
+func (repo *memoryCategoryRepository) GetList(context.Context, *model.GetListCategoryParams) ([]model.Category, int64, error) {
+	return nil, 0, nil
+}
+
+// GetTree returns every category in creation order, which is parents first in the tests
+func (repo *memoryCategoryRepository) GetTree(_ context.Context, rootID pgtype.Int8) ([]model.Category, error) {
+	if _, ok := repo.categories[rootID.Int64]; rootID.Valid && !ok {
+		return nil, nil
+	}
+	var tree []model.Category
+	for _, id := range repo.order {
+		tree = append(tree, repo.categories[id])
+	}
+	return tree, nil
+}
+
+func (repo *memoryCategoryRepository) GetBreadcrumbs(_ context.Context, categoryID int64) ([]model.Category, error) {
+	c, ok := repo.categories[categoryID]
+	if !ok {
+		return nil, nil
+	}
+	return []model.Category{c}, nil
+}
+
+func (repo *memoryCategoryRepository) Create(_ context.Context, arg *model.CreateCategoryParams) (model.Category, error) {
+	if _, ok := repo.categories[arg.ParentID.Int64]; arg.ParentID.Valid && !ok {
+		return model.Category{}, &pgconn.PgError{Code: foreignKeyViolationCode}
+	}
+	c := model.Category{CategoryID: int64(len(repo.order) + 1), ParentID: arg.ParentID, Name: arg.Name, UrlKey: arg.UrlKey}
+	repo.categories[c.CategoryID] = c
+	repo.order = append(repo.order, c.CategoryID)
+	return c, nil
+}
+
+func (repo *memoryCategoryRepository) Update(context.Context, *model.UpdateCategoryParams) (model.Category, error) {
+	return model.Category{}, nil
+}
+
+func (repo *memoryCategoryRepository) Move(_ context.Context, categoryID int64, parentID pgtype.Int8) (model.Category, error) {
+	if repo.treeErr != nil {
+		return model.Category{}, repo.treeErr
+	}
+	c := repo.categories[categoryID]
+	c.ParentID = parentID
+	return c, nil
+}
+
+func (repo *memoryCategoryRepository) Delete(_ context.Context, categoryID int64) (model.Category, error) {
+	if repo.deleteErr != nil {
+		return model.Category{}, repo.deleteErr
+	}
+	return repo.categories[categoryID], nil
+}
+
+func (repo *memoryCategoryRepository) DeleteAndReassign(ctx context.Context, categoryID int64, _ int64) (model.Category, error) {
+	return repo.Delete(ctx, categoryID)
+}
+
+func (repo *memoryCategoryRepository) Merge(_ context.Context, sourceID int64, _ int64) (model.Category, error) {
+	if repo.treeErr != nil {
+		return model.Category{}, repo.treeErr
+	}
+	return repo.categories[sourceID], nil
+}
+
+// slugUrlKeyGenerator
+// Url key generator returning the slug of the name without checking collisions
+type slugUrlKeyGenerator struct{}
+
+func (slugUrlKeyGenerator) GenerateUrlKey(_ context.Context, source string, _ urlRewriteModel.UrlRewriteEntity, _ int64) (string, error) {
+	return slug.Make(source), nil
+}
+
+// newTestService
+// Create a service holding the tree News > World > Europe and a second root Sport
+func newTestService(t *testing.T) (*Service, *memoryCategoryRepository) {
+	repo := &memoryCategoryRepository{categories: map[int64]model.Category{}}
+	service := NewService(repo, slugUrlKeyGenerator{})
+	for _, arg := range []model.CreateCategoryParams{
+		{Name: "News"},
+		{Name: "World", ParentID: pgtype.Int8{Int64: 1, Valid: true}},
+		{Name: "Europe", ParentID: pgtype.Int8{Int64: 2, Valid: true}},
+		{Name: "Sport"},
+	} {
+		_, err := service.CreateCategory(context.Background(), &arg)
+		require.NoError(t, err)
+	}
+	return service, repo
+}
+
+// TestService_GetTree test the rows are nested under their parents
+func TestService_GetTree(t *testing.T) {
+	service, _ := newTestService(t)
+
+	tree, err := service.GetTree(context.Background(), pgtype.Int8{})
+	require.NoError(t, err)
+	require.Len(t, tree, 2)
+	require.Equal(t, "News", tree[0].Name)
+	require.Equal(t, "news", tree[0].UrlKey.String)
+	require.Equal(t, "World", tree[0].Children[0].Name)
+	require.Equal(t, "Europe", tree[0].Children[0].Children[0].Name)
+	require.Empty(t, tree[1].Children)
+
+	_, err = service.GetTree(context.Background(), pgtype.Int8{Int64: 99, Valid: true})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+// TestService_GetBreadcrumbs test a missing category is reported
+func TestService_GetBreadcrumbs(t *testing.T) {
+	service, _ := newTestService(t)
+
+	breadcrumbs, err := service.GetBreadcrumbs(context.Background(), 3)
+	require.NoError(t, err)
+	require.Equal(t, "Europe", breadcrumbs[len(breadcrumbs)-1].Name)
+
+	_, err = service.GetBreadcrumbs(context.Background(), 99)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+// TestService_MoveCategory test the category and the parent are checked before moving
+func TestService_MoveCategory(t *testing.T) {
+	service, repo := newTestService(t)
+	parentOf := func(id int64) pgtype.Int8 { return pgtype.Int8{Int64: id, Valid: true} }
+
+	_, err := service.MoveCategory(context.Background(), 1, parentOf(1))
+	require.ErrorIs(t, err, ErrCategoryCycle)
+	_, err = service.MoveCategory(context.Background(), 1, parentOf(99))
+	require.ErrorIs(t, err, ErrParentNotFound)
+	_, err = service.MoveCategory(context.Background(), 99, parentOf(1))
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	moved, err := service.MoveCategory(context.Background(), 2, parentOf(4))
+	require.NoError(t, err)
+	require.Equal(t, parentOf(4), moved.ParentID)
+
+	repo.treeErr = ErrCategoryCycle
+	_, err = service.MoveCategory(context.Background(), 1, parentOf(3))
+	require.ErrorIs(t, err, ErrCategoryCycle)
+}
+
+// TestService_ConstraintViolations test parent and children constraints are reported with their errors
+func TestService_ConstraintViolations(t *testing.T) {
+	service, repo := newTestService(t)
+
+	_, err := service.CreateCategory(context.Background(), &model.CreateCategoryParams{
+		Name:     "Orphan",
+		ParentID: pgtype.Int8{Int64: 99, Valid: true},
+	})
+	require.ErrorIs(t, err, ErrParentNotFound)
+	_, err = service.CreateCategory(context.Background(), &model.CreateCategoryParams{
+		Name:   "Media",
+		UrlKey: pgtype.Text{String: "media", Valid: true},
+	})
+	require.ErrorIs(t, err, ErrUrlKeyReserved)
+	_, err = service.UpdateCategory(context.Background(), &model.UpdateCategoryParams{
+		CategoryID: 1,
+		UrlKey:     pgtype.Text{String: "admin", Valid: true},
+	})
+	require.ErrorIs(t, err, ErrUrlKeyReserved)
+	_, err = service.CreateCategory(context.Background(), &model.CreateCategoryParams{
+		Name:   "News",
+		UrlKey: pgtype.Text{String: "News--Today", Valid: true},
+	})
+	require.ErrorIs(t, err, ErrUrlKeyInvalid)
+	_, err = service.UpdateCategory(context.Background(), &model.UpdateCategoryParams{
+		CategoryID: 1,
+		UrlKey:     pgtype.Text{String: "news/today", Valid: true},
+	})
+	require.ErrorIs(t, err, ErrUrlKeyInvalid)
+
+	repo.deleteErr = &pgconn.PgError{Code: foreignKeyViolationCode}
+	_, err = service.DeleteCategory(context.Background(), 1, pgtype.Int8{})
+	require.ErrorIs(t, err, ErrCategoryHasChildren)
+	_, err = service.DeleteCategory(context.Background(), 1, pgtype.Int8{Int64: 4, Valid: true})
+	require.ErrorIs(t, err, ErrCategoryHasChildren)
+}
+
+// TestService_DeleteCategoryReassign test the reassign target is validated before deleting
+func TestService_DeleteCategoryReassign(t *testing.T) {
+	service, _ := newTestService(t)
+
+	_, err := service.DeleteCategory(context.Background(), 3, pgtype.Int8{Int64: 3, Valid: true})
+	require.ErrorIs(t, err, ErrInvalidTargetCategory)
+	_, err = service.DeleteCategory(context.Background(), 3, pgtype.Int8{Int64: 99, Valid: true})
+	require.ErrorIs(t, err, ErrTargetCategoryNotFound)
+
+	deleted, err := service.DeleteCategory(context.Background(), 3, pgtype.Int8{Int64: 4, Valid: true})
+	require.NoError(t, err)
+	require.Equal(t, "Europe", deleted.Name)
+}
+
+// TestService_MergeCategory test the source and the target are validated before merging
+func TestService_MergeCategory(t *testing.T) {
+	service, repo := newTestService(t)
+
+	_, err := service.MergeCategory(context.Background(), 1, 1)
+	require.ErrorIs(t, err, ErrInvalidTargetCategory)
+	_, err = service.MergeCategory(context.Background(), 99, 1)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	_, err = service.MergeCategory(context.Background(), 1, 99)
+	require.ErrorIs(t, err, ErrTargetCategoryNotFound)
+
+	merged, err := service.MergeCategory(context.Background(), 1, 4)
+	require.NoError(t, err)
+	require.Equal(t, "News", merged.Name)
+
+	repo.treeErr = ErrCategoryCycle
+	_, err = service.MergeCategory(context.Background(), 1, 3)
+	require.ErrorIs(t, err, ErrCategoryCycle)
+}
@@ -0,0 +1,272 @@
+package comment
+
+import (
+	"context"
+	"errors"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/comment"
+	postModel "github.com/daniel-vuky/go-blog/internal/models/post"
+	"github.com/daniel-vuky/go-blog/internal/repository/comment"
+	"github.com/daniel-vuky/go-blog/internal/service/post"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrParentNotFound   = errors.New("parent comment does not exist")
+	ErrParentOtherPost  = errors.New("parent comment belongs to another post")
+	ErrNotCommentOwner  = errors.New("comment belongs to another user")
+	ErrCommentsClosed   = errors.New("comments are closed on this post")
+	ErrInvalidStatus    = errors.New("invalid comment status")
+	ErrCommentsNotFound = errors.New("none of the comments exist")
+)
+
+// Service
+// Manages the threaded comments of the posts.
+type Service struct {
+	CommentRepo comment.Repository
+	PostService *post.Service
+}
+
+// NewService
+// Returns a new instance of Service.
+func NewService(repo comment.Repository, postService *post.Service) *Service {
+	return &Service{CommentRepo: repo, PostService: postService}
+}
+
+// GetComment
+// Returns a comment by id.
+// @param c context.Context
+// @param commentID int64
+// @return model.Comment
+func (s *Service) GetComment(c context.Context, commentID int64) (model.Comment, error) {
+	return s.CommentRepo.Get(c, commentID)
+}
+
+// ListCommentResponse
+// Struct to hold a page of comments, each one with the first replies of its thread.
+type ListCommentResponse struct {
+	Totals   int64                `json:"totals"`
+	Comments []*model.CommentNode `json:"comments"`
+}
+
+// GetCommentTree
+// Returns a page of the top level comments of a published post with their replies nested up to arg.Depth levels.
+// @param c context.Context
+// @param postID int64
+// @param arg *model.GetCommentTreeParams
+// @return ListCommentResponse
+func (s *Service) GetCommentTree(c context.Context, postID int64, arg *model.GetCommentTreeParams) (ListCommentResponse, error) {
+	if _, err := s.PostService.GetPublishedPost(c, postID); err != nil {
+		return ListCommentResponse{}, err
+	}
+	roots, totalRoot, err := s.CommentRepo.GetListRoot(c, postID, arg.PageSize, arg.PageSize*(arg.CurrentPage-1))
+	if err != nil {
+		return ListCommentResponse{}, err
+	}
+	if err = s.loadReplies(c, roots, arg.Depth-1, arg.ReplyPageSize); err != nil {
+		return ListCommentResponse{}, err
+	}
+
+	return ListCommentResponse{Totals: totalRoot, Comments: nonNilNodes(roots)}, nil
+}
+
+// GetReplyTree
+// Returns a page of the direct replies of a comment with their own replies nested up to arg.Depth levels.
+// @param c context.Context
+// @param commentID int64
+// @param arg *model.GetCommentTreeParams
+// @return ListCommentResponse
+func (s *Service) GetReplyTree(c context.Context, commentID int64, arg *model.GetCommentTreeParams) (ListCommentResponse, error) {
+	parent, err := s.CommentRepo.Get(c, commentID)
+	if err != nil {
+		return ListCommentResponse{}, err
+	}
+	if parent.Status != model.CommentStatusApproved {
+		return ListCommentResponse{}, pgx.ErrNoRows
+	}
+	if _, err = s.PostService.GetPublishedPost(c, parent.PostID); err != nil {
+		return ListCommentResponse{}, err
+	}
+	totalReply, err := s.CommentRepo.CountReply(c, commentID)
+	if err != nil {
+		return ListCommentResponse{}, err
+	}
+	replies, err := s.CommentRepo.GetListReply(c, []int64{commentID}, arg.PageSize, arg.PageSize*(arg.CurrentPage-1))
+	if err != nil {
+		return ListCommentResponse{}, err
+	}
+	if err = s.loadReplies(c, replies, arg.Depth-1, arg.ReplyPageSize); err != nil {
+		return ListCommentResponse{}, err
+	}
+
+	return ListCommentResponse{Totals: totalReply, Comments: nonNilNodes(replies)}, nil
+}
+
+// loadReplies
+// Attaches the first replyPageSize replies to every node, level by level, for depth more levels.
+// Each level is loaded with a single query whatever the number of comments it holds.
+// @param c context.Context
+// @param level []*model.CommentNode
+// @param depth int32
+// @param replyPageSize int32
+// @return error
+func (s *Service) loadReplies(c context.Context, level []*model.CommentNode, depth int32, replyPageSize int32) error {
+	for ; depth > 0 && len(level) > 0; depth-- {
+		nodes := make(map[int64]*model.CommentNode, len(level))
+		var parentIDs []int64
+		for _, node := range level {
+			node.Replies = []*model.CommentNode{}
+			nodes[node.CommentID] = node
+			if node.ReplyCount > 0 {
+				parentIDs = append(parentIDs, node.CommentID)
+			}
+		}
+		if len(parentIDs) == 0 {
+			return nil
+		}
+		replies, err := s.CommentRepo.GetListReply(c, parentIDs, replyPageSize, 0)
+		if err != nil {
+			return err
+		}
+		for _, reply := range replies {
+			parent := nodes[reply.ParentID.Int64]
+			parent.Replies = append(parent.Replies, reply)
+		}
+		level = replies
+	}
+	return nil
+}
+
+// CreateComment
+// Creates a comment on a published post, or a reply when an approved parent comment of the same post is given.
+// The comment is published immediately unless the post requires moderation.
+// @param c context.Context
+// @param arg *model.CreateCommentParams
+// @return model.Comment
+func (s *Service) CreateComment(c context.Context, arg *model.CreateCommentParams) (model.Comment, error) {
+	commentedPost, err := s.PostService.GetPublishedPost(c, arg.PostID)
+	if err != nil {
+		return model.Comment{}, err
+	}
+	if commentedPost.CommentMode == postModel.CommentModeClosed {
+		return model.Comment{}, ErrCommentsClosed
+	}
+	if arg.ParentID.Valid {
+		parent, err := s.CommentRepo.Get(c, arg.ParentID.Int64)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return model.Comment{}, ErrParentNotFound
+			}
+			return model.Comment{}, err
+		}
+		if parent.Status != model.CommentStatusApproved {
+			return model.Comment{}, ErrParentNotFound
+		}
+		if parent.PostID != arg.PostID {
+			return model.Comment{}, ErrParentOtherPost
+		}
+	}
+	arg.Status = model.CommentStatusApproved
+	if commentedPost.CommentMode == postModel.CommentModeModerated {
+		arg.Status = model.CommentStatusPending
+	}
+
+	return s.CommentRepo.Create(c, arg)
+}
+
+// UpdateComment
+// Replaces the text of a comment owned by the user.
+// An approved comment goes back to the moderation queue when the post requires moderation.
+// @param c context.Context
+// @param userID int64
+// @param arg *model.UpdateCommentParams
+// @return model.Comment
+func (s *Service) UpdateComment(c context.Context, userID int64, arg *model.UpdateCommentParams) (model.Comment, error) {
+	existingComment, err := s.CommentRepo.Get(c, arg.CommentID)
+	if err != nil {
+		return model.Comment{}, err
+	}
+	if existingComment.UserID != userID {
+		return model.Comment{}, ErrNotCommentOwner
+	}
+	commentedPost, err := s.PostService.GetPublishedPost(c, existingComment.PostID)
+	if err != nil {
+		return model.Comment{}, err
+	}
+	if commentedPost.CommentMode == postModel.CommentModeClosed {
+		return model.Comment{}, ErrCommentsClosed
+	}
+	arg.Status = existingComment.Status
+	if commentedPost.CommentMode == postModel.CommentModeModerated && arg.Status == model.CommentStatusApproved {
+		arg.Status = model.CommentStatusPending
+	}
+
+	return s.CommentRepo.Update(c, arg)
+}
+
+// AdminListCommentResponse
+// Struct to hold the response of the GetListComment method.
+type AdminListCommentResponse struct {
+	Totals   int64           `json:"totals"`
+	Comments []model.Comment `json:"comments"`
+}
+
+// GetListComment
+// Returns a list of comments whatever their status, used by the moderation queue.
+// @param c context.Context
+// @param arg *model.GetListCommentParams
+// @return AdminListCommentResponse
+func (s *Service) GetListComment(c context.Context, arg *model.GetListCommentParams) (AdminListCommentResponse, error) {
+	var rsp AdminListCommentResponse
+	if arg.OrderBy == "" {
+		arg.OrderBy = "created_at"
+	}
+	if arg.OrderDirection == "" {
+		arg.OrderDirection = "desc"
+	}
+	listComment, totalComment, err := s.CommentRepo.GetList(c, arg)
+	if err != nil {
+		return rsp, err
+	}
+	if listComment == nil {
+		listComment = []model.Comment{}
+	}
+	rsp = AdminListCommentResponse{
+		Totals:   totalComment,
+		Comments: listComment,
+	}
+
+	return rsp, nil
+}
+
+// ModerateComments
+// Sets the status of several comments at once and returns the updated comments.
+// @param c context.Context
+// @param commentIDs []int64
+// @param status model.CommentStatus
+// @return []model.Comment
+func (s *Service) ModerateComments(c context.Context, commentIDs []int64, status model.CommentStatus) ([]model.Comment, error) {
+	if !status.IsValid() {
+		return nil, ErrInvalidStatus
+	}
+	moderatedComments, err := s.CommentRepo.UpdateStatus(c, commentIDs, status)
+	if err != nil {
+		return nil, err
+	}
+	if len(moderatedComments) == 0 {
+		return nil, ErrCommentsNotFound
+	}
+
+	return moderatedComments, nil
+}
+
+// nonNilNodes
+// Returns an empty slice instead of nil so the listing is encoded as an empty array.
+// @param nodes []*model.CommentNode
+// @return []*model.CommentNode
+func nonNilNodes(nodes []*model.CommentNode) []*model.CommentNode {
+	if nodes == nil {
+		return []*model.CommentNode{}
+	}
+	return nodes
+}
@@ -0,0 +1,374 @@
+package comment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/comment"
+	postModel "github.com/daniel-vuky/go-blog/internal/models/post"
+	"github.com/daniel-vuky/go-blog/internal/service/post"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryPostRepository
+// In-memory post repository knowing only the comment mode and the status of the existing posts
+type memoryPostRepository struct {
+	commentModes map[int64]postModel.CommentMode
+	drafts       map[int64]bool
+}
+
+func (repo *memoryPostRepository) Get(_ context.Context, postID int64) (postModel.Post, error) {
+	commentMode, ok := repo.commentModes[postID]
+	if !ok {
+		return postModel.Post{}, pgx.ErrNoRows
+	}
+	status := postModel.PostStatusPublished
+	if repo.drafts[postID] {
+		status = postModel.PostStatusDraft
+	}
+	return postModel.Post{PostID: postID, CommentMode: commentMode, Status: status}, nil
+}
+
+func (repo *memoryPostRepository) GetList(context.Context, *postModel.GetListPostParams) ([]postModel.Post, int64, error) {
+	return nil, 0, nil
+}
+
+func (repo *memoryPostRepository) GetRevision(context.Context, int64, int64) (postModel.PostRevision, error) {
+	return postModel.PostRevision{}, pgx.ErrNoRows
+}
+
+func (repo *memoryPostRepository) GetListRevision(context.Context, *postModel.GetListPostRevisionParams) ([]postModel.PostRevision, int64, error) {
+	return nil, 0, nil
+}
+
+func (repo *memoryPostRepository) Create(context.Context, *postModel.CreatePostParams) (postModel.Post, error) {
+	return postModel.Post{}, nil
+}
+
+func (repo *memoryPostRepository) Update(context.Context, *postModel.UpdatePostParams) (postModel.Post, error) {
+	return postModel.Post{}, nil
+}
+
+func (repo *memoryPostRepository) Delete(context.Context, int64) (postModel.Post, error) {
+	return postModel.Post{}, nil
+}
+
+func (repo *memoryPostRepository) UpdateStatus(context.Context, *postModel.UpdatePostStatusParams) (postModel.Post, error) {
+	return postModel.Post{}, nil
+}
+
+func (repo *memoryPostRepository) PublishScheduled(context.Context, time.Time) ([]postModel.Post, error) {
+	return nil, nil
+}
+
+func (repo *memoryPostRepository) RestoreRevision(context.Context, int64, int64, int64) (postModel.Post, error) {
+	return postModel.Post{}, nil
+}
+
+// memoryCommentRepository
+// In-memory comment repository keeping the comments in creation order
+type memoryCommentRepository struct {
+	comments []model.Comment
+}
+
+func (repo *memoryCommentRepository) Get(_ context.Context, commentID int64) (model.Comment, error) {
+	for _, c := range repo.comments {
+		if c.CommentID == commentID {
+			return c, nil
+		}
+	}
+	return model.Comment{}, pgx.ErrNoRows
+}
+
+func (repo *memoryCommentRepository) node(c model.Comment) *model.CommentNode {
+	count, _ := repo.CountReply(context.Background(), c.CommentID)
+	return &model.CommentNode{Comment: c, ReplyCount: count}
+}
+
+func (repo *memoryCommentRepository) GetListRoot(_ context.Context, postID int64, limit int32, offset int32) ([]*model.CommentNode, int64, error) {
+	var roots []*model.CommentNode
+	for _, c := range repo.comments {
+		if c.PostID == postID && !c.ParentID.Valid && c.Status == model.CommentStatusApproved {
+			roots = append(roots, repo.node(c))
+		}
+	}
+	total := int64(len(roots))
+	return page(roots, limit, offset), total, nil
+}
+
+func (repo *memoryCommentRepository) GetListReply(_ context.Context, parentIDs []int64, limit int32, offset int32) ([]*model.CommentNode, error) {
+	var items []*model.CommentNode
+	for _, parentID := range parentIDs {
+		var replies []*model.CommentNode
+		for _, c := range repo.comments {
+			if c.ParentID.Valid && c.ParentID.Int64 == parentID && c.Status == model.CommentStatusApproved {
+				replies = append(replies, repo.node(c))
+			}
+		}
+		items = append(items, page(replies, limit, offset)...)
+	}
+	return items, nil
+}
+
+func (repo *memoryCommentRepository) CountReply(_ context.Context, commentID int64) (int64, error) {
+	var count int64
+	for _, c := range repo.comments {
+		if c.ParentID.Valid && c.ParentID.Int64 == commentID && c.Status == model.CommentStatusApproved {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (repo *memoryCommentRepository) Create(_ context.Context, arg *model.CreateCommentParams) (model.Comment, error) {
+	c := model.Comment{
+		CommentID: int64(len(repo.comments) + 1),
+		PostID:    arg.PostID,
+		UserID:    arg.UserID,
+		ParentID:  arg.ParentID,
+		Comment:   arg.Comment,
+		Status:    arg.Status,
+	}
+	repo.comments = append(repo.comments, c)
+	return c, nil
+}
+
+func (repo *memoryCommentRepository) Update(_ context.Context, arg *model.UpdateCommentParams) (model.Comment, error) {
+	for i, c := range repo.comments {
+		if c.CommentID == arg.CommentID {
+			repo.comments[i].Comment = arg.Comment
+			repo.comments[i].Status = arg.Status
+			return repo.comments[i], nil
+		}
+	}
+	return model.Comment{}, pgx.ErrNoRows
+}
+
+func (repo *memoryCommentRepository) GetList(_ context.Context, arg *model.GetListCommentParams) ([]model.Comment, int64, error) {
+	var items []model.Comment
+	for _, c := range repo.comments {
+		if !arg.Filter.Status.Valid || string(c.Status) == arg.Filter.Status.String {
+			items = append(items, c)
+		}
+	}
+	return items, int64(len(items)), nil
+}
+
+func (repo *memoryCommentRepository) UpdateStatus(_ context.Context, commentIDs []int64, status model.CommentStatus) ([]model.Comment, error) {
+	var items []model.Comment
+	for i, c := range repo.comments {
+		for _, commentID := range commentIDs {
+			if c.CommentID == commentID {
+				repo.comments[i].Status = status
+				items = append(items, repo.comments[i])
+				break
+			}
+		}
+	}
+	return items, nil
+}
+
+// page
+// Return the nodes between offset and offset + limit
+func page(nodes []*model.CommentNode, limit int32, offset int32) []*model.CommentNode {
+	if int(offset) >= len(nodes) {
+		return nil
+	}
+	end := int(offset + limit)
+	if end > len(nodes) {
+		end = len(nodes)
+	}
+	return nodes[offset:end]
+}
+
+// newTestService
+// Create a service holding the open posts 1 and 2, the moderated post 3, the closed post 4
+// and the open draft post 5
+func newTestService() *Service {
+	postService := post.NewService(&memoryPostRepository{
+		commentModes: map[int64]postModel.CommentMode{
+			1: postModel.CommentModeOpen,
+			2: postModel.CommentModeOpen,
+			3: postModel.CommentModeModerated,
+			4: postModel.CommentModeClosed,
+			5: postModel.CommentModeOpen,
+		},
+		drafts: map[int64]bool{5: true},
+	}, nil)
+	return NewService(&memoryCommentRepository{}, postService)
+}
+
+// createComment
+// Create a comment of the user 1 on the post 1 and return its id
+func createComment(t *testing.T, service *Service, parentID int64) int64 {
+	created, err := service.CreateComment(context.Background(), &model.CreateCommentParams{
+		PostID:   1,
+		UserID:   1,
+		ParentID: pgtype.Int8{Int64: parentID, Valid: parentID > 0},
+		Comment:  "comment",
+	})
+	require.NoError(t, err)
+	return created.CommentID
+}
+
+// TestService_CreateComment test replies must target an existing comment of the same post
+func TestService_CreateComment(t *testing.T) {
+	service := newTestService()
+	rootID := createComment(t, service, 0)
+	createComment(t, service, rootID)
+
+	_, err := service.CreateComment(context.Background(), &model.CreateCommentParams{PostID: 99, UserID: 1, Comment: "x"})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	_, err = service.CreateComment(context.Background(), &model.CreateCommentParams{
+		PostID: 1, UserID: 1, Comment: "x", ParentID: pgtype.Int8{Int64: 99, Valid: true},
+	})
+	require.ErrorIs(t, err, ErrParentNotFound)
+	_, err = service.CreateComment(context.Background(), &model.CreateCommentParams{
+		PostID: 2, UserID: 1, Comment: "x", ParentID: pgtype.Int8{Int64: rootID, Valid: true},
+	})
+	require.ErrorIs(t, err, ErrParentOtherPost)
+}
+
+// TestService_UpdateComment test only the owner can edit a comment
+func TestService_UpdateComment(t *testing.T) {
+	service := newTestService()
+	commentID := createComment(t, service, 0)
+
+	_, err := service.UpdateComment(context.Background(), 2, &model.UpdateCommentParams{CommentID: commentID, Comment: "edited"})
+	require.ErrorIs(t, err, ErrNotCommentOwner)
+	_, err = service.UpdateComment(context.Background(), 1, &model.UpdateCommentParams{CommentID: 99, Comment: "edited"})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	updated, err := service.UpdateComment(context.Background(), 1, &model.UpdateCommentParams{CommentID: commentID, Comment: "edited"})
+	require.NoError(t, err)
+	require.Equal(t, "edited", updated.Comment)
+}
+
+// TestService_GetCommentTree test the depth and the reply pages of the nested tree
+func TestService_GetCommentTree(t *testing.T) {
+	service := newTestService()
+	first := createComment(t, service, 0)
+	createComment(t, service, 0)
+	reply := createComment(t, service, first)
+	createComment(t, service, first)
+	createComment(t, service, first)
+	nested := createComment(t, service, reply)
+	createComment(t, service, nested)
+
+	tree, err := service.GetCommentTree(context.Background(), 1, &model.GetCommentTreeParams{
+		Depth: 3, PageSize: 10, CurrentPage: 1, ReplyPageSize: 2,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), tree.Totals)
+	require.Len(t, tree.Comments, 2)
+	require.Equal(t, int64(3), tree.Comments[0].ReplyCount)
+	require.Len(t, tree.Comments[0].Replies, 2)
+	require.Empty(t, tree.Comments[1].Replies)
+	require.Equal(t, reply, tree.Comments[0].Replies[0].CommentID)
+	require.Equal(t, nested, tree.Comments[0].Replies[0].Replies[0].CommentID)
+	require.Equal(t, int64(1), tree.Comments[0].Replies[0].Replies[0].ReplyCount)
+	require.Nil(t, tree.Comments[0].Replies[0].Replies[0].Replies)
+
+	flat, err := service.GetCommentTree(context.Background(), 1, &model.GetCommentTreeParams{
+		Depth: 1, PageSize: 1, CurrentPage: 2, ReplyPageSize: 2,
+	})
+	require.NoError(t, err)
+	require.Len(t, flat.Comments, 1)
+	require.Nil(t, flat.Comments[0].Replies)
+
+	_, err = service.GetCommentTree(context.Background(), 99, &model.GetCommentTreeParams{Depth: 1, PageSize: 1, CurrentPage: 1})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+// TestService_GetReplyTree test the replies of a comment are paginated
+func TestService_GetReplyTree(t *testing.T) {
+	service := newTestService()
+	first := createComment(t, service, 0)
+	createComment(t, service, first)
+	createComment(t, service, first)
+	last := createComment(t, service, first)
+
+	replies, err := service.GetReplyTree(context.Background(), first, &model.GetCommentTreeParams{
+		Depth: 1, PageSize: 2, CurrentPage: 2, ReplyPageSize: 2,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), replies.Totals)
+	require.Len(t, replies.Comments, 1)
+	require.Equal(t, last, replies.Comments[0].CommentID)
+
+	_, err = service.GetReplyTree(context.Background(), 99, &model.GetCommentTreeParams{Depth: 1, PageSize: 1, CurrentPage: 1})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+// TestService_CommentModes test moderated posts queue the comments and closed posts refuse them
+func TestService_CommentModes(t *testing.T) {
+	service := newTestService()
+
+	open, err := service.CreateComment(context.Background(), &model.CreateCommentParams{PostID: 1, UserID: 1, Comment: "x"})
+	require.NoError(t, err)
+	require.Equal(t, model.CommentStatusApproved, open.Status)
+
+	moderated, err := service.CreateComment(context.Background(), &model.CreateCommentParams{PostID: 3, UserID: 1, Comment: "x"})
+	require.NoError(t, err)
+	require.Equal(t, model.CommentStatusPending, moderated.Status)
+
+	_, err = service.CreateComment(context.Background(), &model.CreateCommentParams{PostID: 4, UserID: 1, Comment: "x"})
+	require.ErrorIs(t, err, ErrCommentsClosed)
+
+	_, err = service.CreateComment(context.Background(), &model.CreateCommentParams{
+		PostID: 3, UserID: 1, Comment: "x", ParentID: pgtype.Int8{Int64: moderated.CommentID, Valid: true},
+	})
+	require.ErrorIs(t, err, ErrParentNotFound)
+
+	tree, err := service.GetCommentTree(context.Background(), 3, &model.GetCommentTreeParams{Depth: 1, PageSize: 10, CurrentPage: 1})
+	require.NoError(t, err)
+	require.Empty(t, tree.Comments)
+	_, err = service.GetReplyTree(context.Background(), moderated.CommentID, &model.GetCommentTreeParams{Depth: 1, PageSize: 10, CurrentPage: 1})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+// TestService_ModerateComments test the approved comments become visible and edits are moderated again
+func TestService_ModerateComments(t *testing.T) {
+	service := newTestService()
+	pending, err := service.CreateComment(context.Background(), &model.CreateCommentParams{PostID: 3, UserID: 1, Comment: "x"})
+	require.NoError(t, err)
+
+	queue, err := service.GetListComment(context.Background(), &model.GetListCommentParams{
+		Filter:      &model.GetListCommentFilterParams{Status: pgtype.Text{String: "pending", Valid: true}},
+		PageSize:    10,
+		CurrentPage: 1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), queue.Totals)
+
+	_, err = service.ModerateComments(context.Background(), []int64{pending.CommentID}, "deleted")
+	require.ErrorIs(t, err, ErrInvalidStatus)
+	_, err = service.ModerateComments(context.Background(), []int64{99}, model.CommentStatusApproved)
+	require.ErrorIs(t, err, ErrCommentsNotFound)
+
+	approved, err := service.ModerateComments(context.Background(), []int64{pending.CommentID, 99}, model.CommentStatusApproved)
+	require.NoError(t, err)
+	require.Len(t, approved, 1)
+	require.Equal(t, model.CommentStatusApproved, approved[0].Status)
+
+	tree, err := service.GetCommentTree(context.Background(), 3, &model.GetCommentTreeParams{Depth: 1, PageSize: 10, CurrentPage: 1})
+	require.NoError(t, err)
+	require.Len(t, tree.Comments, 1)
+
+	edited, err := service.UpdateComment(context.Background(), 1, &model.UpdateCommentParams{CommentID: pending.CommentID, Comment: "edited"})
+	require.NoError(t, err)
+	require.Equal(t, model.CommentStatusPending, edited.Status)
+}
+
+// TestService_UnpublishedPost test the comments of a post which is not published are hidden
+func TestService_UnpublishedPost(t *testing.T) {
+	service := newTestService()
+
+	_, err := service.CreateComment(context.Background(), &model.CreateCommentParams{PostID: 5, UserID: 1, Comment: "x"})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	_, err = service.GetCommentTree(context.Background(), 5, &model.GetCommentTreeParams{Depth: 1, PageSize: 1, CurrentPage: 1})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
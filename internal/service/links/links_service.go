@@ -0,0 +1,165 @@
+package links
+
+import (
+	"context"
+	"errors"
+
+	blogModel "github.com/daniel-vuky/go-blog/internal/models/blog"
+	categoryModel "github.com/daniel-vuky/go-blog/internal/models/category"
+	model "github.com/daniel-vuky/go-blog/internal/models/links"
+	"github.com/daniel-vuky/go-blog/internal/repository/links"
+	"github.com/daniel-vuky/go-blog/internal/service/blog"
+	"github.com/daniel-vuky/go-blog/internal/service/category"
+	"github.com/daniel-vuky/go-blog/internal/service/post"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// foreignKeyViolationCode
+// Postgres error code raised when a foreign key constraint is violated
+const foreignKeyViolationCode = "23503"
+
+var ErrCategoryNotFound = errors.New("one or more categories do not exist")
+
+// Service
+// Assigns posts to categories through the post_links table.
+type Service struct {
+	LinksRepo       links.Repository
+	PostService     *post.Service
+	CategoryService *category.Service
+	BlogService     *blog.Service
+}
+
+// NewService
+// Returns a new instance of Service.
+func NewService(
+	repo links.Repository,
+	postService *post.Service,
+	categoryService *category.Service,
+	blogService *blog.Service,
+) *Service {
+	return &Service{
+		LinksRepo:       repo,
+		PostService:     postService,
+		CategoryService: categoryService,
+		BlogService:     blogService,
+	}
+}
+
+// GetPostCategories
+// Returns the categories a post is assigned to.
+// @param c context.Context
+// @param postID int64
+// @return []categoryModel.Category
+func (s *Service) GetPostCategories(c context.Context, postID int64) ([]categoryModel.Category, error) {
+	if _, err := s.PostService.GetPost(c, postID); err != nil {
+		return nil, err
+	}
+	categories, err := s.LinksRepo.GetPostCategories(c, postID)
+	if err != nil {
+		return nil, err
+	}
+	if categories == nil {
+		categories = []categoryModel.Category{}
+	}
+
+	return categories, nil
+}
+
+// SetPostCategories
+// Replaces every category of a post and returns the new categories.
+// @param c context.Context
+// @param postID int64
+// @param categoryIDs []int64
+// @return []categoryModel.Category
+func (s *Service) SetPostCategories(c context.Context, postID int64, categoryIDs []int64) ([]categoryModel.Category, error) {
+	if _, err := s.PostService.GetPost(c, postID); err != nil {
+		return nil, err
+	}
+	if err := s.LinksRepo.SetPostCategories(c, postID, uniqueIDs(categoryIDs)); err != nil {
+		return nil, convertForeignKeyViolation(err)
+	}
+
+	return s.GetPostCategories(c, postID)
+}
+
+// AddPostCategories
+// Assigns a post to more categories and returns all its categories.
+// @param c context.Context
+// @param postID int64
+// @param categoryIDs []int64
+// @return []categoryModel.Category
+func (s *Service) AddPostCategories(c context.Context, postID int64, categoryIDs []int64) ([]categoryModel.Category, error) {
+	if _, err := s.PostService.GetPost(c, postID); err != nil {
+		return nil, err
+	}
+	if err := s.LinksRepo.AddPostCategories(c, postID, uniqueIDs(categoryIDs)); err != nil {
+		return nil, convertForeignKeyViolation(err)
+	}
+
+	return s.GetPostCategories(c, postID)
+}
+
+// RemovePostCategories
+// Removes a post from categories and returns the categories it keeps.
+// @param c context.Context
+// @param postID int64
+// @param categoryIDs []int64
+// @return []categoryModel.Category
+func (s *Service) RemovePostCategories(c context.Context, postID int64, categoryIDs []int64) ([]categoryModel.Category, error) {
+	if _, err := s.PostService.GetPost(c, postID); err != nil {
+		return nil, err
+	}
+	if err := s.LinksRepo.RemovePostCategories(c, postID, uniqueIDs(categoryIDs)); err != nil {
+		return nil, err
+	}
+
+	return s.GetPostCategories(c, postID)
+}
+
+// GetListCategoryPost
+// Returns the published posts of a category as listed by the public blog,
+// optionally including the posts of its descendant categories.
+// @param c context.Context
+// @param arg *model.GetListCategoryPostParams
+// @return blog.ListPostResponse
+func (s *Service) GetListCategoryPost(c context.Context, arg *model.GetListCategoryPostParams) (blog.ListPostResponse, error) {
+	if _, err := s.CategoryService.GetCategory(c, arg.CategoryID); err != nil {
+		return blog.ListPostResponse{}, err
+	}
+
+	return s.BlogService.GetListPost(c, &blogModel.GetListPostParams{
+		CategoryID:         pgtype.Int8{Int64: arg.CategoryID, Valid: true},
+		IncludeDescendants: arg.IncludeDescendants,
+		PageSize:           arg.PageSize,
+		CurrentPage:        arg.CurrentPage,
+	})
+}
+
+// uniqueIDs
+// Removes the duplicated ids keeping their first position.
+// @param ids []int64
+// @return []int64
+func uniqueIDs(ids []int64) []int64 {
+	seen := make(map[int64]bool, len(ids))
+	unique := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// convertForeignKeyViolation
+// Converts the violation of the category_id foreign key to ErrCategoryNotFound.
+// @param err error
+// @return error
+func convertForeignKeyViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode {
+		return ErrCategoryNotFound
+	}
+	return err
+}
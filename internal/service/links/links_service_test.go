@@ -0,0 +1,26 @@
+package links
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUniqueIDs test duplicated ids are removed keeping the first position
+func TestUniqueIDs(t *testing.T) {
+	require.Equal(t, []int64{3, 1, 2}, uniqueIDs([]int64{3, 1, 3, 2, 1}))
+	require.Equal(t, []int64{}, uniqueIDs(nil))
+}
+
+// TestConvertForeignKeyViolation test only the foreign key violation is converted
+func TestConvertForeignKeyViolation(t *testing.T) {
+	require.ErrorIs(t, convertForeignKeyViolation(&pgconn.PgError{Code: foreignKeyViolationCode}), ErrCategoryNotFound)
+
+	uniqueViolation := &pgconn.PgError{Code: "23505"}
+	require.Equal(t, uniqueViolation, convertForeignKeyViolation(uniqueViolation))
+
+	other := errors.New("connection lost")
+	require.Equal(t, other, convertForeignKeyViolation(other))
+}
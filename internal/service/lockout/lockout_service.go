@@ -0,0 +1,211 @@
+package lockout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/admin"
+	auditModel "github.com/daniel-vuky/go-blog/internal/models/audit"
+	"github.com/daniel-vuky/go-blog/internal/repository/lockout"
+	"github.com/daniel-vuky/go-blog/internal/service/audit"
+	"github.com/daniel-vuky/go-blog/pkg/config"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrClientIpLocked = errors.New("too many failed logins, try again later")
+
+// maxLockDuration is the longest lock, also when no max duration is configured
+const maxLockDuration = 30 * 24 * time.Hour
+
+// Service
+// Tracks failed logins per admin and per client ip and locks them out.
+type Service struct {
+	LockoutRepo  lockout.Repository
+	AuditService *audit.Service
+	config       *config.Lockout
+}
+
+// NewService
+// Returns a new instance of Service.
+func NewService(repo lockout.Repository, auditService *audit.Service, cfg *config.Lockout) *Service {
+	return &Service{
+		LockoutRepo:  repo,
+		AuditService: auditService,
+		config:       cfg,
+	}
+}
+
+// CheckClientIp
+// Returns ErrClientIpLocked while the client ip is locked.
+// @param c context.Context
+// @param clientIp string
+// @return error
+func (s *Service) CheckClientIp(c context.Context, clientIp string) error {
+	lockExpires, err := s.LockoutRepo.GetClientIpLockExpires(c, clientIp)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	if lockExpires.Valid && lockExpires.Time.After(time.Now()) {
+		return ErrClientIpLocked
+	}
+	return nil
+}
+
+// RegisterFailedLogin
+// Counts a failed login and locks the admin or the client ip once its threshold is reached.
+// @param c context.Context
+// @param email string
+// @param clientIp string
+// @return error
+func (s *Service) RegisterFailedLogin(c context.Context, email string, clientIp string) error {
+	if err := s.registerAdminFailure(c, email, clientIp); err != nil {
+		return err
+	}
+	return s.registerClientIpFailure(c, clientIp)
+}
+
+// RegisterFailedClientLogin
+// Counts a failed login of a site user against the client ip only.
+// @param c context.Context
+// @param clientIp string
+// @return error
+func (s *Service) RegisterFailedClientLogin(c context.Context, clientIp string) error {
+	return s.registerClientIpFailure(c, clientIp)
+}
+
+// RegisterSuccessfulLogin
+// Clears the failed login counters of the admin and the client ip.
+// @param c context.Context
+// @param adminID int32
+// @param clientIp string
+// @return error
+func (s *Service) RegisterSuccessfulLogin(c context.Context, adminID int32, clientIp string) error {
+	if err := s.LockoutRepo.ResetAdminFailedLogin(c, adminID); err != nil {
+		return err
+	}
+	return s.LockoutRepo.ResetClientIpFailedLogin(c, clientIp)
+}
+
+// UnlockAdmin
+// Removes the lock of an admin and records who unlocked it.
+// @param c context.Context
+// @param email string
+// @param actorID int32
+// @param clientIp string
+// @return model.Admin
+func (s *Service) UnlockAdmin(c context.Context, email string, actorID int32, clientIp string) (model.Admin, error) {
+	unlockedAdmin, err := s.LockoutRepo.UnlockAdmin(c, email)
+	if err != nil {
+		return unlockedAdmin, err
+	}
+	_, err = s.AuditService.Record(c, &auditModel.CreateAuditLogParams{
+		AdminID:  pgtype.Int8{Int64: int64(unlockedAdmin.AdminID), Valid: true},
+		ActorID:  pgtype.Int8{Int64: int64(actorID), Valid: true},
+		Action:   audit.ActionAdminUnlocked,
+		ClientIp: pgtype.Text{String: clientIp, Valid: clientIp != ""},
+	})
+	return unlockedAdmin, err
+}
+
+// registerAdminFailure
+// Counts a failed login of the admin and locks it once the threshold is reached.
+// @param c context.Context
+// @param email string
+// @param clientIp string
+// @return error
+func (s *Service) registerAdminFailure(c context.Context, email string, clientIp string) error {
+	if s.config.Threshold <= 0 {
+		return nil
+	}
+	counter, err := s.LockoutRepo.IncrementAdminFailedLogin(c, email)
+	if err != nil {
+		// Unknown email or admin already locked
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	if int(counter.FailedLoginCount) < s.config.Threshold {
+		return nil
+	}
+	lockExpires := time.Now().Add(lockDuration(s.config.BaseDuration, s.config.MaxDuration, counter.LockCount))
+	if err = s.LockoutRepo.LockAdmin(c, counter.AdminID, lockExpires); err != nil {
+		return err
+	}
+	_, err = s.AuditService.Record(c, &auditModel.CreateAuditLogParams{
+		AdminID: pgtype.Int8{Int64: int64(counter.AdminID), Valid: true},
+		Action:  audit.ActionAdminLocked,
+		Detail: pgtype.Text{
+			String: fmt.Sprintf(
+				"locked until %s after %d failed logins",
+				lockExpires.Format(time.RFC3339),
+				counter.FailedLoginCount,
+			),
+			Valid: true,
+		},
+		ClientIp: pgtype.Text{String: clientIp, Valid: clientIp != ""},
+	})
+	return err
+}
+
+// registerClientIpFailure
+// Counts a failed login from the client ip and locks it once the threshold is reached.
+// @param c context.Context
+// @param clientIp string
+// @return error
+func (s *Service) registerClientIpFailure(c context.Context, clientIp string) error {
+	if s.config.IpThreshold <= 0 || clientIp == "" {
+		return nil
+	}
+	counter, err := s.LockoutRepo.IncrementClientIpFailedLogin(c, clientIp, s.config.IpWindow)
+	if err != nil {
+		return err
+	}
+	if int(counter.FailedCount) < s.config.IpThreshold {
+		return nil
+	}
+	lockExpires := time.Now().Add(lockDuration(s.config.BaseDuration, s.config.MaxDuration, counter.LockCount))
+	if err = s.LockoutRepo.LockClientIp(c, clientIp, lockExpires); err != nil {
+		return err
+	}
+	_, err = s.AuditService.Record(c, &auditModel.CreateAuditLogParams{
+		Action: audit.ActionClientIpLocked,
+		Detail: pgtype.Text{
+			String: fmt.Sprintf(
+				"locked until %s after %d failed logins",
+				lockExpires.Format(time.RFC3339),
+				counter.FailedCount,
+			),
+			Valid: true,
+		},
+		ClientIp: pgtype.Text{String: clientIp, Valid: true},
+	})
+	return err
+}
+
+// lockDuration
+// Doubles the base duration for every previous lock, capped at the max duration
+// and never longer than maxLockDuration.
+// @param base time.Duration
+// @param max time.Duration
+// @param previousLocks int32
+// @return time.Duration
+func lockDuration(base time.Duration, max time.Duration, previousLocks int32) time.Duration {
+	if max <= 0 || max > maxLockDuration {
+		max = maxLockDuration
+	}
+	duration := base
+	for i := int32(0); i < previousLocks && duration < max; i++ {
+		duration *= 2
+	}
+	if duration > max {
+		return max
+	}
+	return duration
+}
@@ -0,0 +1,299 @@
+package lockout
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/admin"
+	auditModel "github.com/daniel-vuky/go-blog/internal/models/audit"
+	"github.com/daniel-vuky/go-blog/internal/service/audit"
+	"github.com/daniel-vuky/go-blog/pkg/config"
+	goRandom "github.com/daniel-vuky/go-random"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLockDuration test the exponential growth of lock durations
+func TestLockDuration(t *testing.T) {
+	testCases := []struct {
+		name          string
+		previousLocks int32
+		expected      time.Duration
+	}{
+		{"FirstLock", 0, time.Minute},
+		{"SecondLock", 1, 2 * time.Minute},
+		{"FourthLock", 3, 8 * time.Minute},
+		{"Capped", 10, time.Hour},
+		{"CappedOverflow", 100, time.Hour},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, lockDuration(time.Minute, time.Hour, tc.previousLocks))
+		})
+	}
+}
+
+// TestLockDuration_NoMax test the lock duration stays positive and bounded without a max duration
+func TestLockDuration_NoMax(t *testing.T) {
+	testCases := []struct {
+		name          string
+		max           time.Duration
+		previousLocks int32
+		expected      time.Duration
+	}{
+		{"Uncapped", 0, 3, 8 * time.Minute},
+		{"Ceiling", 0, 100, maxLockDuration},
+		{"MaxInt32", 0, math.MaxInt32, maxLockDuration},
+		{"MaxAboveCeiling", 365 * 24 * time.Hour, 100, maxLockDuration},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, lockDuration(time.Minute, tc.max, tc.previousLocks))
+		})
+	}
+}
+
+// memoryAdmin
+// Admin row with its failed login counters
+type memoryAdmin struct {
+	admin        model.Admin
+	failedLogins int32
+	lockCount    int32
+}
+
+// memoryClientIp
+// Failed login counters of a client ip
+type memoryClientIp struct {
+	failedLogins int32
+	lockCount    int32
+	lockExpires  pgtype.Timestamptz
+}
+
+// memoryLockoutRepository
+// In-memory lockout repository
+type memoryLockoutRepository struct {
+	admins    map[string]*memoryAdmin
+	clientIps map[string]*memoryClientIp
+}
+
+func (repo *memoryLockoutRepository) GetClientIpLockExpires(_ context.Context, clientIp string) (pgtype.Timestamptz, error) {
+	if ip, ok := repo.clientIps[clientIp]; ok {
+		return ip.lockExpires, nil
+	}
+	return pgtype.Timestamptz{}, pgx.ErrNoRows
+}
+
+func (repo *memoryLockoutRepository) IncrementAdminFailedLogin(_ context.Context, email string) (model.LoginFailureCounter, error) {
+	a, ok := repo.admins[email]
+	if !ok || (a.admin.LockExpires.Valid && a.admin.LockExpires.Time.After(time.Now())) {
+		return model.LoginFailureCounter{}, pgx.ErrNoRows
+	}
+	a.failedLogins++
+	return model.LoginFailureCounter{
+		AdminID:          a.admin.AdminID,
+		FailedLoginCount: a.failedLogins,
+		LockCount:        a.lockCount,
+	}, nil
+}
+
+func (repo *memoryLockoutRepository) LockAdmin(_ context.Context, adminID int32, lockExpires time.Time) error {
+	for _, a := range repo.admins {
+		if a.admin.AdminID == adminID {
+			a.admin.LockExpires = pgtype.Timestamptz{Time: lockExpires, Valid: true}
+			a.failedLogins = 0
+			a.lockCount++
+			return nil
+		}
+	}
+	return pgx.ErrNoRows
+}
+
+func (repo *memoryLockoutRepository) ResetAdminFailedLogin(_ context.Context, adminID int32) error {
+	for _, a := range repo.admins {
+		if a.admin.AdminID == adminID {
+			a.failedLogins = 0
+			a.lockCount = 0
+		}
+	}
+	return nil
+}
+
+func (repo *memoryLockoutRepository) UnlockAdmin(_ context.Context, email string) (model.Admin, error) {
+	a, ok := repo.admins[email]
+	if !ok {
+		return model.Admin{}, pgx.ErrNoRows
+	}
+	a.admin.LockExpires = pgtype.Timestamptz{}
+	a.failedLogins = 0
+	a.lockCount = 0
+	return a.admin, nil
+}
+
+func (repo *memoryLockoutRepository) IncrementClientIpFailedLogin(_ context.Context, clientIp string, _ time.Duration) (model.ClientIpFailureCounter, error) {
+	ip, ok := repo.clientIps[clientIp]
+	if !ok {
+		ip = &memoryClientIp{}
+		repo.clientIps[clientIp] = ip
+	}
+	ip.failedLogins++
+	return model.ClientIpFailureCounter{FailedCount: ip.failedLogins, LockCount: ip.lockCount}, nil
+}
+
+func (repo *memoryLockoutRepository) LockClientIp(_ context.Context, clientIp string, lockExpires time.Time) error {
+	ip, ok := repo.clientIps[clientIp]
+	if !ok {
+		return pgx.ErrNoRows
+	}
+	ip.lockExpires = pgtype.Timestamptz{Time: lockExpires, Valid: true}
+	ip.failedLogins = 0
+	ip.lockCount++
+	return nil
+}
+
+func (repo *memoryLockoutRepository) ResetClientIpFailedLogin(_ context.Context, clientIp string) error {
+	delete(repo.clientIps, clientIp)
+	return nil
+}
+
+// memoryAuditRepository
+// In-memory audit log repository
+type memoryAuditRepository struct {
+	logs []auditModel.AuditLog
+}
+
+func (repo *memoryAuditRepository) Create(_ context.Context, arg *auditModel.CreateAuditLogParams) (auditModel.AuditLog, error) {
+	log := auditModel.AuditLog{
+		AuditID:   int64(len(repo.logs) + 1),
+		AdminID:   arg.AdminID,
+		ActorID:   arg.ActorID,
+		Action:    arg.Action,
+		Detail:    arg.Detail,
+		ClientIp:  arg.ClientIp,
+		CreatedAt: time.Now(),
+	}
+	repo.logs = append(repo.logs, log)
+	return log, nil
+}
+
+// newTestService
+// Returns a service locking after three failed logins per admin and five per client ip.
+func newTestService(admins ...model.Admin) (*Service, *memoryLockoutRepository, *memoryAuditRepository) {
+	lockoutRepo := &memoryLockoutRepository{
+		admins:    map[string]*memoryAdmin{},
+		clientIps: map[string]*memoryClientIp{},
+	}
+	for _, a := range admins {
+		lockoutRepo.admins[a.Email] = &memoryAdmin{admin: a}
+	}
+	auditRepo := &memoryAuditRepository{}
+	service := NewService(lockoutRepo, audit.NewService(auditRepo), &config.Lockout{
+		Threshold:    3,
+		IpThreshold:  5,
+		IpWindow:     15 * time.Minute,
+		BaseDuration: time.Minute,
+		MaxDuration:  time.Hour,
+	})
+	return service, lockoutRepo, auditRepo
+}
+
+// randomAdmin
+// Returns an admin with a random email.
+func randomAdmin(adminID int32) model.Admin {
+	return model.Admin{AdminID: adminID, Email: goRandom.RandomString(8) + "@example.com"}
+}
+
+// TestService_RegisterFailedLogin_LocksAdmin test the admin is locked and audited once the threshold is reached
+func TestService_RegisterFailedLogin_LocksAdmin(t *testing.T) {
+	admin := randomAdmin(1)
+	service, lockoutRepo, auditRepo := newTestService(admin)
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, service.RegisterFailedLogin(context.Background(), admin.Email, "10.0.0.1"))
+	}
+	require.False(t, lockoutRepo.admins[admin.Email].admin.LockExpires.Valid)
+	require.Empty(t, auditRepo.logs)
+
+	require.NoError(t, service.RegisterFailedLogin(context.Background(), admin.Email, "10.0.0.1"))
+	lockExpires := lockoutRepo.admins[admin.Email].admin.LockExpires
+	require.True(t, lockExpires.Valid)
+	require.WithinDuration(t, time.Now().Add(time.Minute), lockExpires.Time, time.Second)
+
+	require.Len(t, auditRepo.logs, 1)
+	require.Equal(t, audit.ActionAdminLocked, auditRepo.logs[0].Action)
+	require.Equal(t, int64(admin.AdminID), auditRepo.logs[0].AdminID.Int64)
+	require.Equal(t, "10.0.0.1", auditRepo.logs[0].ClientIp.String)
+
+	// Failures during the lock neither extend it nor write another record
+	require.NoError(t, service.RegisterFailedLogin(context.Background(), admin.Email, "10.0.0.1"))
+	require.Equal(t, lockExpires, lockoutRepo.admins[admin.Email].admin.LockExpires)
+	require.Len(t, auditRepo.logs, 1)
+}
+
+// TestService_RegisterFailedLogin_LocksClientIp test the client ip is locked and audited once its threshold is reached
+func TestService_RegisterFailedLogin_LocksClientIp(t *testing.T) {
+	service, _, auditRepo := newTestService()
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, service.RegisterFailedLogin(context.Background(), goRandom.RandomString(8)+"@example.com", "10.0.0.2"))
+	}
+	require.NoError(t, service.CheckClientIp(context.Background(), "10.0.0.2"))
+	require.Empty(t, auditRepo.logs)
+
+	require.NoError(t, service.RegisterFailedClientLogin(context.Background(), "10.0.0.2"))
+	require.ErrorIs(t, service.CheckClientIp(context.Background(), "10.0.0.2"), ErrClientIpLocked)
+	require.NoError(t, service.CheckClientIp(context.Background(), "10.0.0.3"))
+
+	require.Len(t, auditRepo.logs, 1)
+	require.Equal(t, audit.ActionClientIpLocked, auditRepo.logs[0].Action)
+	require.False(t, auditRepo.logs[0].AdminID.Valid)
+	require.Equal(t, "10.0.0.2", auditRepo.logs[0].ClientIp.String)
+}
+
+// TestService_RegisterSuccessfulLogin test a successful login resets the counters of the admin and the client ip
+func TestService_RegisterSuccessfulLogin(t *testing.T) {
+	admin := randomAdmin(1)
+	service, lockoutRepo, auditRepo := newTestService(admin)
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, service.RegisterFailedLogin(context.Background(), admin.Email, "10.0.0.4"))
+	}
+	require.NoError(t, service.RegisterSuccessfulLogin(context.Background(), admin.AdminID, "10.0.0.4"))
+	require.Zero(t, lockoutRepo.admins[admin.Email].failedLogins)
+	require.NotContains(t, lockoutRepo.clientIps, "10.0.0.4")
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, service.RegisterFailedLogin(context.Background(), admin.Email, "10.0.0.4"))
+	}
+	require.False(t, lockoutRepo.admins[admin.Email].admin.LockExpires.Valid)
+	require.Empty(t, auditRepo.logs)
+}
+
+// TestService_UnlockAdmin test unlocking clears the lock and records the actor
+func TestService_UnlockAdmin(t *testing.T) {
+	admin := randomAdmin(1)
+	service, lockoutRepo, auditRepo := newTestService(admin)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, service.RegisterFailedLogin(context.Background(), admin.Email, "10.0.0.5"))
+	}
+	require.True(t, lockoutRepo.admins[admin.Email].admin.LockExpires.Valid)
+
+	unlockedAdmin, err := service.UnlockAdmin(context.Background(), admin.Email, 2, "10.0.0.6")
+	require.NoError(t, err)
+	require.Equal(t, admin.AdminID, unlockedAdmin.AdminID)
+	require.False(t, unlockedAdmin.LockExpires.Valid)
+	require.False(t, lockoutRepo.admins[admin.Email].admin.LockExpires.Valid)
+
+	require.Len(t, auditRepo.logs, 2)
+	require.Equal(t, audit.ActionAdminUnlocked, auditRepo.logs[1].Action)
+	require.Equal(t, int64(admin.AdminID), auditRepo.logs[1].AdminID.Int64)
+	require.Equal(t, int64(2), auditRepo.logs[1].ActorID.Int64)
+	require.Equal(t, "10.0.0.6", auditRepo.logs[1].ClientIp.String)
+
+	_, err = service.UnlockAdmin(context.Background(), "unknown@example.com", 2, "10.0.0.6")
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	require.Len(t, auditRepo.logs, 2)
+}
@@ -0,0 +1,300 @@
+package media
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/sync/singleflight"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/media"
+	"github.com/daniel-vuky/go-blog/internal/repository/media"
+	"github.com/daniel-vuky/go-blog/pkg/config"
+	"github.com/daniel-vuky/go-blog/pkg/filestore"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	// uniqueViolationCode
+	// Postgres error code raised when a unique constraint is violated
+	uniqueViolationCode = "23505"
+	// foreignKeyViolationCode
+	// Postgres error code raised when a foreign key constraint is violated
+	foreignKeyViolationCode = "23503"
+	// defaultMaxSize
+	// Largest accepted file in bytes when none is configured
+	defaultMaxSize = 5 << 20
+	// defaultBaseUrl
+	// Url the files are served from when none is configured
+	defaultBaseUrl = "/media"
+	// maxFileNameLength
+	// Longest original file name kept, in characters
+	maxFileNameLength = 255
+)
+
+var (
+	ErrEmptyFile            = errors.New("file is empty")
+	ErrFileTooLarge         = errors.New("file exceeds the maximum upload size")
+	ErrUnsupportedMediaType = errors.New("file type is not supported, upload a jpeg, png, gif or webp image")
+	ErrMediaInUse           = errors.New("media is used by a post")
+)
+
+// extensions
+// Sniffed content types accepted for upload and the extension of their stored files
+var extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// Service
+// Manages the media library, the uploaded files are stored once per content.
+type Service struct {
+	MediaRepo media.Repository
+	Storage   filestore.Storage
+	maxSize   int64
+	baseUrl   string
+	presets   []config.ImagePreset
+	srcset    []config.ImagePreset
+	variants  singleflight.Group
+}
+
+// NewService
+// Returns a new instance of Service, failing when an image preset is invalid.
+func NewService(repo media.Repository, storage filestore.Storage, cfg *config.Media) (*Service, error) {
+	maxSize := cfg.MaxSize
+	if maxSize <= 0 {
+		maxSize = defaultMaxSize
+	}
+	baseUrl := strings.TrimSuffix(cfg.BaseUrl, "/")
+	if baseUrl == "" {
+		baseUrl = defaultBaseUrl
+	}
+	srcset, err := validatePresets(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		MediaRepo: repo,
+		Storage:   storage,
+		maxSize:   maxSize,
+		baseUrl:   baseUrl,
+		presets:   cfg.Presets,
+		srcset:    srcset,
+	}, nil
+}
+
+// MaxSize
+// Returns the largest accepted file in bytes.
+// @return int64
+func (s *Service) MaxSize() int64 {
+	return s.maxSize
+}
+
+// GetMedia
+// Returns a media file by id with the posts using it.
+// @param c context.Context
+// @param mediaID int64
+// @return model.Media
+func (s *Service) GetMedia(c context.Context, mediaID int64) (model.Media, error) {
+	file, err := s.MediaRepo.Get(c, mediaID)
+	if err != nil {
+		return model.Media{}, err
+	}
+	posts, err := s.MediaRepo.GetListPost(c, mediaID)
+	if err != nil {
+		return model.Media{}, err
+	}
+	if posts == nil {
+		posts = []model.MediaPost{}
+	}
+	file.Posts = posts
+
+	return s.withUrl(file), nil
+}
+
+// ListMediaResponse
+// Struct to hold the response of the GetListMedia method.
+type ListMediaResponse struct {
+	Totals int64         `json:"totals"`
+	Media  []model.Media `json:"media"`
+}
+
+// GetListMedia
+// Returns a list of media files.
+// @param c context.Context
+// @param arg *model.GetListMediaParams
+// @return ListMediaResponse
+func (s *Service) GetListMedia(c context.Context, arg *model.GetListMediaParams) (ListMediaResponse, error) {
+	var rsp ListMediaResponse
+	if arg.OrderBy == "" {
+		arg.OrderBy = "media_id"
+	}
+	if arg.OrderDirection == "" {
+		arg.OrderDirection = "desc"
+	}
+	listMedia, totalMedia, err := s.MediaRepo.GetList(c, arg)
+	if err != nil {
+		return rsp, err
+	}
+	if listMedia == nil {
+		listMedia = []model.Media{}
+	}
+	for i := range listMedia {
+		listMedia[i] = s.withUrl(listMedia[i])
+	}
+	rsp = ListMediaResponse{
+		Totals: totalMedia,
+		Media:  listMedia,
+	}
+
+	return rsp, nil
+}
+
+// UploadMedia
+// Stores an image after sniffing its type. A content already in the library is not stored
+// again, the existing media is returned instead and created is false.
+// @param c context.Context
+// @param arg *model.UploadMediaParams
+// @return model.Media
+// @return bool created
+func (s *Service) UploadMedia(c context.Context, arg *model.UploadMediaParams) (model.Media, bool, error) {
+	if len(arg.Content) == 0 {
+		return model.Media{}, false, ErrEmptyFile
+	}
+	if int64(len(arg.Content)) > s.maxSize {
+		return model.Media{}, false, ErrFileTooLarge
+	}
+	mimeType, _, _ := mime.ParseMediaType(http.DetectContentType(arg.Content))
+	extension, ok := extensions[mimeType]
+	if !ok {
+		return model.Media{}, false, ErrUnsupportedMediaType
+	}
+	sum := sha256.Sum256(arg.Content)
+	contentHash := hex.EncodeToString(sum[:])
+
+	existing, err := s.MediaRepo.GetByHash(c, contentHash)
+	if err == nil {
+		return s.withUrl(existing), false, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return model.Media{}, false, err
+	}
+
+	// The key only depends on the content, storing it again while another upload races is harmless
+	storageKey := path.Join(contentHash[:2], contentHash+extension)
+	if err = s.Storage.Put(c, storageKey, arg.Content, mimeType); err != nil {
+		return model.Media{}, false, err
+	}
+	created, err := s.MediaRepo.Create(c, &model.CreateMediaParams{
+		FileName:    cleanFileName(arg.FileName, extension),
+		StorageKey:  storageKey,
+		ContentHash: contentHash,
+		MimeType:    mimeType,
+		Size:        int64(len(arg.Content)),
+		AdminID:     arg.AdminID,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			existing, err = s.MediaRepo.GetByHash(c, contentHash)
+			if err != nil {
+				return model.Media{}, false, err
+			}
+			return s.withUrl(existing), false, nil
+		}
+		return model.Media{}, false, err
+	}
+
+	return s.withUrl(created), true, nil
+}
+
+// DeleteMedia
+// Deletes a media file no post uses, then removes its stored file and variants.
+// @param c context.Context
+// @param mediaID int64
+// @return model.Media
+func (s *Service) DeleteMedia(c context.Context, mediaID int64) (model.Media, error) {
+	deleted, err := s.MediaRepo.Delete(c, mediaID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode {
+			return model.Media{}, ErrMediaInUse
+		}
+		return model.Media{}, err
+	}
+	// The record is gone, a file left behind is only wasted space and is replaced by a new upload
+	if err = s.Storage.Delete(c, deleted.StorageKey); err != nil {
+		log.Printf("failed to delete the file %s of media %d: %v", deleted.StorageKey, deleted.MediaID, err)
+	}
+	s.deleteVariants(c, deleted.StorageKey)
+
+	return s.withUrl(deleted), nil
+}
+
+// OpenFile
+// Opens a stored file for serving, with the content type of its extension.
+// @param c context.Context
+// @param storageKey string
+// @return io.ReadCloser
+// @return string content type
+func (s *Service) OpenFile(c context.Context, storageKey string) (io.ReadCloser, string, error) {
+	contentType := contentType(storageKey)
+	if contentType == "" {
+		return nil, "", filestore.ErrNotFound
+	}
+	file, err := s.Storage.Open(c, storageKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return file, contentType, nil
+}
+
+// withUrl
+// Sets the url a media file is served from.
+// @param file model.Media
+// @return model.Media
+func (s *Service) withUrl(file model.Media) model.Media {
+	file.Url = s.baseUrl + "/" + file.StorageKey
+	return file
+}
+
+// contentType
+// Returns the content type of a stored file from its extension, empty when it is not supported.
+// @param storageKey string
+// @return string
+func contentType(storageKey string) string {
+	for mimeType, extension := range extensions {
+		if path.Ext(storageKey) == extension {
+			return mimeType
+		}
+	}
+	return ""
+}
+
+// cleanFileName
+// Keeps the base name of an uploaded file, falling back to a name made of the extension.
+// @param fileName string
+// @param extension string
+// @return string
+func cleanFileName(fileName string, extension string) string {
+	fileName = strings.TrimSpace(filepath.Base(strings.ReplaceAll(fileName, "\\", "/")))
+	if fileName == "" || fileName == "." || fileName == "/" {
+		return "upload" + extension
+	}
+	if utf8.RuneCountInString(fileName) > maxFileNameLength {
+		fileName = string([]rune(fileName)[:maxFileNameLength])
+	}
+	return fileName
+}
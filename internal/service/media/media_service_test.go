@@ -0,0 +1,168 @@
+package media
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/media"
+	"github.com/daniel-vuky/go-blog/pkg/config"
+	"github.com/daniel-vuky/go-blog/pkg/filestore"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+// pngHeader
+// Signature sniffed as image/png
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+// memoryMediaRepository
+// Keeps the media in memory, the media of usedIDs are refused for deletion like the foreign key does.
+type memoryMediaRepository struct {
+	media   []model.Media
+	usedIDs map[int64]bool
+}
+
+func (repo *memoryMediaRepository) Get(_ context.Context, mediaID int64) (model.Media, error) {
+	for _, file := range repo.media {
+		if file.MediaID == mediaID {
+			return file, nil
+		}
+	}
+	return model.Media{}, pgx.ErrNoRows
+}
+
+func (repo *memoryMediaRepository) GetByHash(_ context.Context, contentHash string) (model.Media, error) {
+	for _, file := range repo.media {
+		if file.ContentHash == contentHash {
+			return file, nil
+		}
+	}
+	return model.Media{}, pgx.ErrNoRows
+}
+
+func (repo *memoryMediaRepository) GetList(_ context.Context, _ *model.GetListMediaParams) ([]model.Media, int64, error) {
+	return repo.media, int64(len(repo.media)), nil
+}
+
+func (repo *memoryMediaRepository) GetListPost(_ context.Context, _ int64) ([]model.MediaPost, error) {
+	return nil, nil
+}
+
+func (repo *memoryMediaRepository) Create(_ context.Context, arg *model.CreateMediaParams) (model.Media, error) {
+	file := model.Media{
+		MediaID:     int64(len(repo.media) + 1),
+		FileName:    arg.FileName,
+		StorageKey:  arg.StorageKey,
+		ContentHash: arg.ContentHash,
+		MimeType:    arg.MimeType,
+		Size:        arg.Size,
+		AdminID:     arg.AdminID,
+	}
+	repo.media = append(repo.media, file)
+	return file, nil
+}
+
+func (repo *memoryMediaRepository) Delete(_ context.Context, mediaID int64) (model.Media, error) {
+	if repo.usedIDs[mediaID] {
+		return model.Media{}, &pgconn.PgError{Code: foreignKeyViolationCode}
+	}
+	for i, file := range repo.media {
+		if file.MediaID == mediaID {
+			repo.media = append(repo.media[:i], repo.media[i+1:]...)
+			return file, nil
+		}
+	}
+	return model.Media{}, pgx.ErrNoRows
+}
+
+func newTestService(t *testing.T, repo *memoryMediaRepository) *Service {
+	storage, err := filestore.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	s, err := NewService(repo, storage, &config.Media{MaxSize: 64, BaseUrl: "https://cdn.example.com/"})
+	require.NoError(t, err)
+	return s
+}
+
+func TestService_UploadMedia(t *testing.T) {
+	repo := &memoryMediaRepository{}
+	s := newTestService(t, repo)
+	ctx := context.Background()
+
+	uploaded, created, err := s.UploadMedia(ctx, &model.UploadMediaParams{FileName: `C:\photos\cat.png`, Content: pngHeader})
+	require.NoError(t, err)
+	require.True(t, created)
+	require.Equal(t, "cat.png", uploaded.FileName)
+	require.Equal(t, "image/png", uploaded.MimeType)
+	require.Equal(t, uploaded.ContentHash[:2]+"/"+uploaded.ContentHash+".png", uploaded.StorageKey)
+	require.Equal(t, "https://cdn.example.com/"+uploaded.StorageKey, uploaded.Url)
+
+	file, contentType, err := s.OpenFile(ctx, uploaded.StorageKey)
+	require.NoError(t, err)
+	content, err := io.ReadAll(file)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	require.Equal(t, pngHeader, content)
+	require.Equal(t, "image/png", contentType)
+
+	// The same content under another name is not stored twice
+	duplicate, created, err := s.UploadMedia(ctx, &model.UploadMediaParams{FileName: "copy.png", Content: pngHeader})
+	require.NoError(t, err)
+	require.False(t, created)
+	require.Equal(t, uploaded.MediaID, duplicate.MediaID)
+	require.Len(t, repo.media, 1)
+}
+
+// TestService_UploadMedia_StorageKey test the posts using an uploaded file are found by its key
+func TestService_UploadMedia_StorageKey(t *testing.T) {
+	s := newTestService(t, &memoryMediaRepository{})
+
+	uploaded, _, err := s.UploadMedia(context.Background(), &model.UploadMediaParams{FileName: "cat.png", Content: pngHeader})
+	require.NoError(t, err)
+	content := fmt.Sprintf("![cat](%s) and again ![cat](%s)", uploaded.Url, uploaded.Url)
+	require.Equal(t, []string{uploaded.StorageKey}, model.ExtractStorageKeys(uploaded.Url, content))
+
+	// A key whose directory is not the start of its hash is not a file of the library
+	hash := uploaded.ContentHash
+	require.Empty(t, model.ExtractStorageKeys("ff/"+hash+".png", hash[:2]+"/"+hash+".txt", ""))
+}
+
+func TestService_UploadMedia_Rejected(t *testing.T) {
+	s := newTestService(t, &memoryMediaRepository{})
+	ctx := context.Background()
+
+	_, _, err := s.UploadMedia(ctx, &model.UploadMediaParams{FileName: "empty.png"})
+	require.ErrorIs(t, err, ErrEmptyFile)
+
+	_, _, err = s.UploadMedia(ctx, &model.UploadMediaParams{FileName: "big.png", Content: append(pngHeader, make([]byte, 64)...)})
+	require.ErrorIs(t, err, ErrFileTooLarge)
+
+	// The extension is ignored, the content decides
+	for _, content := range []string{"<svg onload=alert(1)></svg>", "<html><script></script></html>", "plain text"} {
+		_, _, err = s.UploadMedia(ctx, &model.UploadMediaParams{FileName: "image.png", Content: []byte(content)})
+		require.ErrorIs(t, err, ErrUnsupportedMediaType, content)
+	}
+
+	_, _, err = s.OpenFile(ctx, "ab/secret.txt")
+	require.ErrorIs(t, err, filestore.ErrNotFound)
+}
+
+func TestService_DeleteMedia(t *testing.T) {
+	repo := &memoryMediaRepository{usedIDs: map[int64]bool{}}
+	s := newTestService(t, repo)
+	ctx := context.Background()
+	uploaded, _, err := s.UploadMedia(ctx, &model.UploadMediaParams{FileName: "cat.png", Content: pngHeader})
+	require.NoError(t, err)
+
+	repo.usedIDs[uploaded.MediaID] = true
+	_, err = s.DeleteMedia(ctx, uploaded.MediaID)
+	require.ErrorIs(t, err, ErrMediaInUse)
+
+	repo.usedIDs[uploaded.MediaID] = false
+	_, err = s.DeleteMedia(ctx, uploaded.MediaID)
+	require.NoError(t, err)
+	_, _, err = s.OpenFile(ctx, uploaded.StorageKey)
+	require.ErrorIs(t, err, filestore.ErrNotFound)
+}
@@ -0,0 +1,199 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/daniel-vuky/go-blog/pkg/config"
+	"github.com/daniel-vuky/go-blog/pkg/filestore"
+	"github.com/daniel-vuky/go-blog/pkg/imaging"
+)
+
+// variantDirectory
+// Key prefix of the stored image variants
+const variantDirectory = "variants"
+
+var ErrVariantNotAllowed = errors.New("image variant is not an allowed preset")
+
+// formats
+// Format of the variants of each uploaded type when none is requested
+var formats = map[string]imaging.Format{
+	"image/jpeg": imaging.FormatJPEG,
+	"image/png":  imaging.FormatPNG,
+	"image/gif":  imaging.FormatPNG,
+	"image/webp": imaging.FormatWebP,
+}
+
+// Variant
+// A requested image variant, an empty Fit covers the size and an empty Format keeps the type of the original.
+type Variant struct {
+	Width  int
+	Height int
+	Fit    imaging.Fit
+	Format imaging.Format
+}
+
+// OpenVariant
+// Opens a resized version of a stored image. The variant must be one of the presets, it is generated
+// on the first request and then served from the storage.
+// @param c context.Context
+// @param storageKey string
+// @param variant Variant
+// @return io.ReadCloser
+// @return string content type
+func (s *Service) OpenVariant(c context.Context, storageKey string, variant Variant) (io.ReadCloser, string, error) {
+	mimeType := contentType(storageKey)
+	if mimeType == "" || strings.HasPrefix(storageKey, variantDirectory+"/") {
+		return nil, "", filestore.ErrNotFound
+	}
+	if variant.Fit == "" {
+		variant.Fit = imaging.FitCover
+	}
+	if variant.Format == "" {
+		variant.Format = formats[mimeType]
+	}
+	if !s.isPreset(variant) || !variant.Format.IsValid() {
+		return nil, "", ErrVariantNotAllowed
+	}
+
+	variantKey := s.variantKey(storageKey, variant)
+	file, err := s.Storage.Open(c, variantKey)
+	if err == nil {
+		return file, variant.Format.ContentType(), nil
+	}
+	if !errors.Is(err, filestore.ErrNotFound) {
+		return nil, "", err
+	}
+
+	// Concurrent requests of a missing variant wait for a single resize, which goes on when the request
+	// starting it is cancelled so the variant is still stored for the others
+	resizeCtx := context.WithoutCancel(c)
+	result := s.variants.DoChan(variantKey, func() (any, error) {
+		original, err := s.Storage.Open(resizeCtx, storageKey)
+		if err != nil {
+			return nil, err
+		}
+		defer original.Close()
+		resized, err := imaging.Resize(original, imaging.Options{
+			Width:  variant.Width,
+			Height: variant.Height,
+			Fit:    variant.Fit,
+			Format: variant.Format,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err = s.Storage.Put(resizeCtx, variantKey, resized, variant.Format.ContentType()); err != nil {
+			return nil, err
+		}
+		return resized, nil
+	})
+	select {
+	case <-c.Done():
+		return nil, "", c.Err()
+	case res := <-result:
+		if res.Err != nil {
+			return nil, "", res.Err
+		}
+		return io.NopCloser(bytes.NewReader(res.Val.([]byte))), variant.Format.ContentType(), nil
+	}
+}
+
+// Srcset
+// Returns the srcset attribute offering the srcset presets of an image of the library,
+// empty when the url is not one of the library.
+// @param imageUrl string
+// @return string
+func (s *Service) Srcset(imageUrl string) string {
+	storageKey, ok := strings.CutPrefix(imageUrl, s.baseUrl+"/")
+	if !ok || contentType(storageKey) == "" {
+		return ""
+	}
+	candidates := make([]string, 0, len(s.srcset))
+	for _, preset := range s.srcset {
+		candidates = append(candidates, fmt.Sprintf(
+			"%s?w=%d&h=%d&fit=%s %dw",
+			imageUrl, preset.Width, preset.Height, preset.Fit, preset.Width,
+		))
+	}
+	return strings.Join(candidates, ", ")
+}
+
+// deleteVariants
+// Removes every variant which may have been generated for a stored image.
+// @param c context.Context
+// @param storageKey string
+func (s *Service) deleteVariants(c context.Context, storageKey string) {
+	for _, preset := range s.presets {
+		for _, format := range []imaging.Format{imaging.FormatJPEG, imaging.FormatPNG, imaging.FormatWebP} {
+			variantKey := s.variantKey(storageKey, Variant{
+				Width:  preset.Width,
+				Height: preset.Height,
+				Fit:    imaging.Fit(preset.Fit),
+				Format: format,
+			})
+			if err := s.Storage.Delete(c, variantKey); err != nil {
+				log.Printf("failed to delete the image variant %s: %v", variantKey, err)
+			}
+		}
+	}
+}
+
+// isPreset
+// Checks the size and the fit of a variant match a preset.
+// @param variant Variant
+// @return bool
+func (s *Service) isPreset(variant Variant) bool {
+	for _, preset := range s.presets {
+		if preset.Width == variant.Width && preset.Height == variant.Height && imaging.Fit(preset.Fit) == variant.Fit {
+			return true
+		}
+	}
+	return false
+}
+
+// variantKey
+// Returns the key of a variant, stored next to the other variants of its image.
+// @param storageKey string
+// @param variant Variant
+// @return string
+func (s *Service) variantKey(storageKey string, variant Variant) string {
+	return path.Join(
+		variantDirectory,
+		strings.TrimSuffix(storageKey, path.Ext(storageKey)),
+		fmt.Sprintf("%dx%d-%s.%s", variant.Width, variant.Height, variant.Fit, variant.Format),
+	)
+}
+
+// validatePresets
+// Checks every preset can be generated and every srcset name is a preset with a width.
+// @param cfg *config.Media
+// @return []config.ImagePreset the presets of the srcset
+// @return error
+func validatePresets(cfg *config.Media) ([]config.ImagePreset, error) {
+	byName := make(map[string]config.ImagePreset, len(cfg.Presets))
+	for _, preset := range cfg.Presets {
+		if preset.Width < 0 || preset.Height < 0 || (preset.Width == 0 && preset.Height == 0) {
+			return nil, fmt.Errorf("image preset %q: %w", preset.Name, imaging.ErrInvalidSize)
+		}
+		if !imaging.Fit(preset.Fit).IsValid() {
+			return nil, fmt.Errorf("image preset %q: %w", preset.Name, imaging.ErrInvalidFit)
+		}
+		byName[preset.Name] = preset
+	}
+	srcset := make([]config.ImagePreset, 0, len(cfg.Srcset))
+	for _, name := range cfg.Srcset {
+		preset, ok := byName[name]
+		if !ok || preset.Width == 0 {
+			return nil, fmt.Errorf("srcset preset %q must be a preset with a width", name)
+		}
+		srcset = append(srcset, preset)
+	}
+	return srcset, nil
+}
@@ -0,0 +1,159 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"testing"
+	"time"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/media"
+	"github.com/daniel-vuky/go-blog/pkg/config"
+	"github.com/daniel-vuky/go-blog/pkg/filestore"
+	"github.com/daniel-vuky/go-blog/pkg/imaging"
+	"github.com/stretchr/testify/require"
+)
+
+// testPresets
+// Presets of the variant tests, small is the only srcset candidate
+var testPresets = &config.Media{
+	BaseUrl: "/media",
+	MaxSize: 1 << 20,
+	Presets: []config.ImagePreset{
+		{Name: "small", Width: 32, Height: 18, Fit: "cover"},
+		{Name: "content", Width: 48, Fit: "contain"},
+	},
+	Srcset: []string{"small"},
+}
+
+func newVariantTestService(t *testing.T) (*Service, model.Media) {
+	storage, err := filestore.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	s, err := NewService(&memoryMediaRepository{}, storage, testPresets)
+	require.NoError(t, err)
+
+	picture := image.NewRGBA(image.Rect(0, 0, 64, 48))
+	for x := 0; x < 64; x++ {
+		for y := 0; y < 48; y++ {
+			picture.Set(x, y, color.RGBA{R: uint8(x * 4), G: uint8(y * 5), B: 128, A: 255})
+		}
+	}
+	var content bytes.Buffer
+	require.NoError(t, png.Encode(&content, picture))
+	uploaded, _, err := s.UploadMedia(context.Background(), &model.UploadMediaParams{FileName: "photo.png", Content: content.Bytes()})
+	require.NoError(t, err)
+
+	return s, uploaded
+}
+
+func TestService_OpenVariant(t *testing.T) {
+	s, uploaded := newVariantTestService(t)
+	ctx := context.Background()
+
+	file, contentType, err := s.OpenVariant(ctx, uploaded.StorageKey, Variant{Width: 32, Height: 18})
+	require.NoError(t, err)
+	require.Equal(t, "image/png", contentType)
+	resized, _, err := image.DecodeConfig(file)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	require.Equal(t, 32, resized.Width)
+	require.Equal(t, 18, resized.Height)
+
+	// The generated variant is stored for the next requests
+	variant := Variant{Width: 32, Height: 18, Fit: imaging.FitCover, Format: imaging.FormatPNG}
+	cached, err := s.Storage.Open(ctx, s.variantKey(uploaded.StorageKey, variant))
+	require.NoError(t, err)
+	require.NoError(t, cached.Close())
+
+	file, contentType, err = s.OpenVariant(ctx, uploaded.StorageKey, Variant{Width: 48, Fit: imaging.FitContain, Format: imaging.FormatJPEG})
+	require.NoError(t, err)
+	require.Equal(t, "image/jpeg", contentType)
+	resized, format, err := image.DecodeConfig(file)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	require.Equal(t, "jpeg", format)
+	require.Equal(t, 48, resized.Width)
+	require.Equal(t, 36, resized.Height)
+}
+
+func TestService_OpenVariant_Cancelled(t *testing.T) {
+	s, uploaded := newVariantTestService(t)
+	variant := Variant{Width: 32, Height: 18, Fit: imaging.FitCover, Format: imaging.FormatPNG}
+
+	// The resize started by a request which is gone still stores the variant
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	file, _, err := s.OpenVariant(ctx, uploaded.StorageKey, variant)
+	if err == nil {
+		require.NoError(t, file.Close())
+	} else {
+		require.ErrorIs(t, err, context.Canceled)
+	}
+	require.Eventually(t, func() bool {
+		cached, err := s.Storage.Open(context.Background(), s.variantKey(uploaded.StorageKey, variant))
+		if err != nil {
+			return false
+		}
+		return cached.Close() == nil
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestService_OpenVariant_Rejected(t *testing.T) {
+	s, uploaded := newVariantTestService(t)
+	ctx := context.Background()
+
+	for _, variant := range []Variant{
+		{Width: 33, Height: 18},
+		{Width: 32, Height: 18, Fit: imaging.FitContain},
+		{Width: 48, Fit: imaging.FitContain, Format: "gif"},
+	} {
+		_, _, err := s.OpenVariant(ctx, uploaded.StorageKey, variant)
+		require.ErrorIs(t, err, ErrVariantNotAllowed, "%+v", variant)
+	}
+
+	_, _, err := s.OpenVariant(ctx, "ab/missing.png", Variant{Width: 32, Height: 18})
+	require.ErrorIs(t, err, filestore.ErrNotFound)
+	_, _, err = s.OpenVariant(ctx, "ab/notes.txt", Variant{Width: 32, Height: 18})
+	require.ErrorIs(t, err, filestore.ErrNotFound)
+}
+
+func TestService_DeleteMedia_Variants(t *testing.T) {
+	s, uploaded := newVariantTestService(t)
+	ctx := context.Background()
+
+	file, _, err := s.OpenVariant(ctx, uploaded.StorageKey, Variant{Width: 32, Height: 18, Format: imaging.FormatWebP})
+	require.NoError(t, err)
+	_, err = io.Copy(io.Discard, file)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	_, err = s.DeleteMedia(ctx, uploaded.MediaID)
+	require.NoError(t, err)
+	variant := Variant{Width: 32, Height: 18, Fit: imaging.FitCover, Format: imaging.FormatWebP}
+	_, err = s.Storage.Open(ctx, s.variantKey(uploaded.StorageKey, variant))
+	require.True(t, errors.Is(err, filestore.ErrNotFound))
+}
+
+func TestService_Srcset(t *testing.T) {
+	s, uploaded := newVariantTestService(t)
+
+	require.Equal(t, uploaded.Url+"?w=32&h=18&fit=cover 32w", s.Srcset(uploaded.Url))
+	require.Empty(t, s.Srcset("https://example.com/photo.png"))
+	require.Empty(t, s.Srcset("/media/ab/notes.txt"))
+}
+
+func TestNewService_InvalidPresets(t *testing.T) {
+	for _, cfg := range []*config.Media{
+		{Presets: []config.ImagePreset{{Name: "empty", Fit: "cover"}}},
+		{Presets: []config.ImagePreset{{Name: "stretch", Width: 10, Height: 10, Fit: "fill"}}},
+		{Presets: []config.ImagePreset{{Name: "tall", Height: 10, Fit: "contain"}}, Srcset: []string{"tall"}},
+		{Srcset: []string{"unknown"}},
+	} {
+		_, err := NewService(&memoryMediaRepository{}, nil, cfg)
+		require.Error(t, err, "%+v", cfg)
+	}
+}
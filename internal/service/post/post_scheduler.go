@@ -0,0 +1,50 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+// defaultSchedulerInterval
+// Interval between two runs of the scheduler when the configuration does not set one
+const defaultSchedulerInterval = time.Minute
+
+// RunScheduler
+// Publishes the scheduled posts once at start, then every interval until the context is done.
+// A failed run is logged and retried at the next interval.
+// @param ctx context.Context
+// @param interval time.Duration
+// @return error
+func (s *Service) RunScheduler(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = defaultSchedulerInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		s.publishScheduledPosts(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishScheduledPosts
+// Runs the scheduler once and logs the outcome.
+// @param ctx context.Context
+func (s *Service) publishScheduledPosts(ctx context.Context) {
+	publishedPosts, err := s.PublishScheduledPosts(ctx)
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			log.Printf("failed to publish scheduled posts: %v", err)
+		}
+		return
+	}
+	for _, publishedPost := range publishedPosts {
+		log.Printf("published scheduled post %d", publishedPost.PostID)
+	}
+}
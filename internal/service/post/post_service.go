@@ -0,0 +1,412 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/post"
+	urlRewriteModel "github.com/daniel-vuky/go-blog/internal/models/url_rewrite"
+	"github.com/daniel-vuky/go-blog/internal/repository/post"
+	"github.com/daniel-vuky/go-blog/internal/service/urlrewrite"
+	"github.com/daniel-vuky/go-blog/pkg/diff"
+	"github.com/daniel-vuky/go-blog/pkg/markdown"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// uniqueViolationCode
+// Postgres error code raised when a unique constraint is violated
+const uniqueViolationCode = "23505"
+
+// maxUrlKeyAttempts
+// Number of times a generated url key is generated again when another request took it meanwhile
+const maxUrlKeyAttempts = 3
+
+var (
+	ErrUrlKeyReserved          = urlrewrite.ErrUrlKeyReserved
+	ErrUrlKeyInvalid           = urlrewrite.ErrUrlKeyInvalid
+	ErrUrlKeyAlreadyExists     = errors.New("url key is already used by another post or category")
+	ErrInvalidStatus           = errors.New("invalid post status")
+	ErrInvalidStatusTransition = errors.New("post cannot move to this status")
+	ErrInvalidPublishedAt      = errors.New("published at must be in the future to schedule a post and in the past to publish it")
+	ErrPublishedAtNotAllowed   = errors.New("published at can only be set when scheduling or publishing a post")
+	ErrUrlKeyRequired          = errors.New("post needs a url key to be published")
+	ErrRevisionNotFound        = errors.New("revision not found")
+)
+
+// statusTransitions
+// Statuses a post can move to from its current status
+var statusTransitions = map[model.PostStatus][]model.PostStatus{
+	model.PostStatusDraft:     {model.PostStatusReview, model.PostStatusScheduled, model.PostStatusPublished, model.PostStatusArchived},
+	model.PostStatusReview:    {model.PostStatusDraft, model.PostStatusScheduled, model.PostStatusPublished, model.PostStatusArchived},
+	model.PostStatusScheduled: {model.PostStatusDraft, model.PostStatusReview, model.PostStatusScheduled, model.PostStatusPublished, model.PostStatusArchived},
+	model.PostStatusPublished: {model.PostStatusDraft, model.PostStatusArchived},
+	model.PostStatusArchived:  {model.PostStatusDraft, model.PostStatusPublished},
+}
+
+// Service
+// Manages the posts of the blog.
+type Service struct {
+	PostRepo        post.Repository
+	UrlKeyGenerator urlrewrite.UrlKeyGenerator
+}
+
+// NewService
+// Returns a new instance of Service.
+func NewService(repo post.Repository, urlKeyGenerator urlrewrite.UrlKeyGenerator) *Service {
+	return &Service{PostRepo: repo, UrlKeyGenerator: urlKeyGenerator}
+}
+
+// GetPost
+// Returns a post by id.
+// @param c context.Context
+// @param postID int64
+// @return model.Post
+func (s *Service) GetPost(c context.Context, postID int64) (model.Post, error) {
+	return s.PostRepo.Get(c, postID)
+}
+
+// GetPublishedPost
+// Returns a post by id, pgx.ErrNoRows when it is not published.
+// @param c context.Context
+// @param postID int64
+// @return model.Post
+func (s *Service) GetPublishedPost(c context.Context, postID int64) (model.Post, error) {
+	loadedPost, err := s.PostRepo.Get(c, postID)
+	if err != nil {
+		return model.Post{}, err
+	}
+	if loadedPost.Status != model.PostStatusPublished {
+		return model.Post{}, pgx.ErrNoRows
+	}
+	return loadedPost, nil
+}
+
+// ListPostResponse
+// Struct to hold the response of the GetListPost method.
+type ListPostResponse struct {
+	Totals int64        `json:"totals"`
+	Posts  []model.Post `json:"posts"`
+}
+
+// GetListPost
+// Returns a list of posts.
+// @param c context.Context
+// @param arg *model.GetListPostParams
+// @return ListPostResponse
+func (s *Service) GetListPost(c context.Context, arg *model.GetListPostParams) (ListPostResponse, error) {
+	var rsp ListPostResponse
+	if arg.OrderBy == "" {
+		arg.OrderBy = "post_id"
+	}
+	if arg.OrderDirection == "" {
+		arg.OrderDirection = "desc"
+	}
+	listPost, totalPost, err := s.PostRepo.GetList(c, arg)
+	if err != nil {
+		return rsp, err
+	}
+	if listPost == nil {
+		listPost = []model.Post{}
+	}
+	rsp = ListPostResponse{
+		Totals: totalPost,
+		Posts:  listPost,
+	}
+
+	return rsp, nil
+}
+
+// CreatePost
+// Creates a new draft post, the url key is generated from the name when it is not set.
+// @param c context.Context
+// @param arg *model.CreatePostParams
+// @return model.Post
+func (s *Service) CreatePost(c context.Context, arg *model.CreatePostParams) (model.Post, error) {
+	if arg.CommentMode == "" {
+		arg.CommentMode = model.CommentModeOpen
+	}
+	generateUrlKey := !arg.UrlKey.Valid
+	if !generateUrlKey {
+		if err := urlrewrite.CheckUrlKey(arg.UrlKey.String); err != nil {
+			return model.Post{}, err
+		}
+	}
+	for attempt := 1; ; attempt++ {
+		if generateUrlKey {
+			urlKey, err := s.generateUrlKey(c, arg.Name, 0)
+			if err != nil {
+				return model.Post{}, err
+			}
+			arg.UrlKey = urlKey
+		}
+		createdPost, err := s.PostRepo.Create(c, arg)
+		if err == nil {
+			return createdPost, nil
+		}
+		err = convertUniqueViolation(err)
+		if !generateUrlKey || !errors.Is(err, ErrUrlKeyAlreadyExists) || attempt == maxUrlKeyAttempts {
+			return model.Post{}, err
+		}
+	}
+}
+
+// UpdatePost
+// Updates a post. The url key is kept unless it is set, or RegenerateUrlKey asks for
+// a new one generated from the name.
+// @param c context.Context
+// @param arg *model.UpdatePostParams
+// @return model.Post
+func (s *Service) UpdatePost(c context.Context, arg *model.UpdatePostParams) (model.Post, error) {
+	generateUrlKey := arg.RegenerateUrlKey && !arg.UrlKey.Valid
+	if arg.UrlKey.Valid {
+		if err := urlrewrite.CheckUrlKey(arg.UrlKey.String); err != nil {
+			return model.Post{}, err
+		}
+	}
+	name := arg.Name.String
+	if generateUrlKey && !arg.Name.Valid {
+		currentPost, err := s.PostRepo.Get(c, arg.PostID)
+		if err != nil {
+			return model.Post{}, err
+		}
+		name = currentPost.Name
+	}
+	for attempt := 1; ; attempt++ {
+		if generateUrlKey {
+			urlKey, err := s.generateUrlKey(c, name, arg.PostID)
+			if err != nil {
+				return model.Post{}, err
+			}
+			arg.UrlKey = urlKey
+		}
+		updatedPost, err := s.PostRepo.Update(c, arg)
+		if err == nil {
+			return updatedPost, nil
+		}
+		err = convertUniqueViolation(err)
+		if !generateUrlKey || !errors.Is(err, ErrUrlKeyAlreadyExists) || attempt == maxUrlKeyAttempts {
+			return model.Post{}, err
+		}
+	}
+}
+
+// DeletePost
+// Deletes a post.
+// @param c context.Context
+// @param postID int64
+// @return model.Post
+func (s *Service) DeletePost(c context.Context, postID int64) (model.Post, error) {
+	return s.PostRepo.Delete(c, postID)
+}
+
+// UpdatePostStatus
+// Moves a post in the publishing workflow. A scheduled post is published by the scheduler once
+// PublishedAt is reached, a published post keeps its previous publication time or gets the current one.
+// @param c context.Context
+// @param arg *model.UpdatePostStatusParams
+// @return model.Post
+func (s *Service) UpdatePostStatus(c context.Context, arg *model.UpdatePostStatusParams) (model.Post, error) {
+	if !arg.Status.IsValid() {
+		return model.Post{}, ErrInvalidStatus
+	}
+	currentPost, err := s.PostRepo.Get(c, arg.PostID)
+	if err != nil {
+		return model.Post{}, err
+	}
+	if !canMoveTo(currentPost.Status, arg.Status) {
+		return model.Post{}, ErrInvalidStatusTransition
+	}
+
+	if (arg.Status == model.PostStatusScheduled || arg.Status == model.PostStatusPublished) && !currentPost.UrlKey.Valid {
+		return model.Post{}, ErrUrlKeyRequired
+	}
+	now := time.Now()
+	switch arg.Status {
+	case model.PostStatusScheduled:
+		if !arg.PublishedAt.Valid || !arg.PublishedAt.Time.After(now) {
+			return model.Post{}, ErrInvalidPublishedAt
+		}
+	case model.PostStatusPublished:
+		if arg.PublishedAt.Valid && arg.PublishedAt.Time.After(now) {
+			return model.Post{}, ErrInvalidPublishedAt
+		}
+		if !arg.PublishedAt.Valid {
+			arg.PublishedAt = currentPost.PublishedAt
+			if !arg.PublishedAt.Valid || arg.PublishedAt.Time.After(now) {
+				arg.PublishedAt = pgtype.Timestamptz{Time: now, Valid: true}
+			}
+		}
+	default:
+		if arg.PublishedAt.Valid {
+			return model.Post{}, ErrPublishedAtNotAllowed
+		}
+		arg.PublishedAt = currentPost.PublishedAt
+	}
+
+	return s.PostRepo.UpdateStatus(c, arg)
+}
+
+// PublishScheduledPosts
+// Publishes the scheduled posts whose publication time is reached.
+// @param c context.Context
+// @return []model.Post the published posts
+func (s *Service) PublishScheduledPosts(c context.Context) ([]model.Post, error) {
+	return s.PostRepo.PublishScheduled(c, time.Now())
+}
+
+// ListPostRevisionResponse
+// Struct to hold the response of the GetListPostRevision method.
+type ListPostRevisionResponse struct {
+	Totals    int64                `json:"totals"`
+	Revisions []model.PostRevision `json:"revisions"`
+}
+
+// GetListPostRevision
+// Returns the revisions of a post, newest first.
+// @param c context.Context
+// @param arg *model.GetListPostRevisionParams
+// @return ListPostRevisionResponse
+func (s *Service) GetListPostRevision(c context.Context, arg *model.GetListPostRevisionParams) (ListPostRevisionResponse, error) {
+	if _, err := s.PostRepo.Get(c, arg.PostID); err != nil {
+		return ListPostRevisionResponse{}, err
+	}
+	revisions, totalRevision, err := s.PostRepo.GetListRevision(c, arg)
+	if err != nil {
+		return ListPostRevisionResponse{}, err
+	}
+	if revisions == nil {
+		revisions = []model.PostRevision{}
+	}
+
+	return ListPostRevisionResponse{Totals: totalRevision, Revisions: revisions}, nil
+}
+
+// PostRevisionDiff
+// The line-level changes of every field of a post between two revisions.
+type PostRevisionDiff struct {
+	FromRevisionID   int64       `json:"from_revision_id"`
+	ToRevisionID     int64       `json:"to_revision_id"`
+	HasChanges       bool        `json:"has_changes"`
+	Name             []diff.Line `json:"name"`
+	ShortDescription []diff.Line `json:"short_description"`
+	Description      []diff.Line `json:"description"`
+	Content          []diff.Line `json:"content"`
+}
+
+// GetPostRevisionDiff
+// Returns the changes turning a revision of a post into another one, in either order.
+// @param c context.Context
+// @param postID int64
+// @param fromRevisionID int64
+// @param toRevisionID int64
+// @return PostRevisionDiff
+func (s *Service) GetPostRevisionDiff(c context.Context, postID int64, fromRevisionID int64, toRevisionID int64) (PostRevisionDiff, error) {
+	from, err := s.getRevision(c, postID, fromRevisionID)
+	if err != nil {
+		return PostRevisionDiff{}, err
+	}
+	to, err := s.getRevision(c, postID, toRevisionID)
+	if err != nil {
+		return PostRevisionDiff{}, err
+	}
+	revisionDiff := PostRevisionDiff{
+		FromRevisionID:   from.RevisionID,
+		ToRevisionID:     to.RevisionID,
+		Name:             diff.Lines(from.Name, to.Name),
+		ShortDescription: diff.Lines(from.ShortDescription.String, to.ShortDescription.String),
+		Description:      diff.Lines(from.Description.String, to.Description.String),
+		Content:          diff.Lines(from.Content.String, to.Content.String),
+	}
+	revisionDiff.HasChanges = diff.HasChanges(revisionDiff.Name) ||
+		diff.HasChanges(revisionDiff.ShortDescription) ||
+		diff.HasChanges(revisionDiff.Description) ||
+		diff.HasChanges(revisionDiff.Content)
+
+	return revisionDiff, nil
+}
+
+// RestorePostRevision
+// Puts back the name, the descriptions and the content of a revision. The history is kept,
+// the restored state is recorded as a new revision of the editor.
+// @param c context.Context
+// @param postID int64
+// @param revisionID int64
+// @param editorID int64
+// @return model.Post
+func (s *Service) RestorePostRevision(c context.Context, postID int64, revisionID int64, editorID int64) (model.Post, error) {
+	if _, err := s.getRevision(c, postID, revisionID); err != nil {
+		return model.Post{}, err
+	}
+	return s.PostRepo.RestoreRevision(c, postID, revisionID, editorID)
+}
+
+// PreviewContent
+// Renders a markdown content the way the blog shows it, without saving anything.
+// @param content string
+// @return markdown.Document
+func (s *Service) PreviewContent(content string) (markdown.Document, error) {
+	return markdown.Render(content)
+}
+
+// getRevision
+// Returns a revision of an existing post, ErrRevisionNotFound when the post has no such revision.
+// @param c context.Context
+// @param postID int64
+// @param revisionID int64
+// @return model.PostRevision
+func (s *Service) getRevision(c context.Context, postID int64, revisionID int64) (model.PostRevision, error) {
+	if _, err := s.PostRepo.Get(c, postID); err != nil {
+		return model.PostRevision{}, err
+	}
+	revision, err := s.PostRepo.GetRevision(c, postID, revisionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.PostRevision{}, ErrRevisionNotFound
+		}
+		return model.PostRevision{}, err
+	}
+	return revision, nil
+}
+
+// canMoveTo
+// Checks the workflow allows a post to move from a status to another.
+// @param from model.PostStatus
+// @param to model.PostStatus
+// @return bool
+func canMoveTo(from model.PostStatus, to model.PostStatus) bool {
+	for _, status := range statusTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// generateUrlKey
+// Returns a free url key generated from the name of a post.
+// @param c context.Context
+// @param name string
+// @param postID int64 0 for a new post
+// @return pgtype.Text
+func (s *Service) generateUrlKey(c context.Context, name string, postID int64) (pgtype.Text, error) {
+	urlKey, err := s.UrlKeyGenerator.GenerateUrlKey(c, name, urlRewriteModel.UrlRewriteEntity2, postID)
+	if err != nil {
+		return pgtype.Text{}, err
+	}
+	return pgtype.Text{String: urlKey, Valid: true}, nil
+}
+
+// convertUniqueViolation
+// Converts the violation of the url_key unique constraint to ErrUrlKeyAlreadyExists.
+// @param err error
+// @return error
+func convertUniqueViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+		return ErrUrlKeyAlreadyExists
+	}
+	return err
+}
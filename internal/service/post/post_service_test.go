@@ -0,0 +1,428 @@
+package post
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/post"
+	urlRewriteModel "github.com/daniel-vuky/go-blog/internal/models/url_rewrite"
+	"github.com/daniel-vuky/go-blog/pkg/diff"
+	"github.com/daniel-vuky/go-blog/pkg/slug"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryPostRepository
+// In-memory post repository enforcing the url_key unique constraint and recording the revisions
+type memoryPostRepository struct {
+	posts     map[int64]model.Post
+	revisions []model.PostRevision
+	lastList  *model.GetListPostParams
+}
+
+func (repo *memoryPostRepository) Get(_ context.Context, postID int64) (model.Post, error) {
+	p, ok := repo.posts[postID]
+	if !ok {
+		return model.Post{}, pgx.ErrNoRows
+	}
+	return p, nil
+}
+
+func (repo *memoryPostRepository) GetList(_ context.Context, arg *model.GetListPostParams) ([]model.Post, int64, error) {
+	repo.lastList = arg
+	return nil, 0, nil
+}
+
+func (repo *memoryPostRepository) Create(_ context.Context, arg *model.CreatePostParams) (model.Post, error) {
+	if err := repo.checkUrlKey(0, arg.UrlKey); err != nil {
+		return model.Post{}, err
+	}
+	p := model.Post{
+		PostID:   int64(len(repo.posts) + 1),
+		Name:     arg.Name,
+		UrlKey:   arg.UrlKey,
+		Content:  arg.Content,
+		AuthorID: arg.AuthorID,
+		Status:   model.PostStatusDraft,
+	}
+	p.CreatedAt, p.UpdatedAt = time.Now(), time.Now()
+	repo.posts[p.PostID] = p
+	repo.addRevision(p, arg.AuthorID, pgtype.Int8{})
+	return p, nil
+}
+
+func (repo *memoryPostRepository) Update(_ context.Context, arg *model.UpdatePostParams) (model.Post, error) {
+	p, ok := repo.posts[arg.PostID]
+	if !ok {
+		return model.Post{}, pgx.ErrNoRows
+	}
+	if arg.UrlKey.Valid {
+		if err := repo.checkUrlKey(arg.PostID, arg.UrlKey); err != nil {
+			return model.Post{}, err
+		}
+		p.UrlKey = arg.UrlKey
+	}
+	if arg.Name.Valid {
+		p.Name = arg.Name.String
+	}
+	if arg.Content.Valid {
+		p.Content = arg.Content
+	}
+	repo.posts[arg.PostID] = p
+	latest, _, _ := repo.GetListRevision(context.Background(), &model.GetListPostRevisionParams{PostID: p.PostID})
+	if latest[0].Name != p.Name || latest[0].Content != p.Content {
+		repo.addRevision(p, arg.EditorID, pgtype.Int8{})
+	}
+	return p, nil
+}
+
+func (repo *memoryPostRepository) GetRevision(_ context.Context, postID int64, revisionID int64) (model.PostRevision, error) {
+	for _, revision := range repo.revisions {
+		if revision.PostID == postID && revision.RevisionID == revisionID {
+			return revision, nil
+		}
+	}
+	return model.PostRevision{}, pgx.ErrNoRows
+}
+
+func (repo *memoryPostRepository) GetListRevision(_ context.Context, arg *model.GetListPostRevisionParams) ([]model.PostRevision, int64, error) {
+	var revisions []model.PostRevision
+	for i := len(repo.revisions) - 1; i >= 0; i-- {
+		if repo.revisions[i].PostID == arg.PostID {
+			revisions = append(revisions, repo.revisions[i])
+		}
+	}
+	return revisions, int64(len(revisions)), nil
+}
+
+func (repo *memoryPostRepository) RestoreRevision(ctx context.Context, postID int64, revisionID int64, editorID int64) (model.Post, error) {
+	revision, err := repo.GetRevision(ctx, postID, revisionID)
+	if err != nil {
+		return model.Post{}, err
+	}
+	p := repo.posts[postID]
+	p.Name, p.Content = revision.Name, revision.Content
+	repo.posts[postID] = p
+	repo.addRevision(p, editorID, pgtype.Int8{Int64: revisionID, Valid: true})
+	return p, nil
+}
+
+// addRevision
+// Record the current state of a post as a new revision
+func (repo *memoryPostRepository) addRevision(p model.Post, adminID int64, restoredFrom pgtype.Int8) {
+	repo.revisions = append(repo.revisions, model.PostRevision{
+		RevisionID:   int64(len(repo.revisions) + 1),
+		PostID:       p.PostID,
+		Name:         p.Name,
+		Content:      p.Content,
+		AdminID:      pgtype.Int8{Int64: adminID, Valid: adminID != 0},
+		RestoredFrom: restoredFrom,
+		CreatedAt:    time.Now(),
+	})
+}
+
+func (repo *memoryPostRepository) Delete(_ context.Context, postID int64) (model.Post, error) {
+	p, ok := repo.posts[postID]
+	if !ok {
+		return model.Post{}, pgx.ErrNoRows
+	}
+	delete(repo.posts, postID)
+	return p, nil
+}
+
+func (repo *memoryPostRepository) UpdateStatus(_ context.Context, arg *model.UpdatePostStatusParams) (model.Post, error) {
+	p, ok := repo.posts[arg.PostID]
+	if !ok {
+		return model.Post{}, pgx.ErrNoRows
+	}
+	p.Status, p.PublishedAt = arg.Status, arg.PublishedAt
+	repo.posts[arg.PostID] = p
+	return p, nil
+}
+
+func (repo *memoryPostRepository) PublishScheduled(_ context.Context, now time.Time) ([]model.Post, error) {
+	var published []model.Post
+	for id, p := range repo.posts {
+		if p.Status == model.PostStatusScheduled && !p.PublishedAt.Time.After(now) {
+			p.Status = model.PostStatusPublished
+			repo.posts[id] = p
+			published = append(published, p)
+		}
+	}
+	return published, nil
+}
+
+// checkUrlKey
+// Return the error raised by postgres when another post already uses the url key
+func (repo *memoryPostRepository) checkUrlKey(postID int64, urlKey pgtype.Text) error {
+	for _, p := range repo.posts {
+		if urlKey.Valid && p.PostID != postID && p.UrlKey == urlKey {
+			return &pgconn.PgError{Code: uniqueViolationCode, ConstraintName: "post_url_key_key"}
+		}
+	}
+	return nil
+}
+
+// memoryUrlKeyGenerator
+// Url key generator checking the keys of the memory repository, the first stale keys
+// ignore the taken keys like a concurrent request taking the key meanwhile
+type memoryUrlKeyGenerator struct {
+	repo  *memoryPostRepository
+	stale int
+}
+
+func (generator *memoryUrlKeyGenerator) GenerateUrlKey(_ context.Context, source string, _ urlRewriteModel.UrlRewriteEntity, entityID int64) (string, error) {
+	base := slug.Make(source)
+	if generator.stale > 0 {
+		generator.stale--
+		return base, nil
+	}
+	var taken []string
+	for _, p := range generator.repo.posts {
+		if p.PostID != entityID && p.UrlKey.Valid {
+			taken = append(taken, p.UrlKey.String)
+		}
+	}
+	return slug.Unique(base, taken), nil
+}
+
+// newTestService
+// Create a service backed by an empty memory repository
+func newTestService() (*Service, *memoryPostRepository, *memoryUrlKeyGenerator) {
+	repo := &memoryPostRepository{posts: map[int64]model.Post{}}
+	generator := &memoryUrlKeyGenerator{repo: repo}
+	return NewService(repo, generator), repo, generator
+}
+
+// TestService_UrlKeyConflict test duplicated url keys are reported as ErrUrlKeyAlreadyExists
+func TestService_UrlKeyConflict(t *testing.T) {
+	service, _, _ := newTestService()
+	urlKey := pgtype.Text{String: "hello-world", Valid: true}
+
+	first, err := service.CreatePost(context.Background(), &model.CreatePostParams{Name: "Hello", UrlKey: urlKey, AuthorID: 1})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, first.AuthorID)
+
+	_, err = service.CreatePost(context.Background(), &model.CreatePostParams{Name: "Hello again", UrlKey: urlKey, AuthorID: 1})
+	require.ErrorIs(t, err, ErrUrlKeyAlreadyExists)
+
+	second, err := service.CreatePost(context.Background(), &model.CreatePostParams{Name: "Other", AuthorID: 2})
+	require.NoError(t, err)
+	_, err = service.UpdatePost(context.Background(), &model.UpdatePostParams{PostID: second.PostID, UrlKey: urlKey})
+	require.ErrorIs(t, err, ErrUrlKeyAlreadyExists)
+
+	_, err = service.UpdatePost(context.Background(), &model.UpdatePostParams{PostID: 99, Name: pgtype.Text{String: "Missing", Valid: true}})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	reserved := pgtype.Text{String: "search", Valid: true}
+	_, err = service.CreatePost(context.Background(), &model.CreatePostParams{Name: "Search", UrlKey: reserved, AuthorID: 1})
+	require.ErrorIs(t, err, ErrUrlKeyReserved)
+	_, err = service.UpdatePost(context.Background(), &model.UpdatePostParams{PostID: second.PostID, UrlKey: reserved})
+	require.ErrorIs(t, err, ErrUrlKeyReserved)
+}
+
+// TestService_GetListPost test the default order and the empty list
+func TestService_GetListPost(t *testing.T) {
+	service, repo, _ := newTestService()
+
+	rsp, err := service.GetListPost(context.Background(), &model.GetListPostParams{PageSize: 10, CurrentPage: 1})
+	require.NoError(t, err)
+	require.NotNil(t, rsp.Posts)
+	require.Empty(t, rsp.Posts)
+	require.Equal(t, "post_id", repo.lastList.OrderBy)
+	require.Equal(t, "desc", repo.lastList.OrderDirection)
+}
+
+// TestService_GenerateUrlKey test url keys are generated from the name unless they are set
+func TestService_GenerateUrlKey(t *testing.T) {
+	service, _, generator := newTestService()
+	ctx := context.Background()
+
+	first, err := service.CreatePost(ctx, &model.CreatePostParams{Name: "Xin chào thế giới", AuthorID: 1})
+	require.NoError(t, err)
+	require.Equal(t, "xin-chao-the-gioi", first.UrlKey.String)
+
+	second, err := service.CreatePost(ctx, &model.CreatePostParams{Name: "Xin chào, thế giới!", AuthorID: 1})
+	require.NoError(t, err)
+	require.Equal(t, "xin-chao-the-gioi-2", second.UrlKey.String)
+
+	manual, err := service.CreatePost(ctx, &model.CreatePostParams{
+		Name:     "Xin chào thế giới",
+		UrlKey:   pgtype.Text{String: "custom-key", Valid: true},
+		AuthorID: 1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "custom-key", manual.UrlKey.String)
+
+	_, err = service.CreatePost(ctx, &model.CreatePostParams{
+		Name:     "Xin chào thế giới",
+		UrlKey:   pgtype.Text{String: "Custom_Key", Valid: true},
+		AuthorID: 1,
+	})
+	require.ErrorIs(t, err, ErrUrlKeyInvalid)
+	_, err = service.UpdatePost(ctx, &model.UpdatePostParams{PostID: manual.PostID, UrlKey: pgtype.Text{String: "custom key/", Valid: true}})
+	require.ErrorIs(t, err, ErrUrlKeyInvalid)
+
+	generator.stale = 1
+	raced, err := service.CreatePost(ctx, &model.CreatePostParams{Name: "Xin chào thế giới", AuthorID: 1})
+	require.NoError(t, err)
+	require.Equal(t, "xin-chao-the-gioi-3", raced.UrlKey.String)
+
+	renamed, err := service.UpdatePost(ctx, &model.UpdatePostParams{PostID: second.PostID, Name: pgtype.Text{String: "Tạm biệt", Valid: true}})
+	require.NoError(t, err)
+	require.Equal(t, "xin-chao-the-gioi-2", renamed.UrlKey.String)
+
+	regenerated, err := service.UpdatePost(ctx, &model.UpdatePostParams{PostID: second.PostID, RegenerateUrlKey: true})
+	require.NoError(t, err)
+	require.Equal(t, "tam-biet", regenerated.UrlKey.String)
+
+	unchanged, err := service.UpdatePost(ctx, &model.UpdatePostParams{PostID: first.PostID, RegenerateUrlKey: true})
+	require.NoError(t, err)
+	require.Equal(t, "xin-chao-the-gioi", unchanged.UrlKey.String)
+
+	_, err = service.UpdatePost(ctx, &model.UpdatePostParams{PostID: 99, RegenerateUrlKey: true})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+// TestService_UpdatePostStatus test the workflow transitions and the publication times
+func TestService_UpdatePostStatus(t *testing.T) {
+	service, repo, _ := newTestService()
+	ctx := context.Background()
+	created, err := service.CreatePost(ctx, &model.CreatePostParams{Name: "Workflow", AuthorID: 1})
+	require.NoError(t, err)
+	require.Equal(t, model.PostStatusDraft, created.Status)
+	at := func(d time.Duration) pgtype.Timestamptz {
+		return pgtype.Timestamptz{Time: time.Now().Add(d), Valid: true}
+	}
+	move := func(status model.PostStatus, publishedAt pgtype.Timestamptz) (model.Post, error) {
+		return service.UpdatePostStatus(ctx, &model.UpdatePostStatusParams{PostID: created.PostID, Status: status, PublishedAt: publishedAt})
+	}
+
+	_, err = move("deleted", pgtype.Timestamptz{})
+	require.ErrorIs(t, err, ErrInvalidStatus)
+	_, err = move(model.PostStatusReview, at(time.Hour))
+	require.ErrorIs(t, err, ErrPublishedAtNotAllowed)
+	_, err = move(model.PostStatusScheduled, pgtype.Timestamptz{})
+	require.ErrorIs(t, err, ErrInvalidPublishedAt)
+	_, err = move(model.PostStatusScheduled, at(-time.Hour))
+	require.ErrorIs(t, err, ErrInvalidPublishedAt)
+	_, err = move(model.PostStatusPublished, at(time.Hour))
+	require.ErrorIs(t, err, ErrInvalidPublishedAt)
+
+	reviewed, err := move(model.PostStatusReview, pgtype.Timestamptz{})
+	require.NoError(t, err)
+	require.False(t, reviewed.PublishedAt.Valid)
+
+	scheduled, err := move(model.PostStatusScheduled, at(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, model.PostStatusScheduled, scheduled.Status)
+
+	published, err := move(model.PostStatusPublished, pgtype.Timestamptz{})
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now(), published.PublishedAt.Time, time.Minute)
+
+	_, err = move(model.PostStatusReview, pgtype.Timestamptz{})
+	require.ErrorIs(t, err, ErrInvalidStatusTransition)
+
+	archived, err := move(model.PostStatusArchived, pgtype.Timestamptz{})
+	require.NoError(t, err)
+	require.Equal(t, published.PublishedAt, archived.PublishedAt)
+	republished, err := move(model.PostStatusPublished, pgtype.Timestamptz{})
+	require.NoError(t, err)
+	require.Equal(t, published.PublishedAt, republished.PublishedAt)
+
+	loaded, err := service.GetPublishedPost(ctx, created.PostID)
+	require.NoError(t, err)
+	require.Equal(t, created.PostID, loaded.PostID)
+
+	p := repo.posts[created.PostID]
+	p.UrlKey = pgtype.Text{}
+	p.Status = model.PostStatusDraft
+	repo.posts[created.PostID] = p
+	_, err = move(model.PostStatusPublished, pgtype.Timestamptz{})
+	require.ErrorIs(t, err, ErrUrlKeyRequired)
+	_, err = service.GetPublishedPost(ctx, created.PostID)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	_, err = service.UpdatePostStatus(ctx, &model.UpdatePostStatusParams{PostID: 99, Status: model.PostStatusReview})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+// TestService_RunScheduler test the scheduler publishes the due posts until the context is done
+func TestService_RunScheduler(t *testing.T) {
+	service, repo, _ := newTestService()
+	ctx, cancel := context.WithCancel(context.Background())
+	due, err := service.CreatePost(ctx, &model.CreatePostParams{Name: "Due", AuthorID: 1})
+	require.NoError(t, err)
+	later, err := service.CreatePost(ctx, &model.CreatePostParams{Name: "Later", AuthorID: 1})
+	require.NoError(t, err)
+	for _, p := range []struct {
+		postID int64
+		delay  time.Duration
+	}{{due.PostID, -time.Minute}, {later.PostID, time.Hour}} {
+		scheduled := repo.posts[p.postID]
+		scheduled.Status = model.PostStatusScheduled
+		scheduled.PublishedAt = pgtype.Timestamptz{Time: time.Now().Add(p.delay), Valid: true}
+		repo.posts[p.postID] = scheduled
+	}
+
+	cancel()
+	require.NoError(t, service.RunScheduler(ctx, time.Hour))
+	require.Equal(t, model.PostStatusPublished, repo.posts[due.PostID].Status)
+	require.Equal(t, model.PostStatusScheduled, repo.posts[later.PostID].Status)
+}
+
+// TestService_PostRevisions test updates are recorded as revisions which can be compared and restored
+func TestService_PostRevisions(t *testing.T) {
+	service, repo, _ := newTestService()
+	ctx := context.Background()
+	text := func(s string) pgtype.Text { return pgtype.Text{String: s, Valid: true} }
+
+	created, err := service.CreatePost(ctx, &model.CreatePostParams{Name: "Draft", Content: text("line 1\nline 2"), AuthorID: 1})
+	require.NoError(t, err)
+	_, err = service.UpdatePost(ctx, &model.UpdatePostParams{PostID: created.PostID, Content: text("line 1\nline two\nline 3"), EditorID: 2})
+	require.NoError(t, err)
+	_, err = service.UpdatePost(ctx, &model.UpdatePostParams{PostID: created.PostID, CommentMode: text("closed"), EditorID: 2})
+	require.NoError(t, err)
+
+	list, err := service.GetListPostRevision(ctx, &model.GetListPostRevisionParams{PostID: created.PostID, PageSize: 10, CurrentPage: 1})
+	require.NoError(t, err)
+	require.EqualValues(t, 2, list.Totals)
+	require.EqualValues(t, 2, list.Revisions[0].AdminID.Int64)
+
+	revisionDiff, err := service.GetPostRevisionDiff(ctx, created.PostID, 1, 2)
+	require.NoError(t, err)
+	require.True(t, revisionDiff.HasChanges)
+	require.False(t, diff.HasChanges(revisionDiff.Name))
+	require.Equal(t, []diff.Op{diff.OpEqual, diff.OpDelete, diff.OpInsert, diff.OpInsert}, ops(revisionDiff.Content))
+
+	restored, err := service.RestorePostRevision(ctx, created.PostID, 1, 3)
+	require.NoError(t, err)
+	require.Equal(t, "line 1\nline 2", restored.Content.String)
+	require.Len(t, repo.revisions, 3)
+	require.Equal(t, pgtype.Int8{Int64: 1, Valid: true}, repo.revisions[2].RestoredFrom)
+
+	unchanged, err := service.GetPostRevisionDiff(ctx, created.PostID, 3, 1)
+	require.NoError(t, err)
+	require.False(t, unchanged.HasChanges)
+
+	_, err = service.GetPostRevisionDiff(ctx, created.PostID, 1, 99)
+	require.ErrorIs(t, err, ErrRevisionNotFound)
+	_, err = service.RestorePostRevision(ctx, 99, 1, 3)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	_, err = service.GetListPostRevision(ctx, &model.GetListPostRevisionParams{PostID: 99, PageSize: 10, CurrentPage: 1})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+// ops
+// Return the operations of a diff
+func ops(lines []diff.Line) []diff.Op {
+	var result []diff.Op
+	for _, line := range lines {
+		result = append(result, line.Op)
+	}
+	return result
+}
@@ -0,0 +1,64 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ParseQuery
+// Converts the text typed by a reader into a to_tsquery expression. Every word must match,
+// words between double quotes must follow each other and a word ending with * matches as a prefix.
+// Anything but letters and digits separates the words, so the expression is always valid.
+// @param text string
+// @return string the expression, empty when the text holds no word
+func ParseQuery(text string) string {
+	var terms []string
+	for i, part := range strings.Split(text, `"`) {
+		// Odd parts are between quotes, an unterminated quote runs until the end
+		if i%2 == 1 {
+			if term := parseTerm(part); term != "" {
+				terms = append(terms, term)
+			}
+			continue
+		}
+		for _, word := range strings.Fields(part) {
+			if term := parseTerm(word); term != "" {
+				terms = append(terms, term)
+			}
+		}
+	}
+	return strings.Join(terms, " & ")
+}
+
+// parseTerm
+// Converts a word or a phrase into the lexemes that must follow each other,
+// a hyphenated word being matched as a phrase too.
+// @param text string
+// @return string
+func parseTerm(text string) string {
+	var lexemes []string
+	var lexeme strings.Builder
+	flush := func(prefix bool) {
+		if lexeme.Len() == 0 {
+			return
+		}
+		value := strings.ToLower(lexeme.String())
+		if prefix {
+			value += ":*"
+		}
+		lexemes = append(lexemes, value)
+		lexeme.Reset()
+	}
+	for _, r := range text {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			lexeme.WriteRune(r)
+		case r == '*':
+			flush(true)
+		default:
+			flush(false)
+		}
+	}
+	flush(false)
+	return strings.Join(lexemes, " <-> ")
+}
@@ -0,0 +1,30 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "words", text: "Go  blog", want: "go & blog"},
+		{name: "phrase", text: `"Clean Code" go`, want: "clean <-> code & go"},
+		{name: "prefix", text: "post* migr*", want: "post:* & migr:*"},
+		{name: "prefix in phrase", text: `"static site gen*"`, want: "static <-> site <-> gen:*"},
+		{name: "hyphenated word", text: "e-mail", want: "e <-> mail"},
+		{name: "unterminated quote", text: `go "error handl*`, want: "go & error <-> handl:*"},
+		{name: "operators are not passed through", text: "go & !rust | (c:*) <-> 'x'", want: "go & rust & c & x"},
+		{name: "accents", text: "Café", want: "café"},
+		{name: "no word", text: ` "" * & `, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, ParseQuery(tt.text))
+		})
+	}
+}
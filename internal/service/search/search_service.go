@@ -0,0 +1,119 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"html"
+	"log"
+	"strings"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/search"
+	"github.com/daniel-vuky/go-blog/internal/repository/search"
+	"github.com/daniel-vuky/go-blog/pkg/config"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	// defaultLanguage
+	// Text search configuration used when none is configured
+	defaultLanguage = "english"
+	// undefinedObjectCode
+	// Postgres error code raised when the text search configuration does not exist
+	undefinedObjectCode = "42704"
+)
+
+var (
+	ErrEmptyQuery      = errors.New("search query must contain at least one word")
+	ErrInvalidLanguage = errors.New("search language is not a text search configuration of the database")
+)
+
+// highlighter
+// Turns the match markers of an escaped headline into html tags
+var highlighter = strings.NewReplacer(model.HighlightStart, "<mark>", model.HighlightStop, "</mark>")
+
+// Service
+// Searches the published posts of the blog.
+type Service struct {
+	SearchRepo search.Repository
+	language   string
+}
+
+// NewService
+// Returns a new instance of Service.
+func NewService(repo search.Repository, cfg *config.Search) *Service {
+	language := cfg.Language
+	if language == "" {
+		language = defaultLanguage
+	}
+	return &Service{SearchRepo: repo, language: language}
+}
+
+// SyncLanguage
+// Makes the post vectors use the configured language, rebuilding them when it changed.
+// @param c context.Context
+// @return error
+func (s *Service) SyncLanguage(c context.Context) error {
+	changed, err := s.SearchRepo.UpdateLanguage(c, s.language)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == undefinedObjectCode {
+			return ErrInvalidLanguage
+		}
+		return err
+	}
+	if changed {
+		log.Printf("search language changed to %s, post vectors rebuilt", s.language)
+	}
+	return nil
+}
+
+// ListPostResponse
+// Struct to hold the response of the SearchPost method.
+type ListPostResponse struct {
+	Totals int64        `json:"totals"`
+	Posts  []model.Post `json:"posts"`
+}
+
+// SearchPost
+// Returns the published posts matching the text, best match first, with their matches highlighted.
+// @param c context.Context
+// @param text string
+// @param pageSize int32
+// @param currentPage int32
+// @return ListPostResponse
+func (s *Service) SearchPost(c context.Context, text string, pageSize int32, currentPage int32) (ListPostResponse, error) {
+	var rsp ListPostResponse
+	query := ParseQuery(text)
+	if query == "" {
+		return rsp, ErrEmptyQuery
+	}
+	listPost, totalPost, err := s.SearchRepo.SearchPost(c, &model.SearchPostParams{
+		Language:    s.language,
+		Query:       query,
+		PageSize:    pageSize,
+		CurrentPage: currentPage,
+	})
+	if err != nil {
+		return rsp, err
+	}
+	if listPost == nil {
+		listPost = []model.Post{}
+	}
+	for i := range listPost {
+		listPost[i].Headline = highlight(listPost[i].Headline)
+	}
+	rsp = ListPostResponse{
+		Totals: totalPost,
+		Posts:  listPost,
+	}
+
+	return rsp, nil
+}
+
+// highlight
+// Escapes a headline and wraps its matches in mark tags.
+// @param headline string
+// @return string
+func highlight(headline string) string {
+	return highlighter.Replace(html.EscapeString(headline))
+}
@@ -0,0 +1,63 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/search"
+	"github.com/daniel-vuky/go-blog/pkg/config"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySearchRepository
+// Returns fixed results and records the searches it received.
+type memorySearchRepository struct {
+	posts       []model.Post
+	searches    []model.SearchPostParams
+	languages   []string
+	languageErr error
+}
+
+func (repo *memorySearchRepository) SearchPost(_ context.Context, arg *model.SearchPostParams) ([]model.Post, int64, error) {
+	repo.searches = append(repo.searches, *arg)
+	return repo.posts, int64(len(repo.posts)), nil
+}
+
+func (repo *memorySearchRepository) UpdateLanguage(_ context.Context, language string) (bool, error) {
+	repo.languages = append(repo.languages, language)
+	return true, repo.languageErr
+}
+
+func TestService_SearchPost(t *testing.T) {
+	repo := &memorySearchRepository{posts: []model.Post{{
+		Name:     "Go",
+		Headline: "use <b>" + model.HighlightStart + "generics" + model.HighlightStop + "</b> & more",
+	}}}
+	s := NewService(repo, &config.Search{Language: "simple"})
+
+	rsp, err := s.SearchPost(context.Background(), `"Go generics" tip*`, 20, 2)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rsp.Totals)
+	require.Equal(t, "use &lt;b&gt;<mark>generics</mark>&lt;/b&gt; &amp; more", rsp.Posts[0].Headline)
+	require.Equal(t, []model.SearchPostParams{{
+		Language:    "simple",
+		Query:       "go <-> generics & tip:*",
+		PageSize:    20,
+		CurrentPage: 2,
+	}}, repo.searches)
+
+	_, err = s.SearchPost(context.Background(), `"" -`, 20, 1)
+	require.ErrorIs(t, err, ErrEmptyQuery)
+	require.Len(t, repo.searches, 1)
+}
+
+func TestService_SyncLanguage(t *testing.T) {
+	repo := &memorySearchRepository{}
+	require.NoError(t, NewService(repo, &config.Search{}).SyncLanguage(context.Background()))
+	require.Equal(t, []string{defaultLanguage}, repo.languages)
+
+	repo.languageErr = &pgconn.PgError{Code: undefinedObjectCode}
+	err := NewService(repo, &config.Search{Language: "klingon"}).SyncLanguage(context.Background())
+	require.ErrorIs(t, err, ErrInvalidLanguage)
+}
@@ -0,0 +1,230 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/session"
+	"github.com/daniel-vuky/go-blog/internal/repository/session"
+	"github.com/daniel-vuky/go-blog/pkg/config"
+	"github.com/daniel-vuky/go-blog/pkg/token"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// refreshTokenBytes
+// Amount of random bytes of a refresh token
+const refreshTokenBytes = 32
+
+var (
+	ErrInvalidRefreshToken = errors.New("refresh token is invalid")
+	ErrExpiredRefreshToken = errors.New("refresh token has expired")
+	ErrRefreshTokenReused  = errors.New("refresh token was reused, the session has been revoked")
+)
+
+// Tokens
+// Access token and refresh token handed to a signed in user.
+type Tokens struct {
+	AccessToken           string    `json:"access_token"`
+	AccessTokenExpiresAt  time.Time `json:"access_token_expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+}
+
+// Service
+// Issues, rotates and revokes the refresh token sessions of site users.
+type Service struct {
+	SessionRepo session.Repository
+	TokenMaker  token.Maker
+	config      *config.Token
+}
+
+// NewService
+// Returns a new instance of Service.
+func NewService(repo session.Repository, tokenMaker token.Maker, cfg *config.Token) *Service {
+	return &Service{
+		SessionRepo: repo,
+		TokenMaker:  tokenMaker,
+		config:      cfg,
+	}
+}
+
+// CreateSession
+// Starts a new session of a user and returns its first pair of tokens.
+// @param c context.Context
+// @param userID int64
+// @param userAgent string
+// @param clientIp string
+// @return Tokens, error
+func (s *Service) CreateSession(c context.Context, userID int64, userAgent string, clientIp string) (Tokens, error) {
+	familyID, err := uuid.NewRandom()
+	if err != nil {
+		return Tokens{}, err
+	}
+	refreshToken, hashedToken, err := generateRefreshToken()
+	if err != nil {
+		return Tokens{}, err
+	}
+	storedToken, err := s.SessionRepo.Create(c, &model.CreateRefreshTokenParams{
+		UserID:       userID,
+		RefreshToken: hashedToken,
+		UserAgent:    userAgent,
+		ClientIp:     clientIp,
+		ExpiredAt:    time.Now().Add(s.config.RefreshTokenDuration),
+		FamilyID:     familyID,
+	})
+	if err != nil {
+		return Tokens{}, err
+	}
+	return s.issueTokens(refreshToken, storedToken)
+}
+
+// Refresh
+// Exchanges a refresh token for a new pair of tokens.
+// Presenting a refresh token twice blocks every token of its family.
+// @param c context.Context
+// @param refreshToken string
+// @param userAgent string
+// @param clientIp string
+// @return Tokens, error
+func (s *Service) Refresh(c context.Context, refreshToken string, userAgent string, clientIp string) (Tokens, error) {
+	storedToken, err := s.getRefreshToken(c, refreshToken)
+	if err != nil {
+		return Tokens{}, err
+	}
+	if storedToken.UsedAt.Valid {
+		if err := s.SessionRepo.BlockFamily(c, storedToken.FamilyID); err != nil {
+			return Tokens{}, err
+		}
+		return Tokens{}, ErrRefreshTokenReused
+	}
+	if storedToken.IsBlocked {
+		return Tokens{}, ErrInvalidRefreshToken
+	}
+	if time.Now().After(storedToken.ExpiredAt) {
+		return Tokens{}, ErrExpiredRefreshToken
+	}
+
+	nextToken, hashedToken, err := generateRefreshToken()
+	if err != nil {
+		return Tokens{}, err
+	}
+	rotatedToken, err := s.SessionRepo.Rotate(c, storedToken.RefreshTokenID, &model.CreateRefreshTokenParams{
+		UserID:       storedToken.UserID,
+		RefreshToken: hashedToken,
+		UserAgent:    userAgent,
+		ClientIp:     clientIp,
+		ExpiredAt:    storedToken.ExpiredAt,
+		FamilyID:     storedToken.FamilyID,
+	})
+	if err != nil {
+		if errors.Is(err, session.ErrRefreshTokenUsed) {
+			// Another request rotated the same token first
+			if err := s.SessionRepo.BlockFamily(c, storedToken.FamilyID); err != nil {
+				return Tokens{}, err
+			}
+			return Tokens{}, ErrRefreshTokenReused
+		}
+		return Tokens{}, err
+	}
+	return s.issueTokens(nextToken, rotatedToken)
+}
+
+// Logout
+// Ends the session the refresh token belongs to.
+// @param c context.Context
+// @param refreshToken string
+// @return error
+func (s *Service) Logout(c context.Context, refreshToken string) error {
+	storedToken, err := s.getRefreshToken(c, refreshToken)
+	if err != nil {
+		return err
+	}
+	return s.SessionRepo.BlockFamily(c, storedToken.FamilyID)
+}
+
+// GetListSessions
+// Returns the active sessions of a user.
+// @param c context.Context
+// @param userID int64
+// @return []model.RefreshToken, error
+func (s *Service) GetListSessions(c context.Context, userID int64) ([]model.RefreshToken, error) {
+	return s.SessionRepo.GetListActive(c, userID)
+}
+
+// RevokeUserSessions
+// Ends every session of a user.
+// Access tokens already issued stay valid until they expire.
+// @param c context.Context
+// @param userID int64
+// @return error
+func (s *Service) RevokeUserSessions(c context.Context, userID int64) error {
+	return s.SessionRepo.BlockUser(c, userID)
+}
+
+// getRefreshToken
+// Looks up a refresh token by the hash of its plain value.
+// @param c context.Context
+// @param refreshToken string
+// @return model.RefreshToken, error
+func (s *Service) getRefreshToken(c context.Context, refreshToken string) (model.RefreshToken, error) {
+	if refreshToken == "" {
+		return model.RefreshToken{}, ErrInvalidRefreshToken
+	}
+	storedToken, err := s.SessionRepo.Get(c, hashRefreshToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.RefreshToken{}, ErrInvalidRefreshToken
+		}
+		return model.RefreshToken{}, err
+	}
+	return storedToken, nil
+}
+
+// issueTokens
+// Creates the access token accompanying a stored refresh token.
+// @param refreshToken string
+// @param storedToken model.RefreshToken
+// @return Tokens, error
+func (s *Service) issueTokens(refreshToken string, storedToken model.RefreshToken) (Tokens, error) {
+	payload, err := token.NewUserPayload(storedToken.UserID, s.config.AccessTokenDuration)
+	if err != nil {
+		return Tokens{}, err
+	}
+	accessToken, err := s.TokenMaker.CreateToken(payload)
+	if err != nil {
+		return Tokens{}, err
+	}
+	return Tokens{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  payload.ExpiredAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: storedToken.ExpiredAt,
+	}, nil
+}
+
+// generateRefreshToken
+// Creates a random refresh token and the hash which is stored instead of it.
+// @return string, string, error
+func generateRefreshToken() (string, string, error) {
+	buffer := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", "", err
+	}
+	refreshToken := base64.RawURLEncoding.EncodeToString(buffer)
+	return refreshToken, hashRefreshToken(refreshToken), nil
+}
+
+// hashRefreshToken
+// Hashes a refresh token, a fast hash is enough for random high entropy values.
+// @param refreshToken string
+// @return string
+func hashRefreshToken(refreshToken string) string {
+	sum := sha256.Sum256([]byte(refreshToken))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,165 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/session"
+	"github.com/daniel-vuky/go-blog/internal/repository/session"
+	"github.com/daniel-vuky/go-blog/pkg/config"
+	"github.com/daniel-vuky/go-blog/pkg/token"
+	goRandom "github.com/daniel-vuky/go-random"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySessionRepository
+// In-memory refresh token repository
+type memorySessionRepository struct {
+	tokens map[int64]*model.RefreshToken
+	nextID int64
+}
+
+func (repo *memorySessionRepository) Get(_ context.Context, hashedToken string) (model.RefreshToken, error) {
+	for _, t := range repo.tokens {
+		if t.RefreshToken == hashedToken {
+			return *t, nil
+		}
+	}
+	return model.RefreshToken{}, pgx.ErrNoRows
+}
+
+func (repo *memorySessionRepository) GetListActive(_ context.Context, userID int64) ([]model.RefreshToken, error) {
+	var items []model.RefreshToken
+	for _, t := range repo.tokens {
+		if t.UserID == userID && !t.IsBlocked && !t.UsedAt.Valid && t.ExpiredAt.After(time.Now()) {
+			items = append(items, *t)
+		}
+	}
+	return items, nil
+}
+
+func (repo *memorySessionRepository) Create(_ context.Context, arg *model.CreateRefreshTokenParams) (model.RefreshToken, error) {
+	repo.nextID++
+	t := &model.RefreshToken{
+		RefreshTokenID: repo.nextID,
+		UserID:         arg.UserID,
+		RefreshToken:   arg.RefreshToken,
+		UserAgent:      arg.UserAgent,
+		ClientIp:       arg.ClientIp,
+		ExpiredAt:      arg.ExpiredAt,
+		CreatedAt:      time.Now(),
+		FamilyID:       arg.FamilyID,
+	}
+	repo.tokens[t.RefreshTokenID] = t
+	return *t, nil
+}
+
+func (repo *memorySessionRepository) Rotate(ctx context.Context, refreshTokenID int64, arg *model.CreateRefreshTokenParams) (model.RefreshToken, error) {
+	t := repo.tokens[refreshTokenID]
+	if t.UsedAt.Valid || t.IsBlocked {
+		return model.RefreshToken{}, session.ErrRefreshTokenUsed
+	}
+	t.UsedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	return repo.Create(ctx, arg)
+}
+
+func (repo *memorySessionRepository) BlockFamily(_ context.Context, familyID uuid.UUID) error {
+	for _, t := range repo.tokens {
+		if t.FamilyID == familyID {
+			t.IsBlocked = true
+		}
+	}
+	return nil
+}
+
+func (repo *memorySessionRepository) BlockUser(_ context.Context, userID int64) error {
+	for _, t := range repo.tokens {
+		if t.UserID == userID {
+			t.IsBlocked = true
+		}
+	}
+	return nil
+}
+
+// newTestService
+// Create a session service backed by an in-memory repository
+func newTestService(t *testing.T) *Service {
+	tokenMaker, err := token.NewJWTMaker(goRandom.RandomString(32))
+	require.NoError(t, err)
+	return NewService(
+		&memorySessionRepository{tokens: map[int64]*model.RefreshToken{}},
+		tokenMaker,
+		&config.Token{AccessTokenDuration: time.Minute, RefreshTokenDuration: time.Hour},
+	)
+}
+
+// TestService_Refresh test rotating refresh tokens
+func TestService_Refresh(t *testing.T) {
+	service := newTestService(t)
+	ctx := context.Background()
+
+	tokens, err := service.CreateSession(ctx, 1, "agent", "127.0.0.1")
+	require.NoError(t, err)
+	payload, err := service.TokenMaker.VerifyToken(tokens.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, token.AudienceUserAccess, payload.Audience)
+	require.Equal(t, int64(1), payload.UserID)
+
+	rotated, err := service.Refresh(ctx, tokens.RefreshToken, "agent", "127.0.0.1")
+	require.NoError(t, err)
+	require.NotEqual(t, tokens.RefreshToken, rotated.RefreshToken)
+	require.WithinDuration(t, tokens.RefreshTokenExpiresAt, rotated.RefreshTokenExpiresAt, time.Second)
+
+	sessions, err := service.GetListSessions(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+
+	_, err = service.Refresh(ctx, goRandom.RandomString(43), "agent", "127.0.0.1")
+	require.ErrorIs(t, err, ErrInvalidRefreshToken)
+}
+
+// TestService_Refresh_Reuse test reusing a rotated token revokes the whole family
+func TestService_Refresh_Reuse(t *testing.T) {
+	service := newTestService(t)
+	ctx := context.Background()
+
+	tokens, err := service.CreateSession(ctx, 1, "agent", "127.0.0.1")
+	require.NoError(t, err)
+	otherSession, err := service.CreateSession(ctx, 1, "agent", "127.0.0.1")
+	require.NoError(t, err)
+	rotated, err := service.Refresh(ctx, tokens.RefreshToken, "agent", "127.0.0.1")
+	require.NoError(t, err)
+
+	_, err = service.Refresh(ctx, tokens.RefreshToken, "agent", "127.0.0.1")
+	require.ErrorIs(t, err, ErrRefreshTokenReused)
+	_, err = service.Refresh(ctx, rotated.RefreshToken, "agent", "127.0.0.1")
+	require.ErrorIs(t, err, ErrInvalidRefreshToken)
+
+	_, err = service.Refresh(ctx, otherSession.RefreshToken, "agent", "127.0.0.1")
+	require.NoError(t, err)
+}
+
+// TestService_Logout test ending sessions
+func TestService_Logout(t *testing.T) {
+	service := newTestService(t)
+	ctx := context.Background()
+
+	tokens, err := service.CreateSession(ctx, 1, "agent", "127.0.0.1")
+	require.NoError(t, err)
+	require.NoError(t, service.Logout(ctx, tokens.RefreshToken))
+	_, err = service.Refresh(ctx, tokens.RefreshToken, "agent", "127.0.0.1")
+	require.ErrorIs(t, err, ErrInvalidRefreshToken)
+
+	tokens, err = service.CreateSession(ctx, 1, "agent", "127.0.0.1")
+	require.NoError(t, err)
+	require.NoError(t, service.RevokeUserSessions(ctx, 1))
+	_, err = service.Refresh(ctx, tokens.RefreshToken, "agent", "127.0.0.1")
+	require.ErrorIs(t, err, ErrInvalidRefreshToken)
+	sessions, err := service.GetListSessions(ctx, 1)
+	require.NoError(t, err)
+	require.Empty(t, sessions)
+}
@@ -0,0 +1,406 @@
+package twofactor
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"image/png"
+	"log"
+	"strings"
+	"time"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/admin"
+	"github.com/daniel-vuky/go-blog/internal/repository/twofactor"
+	"github.com/daniel-vuky/go-blog/internal/service/authorization"
+	"github.com/daniel-vuky/go-blog/internal/service/lockout"
+	"github.com/daniel-vuky/go-blog/pkg/config"
+	"github.com/daniel-vuky/go-blog/pkg/encryption"
+	"github.com/jackc/pgx/v5"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	// totpPeriod
+	// Lifetime of a TOTP code
+	totpPeriod = 30 * time.Second
+	// qrCodeSize
+	// Width and height of the provisioning QR code in pixels
+	qrCodeSize = 256
+	// recoveryCodeBytes
+	// Amount of random bytes of a recovery code
+	recoveryCodeBytes = 5
+)
+
+var (
+	ErrTwoFactorAlreadyEnabled = twofactor.ErrTwoFactorAlreadyEnabled
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorNotSetUp       = errors.New("two-factor authentication setup has not been started")
+	ErrTwoFactorRequired       = errors.New("two-factor authentication is required for this role")
+	ErrInvalidTwoFactorCode    = errors.New("two-factor code is invalid")
+)
+
+// totpOpts
+// Parameters of the generated TOTP codes, matching the defaults of authenticator apps
+var totpOpts = totp.ValidateOpts{
+	Period:    uint(totpPeriod / time.Second),
+	Digits:    otp.DigitsSix,
+	Algorithm: otp.AlgorithmSHA1,
+}
+
+// SetupResponse
+// Secret of a pending enrollment and the ways to add it to an authenticator app.
+type SetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningUri string `json:"provisioning_uri"`
+	QrCode          string `json:"qr_code"`
+}
+
+// Status
+// Two-factor state of an admin.
+type Status struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at,omitempty"`
+	RemainingRecoveryCodes int64      `json:"remaining_recovery_codes"`
+}
+
+// Service
+// Enrolls admins into TOTP two-factor authentication and verifies their codes.
+type Service struct {
+	TwoFactorRepo        twofactor.Repository
+	AuthorizationService *authorization.Service
+	Lockout              *lockout.Service
+	cipher               *encryption.AESGCM
+	config               *config.TwoFactor
+}
+
+// NewService
+// Returns a new instance of Service.
+func NewService(
+	repo twofactor.Repository,
+	authorizationService *authorization.Service,
+	lockoutService *lockout.Service,
+	cfg *config.TwoFactor,
+) (*Service, error) {
+	cipher, err := encryption.NewAESGCM(cfg.EncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		TwoFactorRepo:        repo,
+		AuthorizationService: authorizationService,
+		Lockout:              lockoutService,
+		cipher:               cipher,
+		config:               cfg,
+	}, nil
+}
+
+// Setup
+// Starts an enrollment by creating a new secret, which is only active once Enable confirmed a code.
+// @param c context.Context
+// @param adminUser *model.Admin
+// @return SetupResponse, error
+func (s *Service) Setup(c context.Context, adminUser *model.Admin) (SetupResponse, error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      s.config.Issuer,
+		AccountName: adminUser.Email,
+		Period:      totpOpts.Period,
+		Digits:      totpOpts.Digits,
+		Algorithm:   totpOpts.Algorithm,
+	})
+	if err != nil {
+		return SetupResponse{}, err
+	}
+	encryptedSecret, err := s.cipher.Encrypt(key.Secret())
+	if err != nil {
+		return SetupResponse{}, err
+	}
+	if _, err = s.TwoFactorRepo.SaveSecret(c, adminUser.AdminID, encryptedSecret); err != nil {
+		return SetupResponse{}, err
+	}
+	image, err := key.Image(qrCodeSize, qrCodeSize)
+	if err != nil {
+		return SetupResponse{}, err
+	}
+	var buffer bytes.Buffer
+	if err = png.Encode(&buffer, image); err != nil {
+		return SetupResponse{}, err
+	}
+	return SetupResponse{
+		Secret:          key.Secret(),
+		ProvisioningUri: key.URL(),
+		QrCode:          "data:image/png;base64," + base64.StdEncoding.EncodeToString(buffer.Bytes()),
+	}, nil
+}
+
+// Enable
+// Completes an enrollment with a code of the pending secret and returns the recovery codes.
+// @param c context.Context
+// @param adminID int32
+// @param code string
+// @return []string, error
+func (s *Service) Enable(c context.Context, adminID int32, code string) ([]string, error) {
+	settings, err := s.TwoFactorRepo.Get(c, adminID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTwoFactorNotSetUp
+		}
+		return nil, err
+	}
+	if settings.EnabledAt.Valid {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	step, ok, err := s.matchCode(&settings, code, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+	recoveryCodes, hashes, err := s.generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err = s.TwoFactorRepo.Enable(c, adminID, step, hashes); err != nil {
+		return nil, err
+	}
+	return recoveryCodes, nil
+}
+
+// Disable
+// Turns two-factor authentication off after checking a code, unless the role of the admin requires it.
+// @param c context.Context
+// @param adminUser *model.Admin
+// @param code string
+// @return error
+func (s *Service) Disable(c context.Context, adminUser *model.Admin, code string) error {
+	isRequired, err := s.IsRequired(c, adminUser.RoleID)
+	if err != nil {
+		return err
+	}
+	if isRequired {
+		return ErrTwoFactorRequired
+	}
+	if err = s.verifyCode(c, adminUser.AdminID, code); err != nil {
+		return err
+	}
+	return s.TwoFactorRepo.Delete(c, adminUser.AdminID)
+}
+
+// Reset
+// Removes the two-factor settings of an admin who lost its device and recovery codes.
+// @param c context.Context
+// @param adminID int32
+// @return error
+func (s *Service) Reset(c context.Context, adminID int32) error {
+	return s.TwoFactorRepo.Delete(c, adminID)
+}
+
+// RegenerateRecoveryCodes
+// Replaces the recovery codes of an admin after checking a code.
+// @param c context.Context
+// @param adminID int32
+// @param code string
+// @return []string, error
+func (s *Service) RegenerateRecoveryCodes(c context.Context, adminID int32, code string) ([]string, error) {
+	if err := s.verifyCode(c, adminID, code); err != nil {
+		return nil, err
+	}
+	recoveryCodes, hashes, err := s.generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err = s.TwoFactorRepo.ReplaceRecoveryCodes(c, adminID, hashes); err != nil {
+		return nil, err
+	}
+	return recoveryCodes, nil
+}
+
+// GetStatus
+// Returns the two-factor state of an admin.
+// @param c context.Context
+// @param adminID int32
+// @return Status, error
+func (s *Service) GetStatus(c context.Context, adminID int32) (Status, error) {
+	settings, err := s.TwoFactorRepo.Get(c, adminID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Status{}, nil
+		}
+		return Status{}, err
+	}
+	if !settings.EnabledAt.Valid {
+		return Status{}, nil
+	}
+	remaining, err := s.TwoFactorRepo.CountRecoveryCodes(c, adminID)
+	if err != nil {
+		return Status{}, err
+	}
+	return Status{
+		Enabled:                true,
+		EnabledAt:              &settings.EnabledAt.Time,
+		RemainingRecoveryCodes: remaining,
+	}, nil
+}
+
+// IsEnabled
+// Checks if an admin completed the two-factor enrollment.
+// @param c context.Context
+// @param adminID int32
+// @return bool, error
+func (s *Service) IsEnabled(c context.Context, adminID int32) (bool, error) {
+	settings, err := s.TwoFactorRepo.Get(c, adminID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return settings.EnabledAt.Valid, nil
+}
+
+// IsRequired
+// Checks if the role makes two-factor authentication mandatory.
+// @param c context.Context
+// @param roleID int64
+// @return bool, error
+func (s *Service) IsRequired(c context.Context, roleID int64) (bool, error) {
+	return s.AuthorizationService.IsTwoFactorRequired(c, roleID)
+}
+
+// Verify
+// Checks the second factor of a login. A failure counts as a failed login of the admin
+// and a success clears the failed login counters left by the password step.
+// @param c context.Context
+// @param adminUser *model.Admin
+// @param code string
+// @param clientIp string
+// @return error
+func (s *Service) Verify(c context.Context, adminUser *model.Admin, code string, clientIp string) error {
+	if err := s.Lockout.CheckClientIp(c, clientIp); err != nil {
+		return err
+	}
+	err := s.verifyCode(c, adminUser.AdminID, code)
+	if err != nil {
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			if lockErr := s.Lockout.RegisterFailedLogin(c, adminUser.Email, clientIp); lockErr != nil {
+				log.Printf("failed to register failed two-factor login of %s: %v", clientIp, lockErr)
+			}
+		}
+		return err
+	}
+	if err = s.Lockout.RegisterSuccessfulLogin(c, adminUser.AdminID, clientIp); err != nil {
+		log.Printf("failed to reset failed logins of admin %d: %v", adminUser.AdminID, err)
+	}
+	return nil
+}
+
+// verifyCode
+// Accepts a TOTP code which was not used yet or an unused recovery code.
+// @param c context.Context
+// @param adminID int32
+// @param code string
+// @return error
+func (s *Service) verifyCode(c context.Context, adminID int32, code string) error {
+	settings, err := s.TwoFactorRepo.Get(c, adminID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrTwoFactorNotEnabled
+		}
+		return err
+	}
+	if !settings.EnabledAt.Valid {
+		return ErrTwoFactorNotEnabled
+	}
+	step, ok, err := s.matchCode(&settings, code, time.Now())
+	if err != nil {
+		return err
+	}
+	if ok {
+		// A code is only accepted once, even while it is still displayed
+		accepted, err := s.TwoFactorRepo.UseStep(c, adminID, step)
+		if err != nil {
+			return err
+		}
+		if !accepted {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+	used, err := s.TwoFactorRepo.UseRecoveryCode(c, adminID, hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// matchCode
+// Compares a code with the codes of the current, previous and next time steps.
+// @param settings *model.AdminTwoFactor
+// @param code string
+// @param now time.Time
+// @return int64, bool, error
+func (s *Service) matchCode(settings *model.AdminTwoFactor, code string, now time.Time) (int64, bool, error) {
+	code = strings.TrimSpace(code)
+	if len(code) != totpOpts.Digits.Length() {
+		return 0, false, nil
+	}
+	secret, err := s.cipher.Decrypt(settings.EncryptedSecret)
+	if err != nil {
+		return 0, false, err
+	}
+	for _, skew := range []int64{0, -1, 1} {
+		at := now.Add(time.Duration(skew) * totpPeriod)
+		expected, err := totp.GenerateCodeCustom(secret, at, totpOpts)
+		if err != nil {
+			return 0, false, err
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return at.Unix() / int64(totpPeriod/time.Second), true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// generateRecoveryCodes
+// Creates new recovery codes and the hashes which are stored instead of them.
+// @return []string, []string, error
+func (s *Service) generateRecoveryCodes() ([]string, []string, error) {
+	count := s.config.RecoveryCodes
+	if count <= 0 {
+		count = 10
+	}
+	codes := make([]string, 0, count)
+	hashes := make([]string, 0, count)
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	for i := 0; i < count; i++ {
+		buffer := make([]byte, recoveryCodeBytes*2)
+		if _, err := rand.Read(buffer); err != nil {
+			return nil, nil, err
+		}
+		encoded := strings.ToLower(encoding.EncodeToString(buffer))
+		code := encoded[:8] + "-" + encoded[8:16]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode
+// Hashes a recovery code ignoring its case, spaces and dashes.
+// @param code string
+// @return string
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,237 @@
+package twofactor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/admin"
+	"github.com/daniel-vuky/go-blog/internal/repository/twofactor"
+	"github.com/daniel-vuky/go-blog/internal/service/authorization"
+	"github.com/daniel-vuky/go-blog/pkg/config"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryTwoFactorRepository
+// In-memory two-factor repository
+type memoryTwoFactorRepository struct {
+	settings      map[int32]*model.AdminTwoFactor
+	recoveryCodes map[int32]map[string]bool
+}
+
+func (repo *memoryTwoFactorRepository) Get(_ context.Context, adminID int32) (model.AdminTwoFactor, error) {
+	settings, ok := repo.settings[adminID]
+	if !ok {
+		return model.AdminTwoFactor{}, pgx.ErrNoRows
+	}
+	return *settings, nil
+}
+
+func (repo *memoryTwoFactorRepository) CountRecoveryCodes(_ context.Context, adminID int32) (int64, error) {
+	var count int64
+	for _, used := range repo.recoveryCodes[adminID] {
+		if !used {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (repo *memoryTwoFactorRepository) SaveSecret(_ context.Context, adminID int32, encryptedSecret string) (model.AdminTwoFactor, error) {
+	if settings, ok := repo.settings[adminID]; ok && settings.EnabledAt.Valid {
+		return model.AdminTwoFactor{}, twofactor.ErrTwoFactorAlreadyEnabled
+	}
+	repo.settings[adminID] = &model.AdminTwoFactor{AdminID: adminID, EncryptedSecret: encryptedSecret, CreatedAt: time.Now()}
+	return *repo.settings[adminID], nil
+}
+
+func (repo *memoryTwoFactorRepository) Enable(ctx context.Context, adminID int32, step int64, recoveryCodeHashes []string) error {
+	repo.settings[adminID].LastUsedStep = step
+	repo.settings[adminID].EnabledAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	return repo.ReplaceRecoveryCodes(ctx, adminID, recoveryCodeHashes)
+}
+
+func (repo *memoryTwoFactorRepository) UseStep(_ context.Context, adminID int32, step int64) (bool, error) {
+	settings := repo.settings[adminID]
+	if settings.LastUsedStep >= step {
+		return false, nil
+	}
+	settings.LastUsedStep = step
+	return true, nil
+}
+
+func (repo *memoryTwoFactorRepository) UseRecoveryCode(_ context.Context, adminID int32, codeHash string) (bool, error) {
+	used, ok := repo.recoveryCodes[adminID][codeHash]
+	if !ok || used {
+		return false, nil
+	}
+	repo.recoveryCodes[adminID][codeHash] = true
+	return true, nil
+}
+
+func (repo *memoryTwoFactorRepository) ReplaceRecoveryCodes(_ context.Context, adminID int32, recoveryCodeHashes []string) error {
+	repo.recoveryCodes[adminID] = map[string]bool{}
+	for _, codeHash := range recoveryCodeHashes {
+		repo.recoveryCodes[adminID][codeHash] = false
+	}
+	return nil
+}
+
+func (repo *memoryTwoFactorRepository) Delete(_ context.Context, adminID int32) error {
+	delete(repo.settings, adminID)
+	delete(repo.recoveryCodes, adminID)
+	return nil
+}
+
+// memoryAuthorizationRepository
+// In-memory authorization repository only supporting the role lookups
+type memoryAuthorizationRepository struct {
+	roles map[int64]model.AuthorizationRole
+}
+
+func (repo *memoryAuthorizationRepository) GetRole(_ context.Context, roleID int64) (model.AuthorizationRole, error) {
+	role, ok := repo.roles[roleID]
+	if !ok {
+		return model.AuthorizationRole{}, pgx.ErrNoRows
+	}
+	return role, nil
+}
+
+func (repo *memoryAuthorizationRepository) GetListRoles(context.Context) ([]model.AuthorizationRole, error) {
+	return nil, nil
+}
+
+func (repo *memoryAuthorizationRepository) GetRules(context.Context, int64) ([]model.AuthorizationRule, error) {
+	return nil, nil
+}
+
+func (repo *memoryAuthorizationRepository) CreateRole(context.Context, *model.CreateAuthorizationRoleParams) (model.AuthorizationRole, error) {
+	return model.AuthorizationRole{}, nil
+}
+
+func (repo *memoryAuthorizationRepository) UpdateRole(context.Context, *model.UpdateAuthorizationRoleParams) (model.AuthorizationRole, error) {
+	return model.AuthorizationRole{}, nil
+}
+
+func (repo *memoryAuthorizationRepository) DeleteRole(context.Context, int64) (model.AuthorizationRole, error) {
+	return model.AuthorizationRole{}, nil
+}
+
+func (repo *memoryAuthorizationRepository) ReplaceRules(context.Context, *model.ReplaceAuthorizationRulesParams) ([]model.AuthorizationRule, error) {
+	return nil, nil
+}
+
+// newTestService
+// Create a service with an optional role (1) and a mandatory role (2)
+func newTestService(t *testing.T) (*Service, *memoryTwoFactorRepository) {
+	repo := &memoryTwoFactorRepository{
+		settings:      map[int32]*model.AdminTwoFactor{},
+		recoveryCodes: map[int32]map[string]bool{},
+	}
+	authorizationService := authorization.NewService(&memoryAuthorizationRepository{
+		roles: map[int64]model.AuthorizationRole{
+			1: {RoleID: 1, RoleName: "Editor"},
+			2: {RoleID: 2, RoleName: "Administrator", IsAdministrator: true, RequireTwoFactor: true},
+		},
+	})
+	service, err := NewService(repo, authorizationService, nil, &config.TwoFactor{
+		Issuer:        "Go Blog",
+		EncryptionKey: "abcdefghijklmnopqrstuvwxyz123456",
+		RecoveryCodes: 4,
+	})
+	require.NoError(t, err)
+	return service, repo
+}
+
+// enroll
+// Complete the enrollment of an admin and return its secret and recovery codes
+func enroll(t *testing.T, service *Service, adminUser *model.Admin) (string, []string) {
+	setup, err := service.Setup(context.Background(), adminUser)
+	require.NoError(t, err)
+	code, err := totp.GenerateCode(setup.Secret, time.Now())
+	require.NoError(t, err)
+	recoveryCodes, err := service.Enable(context.Background(), adminUser.AdminID, code)
+	require.NoError(t, err)
+	return setup.Secret, recoveryCodes
+}
+
+// TestService_Enrollment test setting up and enabling two-factor authentication
+func TestService_Enrollment(t *testing.T) {
+	service, repo := newTestService(t)
+	adminUser := &model.Admin{AdminID: 1, RoleID: 1, Email: "admin@example.com"}
+
+	_, err := service.Enable(context.Background(), adminUser.AdminID, "123456")
+	require.ErrorIs(t, err, ErrTwoFactorNotSetUp)
+
+	setup, err := service.Setup(context.Background(), adminUser)
+	require.NoError(t, err)
+	require.Contains(t, setup.ProvisioningUri, "otpauth://totp/")
+	require.Contains(t, setup.QrCode, "data:image/png;base64,")
+	require.NotContains(t, repo.settings[adminUser.AdminID].EncryptedSecret, setup.Secret)
+
+	isEnabled, err := service.IsEnabled(context.Background(), adminUser.AdminID)
+	require.NoError(t, err)
+	require.False(t, isEnabled)
+
+	_, err = service.Enable(context.Background(), adminUser.AdminID, "abcdef")
+	require.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+
+	code, err := totp.GenerateCode(setup.Secret, time.Now())
+	require.NoError(t, err)
+	recoveryCodes, err := service.Enable(context.Background(), adminUser.AdminID, code)
+	require.NoError(t, err)
+	require.Len(t, recoveryCodes, 4)
+
+	status, err := service.GetStatus(context.Background(), adminUser.AdminID)
+	require.NoError(t, err)
+	require.True(t, status.Enabled)
+	require.EqualValues(t, 4, status.RemainingRecoveryCodes)
+
+	_, err = service.Setup(context.Background(), adminUser)
+	require.ErrorIs(t, err, ErrTwoFactorAlreadyEnabled)
+}
+
+// TestService_VerifyCode test replayed TOTP codes and single use recovery codes are rejected
+func TestService_VerifyCode(t *testing.T) {
+	service, _ := newTestService(t)
+	adminUser := &model.Admin{AdminID: 1, RoleID: 1, Email: "admin@example.com"}
+	secret, recoveryCodes := enroll(t, service, adminUser)
+
+	// The code confirming the enrollment cannot be used again
+	code, err := totp.GenerateCode(secret, time.Now())
+	require.NoError(t, err)
+	require.ErrorIs(t, service.verifyCode(context.Background(), adminUser.AdminID, code), ErrInvalidTwoFactorCode)
+
+	// A code of the next time step is accepted once to tolerate clock drift
+	nextCode, err := totp.GenerateCode(secret, time.Now().Add(totpPeriod))
+	require.NoError(t, err)
+	require.NoError(t, service.verifyCode(context.Background(), adminUser.AdminID, nextCode))
+	require.ErrorIs(t, service.verifyCode(context.Background(), adminUser.AdminID, nextCode), ErrInvalidTwoFactorCode)
+
+	require.NoError(t, service.verifyCode(context.Background(), adminUser.AdminID, recoveryCodes[0]))
+	require.ErrorIs(t, service.verifyCode(context.Background(), adminUser.AdminID, recoveryCodes[0]), ErrInvalidTwoFactorCode)
+
+	status, err := service.GetStatus(context.Background(), adminUser.AdminID)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, status.RemainingRecoveryCodes)
+}
+
+// TestService_Disable test disabling is refused while the role requires two-factor authentication
+func TestService_Disable(t *testing.T) {
+	service, _ := newTestService(t)
+	optionalAdmin := &model.Admin{AdminID: 1, RoleID: 1, Email: "editor@example.com"}
+	mandatoryAdmin := &model.Admin{AdminID: 2, RoleID: 2, Email: "admin@example.com"}
+	_, optionalCodes := enroll(t, service, optionalAdmin)
+	_, mandatoryCodes := enroll(t, service, mandatoryAdmin)
+
+	require.ErrorIs(t, service.Disable(context.Background(), mandatoryAdmin, mandatoryCodes[0]), ErrTwoFactorRequired)
+	require.NoError(t, service.Disable(context.Background(), optionalAdmin, optionalCodes[0]))
+
+	isEnabled, err := service.IsEnabled(context.Background(), optionalAdmin.AdminID)
+	require.NoError(t, err)
+	require.False(t, isEnabled)
+	require.ErrorIs(t, service.Disable(context.Background(), optionalAdmin, optionalCodes[1]), ErrTwoFactorNotEnabled)
+}
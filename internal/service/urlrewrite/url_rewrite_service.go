@@ -0,0 +1,187 @@
+package urlrewrite
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	blogModel "github.com/daniel-vuky/go-blog/internal/models/blog"
+	model "github.com/daniel-vuky/go-blog/internal/models/url_rewrite"
+	"github.com/daniel-vuky/go-blog/internal/repository/urlrewrite"
+	"github.com/daniel-vuky/go-blog/internal/service/blog"
+	"github.com/daniel-vuky/go-blog/pkg/slug"
+	"github.com/jackc/pgx/v5"
+)
+
+// Entity types exposed by the resolver
+const (
+	EntityTypeCategory = "category"
+	EntityTypePost     = "post"
+)
+
+var (
+	ErrUrlKeyReserved = errors.New("url key is reserved by a route of the blog")
+	ErrUrlKeyInvalid  = errors.New("url key must be made of lowercase letters, digits and single hyphens")
+)
+
+// reservedUrlKeys
+// First path segments of the registered routes, a post or a category using one of them
+// would be served by the route instead of the resolver
+var reservedUrlKeys = map[string]bool{
+	"admin":      true,
+	"auth":       true,
+	"blog":       true,
+	"categories": true,
+	"comments":   true,
+	"media":      true,
+	"posts":      true,
+	"resolve":    true,
+	"search":     true,
+	"setup":      true,
+	"users":      true,
+}
+
+// UrlKeyGenerator
+// Generates free url keys for the posts and the categories.
+type UrlKeyGenerator interface {
+	GenerateUrlKey(c context.Context, source string, entityType model.UrlRewriteEntity, entityID int64) (string, error)
+}
+
+// Service
+// Resolves the public paths of the blog to posts and categories through the url rewrites.
+type Service struct {
+	UrlRewriteRepo urlrewrite.Repository
+	BlogService    *blog.Service
+}
+
+// NewService
+// Returns a new instance of Service.
+func NewService(repo urlrewrite.Repository, blogService *blog.Service) *Service {
+	return &Service{UrlRewriteRepo: repo, BlogService: blogService}
+}
+
+// Resolution
+// The entity a path points to. RedirectTo is set instead of the entity when the path
+// is a previous url key, the client is then expected to follow a permanent redirect.
+type Resolution struct {
+	EntityType string              `json:"entity_type"`
+	UrlKey     string              `json:"url_key"`
+	RedirectTo string              `json:"redirect_to,omitempty"`
+	Post       *blogModel.Post     `json:"post,omitempty"`
+	Category   *blogModel.Category `json:"category,omitempty"`
+}
+
+// Resolve
+// Returns the published post or the category of a path, or where its previous url key now lives.
+// @param c context.Context
+// @param path string
+// @return Resolution
+func (s *Service) Resolve(c context.Context, path string) (Resolution, error) {
+	urlKey := NormalizePath(path)
+	if urlKey == "" {
+		return Resolution{}, pgx.ErrNoRows
+	}
+	rewrite, err := s.UrlRewriteRepo.Get(c, urlKey)
+	if err != nil {
+		return Resolution{}, err
+	}
+	resolution := Resolution{EntityType: EntityTypePost, UrlKey: urlKey}
+	if rewrite.EntityType.UrlRewriteEntity == model.UrlRewriteEntity1 {
+		resolution.EntityType = EntityTypeCategory
+	}
+	if rewrite.IsRedirect {
+		canonical, err := s.UrlRewriteRepo.GetCanonical(c, rewrite.EntityType.UrlRewriteEntity, rewrite.EntityID.Int64)
+		if err != nil {
+			return Resolution{}, err
+		}
+		if resolution.EntityType == EntityTypePost {
+			// Previous keys of unpublished posts must not reveal their current key
+			if _, err = s.BlogService.GetPost(c, canonical.UrlKey.String); err != nil {
+				return Resolution{}, err
+			}
+		}
+		resolution.RedirectTo = canonical.UrlKey.String
+		return resolution, nil
+	}
+
+	switch resolution.EntityType {
+	case EntityTypeCategory:
+		category, err := s.BlogService.GetCategory(c, urlKey)
+		if err != nil {
+			return Resolution{}, err
+		}
+		resolution.Category = &category
+	default:
+		post, err := s.BlogService.GetPost(c, urlKey)
+		if err != nil {
+			return Resolution{}, err
+		}
+		resolution.Post = &post
+	}
+
+	return resolution, nil
+}
+
+// GenerateUrlKey
+// Returns the slug of the source followed by a numeric suffix when the slug is already used,
+// by a post, a category, a redirect of a previous url key or a route. The url keys of the entity
+// itself are free, entityID is 0 for an entity which is not created yet.
+// @param c context.Context
+// @param source string the name of the entity
+// @param entityType model.UrlRewriteEntity
+// @param entityID int64
+// @return string
+func (s *Service) GenerateUrlKey(
+	c context.Context,
+	source string,
+	entityType model.UrlRewriteEntity,
+	entityID int64,
+) (string, error) {
+	base := slug.Make(source)
+	if base == "" {
+		base = EntityTypePost
+		if entityType == model.UrlRewriteEntity1 {
+			base = EntityTypeCategory
+		}
+	}
+	taken, err := s.UrlRewriteRepo.GetListTakenUrlKey(c, base, entityType, entityID)
+	if err != nil {
+		return "", err
+	}
+	if IsReservedUrlKey(base) {
+		taken = append(taken, base)
+	}
+
+	return slug.Unique(base, taken), nil
+}
+
+// CheckUrlKey
+// Validates a url key set by hand, it must already be a slug and not be reserved by a route.
+// @param urlKey string
+// @return error
+func CheckUrlKey(urlKey string) error {
+	if urlKey == "" || slug.Make(urlKey) != urlKey {
+		return ErrUrlKeyInvalid
+	}
+	if IsReservedUrlKey(urlKey) {
+		return ErrUrlKeyReserved
+	}
+	return nil
+}
+
+// IsReservedUrlKey
+// Checks whether the first segment of a url key is the one of a registered route.
+// @param urlKey string
+// @return bool
+func IsReservedUrlKey(urlKey string) bool {
+	segment, _, _ := strings.Cut(NormalizePath(urlKey), "/")
+	return reservedUrlKeys[strings.ToLower(segment)]
+}
+
+// NormalizePath
+// Turns a request path into the url key it refers to.
+// @param path string
+// @return string
+func NormalizePath(path string) string {
+	return strings.Trim(strings.TrimSpace(path), "/")
+}
@@ -0,0 +1,210 @@
+package urlrewrite
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	blogModel "github.com/daniel-vuky/go-blog/internal/models/blog"
+	model "github.com/daniel-vuky/go-blog/internal/models/url_rewrite"
+	"github.com/daniel-vuky/go-blog/internal/service/blog"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryUrlRewriteRepository
+// In-memory url rewrite repository indexed by url key
+type memoryUrlRewriteRepository struct {
+	rewrites map[string]model.UrlRewrite
+}
+
+func (repo *memoryUrlRewriteRepository) Get(_ context.Context, urlKey string) (model.UrlRewrite, error) {
+	rewrite, ok := repo.rewrites[urlKey]
+	if !ok {
+		return model.UrlRewrite{}, pgx.ErrNoRows
+	}
+	return rewrite, nil
+}
+
+func (repo *memoryUrlRewriteRepository) GetCanonical(_ context.Context, entityType model.UrlRewriteEntity, entityID int64) (model.UrlRewrite, error) {
+	for _, rewrite := range repo.rewrites {
+		if rewrite.EntityType.UrlRewriteEntity == entityType && rewrite.EntityID.Int64 == entityID && !rewrite.IsRedirect {
+			return rewrite, nil
+		}
+	}
+	return model.UrlRewrite{}, pgx.ErrNoRows
+}
+
+func (repo *memoryUrlRewriteRepository) GetListTakenUrlKey(_ context.Context, base string, entityType model.UrlRewriteEntity, entityID int64) ([]string, error) {
+	var taken []string
+	for urlKey, rewrite := range repo.rewrites {
+		if rewrite.EntityType.UrlRewriteEntity == entityType && rewrite.EntityID.Int64 == entityID {
+			continue
+		}
+		if urlKey == base || strings.HasPrefix(urlKey, base+"-") {
+			taken = append(taken, urlKey)
+		}
+	}
+	return taken, nil
+}
+
+// memoryBlogRepository
+// In-memory blog repository holding the published posts and the categories by url key
+type memoryBlogRepository struct {
+	posts      map[string]blogModel.Post
+	categories map[string]blogModel.Category
+}
+
+func (repo *memoryBlogRepository) GetPost(_ context.Context, urlKey string) (blogModel.Post, error) {
+	post, ok := repo.posts[urlKey]
+	if !ok {
+		return blogModel.Post{}, pgx.ErrNoRows
+	}
+	return post, nil
+}
+
+func (repo *memoryBlogRepository) GetPostCategories(context.Context, int64) ([]blogModel.CategorySummary, error) {
+	return nil, nil
+}
+
+func (repo *memoryBlogRepository) GetListPost(context.Context, *blogModel.GetListPostParams) ([]blogModel.PostSummary, int64, error) {
+	return nil, 0, nil
+}
+
+func (repo *memoryBlogRepository) GetCategory(_ context.Context, urlKey string) (blogModel.Category, error) {
+	category, ok := repo.categories[urlKey]
+	if !ok {
+		return blogModel.Category{}, pgx.ErrNoRows
+	}
+	return category, nil
+}
+
+func (repo *memoryBlogRepository) GetListCategory(context.Context) ([]blogModel.Category, error) {
+	return nil, nil
+}
+
+func (repo *memoryBlogRepository) GetListComment(context.Context, *blogModel.GetListCommentParams) ([]blogModel.Comment, int64, error) {
+	return nil, 0, nil
+}
+
+// rewrite
+// Create a url rewrite of an entity
+func rewrite(entityType model.UrlRewriteEntity, entityID int64, urlKey string, isRedirect bool) model.UrlRewrite {
+	return model.UrlRewrite{
+		EntityType: model.NullUrlRewriteEntity{UrlRewriteEntity: entityType, Valid: true},
+		EntityID:   pgtype.Int8{Int64: entityID, Valid: true},
+		UrlKey:     pgtype.Text{String: urlKey, Valid: true},
+		IsRedirect: isRedirect,
+	}
+}
+
+// TestService_Resolve test current keys resolve to their entity and previous keys to a redirect
+func TestService_Resolve(t *testing.T) {
+	service := NewService(
+		&memoryUrlRewriteRepository{rewrites: map[string]model.UrlRewrite{
+			"news":           rewrite(model.UrlRewriteEntity1, 1, "news", false),
+			"hello-world":    rewrite(model.UrlRewriteEntity2, 7, "hello-world", false),
+			"hello":          rewrite(model.UrlRewriteEntity2, 7, "hello", true),
+			"draft":          rewrite(model.UrlRewriteEntity2, 8, "draft", false),
+			"early-draft":    rewrite(model.UrlRewriteEntity2, 8, "early-draft", true),
+			"orphan-history": rewrite(model.UrlRewriteEntity2, 9, "orphan-history", true),
+		}},
+		blog.NewService(&memoryBlogRepository{
+			posts:      map[string]blogModel.Post{"hello-world": {PostID: 7, Name: "Hello world", UrlKey: "hello-world"}},
+			categories: map[string]blogModel.Category{"news": {CategoryID: 1, Name: "News", UrlKey: "news"}},
+		}, nil),
+	)
+
+	resolution, err := service.Resolve(context.Background(), "/hello-world/")
+	require.NoError(t, err)
+	require.Equal(t, EntityTypePost, resolution.EntityType)
+	require.Equal(t, "Hello world", resolution.Post.Name)
+	require.Nil(t, resolution.Category)
+
+	resolution, err = service.Resolve(context.Background(), "news")
+	require.NoError(t, err)
+	require.Equal(t, EntityTypeCategory, resolution.EntityType)
+	require.Equal(t, "News", resolution.Category.Name)
+
+	resolution, err = service.Resolve(context.Background(), "/hello")
+	require.NoError(t, err)
+	require.Equal(t, "hello-world", resolution.RedirectTo)
+	require.Nil(t, resolution.Post)
+
+	for _, path := range []string{"/", "missing", "draft", "early-draft", "orphan-history"} {
+		_, err = service.Resolve(context.Background(), path)
+		require.ErrorIs(t, err, pgx.ErrNoRows, path)
+	}
+}
+
+// TestService_GenerateUrlKey test generated keys skip the keys of other entities and their redirects
+func TestService_GenerateUrlKey(t *testing.T) {
+	service := NewService(
+		&memoryUrlRewriteRepository{rewrites: map[string]model.UrlRewrite{
+			"tin-tuc":     rewrite(model.UrlRewriteEntity1, 1, "tin-tuc", false),
+			"tin-tuc-2":   rewrite(model.UrlRewriteEntity2, 7, "tin-tuc-2", true),
+			"hello-world": rewrite(model.UrlRewriteEntity2, 8, "hello-world", false),
+		}},
+		blog.NewService(&memoryBlogRepository{}, nil),
+	)
+	ctx := context.Background()
+
+	urlKey, err := service.GenerateUrlKey(ctx, "Tin tức", model.UrlRewriteEntity2, 0)
+	require.NoError(t, err)
+	require.Equal(t, "tin-tuc-3", urlKey)
+
+	urlKey, err = service.GenerateUrlKey(ctx, "Tin tức", model.UrlRewriteEntity1, 1)
+	require.NoError(t, err)
+	require.Equal(t, "tin-tuc", urlKey)
+
+	urlKey, err = service.GenerateUrlKey(ctx, "Hello, World!", model.UrlRewriteEntity2, 9)
+	require.NoError(t, err)
+	require.Equal(t, "hello-world-2", urlKey)
+
+	urlKey, err = service.GenerateUrlKey(ctx, "日本語", model.UrlRewriteEntity1, 0)
+	require.NoError(t, err)
+	require.Equal(t, EntityTypeCategory, urlKey)
+}
+
+// TestService_GenerateUrlKey_Reserved test the first segments of the routes are never generated
+func TestService_GenerateUrlKey_Reserved(t *testing.T) {
+	service := NewService(
+		&memoryUrlRewriteRepository{rewrites: map[string]model.UrlRewrite{
+			"search-2": rewrite(model.UrlRewriteEntity2, 7, "search-2", false),
+		}},
+		blog.NewService(&memoryBlogRepository{}, nil),
+	)
+	ctx := context.Background()
+
+	urlKey, err := service.GenerateUrlKey(ctx, "Search", model.UrlRewriteEntity2, 0)
+	require.NoError(t, err)
+	require.Equal(t, "search-3", urlKey)
+
+	urlKey, err = service.GenerateUrlKey(ctx, "Media", model.UrlRewriteEntity1, 0)
+	require.NoError(t, err)
+	require.Equal(t, "media-2", urlKey)
+
+	urlKey, err = service.GenerateUrlKey(ctx, "Media library", model.UrlRewriteEntity1, 0)
+	require.NoError(t, err)
+	require.Equal(t, "media-library", urlKey)
+}
+
+// TestCheckUrlKey test a url key set by hand must be a slug which is not reserved
+func TestCheckUrlKey(t *testing.T) {
+	require.NoError(t, CheckUrlKey("hello-world-2"))
+	require.ErrorIs(t, CheckUrlKey("search"), ErrUrlKeyReserved)
+	for _, urlKey := range []string{"", "Hello", "hello_world", "hello--world", "-hello", "hello-", "hello/world", "xin-chào"} {
+		require.ErrorIs(t, CheckUrlKey(urlKey), ErrUrlKeyInvalid, urlKey)
+	}
+}
+
+// TestIsReservedUrlKey test only the first segment of a url key is compared with the routes
+func TestIsReservedUrlKey(t *testing.T) {
+	require.True(t, IsReservedUrlKey("resolve"))
+	require.True(t, IsReservedUrlKey("/Admin/"))
+	require.True(t, IsReservedUrlKey("media/photo"))
+	require.False(t, IsReservedUrlKey("resolved"))
+	require.False(t, IsReservedUrlKey("news/media"))
+	require.False(t, IsReservedUrlKey(""))
+}
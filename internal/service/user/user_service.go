@@ -0,0 +1,351 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/user"
+	"github.com/daniel-vuky/go-blog/internal/repository/user"
+	"github.com/daniel-vuky/go-blog/internal/service/lockout"
+	"github.com/daniel-vuky/go-blog/internal/service/session"
+	"github.com/daniel-vuky/go-blog/pkg/password"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// uniqueViolationCode
+// Postgres error code raised when a unique constraint is violated
+const uniqueViolationCode = "23505"
+
+// minDob
+// Earliest accepted date of birth
+var minDob = time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+var (
+	ErrEmailAlreadyExists = errors.New("email is already registered")
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrInvalidPassword    = errors.New("current password is incorrect")
+	ErrUserDisabled       = errors.New("user account is disabled")
+	ErrInvalidGender      = errors.New("gender is invalid")
+	ErrInvalidDob         = errors.New("date of birth must be between 1900-01-01 and today")
+	ErrClientIpLocked     = lockout.ErrClientIpLocked
+)
+
+// Service
+// Registers, authenticates and manages the site users.
+type Service struct {
+	UserRepo user.Repository
+	Hasher   password.Hasher
+	Lockout  *lockout.Service
+	Session  *session.Service
+}
+
+// NewService
+// Returns a new instance of Service.
+func NewService(
+	repo user.Repository,
+	hasher password.Hasher,
+	lockoutService *lockout.Service,
+	sessionService *session.Service,
+) *Service {
+	return &Service{UserRepo: repo, Hasher: hasher, Lockout: lockoutService, Session: sessionService}
+}
+
+// convertUserToModel
+// Converts a user from the repository to the model without its password hash.
+// @param siteUser *model.User
+// @return model.User
+func convertUserToModel(siteUser *model.User) model.User {
+	converted := *siteUser
+	converted.HashedPassword = ""
+	return converted
+}
+
+// Register
+// Creates a new site user.
+// @param c context.Context
+// @param arg *model.CreateUserParams
+// @return model.User
+func (s *Service) Register(c context.Context, arg *model.CreateUserParams) (model.User, error) {
+	if err := validateProfile(arg.Gender, arg.Dob); err != nil {
+		return model.User{}, err
+	}
+	hashedPassword, err := s.Hasher.Hash(arg.Password)
+	if err != nil {
+		return model.User{}, err
+	}
+	arg.HashedPassword = hashedPassword
+	createdUser, err := s.UserRepo.Create(c, arg)
+	if err != nil {
+		return model.User{}, convertUniqueViolation(err)
+	}
+
+	return convertUserToModel(&createdUser), nil
+}
+
+// Login
+// Verifies the credentials of a site user, failures are counted against the client ip.
+// @param c context.Context
+// @param email string
+// @param plainPassword string
+// @param clientIp string
+// @return model.User
+func (s *Service) Login(c context.Context, email string, plainPassword string, clientIp string) (model.User, error) {
+	if err := s.Lockout.CheckClientIp(c, clientIp); err != nil {
+		return model.User{}, err
+	}
+	siteUser, err := s.UserRepo.Get(c, email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			s.registerFailedLogin(c, clientIp)
+			return model.User{}, ErrInvalidCredentials
+		}
+		return model.User{}, err
+	}
+	if err = s.verifyPassword(c, &siteUser, plainPassword); err != nil {
+		if errors.Is(err, ErrInvalidCredentials) {
+			s.registerFailedLogin(c, clientIp)
+		}
+		return model.User{}, err
+	}
+	if !siteUser.Active {
+		return model.User{}, ErrUserDisabled
+	}
+
+	return convertUserToModel(&siteUser), nil
+}
+
+// GetUserByID
+// Returns a user by id.
+// @param c context.Context
+// @param userID int64
+// @return model.User
+func (s *Service) GetUserByID(c context.Context, userID int64) (model.User, error) {
+	siteUser, err := s.UserRepo.GetByID(c, userID)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	return convertUserToModel(&siteUser), nil
+}
+
+// GetUserByEmail
+// Returns a user by email.
+// @param c context.Context
+// @param email string
+// @return model.User
+func (s *Service) GetUserByEmail(c context.Context, email string) (model.User, error) {
+	siteUser, err := s.UserRepo.Get(c, email)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	return convertUserToModel(&siteUser), nil
+}
+
+// UpdateProfile
+// Updates the profile of a user, changing the password requires the current one.
+// Every session of the user is revoked once the password changed.
+// @param c context.Context
+// @param arg *model.UpdateUserParams
+// @param currentPassword string
+// @return model.User
+func (s *Service) UpdateProfile(c context.Context, arg *model.UpdateUserParams, currentPassword string) (model.User, error) {
+	if err := validateProfile(arg.Gender, arg.Dob); err != nil {
+		return model.User{}, err
+	}
+	arg.HashedPassword = pgtype.Text{}
+	arg.Active = pgtype.Bool{}
+	if arg.Password.Valid {
+		siteUser, err := s.UserRepo.GetByID(c, arg.UserID)
+		if err != nil {
+			return model.User{}, err
+		}
+		if err = s.verifyPassword(c, &siteUser, currentPassword); err != nil {
+			if errors.Is(err, ErrInvalidCredentials) {
+				return model.User{}, ErrInvalidPassword
+			}
+			return model.User{}, err
+		}
+		hashedPassword, err := s.Hasher.Hash(arg.Password.String)
+		if err != nil {
+			return model.User{}, err
+		}
+		arg.HashedPassword = pgtype.Text{String: hashedPassword, Valid: true}
+	}
+	updatedUser, err := s.UserRepo.Update(c, arg)
+	if err != nil {
+		return model.User{}, err
+	}
+	if arg.HashedPassword.Valid {
+		if err = s.Session.RevokeUserSessions(c, updatedUser.UserID); err != nil {
+			return model.User{}, err
+		}
+	}
+
+	return convertUserToModel(&updatedUser), nil
+}
+
+// ResetPassword
+// Replaces the password of a user without the current one and revokes every session of the user.
+// @param c context.Context
+// @param userID int64
+// @param newPassword string
+// @return model.User
+func (s *Service) ResetPassword(c context.Context, userID int64, newPassword string) (model.User, error) {
+	hashedPassword, err := s.Hasher.Hash(newPassword)
+	if err != nil {
+		return model.User{}, err
+	}
+	updatedUser, err := s.UserRepo.Update(c, &model.UpdateUserParams{
+		UserID:         userID,
+		HashedPassword: pgtype.Text{String: hashedPassword, Valid: true},
+	})
+	if err != nil {
+		return model.User{}, err
+	}
+	if err = s.Session.RevokeUserSessions(c, userID); err != nil {
+		return model.User{}, err
+	}
+
+	return convertUserToModel(&updatedUser), nil
+}
+
+// VerifyEmail
+// Marks the email of a user as verified.
+// @param c context.Context
+// @param userID int64
+// @return model.User
+func (s *Service) VerifyEmail(c context.Context, userID int64) (model.User, error) {
+	verifiedUser, err := s.UserRepo.VerifyEmail(c, userID)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	return convertUserToModel(&verifiedUser), nil
+}
+
+// ListUserResponse
+// Struct to hold the response of the GetListUser method.
+type ListUserResponse struct {
+	Totals int64        `json:"totals"`
+	Users  []model.User `json:"users"`
+}
+
+// GetListUser
+// Returns a list of users.
+// @param c context.Context
+// @param arg *model.GetListUserParams
+// @return ListUserResponse
+func (s *Service) GetListUser(c context.Context, arg *model.GetListUserParams) (ListUserResponse, error) {
+	var rsp ListUserResponse
+	if arg.OrderBy == "" {
+		arg.OrderBy = "user_id"
+	}
+	if arg.OrderDirection == "" {
+		arg.OrderDirection = "desc"
+	}
+	listUser, totalUser, err := s.UserRepo.GetList(c, arg)
+	if err != nil {
+		return rsp, err
+	}
+	users := make([]model.User, 0, len(listUser))
+	for i := range listUser {
+		users = append(users, convertUserToModel(&listUser[i]))
+	}
+	rsp = ListUserResponse{
+		Totals: totalUser,
+		Users:  users,
+	}
+
+	return rsp, nil
+}
+
+// SetUserActive
+// Enables or disables a user, disabling also revokes every session of the user.
+// @param c context.Context
+// @param userID int64
+// @param active bool
+// @return model.User
+func (s *Service) SetUserActive(c context.Context, userID int64, active bool) (model.User, error) {
+	updatedUser, err := s.UserRepo.Update(c, &model.UpdateUserParams{
+		UserID: userID,
+		Active: pgtype.Bool{Bool: active, Valid: true},
+	})
+	if err != nil {
+		return model.User{}, err
+	}
+	if !active {
+		if err = s.Session.RevokeUserSessions(c, userID); err != nil {
+			return model.User{}, err
+		}
+	}
+
+	return convertUserToModel(&updatedUser), nil
+}
+
+// verifyPassword
+// Checks the password of a user and upgrades its hash when the hashing settings changed.
+// @param c context.Context
+// @param siteUser *model.User
+// @param plainPassword string
+// @return error
+func (s *Service) verifyPassword(c context.Context, siteUser *model.User, plainPassword string) error {
+	ok, err := s.Hasher.Verify(siteUser.HashedPassword, plainPassword)
+	if err != nil && !errors.Is(err, password.ErrUnknownHashFormat) {
+		return err
+	}
+	if !ok {
+		return ErrInvalidCredentials
+	}
+	if s.Hasher.NeedsRehash(siteUser.HashedPassword) {
+		hashedPassword, err := s.Hasher.Hash(plainPassword)
+		if err == nil {
+			err = s.UserRepo.Rehash(c, siteUser.UserID, hashedPassword)
+		}
+		if err != nil {
+			log.Printf("failed to rehash password of user %d: %v", siteUser.UserID, err)
+		}
+	}
+	return nil
+}
+
+// registerFailedLogin
+// Counts a failed login against the client ip, errors are only logged.
+// @param c context.Context
+// @param clientIp string
+func (s *Service) registerFailedLogin(c context.Context, clientIp string) {
+	if err := s.Lockout.RegisterFailedClientLogin(c, clientIp); err != nil {
+		log.Printf("failed to register failed login of %s: %v", clientIp, err)
+	}
+}
+
+// validateProfile
+// Checks the gender and the date of birth when they are provided.
+// @param gender model.NullGender
+// @param dob pgtype.Timestamptz
+// @return error
+func validateProfile(gender model.NullGender, dob pgtype.Timestamptz) error {
+	if gender.Valid && !gender.Gender.IsValid() {
+		return ErrInvalidGender
+	}
+	if dob.Valid && (dob.Time.Before(minDob) || dob.Time.After(time.Now())) {
+		return ErrInvalidDob
+	}
+	return nil
+}
+
+// convertUniqueViolation
+// Maps a unique violation of the email to ErrEmailAlreadyExists.
+// @param err error
+// @return error
+func convertUniqueViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+		return ErrEmailAlreadyExists
+	}
+	return err
+}
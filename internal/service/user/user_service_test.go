@@ -0,0 +1,45 @@
+package user
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/user"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+// TestValidateProfile test validating the gender and date of birth
+func TestValidateProfile(t *testing.T) {
+	testCases := []struct {
+		name     string
+		gender   model.NullGender
+		dob      pgtype.Timestamptz
+		expected error
+	}{
+		{"Empty", model.NullGender{}, pgtype.Timestamptz{}, nil},
+		{"Valid", model.NullGender{Gender: model.Gender2, Valid: true}, pgtype.Timestamptz{Time: time.Date(1990, 5, 1, 0, 0, 0, 0, time.UTC), Valid: true}, nil},
+		{"UnknownGender", model.NullGender{Gender: "4", Valid: true}, pgtype.Timestamptz{}, ErrInvalidGender},
+		{"FutureDob", model.NullGender{}, pgtype.Timestamptz{Time: time.Now().AddDate(0, 0, 1), Valid: true}, ErrInvalidDob},
+		{"AncientDob", model.NullGender{}, pgtype.Timestamptz{Time: time.Date(1850, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true}, ErrInvalidDob},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateProfile(tc.gender, tc.dob)
+			if tc.expected == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tc.expected)
+		})
+	}
+}
+
+// TestConvertUniqueViolation test mapping the unique email violation
+func TestConvertUniqueViolation(t *testing.T) {
+	require.ErrorIs(t, convertUniqueViolation(&pgconn.PgError{Code: uniqueViolationCode}), ErrEmailAlreadyExists)
+	otherErr := errors.New("connection reset")
+	require.Equal(t, otherErr, convertUniqueViolation(otherErr))
+}
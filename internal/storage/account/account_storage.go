@@ -0,0 +1,146 @@
+package account
+
+import (
+	"context"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/account"
+	"github.com/daniel-vuky/go-blog/internal/storage"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository
+// Wraps the Queries struct from the storage package and a connection pool.
+type Repository struct {
+	*storage.Queries
+	connPool *pgxpool.Pool
+}
+
+// NewAccountTokenRepository
+// Returns a new instance of Repository.
+// @param connPool *pgxpool.Pool
+// @return *Repository
+func NewAccountTokenRepository(connPool *pgxpool.Pool) *Repository {
+	return &Repository{
+		Queries:  storage.New(connPool),
+		connPool: connPool,
+	}
+}
+
+// scanAccountToken
+// Scans a full account_tokens row.
+// @param row pgx.Row
+// @return model.AccountToken, error
+func scanAccountToken(row pgx.Row) (model.AccountToken, error) {
+	var i model.AccountToken
+	err := row.Scan(
+		&i.TokenID,
+		&i.AccountType,
+		&i.AccountID,
+		&i.Purpose,
+		&i.TokenHash,
+		&i.ExpiredAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getLatestAccountToken = `-- name: GetLatestAccountToken :one
+SELECT token_id, account_type, account_id, purpose, token_hash, expired_at, used_at, created_at
+FROM account_tokens
+WHERE account_type = $1
+  AND account_id = $2
+  AND purpose = $3
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+// GetLatest
+// Returns the last token issued to an account for a purpose, pgx.ErrNoRows when there is none.
+// @param ctx context.Context
+// @param accountType model.AccountType
+// @param accountID int64
+// @param purpose model.TokenPurpose
+// @return model.AccountToken
+func (repo *Repository) GetLatest(
+	ctx context.Context,
+	accountType model.AccountType,
+	accountID int64,
+	purpose model.TokenPurpose,
+) (model.AccountToken, error) {
+	return scanAccountToken(repo.connPool.QueryRow(ctx, getLatestAccountToken, accountType, accountID, purpose))
+}
+
+const revokeAccountTokens = `-- name: RevokeAccountTokens :exec
+UPDATE account_tokens
+SET used_at = NOW()
+WHERE account_type = $1
+  AND account_id = $2
+  AND purpose = $3
+  AND used_at IS NULL
+`
+
+const createAccountToken = `-- name: CreateAccountToken :one
+INSERT INTO account_tokens
+    (
+        account_type,
+        account_id,
+        purpose,
+        token_hash,
+        expired_at
+    )
+VALUES ($1, $2, $3, $4, $5)
+RETURNING token_id, account_type, account_id, purpose, token_hash, expired_at, used_at, created_at
+`
+
+// Create
+// Stores a new account token, the unused tokens of the same account and purpose are revoked.
+// @param ctx context.Context
+// @param arg *model.CreateAccountTokenParams
+// @return model.AccountToken
+func (repo *Repository) Create(
+	ctx context.Context,
+	arg *model.CreateAccountTokenParams,
+) (model.AccountToken, error) {
+	var i model.AccountToken
+	err := pgx.BeginFunc(ctx, repo.connPool, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, revokeAccountTokens, arg.AccountType, arg.AccountID, arg.Purpose)
+		if err != nil {
+			return err
+		}
+		i, err = scanAccountToken(tx.QueryRow(ctx, createAccountToken,
+			arg.AccountType,
+			arg.AccountID,
+			arg.Purpose,
+			arg.TokenHash,
+			arg.ExpiredAt,
+		))
+		return err
+	})
+	return i, err
+}
+
+const consumeAccountToken = `-- name: ConsumeAccountToken :one
+UPDATE account_tokens
+SET used_at = NOW()
+WHERE token_hash = $1
+  AND purpose = $2
+  AND used_at IS NULL
+  AND expired_at > NOW()
+RETURNING token_id, account_type, account_id, purpose, token_hash, expired_at, used_at, created_at
+`
+
+// Consume
+// Marks an unused and unexpired token as used, pgx.ErrNoRows is returned otherwise.
+// @param ctx context.Context
+// @param tokenHash string
+// @param purpose model.TokenPurpose
+// @return model.AccountToken
+func (repo *Repository) Consume(
+	ctx context.Context,
+	tokenHash string,
+	purpose model.TokenPurpose,
+) (model.AccountToken, error) {
+	return scanAccountToken(repo.connPool.QueryRow(ctx, consumeAccountToken, tokenHash, purpose))
+}
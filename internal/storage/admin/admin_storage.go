@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	model "github.com/daniel-vuky/go-blog/internal/models/admin"
+	"github.com/daniel-vuky/go-blog/internal/repository/admin"
 	"github.com/daniel-vuky/go-blog/internal/storage"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -77,6 +79,62 @@ func (repo *Repository) Create(
 	return i, err
 }
 
+const lockAdminTable = `-- name: LockAdminTable :exec
+LOCK TABLE admin IN EXCLUSIVE MODE
+`
+
+const countAdmin = `-- name: CountAdmin :one
+SELECT COUNT(*)
+FROM admin
+`
+
+// CreateFirst
+// Creates an admin only when the admin table is still empty.
+// The table is locked for the transaction so concurrent calls cannot both succeed.
+// @param ctx context.Context
+// @param arg *model.CreateAdminParams
+// @return model.Admin
+func (repo *Repository) CreateFirst(
+	ctx context.Context,
+	arg *model.CreateAdminParams,
+) (model.Admin, error) {
+	var i model.Admin
+	err := pgx.BeginFunc(ctx, repo.connPool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, lockAdminTable); err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.QueryRow(ctx, countAdmin).Scan(&count); err != nil {
+			return err
+		}
+		if count > 0 {
+			return admin.ErrAdminAlreadyExists
+		}
+		return tx.QueryRow(ctx, createAdmin,
+			arg.RoleID,
+			arg.Email,
+			arg.HashedPassword,
+			arg.Firstname,
+			arg.Lastname,
+			arg.Active,
+			arg.LockExpires,
+			arg.PasswordChangedAt,
+		).Scan(
+			&i.AdminID,
+			&i.RoleID,
+			&i.Email,
+			&i.HashedPassword,
+			&i.Firstname,
+			&i.Lastname,
+			&i.Active,
+			&i.LockExpires,
+			&i.PasswordChangedAt,
+			&i.CreatedAt,
+		)
+	})
+	return i, err
+}
+
 const deleteAdmin = `-- name: DeleteAdmin :one
 DELETE FROM admin
 WHERE email = $1
@@ -141,6 +199,38 @@ func (repo *Repository) Get(
 	return i, err
 }
 
+const getAdminByID = `-- name: GetAdminByID :one
+SELECT admin_id, role_id, email, hashed_password, firstname, lastname, active, lock_expires, password_changed_at, created_at
+FROM admin
+WHERE admin_id = $1
+`
+
+// GetByID
+// Returns an admin by id.
+// @param ctx context.Context
+// @param adminID int32
+// @return model.Admin
+func (repo *Repository) GetByID(
+	ctx context.Context,
+	adminID int32,
+) (model.Admin, error) {
+	row := repo.connPool.QueryRow(ctx, getAdminByID, adminID)
+	var i model.Admin
+	err := row.Scan(
+		&i.AdminID,
+		&i.RoleID,
+		&i.Email,
+		&i.HashedPassword,
+		&i.Firstname,
+		&i.Lastname,
+		&i.Active,
+		&i.LockExpires,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getListAdmin = `-- name: GetListAdmin :many
 SELECT admin_id, role_id, email, hashed_password, firstname, lastname, active, lock_expires, password_changed_at, created_at
 FROM admin
@@ -272,3 +362,30 @@ func (repo *Repository) Update(
 	)
 	return i, err
 }
+
+const rehashAdminPassword = `-- name: RehashAdminPassword :exec
+UPDATE admin
+SET hashed_password = $2
+WHERE email = $1
+`
+
+// Rehash
+// Replaces the hash of an unchanged password without touching password_changed_at.
+// @param ctx context.Context
+// @param email string
+// @param hashedPassword string
+// @return error
+func (repo *Repository) Rehash(
+	ctx context.Context,
+	email string,
+	hashedPassword string,
+) error {
+	return pgx.BeginFunc(ctx, repo.connPool, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, "SELECT set_config('go_blog.password_rehash', 'on', true)")
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, rehashAdminPassword, email, hashedPassword)
+		return err
+	})
+}
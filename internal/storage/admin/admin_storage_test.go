@@ -3,6 +3,7 @@ package admin
 import (
 	"context"
 	model "github.com/daniel-vuky/go-blog/internal/models/admin"
+	"github.com/daniel-vuky/go-blog/internal/repository/admin"
 	"github.com/daniel-vuky/go-blog/pkg/config"
 	goRandom "github.com/daniel-vuky/go-random"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -138,6 +139,21 @@ func TestRepository_Create_InvalidParam(t *testing.T) {
 	require.Empty(t, createdAdmin)
 }
 
+// TestRepository_CreateFirst_AlreadyExists
+// Tests the CreateFirst method when admins already exist.
+func TestRepository_CreateFirst_AlreadyExists(t *testing.T) {
+	createRandomAdmin(t)
+	arg := &model.CreateAdminParams{
+		RoleID:         1,
+		Email:          goRandom.RandomEmail(),
+		HashedPassword: goRandom.RandomString(10),
+		Firstname:      goRandom.RandomString(10),
+	}
+	createdAdmin, err := repository.CreateFirst(context.Background(), arg)
+	require.ErrorIs(t, err, admin.ErrAdminAlreadyExists)
+	require.Empty(t, createdAdmin)
+}
+
 // TestRepository_Delete_Success
 // Tests the Delete method.
 func TestRepository_Delete_Success(t *testing.T) {
@@ -181,6 +197,16 @@ func TestRepository_Get_NonExistedAdmin(t *testing.T) {
 	require.Empty(t, fetchedAdmin)
 }
 
+// TestRepository_GetByID_Success
+// Tests the GetByID method.
+func TestRepository_GetByID_Success(t *testing.T) {
+	randomAdmin := createRandomAdmin(t)
+	fetchedAdmin, err := repository.GetByID(context.Background(), randomAdmin.AdminID)
+	require.NoError(t, err)
+	require.NotEmpty(t, fetchedAdmin)
+	compareAdmin(t, &randomAdmin, &fetchedAdmin)
+}
+
 // TestRepository_GetList_Success
 // Tests the GetList method.
 func TestRepository_GetList_Success(t *testing.T) {
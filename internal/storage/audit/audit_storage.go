@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"context"
+	model "github.com/daniel-vuky/go-blog/internal/models/audit"
+	"github.com/daniel-vuky/go-blog/internal/storage"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository
+// Wraps the Queries struct from the storage package and a connection pool.
+type Repository struct {
+	*storage.Queries
+	connPool *pgxpool.Pool
+}
+
+// NewAuditRepository
+// Returns a new instance of Repository.
+// @param connPool *pgxpool.Pool
+// @return *Repository
+func NewAuditRepository(connPool *pgxpool.Pool) *Repository {
+	return &Repository{
+		Queries:  storage.New(connPool),
+		connPool: connPool,
+	}
+}
+
+const createAuditLog = `-- name: CreateAuditLog :one
+INSERT INTO admin_audit_log
+    (
+        admin_id,
+        actor_id,
+        action,
+        detail,
+        client_ip
+    )
+VALUES ($1, $2, $3, $4, $5)
+RETURNING audit_id, admin_id, actor_id, action, detail, client_ip, created_at
+`
+
+// Create
+// Creates a new audit log entry.
+// @param ctx context.Context
+// @param arg *model.CreateAuditLogParams
+// @return model.AuditLog
+func (repo *Repository) Create(
+	ctx context.Context,
+	arg *model.CreateAuditLogParams,
+) (model.AuditLog, error) {
+	row := repo.connPool.QueryRow(ctx, createAuditLog,
+		arg.AdminID,
+		arg.ActorID,
+		arg.Action,
+		arg.Detail,
+		arg.ClientIp,
+	)
+	var i model.AuditLog
+	err := row.Scan(
+		&i.AuditID,
+		&i.AdminID,
+		&i.ActorID,
+		&i.Action,
+		&i.Detail,
+		&i.ClientIp,
+		&i.CreatedAt,
+	)
+	return i, err
+}
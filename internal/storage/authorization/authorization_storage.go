@@ -0,0 +1,313 @@
+package authorization
+
+import (
+	"context"
+	model "github.com/daniel-vuky/go-blog/internal/models/admin"
+	"github.com/daniel-vuky/go-blog/internal/repository/authorization"
+	"github.com/daniel-vuky/go-blog/internal/storage"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository
+// Wraps the Queries struct from the storage package and a connection pool.
+type Repository struct {
+	*storage.Queries
+	connPool *pgxpool.Pool
+}
+
+// NewAuthorizationRepository
+// Returns a new instance of Repository.
+// @param connPool *pgxpool.Pool
+// @return *Repository
+func NewAuthorizationRepository(connPool *pgxpool.Pool) *Repository {
+	return &Repository{
+		Queries:  storage.New(connPool),
+		connPool: connPool,
+	}
+}
+
+const getAuthorizationRole = `-- name: GetAuthorizationRole :one
+SELECT role_id, role_name, is_administrator, require_two_factor, created_at
+FROM authorization_roles
+WHERE role_id = $1
+`
+
+// GetRole
+// Returns an authorization role by id.
+// @param ctx context.Context
+// @param roleID int64
+// @return model.AuthorizationRole
+func (repo *Repository) GetRole(
+	ctx context.Context,
+	roleID int64,
+) (model.AuthorizationRole, error) {
+	row := repo.connPool.QueryRow(ctx, getAuthorizationRole, roleID)
+	var i model.AuthorizationRole
+	err := row.Scan(
+		&i.RoleID,
+		&i.RoleName,
+		&i.IsAdministrator,
+		&i.RequireTwoFactor,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getAuthorizationRules = `-- name: GetAuthorizationRules :many
+SELECT rule_id, role_id, permission_code, is_allowed, created_at
+FROM authorization_rules
+WHERE role_id = $1
+ORDER BY permission_code
+`
+
+// GetRules
+// Returns all rules of an authorization role.
+// @param ctx context.Context
+// @param roleID int64
+// @return []model.AuthorizationRule
+func (repo *Repository) GetRules(
+	ctx context.Context,
+	roleID int64,
+) ([]model.AuthorizationRule, error) {
+	rows, err := repo.connPool.Query(ctx, getAuthorizationRules, roleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []model.AuthorizationRule{}
+	for rows.Next() {
+		var i model.AuthorizationRule
+		if err := rows.Scan(
+			&i.RuleID,
+			&i.RoleID,
+			&i.PermissionCode,
+			&i.IsAllowed,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getListAuthorizationRoles = `-- name: GetListAuthorizationRoles :many
+SELECT role_id, role_name, is_administrator, require_two_factor, created_at
+FROM authorization_roles
+ORDER BY role_id
+`
+
+// GetListRoles
+// Returns all authorization roles.
+// @param ctx context.Context
+// @return []model.AuthorizationRole
+func (repo *Repository) GetListRoles(ctx context.Context) ([]model.AuthorizationRole, error) {
+	rows, err := repo.connPool.Query(ctx, getListAuthorizationRoles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []model.AuthorizationRole{}
+	for rows.Next() {
+		var i model.AuthorizationRole
+		if err := rows.Scan(
+			&i.RoleID,
+			&i.RoleName,
+			&i.IsAdministrator,
+			&i.RequireTwoFactor,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createAuthorizationRole = `-- name: CreateAuthorizationRole :one
+INSERT INTO authorization_roles
+    (
+        role_name,
+        is_administrator,
+        require_two_factor
+    )
+VALUES ($1, $2, $3)
+RETURNING role_id, role_name, is_administrator, require_two_factor, created_at
+`
+
+// CreateRole
+// Creates a new authorization role.
+// @param ctx context.Context
+// @param arg *model.CreateAuthorizationRoleParams
+// @return model.AuthorizationRole
+func (repo *Repository) CreateRole(
+	ctx context.Context,
+	arg *model.CreateAuthorizationRoleParams,
+) (model.AuthorizationRole, error) {
+	row := repo.connPool.QueryRow(ctx, createAuthorizationRole, arg.RoleName, arg.IsAdministrator, arg.RequireTwoFactor)
+	var i model.AuthorizationRole
+	err := row.Scan(
+		&i.RoleID,
+		&i.RoleName,
+		&i.IsAdministrator,
+		&i.RequireTwoFactor,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const updateAuthorizationRole = `-- name: UpdateAuthorizationRole :one
+UPDATE authorization_roles
+SET role_name = COALESCE($2, role_name),
+    is_administrator = COALESCE($3, is_administrator),
+    require_two_factor = COALESCE($4, require_two_factor)
+WHERE role_id = $1
+RETURNING role_id, role_name, is_administrator, require_two_factor, created_at
+`
+
+// UpdateRole
+// Updates an authorization role.
+// @param ctx context.Context
+// @param arg *model.UpdateAuthorizationRoleParams
+// @return model.AuthorizationRole
+func (repo *Repository) UpdateRole(
+	ctx context.Context,
+	arg *model.UpdateAuthorizationRoleParams,
+) (model.AuthorizationRole, error) {
+	row := repo.connPool.QueryRow(ctx, updateAuthorizationRole,
+		arg.RoleID,
+		arg.RoleName,
+		arg.IsAdministrator,
+		arg.RequireTwoFactor,
+	)
+	var i model.AuthorizationRole
+	err := row.Scan(
+		&i.RoleID,
+		&i.RoleName,
+		&i.IsAdministrator,
+		&i.RequireTwoFactor,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const lockAuthorizationRole = `-- name: LockAuthorizationRole :one
+SELECT role_id
+FROM authorization_roles
+WHERE role_id = $1
+FOR UPDATE
+`
+
+const countAdminByRole = `-- name: CountAdminByRole :one
+SELECT COUNT(*)
+FROM admin
+WHERE role_id = $1
+`
+
+const deleteAuthorizationRole = `-- name: DeleteAuthorizationRole :one
+DELETE FROM authorization_roles
+WHERE role_id = $1
+RETURNING role_id, role_name, is_administrator, require_two_factor, created_at
+`
+
+// DeleteRole
+// Deletes an authorization role which is not assigned to any admin.
+// @param ctx context.Context
+// @param roleID int64
+// @return model.AuthorizationRole
+func (repo *Repository) DeleteRole(
+	ctx context.Context,
+	roleID int64,
+) (model.AuthorizationRole, error) {
+	var i model.AuthorizationRole
+	err := pgx.BeginFunc(ctx, repo.connPool, func(tx pgx.Tx) error {
+		var lockedRoleID int32
+		if err := tx.QueryRow(ctx, lockAuthorizationRole, roleID).Scan(&lockedRoleID); err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.QueryRow(ctx, countAdminByRole, roleID).Scan(&count); err != nil {
+			return err
+		}
+		if count > 0 {
+			return authorization.ErrRoleInUse
+		}
+		return tx.QueryRow(ctx, deleteAuthorizationRole, roleID).Scan(
+			&i.RoleID,
+			&i.RoleName,
+			&i.IsAdministrator,
+			&i.RequireTwoFactor,
+			&i.CreatedAt,
+		)
+	})
+	return i, err
+}
+
+const deleteAuthorizationRules = `-- name: DeleteAuthorizationRules :exec
+DELETE FROM authorization_rules
+WHERE role_id = $1
+`
+
+const createAuthorizationRule = `-- name: CreateAuthorizationRule :one
+INSERT INTO authorization_rules
+    (
+        role_id,
+        permission_code,
+        is_allowed
+    )
+VALUES ($1, $2, $3)
+RETURNING rule_id, role_id, permission_code, is_allowed, created_at
+`
+
+// ReplaceRules
+// Replaces all rules of an authorization role in a single transaction.
+// @param ctx context.Context
+// @param arg *model.ReplaceAuthorizationRulesParams
+// @return []model.AuthorizationRule
+func (repo *Repository) ReplaceRules(
+	ctx context.Context,
+	arg *model.ReplaceAuthorizationRulesParams,
+) ([]model.AuthorizationRule, error) {
+	items := []model.AuthorizationRule{}
+	err := pgx.BeginFunc(ctx, repo.connPool, func(tx pgx.Tx) error {
+		var lockedRoleID int32
+		if err := tx.QueryRow(ctx, lockAuthorizationRole, arg.RoleID).Scan(&lockedRoleID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, deleteAuthorizationRules, arg.RoleID); err != nil {
+			return err
+		}
+		for _, rule := range arg.Rules {
+			var i model.AuthorizationRule
+			err := tx.QueryRow(ctx, createAuthorizationRule,
+				arg.RoleID,
+				rule.PermissionCode,
+				rule.IsAllowed,
+			).Scan(
+				&i.RuleID,
+				&i.RoleID,
+				&i.PermissionCode,
+				&i.IsAllowed,
+				&i.CreatedAt,
+			)
+			if err != nil {
+				return err
+			}
+			items = append(items, i)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
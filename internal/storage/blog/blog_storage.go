@@ -0,0 +1,334 @@
+package blog
+
+import (
+	"context"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/blog"
+	"github.com/daniel-vuky/go-blog/internal/storage"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// publishedPostCondition
+// Condition every post must match to be visible on the public blog
+const publishedPostCondition = `post.url_key IS NOT NULL AND post.status = 'published'`
+
+// Repository
+// Wraps the Queries struct from the storage package and a connection pool.
+type Repository struct {
+	*storage.Queries
+	connPool *pgxpool.Pool
+}
+
+// NewBlogRepository
+// Returns a new instance of Repository.
+// @param connPool *pgxpool.Pool
+// @return *Repository
+func NewBlogRepository(connPool *pgxpool.Pool) *Repository {
+	return &Repository{
+		Queries:  storage.New(connPool),
+		connPool: connPool,
+	}
+}
+
+const getPublishedPost = `-- name: GetPublishedPost :one
+SELECT post.post_id, post.name, post.url_key, post.short_description, post.description, post.content, post.thumbnail,
+       COALESCE(TRIM(CONCAT_WS(' ', admin.firstname, admin.lastname)), '') AS author_name,
+       post.published_at, post.created_at, post.updated_at
+FROM "post"
+LEFT JOIN "admin" ON admin.admin_id = post.author_id
+WHERE post.url_key = $1
+  AND ` + publishedPostCondition + `
+`
+
+// GetPost
+// Returns a published post by url key.
+// @param ctx context.Context
+// @param urlKey string
+// @return model.Post
+func (repo *Repository) GetPost(
+	ctx context.Context,
+	urlKey string,
+) (model.Post, error) {
+	var i model.Post
+	err := repo.connPool.QueryRow(ctx, getPublishedPost, urlKey).Scan(
+		&i.PostID,
+		&i.Name,
+		&i.UrlKey,
+		&i.ShortDescription,
+		&i.Description,
+		&i.Content,
+		&i.Thumbnail,
+		&i.AuthorName,
+		&i.PublishedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getPublishedPostCategories = `-- name: GetPublishedPostCategories :many
+SELECT category.name, category.url_key, category.updated_at
+FROM "category"
+JOIN "post_links" ON post_links.category_id = category.category_id
+WHERE post_links.post_id = $1
+  AND category.url_key IS NOT NULL
+ORDER BY category.name, category.category_id
+`
+
+// GetPostCategories
+// Returns the public categories a post belongs to.
+// @param ctx context.Context
+// @param postID int64
+// @return []model.CategorySummary
+func (repo *Repository) GetPostCategories(
+	ctx context.Context,
+	postID int64,
+) ([]model.CategorySummary, error) {
+	rows, err := repo.connPool.Query(ctx, getPublishedPostCategories, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []model.CategorySummary
+	for rows.Next() {
+		var i model.CategorySummary
+		if err := rows.Scan(&i.Name, &i.UrlKey, &i.UpdatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// categorySubtree
+// Category of a listing with its descendants when they are included, $1 is the category and $2 includes them
+const categorySubtree = `WITH RECURSIVE subtree AS (
+    SELECT category_id
+    FROM "category"
+    WHERE category_id = $1
+    UNION ALL
+    SELECT child.category_id
+    FROM "category" child
+    JOIN subtree ON child.parent_id = subtree.category_id
+    WHERE $2::bool
+)
+`
+
+// categoryPostCondition
+// Condition restricting a listing to the posts of the category subtree, when a category is set
+const categoryPostCondition = `($1::bigint IS NULL OR EXISTS (
+    SELECT 1 FROM "post_links"
+    JOIN subtree ON subtree.category_id = post_links.category_id
+    WHERE post_links.post_id = post.post_id
+  ))`
+
+const getListPublishedPost = `-- name: GetListPublishedPost :many
+` + categorySubtree + `SELECT post.name, post.url_key, post.short_description, post.thumbnail,
+       COALESCE(TRIM(CONCAT_WS(' ', admin.firstname, admin.lastname)), '') AS author_name,
+       post.published_at, post.created_at, post.updated_at
+FROM "post"
+LEFT JOIN "admin" ON admin.admin_id = post.author_id
+WHERE ` + publishedPostCondition + `
+  AND ` + categoryPostCondition + `
+ORDER BY post.published_at DESC, post.post_id DESC
+LIMIT $3 OFFSET $4
+`
+
+const getTotalPublishedPost = `-- name: GetTotalPublishedPost :one
+` + categorySubtree + `SELECT COUNT(*)
+FROM "post"
+WHERE ` + publishedPostCondition + `
+  AND ` + categoryPostCondition + `
+`
+
+// GetListPost
+// Returns the published posts, newest first, optionally restricted to a category or to a category and its descendants.
+// @param ctx context.Context
+// @param arg *model.GetListPostParams
+// @return []model.PostSummary
+// @return total post
+// @return error
+func (repo *Repository) GetListPost(
+	ctx context.Context,
+	arg *model.GetListPostParams,
+) ([]model.PostSummary, int64, error) {
+	offset := arg.PageSize * (arg.CurrentPage - 1)
+	rows, err := repo.connPool.Query(ctx, getListPublishedPost,
+		arg.CategoryID,
+		arg.IncludeDescendants,
+		arg.PageSize,
+		offset,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	var items []model.PostSummary
+	for rows.Next() {
+		var i model.PostSummary
+		if err := rows.Scan(
+			&i.Name,
+			&i.UrlKey,
+			&i.ShortDescription,
+			&i.Thumbnail,
+			&i.AuthorName,
+			&i.PublishedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var count int64
+	err = repo.connPool.QueryRow(ctx, getTotalPublishedPost, arg.CategoryID, arg.IncludeDescendants).Scan(&count)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, count, nil
+}
+
+const getPublicCategory = `-- name: GetPublicCategory :one
+SELECT category.category_id, category.name, category.url_key, parent.url_key AS parent_url_key,
+       category.short_description, category.description, category.updated_at
+FROM "category"
+LEFT JOIN "category" parent ON parent.category_id = category.parent_id
+WHERE category.url_key = $1
+`
+
+// GetCategory
+// Returns a category by url key.
+// @param ctx context.Context
+// @param urlKey string
+// @return model.Category
+func (repo *Repository) GetCategory(
+	ctx context.Context,
+	urlKey string,
+) (model.Category, error) {
+	var i model.Category
+	err := repo.connPool.QueryRow(ctx, getPublicCategory, urlKey).Scan(
+		&i.CategoryID,
+		&i.Name,
+		&i.UrlKey,
+		&i.ParentUrlKey,
+		&i.ShortDescription,
+		&i.Description,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getListPublicCategory = `-- name: GetListPublicCategory :many
+SELECT category.category_id, category.name, category.url_key, parent.url_key AS parent_url_key,
+       category.short_description, category.description, category.updated_at
+FROM "category"
+LEFT JOIN "category" parent ON parent.category_id = category.parent_id
+WHERE category.url_key IS NOT NULL
+ORDER BY category.name, category.category_id
+`
+
+// GetListCategory
+// Returns every category having a url key.
+// @param ctx context.Context
+// @return []model.Category
+func (repo *Repository) GetListCategory(
+	ctx context.Context,
+) ([]model.Category, error) {
+	rows, err := repo.connPool.Query(ctx, getListPublicCategory)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []model.Category
+	for rows.Next() {
+		var i model.Category
+		if err := rows.Scan(
+			&i.CategoryID,
+			&i.Name,
+			&i.UrlKey,
+			&i.ParentUrlKey,
+			&i.ShortDescription,
+			&i.Description,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getListPublicComment = `-- name: GetListPublicComment :many
+SELECT comment.comment_id, comment.parent_id,
+       COALESCE(TRIM(CONCAT_WS(' ', "user".firstname, "user".lastname)), '') AS author_name,
+       comment.comment, comment.created_at, comment.updated_at
+FROM "comment"
+LEFT JOIN "user" ON "user".user_id = comment.user_id
+WHERE comment.post_id = $1
+  AND comment.status = 'approved'
+ORDER BY comment.created_at, comment.comment_id
+LIMIT $2 OFFSET $3
+`
+
+const getTotalPublicComment = `-- name: GetTotalPublicComment :one
+SELECT COUNT(*)
+FROM "comment"
+WHERE post_id = $1
+  AND status = 'approved'
+`
+
+// GetListComment
+// Returns the approved comments of a post, oldest first.
+// @param ctx context.Context
+// @param arg *model.GetListCommentParams
+// @return []model.Comment
+// @return total comment
+// @return error
+func (repo *Repository) GetListComment(
+	ctx context.Context,
+	arg *model.GetListCommentParams,
+) ([]model.Comment, int64, error) {
+	offset := arg.PageSize * (arg.CurrentPage - 1)
+	rows, err := repo.connPool.Query(ctx, getListPublicComment, arg.PostID, arg.PageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	var items []model.Comment
+	for rows.Next() {
+		var i model.Comment
+		if err := rows.Scan(
+			&i.CommentID,
+			&i.ParentID,
+			&i.AuthorName,
+			&i.Comment,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var count int64
+	err = repo.connPool.QueryRow(ctx, getTotalPublicComment, arg.PostID).Scan(&count)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, count, nil
+}
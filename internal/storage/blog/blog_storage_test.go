@@ -0,0 +1,126 @@
+package blog
+
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/blog"
+	"github.com/daniel-vuky/go-blog/pkg/config"
+	goRandom "github.com/daniel-vuky/go-random"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+var repository *Repository
+
+// TestMain
+// Initializes the repository and closes the connection pool after all tests have run.
+func TestMain(m *testing.M) {
+	loadedConfig, err := config.LoadConfig("../../../")
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+	connPool, err := loadedConfig.ConnectToPgxPool()
+	if err != nil {
+		log.Fatalf("failed to create connection pool: %v", err)
+	}
+	repository = NewBlogRepository(connPool)
+	code := m.Run()
+	repository.connPool.Close()
+	os.Exit(code)
+}
+
+// createRandomCategory
+// Creates a category with a random name under parentID, or as a root when parentID is 0.
+func createRandomCategory(t *testing.T, parentID int64) int64 {
+	name := goRandom.RandomString(12)
+	var categoryID int64
+	err := repository.connPool.QueryRow(context.Background(), `
+		INSERT INTO "category" (parent_id, name, url_key)
+		VALUES (NULLIF($1::bigint, 0), $2, $3)
+		RETURNING category_id
+	`, parentID, name, strings.ToLower(name)).Scan(&categoryID)
+	require.NoError(t, err)
+	return categoryID
+}
+
+// createRandomPost
+// Creates a post with the given status linked to the given categories.
+func createRandomPost(t *testing.T, status string, categoryIDs ...int64) string {
+	ctx := context.Background()
+	urlKey := strings.ToLower(goRandom.RandomString(16))
+	var postID int64
+	err := repository.connPool.QueryRow(ctx, `
+		INSERT INTO "post" (name, url_key, author_id, status, published_at)
+		VALUES ($1, $2, 0, $3::post_status, CASE WHEN $3::post_status = 'draft' THEN NULL ELSE NOW() END)
+		RETURNING post_id
+	`, goRandom.RandomString(12), urlKey, status).Scan(&postID)
+	require.NoError(t, err)
+	for _, categoryID := range categoryIDs {
+		_, err = repository.connPool.Exec(ctx, `INSERT INTO "post_links" (post_id, category_id) VALUES ($1, $2)`, postID, categoryID)
+		require.NoError(t, err)
+	}
+	return urlKey
+}
+
+// listUrlKeys
+// Returns the url keys of the published posts of a category.
+func listUrlKeys(t *testing.T, categoryID int64, includeDescendants bool) ([]string, int64) {
+	posts, total, err := repository.GetListPost(context.Background(), &model.GetListPostParams{
+		CategoryID:         pgtype.Int8{Int64: categoryID, Valid: true},
+		IncludeDescendants: includeDescendants,
+		PageSize:           10,
+		CurrentPage:        1,
+	})
+	require.NoError(t, err)
+	urlKeys := make([]string, 0, len(posts))
+	for _, post := range posts {
+		urlKeys = append(urlKeys, post.UrlKey)
+	}
+	return urlKeys, total
+}
+
+// TestRepository_GetListPost_Category
+// Tests the posts of the descendants are only listed when asked, once and only when published.
+func TestRepository_GetListPost_Category(t *testing.T) {
+	root := createRandomCategory(t, 0)
+	child := createRandomCategory(t, root)
+	grandchild := createRandomCategory(t, child)
+	other := createRandomCategory(t, 0)
+
+	inRoot := createRandomPost(t, "published", root)
+	inGrandchild := createRandomPost(t, "published", grandchild)
+	inBoth := createRandomPost(t, "published", root, child)
+	createRandomPost(t, "draft", child)
+	createRandomPost(t, "published", other)
+
+	urlKeys, total := listUrlKeys(t, root, false)
+	require.ElementsMatch(t, []string{inRoot, inBoth}, urlKeys)
+	require.Equal(t, int64(2), total)
+
+	urlKeys, total = listUrlKeys(t, root, true)
+	require.ElementsMatch(t, []string{inRoot, inGrandchild, inBoth}, urlKeys)
+	require.Equal(t, int64(3), total)
+
+	urlKeys, total = listUrlKeys(t, child, true)
+	require.ElementsMatch(t, []string{inGrandchild, inBoth}, urlKeys)
+	require.Equal(t, int64(2), total)
+}
+
+// TestRepository_GetListPost_AllCategories
+// Tests the listing without category is not restricted by the descendants flag.
+func TestRepository_GetListPost_AllCategories(t *testing.T) {
+	createRandomPost(t, "published")
+
+	posts, total, err := repository.GetListPost(context.Background(), &model.GetListPostParams{
+		IncludeDescendants: true,
+		PageSize:           1,
+		CurrentPage:        1,
+	})
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	require.GreaterOrEqual(t, total, int64(1))
+}
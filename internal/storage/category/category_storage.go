@@ -0,0 +1,427 @@
+package category
+
+import (
+	"context"
+	"fmt"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/category"
+	"github.com/daniel-vuky/go-blog/internal/repository/category"
+	"github.com/daniel-vuky/go-blog/internal/storage"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// categoryTreeLockKey
+// Key of the advisory lock serializing the moves of categories
+const categoryTreeLockKey = "category_tree"
+
+// Repository
+// Wraps the Queries struct from the storage package and a connection pool.
+type Repository struct {
+	*storage.Queries
+	connPool *pgxpool.Pool
+}
+
+// NewCategoryRepository
+// Returns a new instance of Repository.
+// @param connPool *pgxpool.Pool
+// @return *Repository
+func NewCategoryRepository(connPool *pgxpool.Pool) *Repository {
+	return &Repository{
+		Queries:  storage.New(connPool),
+		connPool: connPool,
+	}
+}
+
+// scanCategory
+// Scans a full category row.
+// @param row pgx.Row
+// @return model.Category, error
+func scanCategory(row pgx.Row) (model.Category, error) {
+	var i model.Category
+	err := row.Scan(
+		&i.CategoryID,
+		&i.ParentID,
+		&i.Name,
+		&i.UrlKey,
+		&i.ShortDescription,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+// collectCategories
+// Scans every category row of a query.
+// @param rows pgx.Rows
+// @return []model.Category, error
+func collectCategories(rows pgx.Rows) ([]model.Category, error) {
+	defer rows.Close()
+	var items []model.Category
+	for rows.Next() {
+		i, err := scanCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCategory = `-- name: GetCategory :one
+SELECT category_id, parent_id, name, url_key, short_description, description, created_at, updated_at
+FROM "category"
+WHERE category_id = $1
+`
+
+// Get
+// Returns a category by id.
+// @param ctx context.Context
+// @param categoryID int64
+// @return model.Category
+func (repo *Repository) Get(
+	ctx context.Context,
+	categoryID int64,
+) (model.Category, error) {
+	return scanCategory(repo.connPool.QueryRow(ctx, getCategory, categoryID))
+}
+
+const getListCategory = `-- name: GetListCategory :many
+SELECT category_id, parent_id, name, url_key, short_description, description, created_at, updated_at
+FROM "category"
+WHERE category_id != 0
+%s
+ORDER BY %s %s
+LIMIT %d OFFSET %d
+`
+
+const getTotalCategory = `-- name: GetTotalCategory :one
+SELECT COUNT(*)
+FROM "category"
+WHERE category_id != 0
+%s
+`
+
+// GetList returns a list of categories.
+// @param ctx context.Context
+// @param arg *model.GetListCategoryParams
+// @return []model.Category
+// @return total category
+// @return error
+func (repo *Repository) GetList(
+	ctx context.Context,
+	arg *model.GetListCategoryParams,
+) ([]model.Category, int64, error) {
+	offset := arg.PageSize * (arg.CurrentPage - 1)
+
+	// Build dynamic filter conditions
+	filterConditions, filterArgs := arg.BuildFilterConditions(arg.Filter)
+
+	// Prepare the main query with dynamic filters
+	query := fmt.Sprintf(
+		getListCategory,
+		filterConditions,
+		arg.OrderBy,
+		arg.OrderDirection,
+		arg.PageSize,
+		offset,
+	)
+
+	// Execute the main query
+	rows, err := repo.connPool.Query(ctx, query, filterArgs...)
+	if err != nil {
+		return nil, 0, err
+	}
+	items, err := collectCategories(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Build and execute the total count query
+	totalQuery := fmt.Sprintf(getTotalCategory, filterConditions)
+	totalRow := repo.connPool.QueryRow(ctx, totalQuery, filterArgs...)
+
+	var count int64
+	err = totalRow.Scan(&count)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, count, nil
+}
+
+const getCategoryTree = `-- name: GetCategoryTree :many
+WITH RECURSIVE tree AS (
+    SELECT category.*, 0 AS depth
+    FROM "category"
+    WHERE ($1::bigint IS NULL AND parent_id IS NULL)
+       OR category_id = $1
+    UNION ALL
+    SELECT child.*, tree.depth + 1
+    FROM "category" child
+    JOIN tree ON child.parent_id = tree.category_id
+)
+SELECT category_id, parent_id, name, url_key, short_description, description, created_at, updated_at
+FROM tree
+ORDER BY depth, name, category_id
+`
+
+// GetTree
+// Returns the categories of the whole tree, or of the subtree of rootID,
+// ordered so every parent comes before its children.
+// @param ctx context.Context
+// @param rootID pgtype.Int8
+// @return []model.Category
+func (repo *Repository) GetTree(
+	ctx context.Context,
+	rootID pgtype.Int8,
+) ([]model.Category, error) {
+	rows, err := repo.connPool.Query(ctx, getCategoryTree, rootID)
+	if err != nil {
+		return nil, err
+	}
+	return collectCategories(rows)
+}
+
+const getCategoryBreadcrumbs = `-- name: GetCategoryBreadcrumbs :many
+WITH RECURSIVE ancestors AS (
+    SELECT category.*, 0 AS depth
+    FROM "category"
+    WHERE category_id = $1
+    UNION ALL
+    SELECT parent.*, ancestors.depth + 1
+    FROM "category" parent
+    JOIN ancestors ON parent.category_id = ancestors.parent_id
+)
+SELECT category_id, parent_id, name, url_key, short_description, description, created_at, updated_at
+FROM ancestors
+ORDER BY depth DESC
+`
+
+// GetBreadcrumbs
+// Returns the path from the root to a category, the category being the last element.
+// @param ctx context.Context
+// @param categoryID int64
+// @return []model.Category
+func (repo *Repository) GetBreadcrumbs(
+	ctx context.Context,
+	categoryID int64,
+) ([]model.Category, error) {
+	rows, err := repo.connPool.Query(ctx, getCategoryBreadcrumbs, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	return collectCategories(rows)
+}
+
+const createCategory = `-- name: CreateCategory :one
+INSERT INTO "category"
+    (
+        parent_id,
+        name,
+        url_key,
+        short_description,
+        description
+    )
+VALUES ($1, $2, $3, $4, $5)
+RETURNING category_id, parent_id, name, url_key, short_description, description, created_at, updated_at
+`
+
+// Create
+// Creates a new category.
+// @param ctx context.Context
+// @param arg *model.CreateCategoryParams
+// @return model.Category
+func (repo *Repository) Create(
+	ctx context.Context,
+	arg *model.CreateCategoryParams,
+) (model.Category, error) {
+	return scanCategory(repo.connPool.QueryRow(ctx, createCategory,
+		arg.ParentID,
+		arg.Name,
+		arg.UrlKey,
+		arg.ShortDescription,
+		arg.Description,
+	))
+}
+
+const updateCategory = `-- name: UpdateCategory :one
+UPDATE "category"
+SET name = COALESCE($2, name),
+    url_key = COALESCE($3, url_key),
+    short_description = COALESCE($4, short_description),
+    description = COALESCE($5, description)
+WHERE category_id = $1
+RETURNING category_id, parent_id, name, url_key, short_description, description, created_at, updated_at
+`
+
+// Update
+// Updates a category without moving it.
+// @param ctx context.Context
+// @param arg *model.UpdateCategoryParams
+// @return model.Category
+func (repo *Repository) Update(
+	ctx context.Context,
+	arg *model.UpdateCategoryParams,
+) (model.Category, error) {
+	return scanCategory(repo.connPool.QueryRow(ctx, updateCategory,
+		arg.CategoryID,
+		arg.Name,
+		arg.UrlKey,
+		arg.ShortDescription,
+		arg.Description,
+	))
+}
+
+const isCategoryInSubtree = `-- name: IsCategoryInSubtree :one
+WITH RECURSIVE subtree AS (
+    SELECT category_id
+    FROM "category"
+    WHERE category_id = $1
+    UNION ALL
+    SELECT child.category_id
+    FROM "category" child
+    JOIN subtree ON child.parent_id = subtree.category_id
+)
+SELECT EXISTS (SELECT 1 FROM subtree WHERE category_id = $2)
+`
+
+const moveCategory = `-- name: MoveCategory :one
+UPDATE "category"
+SET parent_id = $2
+WHERE category_id = $1
+RETURNING category_id, parent_id, name, url_key, short_description, description, created_at, updated_at
+`
+
+// Move
+// Moves a category with its subtree under another parent, or to the root when parentID is not valid.
+// Moves are serialized so two concurrent moves cannot build a cycle together.
+// Returns category.ErrCategoryCycle when the parent belongs to the subtree of the category.
+// @param ctx context.Context
+// @param categoryID int64
+// @param parentID pgtype.Int8
+// @return model.Category
+func (repo *Repository) Move(
+	ctx context.Context,
+	categoryID int64,
+	parentID pgtype.Int8,
+) (model.Category, error) {
+	var i model.Category
+	err := pgx.BeginFunc(ctx, repo.connPool, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", categoryTreeLockKey)
+		if err != nil {
+			return err
+		}
+		if parentID.Valid {
+			var isInSubtree bool
+			err = tx.QueryRow(ctx, isCategoryInSubtree, categoryID, parentID.Int64).Scan(&isInSubtree)
+			if err != nil {
+				return err
+			}
+			if isInSubtree {
+				return category.ErrCategoryCycle
+			}
+		}
+		i, err = scanCategory(tx.QueryRow(ctx, moveCategory, categoryID, parentID))
+		return err
+	})
+	return i, err
+}
+
+const deleteCategory = `-- name: DeleteCategory :one
+DELETE FROM "category"
+WHERE category_id = $1
+RETURNING category_id, parent_id, name, url_key, short_description, description, created_at, updated_at
+`
+
+// Delete
+// Deletes a category, the foreign key rejects categories which still have children.
+// @param ctx context.Context
+// @param categoryID int64
+// @return model.Category
+func (repo *Repository) Delete(
+	ctx context.Context,
+	categoryID int64,
+) (model.Category, error) {
+	return scanCategory(repo.connPool.QueryRow(ctx, deleteCategory, categoryID))
+}
+
+const reassignPostLinks = `-- name: ReassignPostLinks :exec
+INSERT INTO "post_links" (post_id, category_id)
+SELECT post_id, $2
+FROM "post_links"
+WHERE category_id = $1
+ON CONFLICT (post_id, category_id) DO NOTHING
+`
+
+// DeleteAndReassign
+// Assigns the posts of a category to the target category, then deletes the category.
+// @param ctx context.Context
+// @param categoryID int64
+// @param targetID int64
+// @return model.Category
+func (repo *Repository) DeleteAndReassign(
+	ctx context.Context,
+	categoryID int64,
+	targetID int64,
+) (model.Category, error) {
+	var i model.Category
+	err := pgx.BeginFunc(ctx, repo.connPool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, reassignPostLinks, categoryID, targetID); err != nil {
+			return err
+		}
+		var err error
+		i, err = scanCategory(tx.QueryRow(ctx, deleteCategory, categoryID))
+		return err
+	})
+	return i, err
+}
+
+const moveChildCategories = `-- name: MoveChildCategories :exec
+UPDATE "category"
+SET parent_id = $2
+WHERE parent_id = $1
+`
+
+// Merge
+// Moves the posts and the child categories of the source category to the target category,
+// then deletes the source category.
+// Returns category.ErrCategoryCycle when the target belongs to the subtree of the source.
+// @param ctx context.Context
+// @param sourceID int64
+// @param targetID int64
+// @return model.Category
+func (repo *Repository) Merge(
+	ctx context.Context,
+	sourceID int64,
+	targetID int64,
+) (model.Category, error) {
+	var i model.Category
+	err := pgx.BeginFunc(ctx, repo.connPool, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", categoryTreeLockKey)
+		if err != nil {
+			return err
+		}
+		var isInSubtree bool
+		if err = tx.QueryRow(ctx, isCategoryInSubtree, sourceID, targetID).Scan(&isInSubtree); err != nil {
+			return err
+		}
+		if isInSubtree {
+			return category.ErrCategoryCycle
+		}
+		if _, err = tx.Exec(ctx, reassignPostLinks, sourceID, targetID); err != nil {
+			return err
+		}
+		if _, err = tx.Exec(ctx, moveChildCategories, sourceID, targetID); err != nil {
+			return err
+		}
+		i, err = scanCategory(tx.QueryRow(ctx, deleteCategory, sourceID))
+		return err
+	})
+	return i, err
+}
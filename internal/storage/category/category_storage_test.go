@@ -0,0 +1,212 @@
+package category
+
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/category"
+	"github.com/daniel-vuky/go-blog/internal/repository/category"
+	"github.com/daniel-vuky/go-blog/pkg/config"
+	goRandom "github.com/daniel-vuky/go-random"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+var repository *Repository
+
+// TestMain
+// Initializes the repository and closes the connection pool after all tests have run.
+func TestMain(m *testing.M) {
+	loadedConfig, err := config.LoadConfig("../../../")
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+	connPool, err := loadedConfig.ConnectToPgxPool()
+	if err != nil {
+		log.Fatalf("failed to create connection pool: %v", err)
+	}
+	repository = NewCategoryRepository(connPool)
+	code := m.Run()
+	repository.connPool.Close()
+	os.Exit(code)
+}
+
+// createRandomCategory
+// Creates a category with a random name under parentID, or as a root when parentID is not valid.
+func createRandomCategory(t *testing.T, parentID pgtype.Int8) model.Category {
+	name := goRandom.RandomString(12)
+	createdCategory, err := repository.Create(context.Background(), &model.CreateCategoryParams{
+		ParentID: parentID,
+		Name:     name,
+		UrlKey:   pgtype.Text{String: strings.ToLower(name), Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, name, createdCategory.Name)
+	require.Equal(t, parentID, createdCategory.ParentID)
+
+	return createdCategory
+}
+
+// createRandomTree
+// Creates the chain root > child > grandchild.
+func createRandomTree(t *testing.T) (model.Category, model.Category, model.Category) {
+	root := createRandomCategory(t, pgtype.Int8{})
+	child := createRandomCategory(t, pgtype.Int8{Int64: root.CategoryID, Valid: true})
+	grandchild := createRandomCategory(t, pgtype.Int8{Int64: child.CategoryID, Valid: true})
+	return root, child, grandchild
+}
+
+// createPublishedPost
+// Creates a published post linked to the given categories.
+func createPublishedPost(t *testing.T, categoryIDs ...int64) int64 {
+	ctx := context.Background()
+	var postID int64
+	err := repository.connPool.QueryRow(ctx, `
+		INSERT INTO "post" (name, url_key, author_id, status, published_at)
+		VALUES ($1, $2, 0, 'published', NOW())
+		RETURNING post_id
+	`, goRandom.RandomString(12), strings.ToLower(goRandom.RandomString(16))).Scan(&postID)
+	require.NoError(t, err)
+	for _, categoryID := range categoryIDs {
+		_, err = repository.connPool.Exec(ctx, `INSERT INTO "post_links" (post_id, category_id) VALUES ($1, $2)`, postID, categoryID)
+		require.NoError(t, err)
+	}
+	return postID
+}
+
+// getPostCategoryIDs
+// Returns the ids of the categories a post is linked to.
+func getPostCategoryIDs(t *testing.T, postID int64) []int64 {
+	rows, err := repository.connPool.Query(context.Background(), `
+		SELECT category_id FROM "post_links" WHERE post_id = $1 ORDER BY category_id
+	`, postID)
+	require.NoError(t, err)
+	categoryIDs, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	require.NoError(t, err)
+	return categoryIDs
+}
+
+// TestRepository_GetTree
+// Tests the subtree is returned parents first.
+func TestRepository_GetTree(t *testing.T) {
+	root, child, grandchild := createRandomTree(t)
+	sibling := createRandomCategory(t, pgtype.Int8{Int64: root.CategoryID, Valid: true})
+
+	subtree, err := repository.GetTree(context.Background(), pgtype.Int8{Int64: root.CategoryID, Valid: true})
+	require.NoError(t, err)
+	require.Len(t, subtree, 4)
+	require.Equal(t, root.CategoryID, subtree[0].CategoryID)
+	require.ElementsMatch(t, []int64{child.CategoryID, sibling.CategoryID}, []int64{subtree[1].CategoryID, subtree[2].CategoryID})
+	require.Equal(t, grandchild.CategoryID, subtree[3].CategoryID)
+
+	breadcrumbs, err := repository.GetBreadcrumbs(context.Background(), grandchild.CategoryID)
+	require.NoError(t, err)
+	require.Len(t, breadcrumbs, 3)
+	require.Equal(t, root.CategoryID, breadcrumbs[0].CategoryID)
+	require.Equal(t, grandchild.CategoryID, breadcrumbs[2].CategoryID)
+}
+
+// TestRepository_Move
+// Tests a category moves with its subtree and cannot move into it.
+func TestRepository_Move(t *testing.T) {
+	root, child, grandchild := createRandomTree(t)
+	other := createRandomCategory(t, pgtype.Int8{})
+
+	_, err := repository.Move(context.Background(), root.CategoryID, pgtype.Int8{Int64: root.CategoryID, Valid: true})
+	require.ErrorIs(t, err, category.ErrCategoryCycle)
+	_, err = repository.Move(context.Background(), root.CategoryID, pgtype.Int8{Int64: grandchild.CategoryID, Valid: true})
+	require.ErrorIs(t, err, category.ErrCategoryCycle)
+
+	moved, err := repository.Move(context.Background(), child.CategoryID, pgtype.Int8{Int64: other.CategoryID, Valid: true})
+	require.NoError(t, err)
+	require.Equal(t, other.CategoryID, moved.ParentID.Int64)
+	subtree, err := repository.GetTree(context.Background(), pgtype.Int8{Int64: other.CategoryID, Valid: true})
+	require.NoError(t, err)
+	require.Len(t, subtree, 3)
+	require.Equal(t, grandchild.CategoryID, subtree[2].CategoryID)
+
+	moved, err = repository.Move(context.Background(), child.CategoryID, pgtype.Int8{})
+	require.NoError(t, err)
+	require.False(t, moved.ParentID.Valid)
+}
+
+// TestRepository_Delete_HasChildren
+// Tests a category with children cannot be deleted.
+func TestRepository_Delete_HasChildren(t *testing.T) {
+	root, _, grandchild := createRandomTree(t)
+
+	deletedCategory, err := repository.Delete(context.Background(), root.CategoryID)
+	require.Error(t, err)
+	require.Empty(t, deletedCategory)
+
+	deletedCategory, err = repository.Delete(context.Background(), grandchild.CategoryID)
+	require.NoError(t, err)
+	require.Equal(t, grandchild.CategoryID, deletedCategory.CategoryID)
+}
+
+// TestRepository_DeleteAndReassign
+// Tests the posts of the deleted category are linked to the target once.
+func TestRepository_DeleteAndReassign(t *testing.T) {
+	source := createRandomCategory(t, pgtype.Int8{})
+	target := createRandomCategory(t, pgtype.Int8{})
+	onlySource := createPublishedPost(t, source.CategoryID)
+	both := createPublishedPost(t, source.CategoryID, target.CategoryID)
+
+	deletedCategory, err := repository.DeleteAndReassign(context.Background(), source.CategoryID, target.CategoryID)
+	require.NoError(t, err)
+	require.Equal(t, source.CategoryID, deletedCategory.CategoryID)
+	require.Equal(t, []int64{target.CategoryID}, getPostCategoryIDs(t, onlySource))
+	require.Equal(t, []int64{target.CategoryID}, getPostCategoryIDs(t, both))
+
+	_, err = repository.Get(context.Background(), source.CategoryID)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+// TestRepository_DeleteAndReassign_HasChildren
+// Tests the reassignment is rolled back when the category still has children.
+func TestRepository_DeleteAndReassign_HasChildren(t *testing.T) {
+	root, _, _ := createRandomTree(t)
+	target := createRandomCategory(t, pgtype.Int8{})
+	postID := createPublishedPost(t, root.CategoryID)
+
+	_, err := repository.DeleteAndReassign(context.Background(), root.CategoryID, target.CategoryID)
+	require.Error(t, err)
+	require.Equal(t, []int64{root.CategoryID}, getPostCategoryIDs(t, postID))
+}
+
+// TestRepository_Merge
+// Tests the posts and the children of the source move to the target.
+func TestRepository_Merge(t *testing.T) {
+	source, child, grandchild := createRandomTree(t)
+	target := createRandomCategory(t, pgtype.Int8{})
+	postID := createPublishedPost(t, source.CategoryID)
+
+	mergedCategory, err := repository.Merge(context.Background(), source.CategoryID, target.CategoryID)
+	require.NoError(t, err)
+	require.Equal(t, source.CategoryID, mergedCategory.CategoryID)
+	require.Equal(t, []int64{target.CategoryID}, getPostCategoryIDs(t, postID))
+
+	subtree, err := repository.GetTree(context.Background(), pgtype.Int8{Int64: target.CategoryID, Valid: true})
+	require.NoError(t, err)
+	require.Len(t, subtree, 3)
+	require.Equal(t, child.CategoryID, subtree[1].CategoryID)
+	require.Equal(t, grandchild.CategoryID, subtree[2].CategoryID)
+}
+
+// TestRepository_Merge_Cycle
+// Tests a category cannot be merged into its own subtree.
+func TestRepository_Merge_Cycle(t *testing.T) {
+	source, _, grandchild := createRandomTree(t)
+	postID := createPublishedPost(t, source.CategoryID)
+
+	_, err := repository.Merge(context.Background(), source.CategoryID, grandchild.CategoryID)
+	require.ErrorIs(t, err, category.ErrCategoryCycle)
+	require.Equal(t, []int64{source.CategoryID}, getPostCategoryIDs(t, postID))
+
+	_, err = repository.Get(context.Background(), source.CategoryID)
+	require.NoError(t, err)
+}
@@ -0,0 +1,367 @@
+package comment
+
+import (
+	"context"
+	"fmt"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/comment"
+	"github.com/daniel-vuky/go-blog/internal/storage"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository
+// Wraps the Queries struct from the storage package and a connection pool.
+type Repository struct {
+	*storage.Queries
+	connPool *pgxpool.Pool
+}
+
+// NewCommentRepository
+// Returns a new instance of Repository.
+// @param connPool *pgxpool.Pool
+// @return *Repository
+func NewCommentRepository(connPool *pgxpool.Pool) *Repository {
+	return &Repository{
+		Queries:  storage.New(connPool),
+		connPool: connPool,
+	}
+}
+
+// scanComment
+// Scans a full comment row.
+// @param row pgx.Row
+// @return model.Comment, error
+func scanComment(row pgx.Row) (model.Comment, error) {
+	var i model.Comment
+	err := row.Scan(
+		&i.CommentID,
+		&i.PostID,
+		&i.UserID,
+		&i.ParentID,
+		&i.Comment,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+// collectCommentNodes
+// Scans every comment row of a query along with its author name and reply count.
+// @param rows pgx.Rows
+// @return []*model.CommentNode, error
+func collectCommentNodes(rows pgx.Rows) ([]*model.CommentNode, error) {
+	defer rows.Close()
+	var items []*model.CommentNode
+	for rows.Next() {
+		i := &model.CommentNode{}
+		if err := rows.Scan(
+			&i.CommentID,
+			&i.PostID,
+			&i.UserID,
+			&i.ParentID,
+			&i.Comment.Comment,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.AuthorName,
+			&i.ReplyCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getComment = `-- name: GetComment :one
+SELECT comment_id, post_id, user_id, parent_id, comment, status, created_at, updated_at
+FROM "comment"
+WHERE comment_id = $1
+`
+
+// Get
+// Returns a comment by id.
+// @param ctx context.Context
+// @param commentID int64
+// @return model.Comment
+func (repo *Repository) Get(
+	ctx context.Context,
+	commentID int64,
+) (model.Comment, error) {
+	return scanComment(repo.connPool.QueryRow(ctx, getComment, commentID))
+}
+
+const getListRootComment = `-- name: GetListRootComment :many
+SELECT comment.comment_id, comment.post_id, comment.user_id, comment.parent_id, comment.comment,
+       comment.status, comment.created_at, comment.updated_at,
+       COALESCE(TRIM(CONCAT_WS(' ', "user".firstname, "user".lastname)), '') AS author_name,
+       (SELECT COUNT(*) FROM "comment" reply WHERE reply.parent_id = comment.comment_id AND reply.status = 'approved') AS reply_count
+FROM "comment"
+LEFT JOIN "user" ON "user".user_id = comment.user_id
+WHERE comment.post_id = $1
+  AND comment.parent_id IS NULL
+  AND comment.status = 'approved'
+ORDER BY comment.created_at, comment.comment_id
+LIMIT $2 OFFSET $3
+`
+
+const getTotalRootComment = `-- name: GetTotalRootComment :one
+SELECT COUNT(*)
+FROM "comment"
+WHERE post_id = $1
+  AND parent_id IS NULL
+  AND status = 'approved'
+`
+
+// GetListRoot
+// Returns a page of the approved top level comments of a post, oldest first.
+// @param ctx context.Context
+// @param postID int64
+// @param limit int32
+// @param offset int32
+// @return []*model.CommentNode
+// @return total top level comment
+// @return error
+func (repo *Repository) GetListRoot(
+	ctx context.Context,
+	postID int64,
+	limit int32,
+	offset int32,
+) ([]*model.CommentNode, int64, error) {
+	rows, err := repo.connPool.Query(ctx, getListRootComment, postID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	items, err := collectCommentNodes(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var count int64
+	if err = repo.connPool.QueryRow(ctx, getTotalRootComment, postID).Scan(&count); err != nil {
+		return nil, 0, err
+	}
+
+	return items, count, nil
+}
+
+const getListReplyComment = `-- name: GetListReplyComment :many
+SELECT reply.comment_id, reply.post_id, reply.user_id, reply.parent_id, reply.comment,
+       reply.status, reply.created_at, reply.updated_at,
+       COALESCE(TRIM(CONCAT_WS(' ', "user".firstname, "user".lastname)), '') AS author_name,
+       (SELECT COUNT(*) FROM "comment" child WHERE child.parent_id = reply.comment_id AND child.status = 'approved') AS reply_count
+FROM (
+    SELECT comment.*,
+           ROW_NUMBER() OVER (PARTITION BY comment.parent_id ORDER BY comment.created_at, comment.comment_id) AS position
+    FROM "comment"
+    WHERE comment.parent_id = ANY($1::bigint[])
+      AND comment.status = 'approved'
+) reply
+LEFT JOIN "user" ON "user".user_id = reply.user_id
+WHERE reply.position > $3
+  AND reply.position <= $2 + $3
+ORDER BY reply.parent_id, reply.position
+`
+
+// GetListReply
+// Returns the same page of approved replies of every given comment, oldest first.
+// @param ctx context.Context
+// @param parentIDs []int64
+// @param limit int32
+// @param offset int32
+// @return []*model.CommentNode
+func (repo *Repository) GetListReply(
+	ctx context.Context,
+	parentIDs []int64,
+	limit int32,
+	offset int32,
+) ([]*model.CommentNode, error) {
+	rows, err := repo.connPool.Query(ctx, getListReplyComment, parentIDs, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return collectCommentNodes(rows)
+}
+
+const countReplyComment = `-- name: CountReplyComment :one
+SELECT COUNT(*)
+FROM "comment"
+WHERE parent_id = $1
+  AND status = 'approved'
+`
+
+// CountReply
+// Returns the number of approved direct replies of a comment.
+// @param ctx context.Context
+// @param commentID int64
+// @return int64
+func (repo *Repository) CountReply(
+	ctx context.Context,
+	commentID int64,
+) (int64, error) {
+	var count int64
+	err := repo.connPool.QueryRow(ctx, countReplyComment, commentID).Scan(&count)
+	return count, err
+}
+
+const createComment = `-- name: CreateComment :one
+INSERT INTO "comment" (post_id, user_id, parent_id, comment, status)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING comment_id, post_id, user_id, parent_id, comment, status, created_at, updated_at
+`
+
+// Create
+// Creates a comment or a reply.
+// @param ctx context.Context
+// @param arg *model.CreateCommentParams
+// @return model.Comment
+func (repo *Repository) Create(
+	ctx context.Context,
+	arg *model.CreateCommentParams,
+) (model.Comment, error) {
+	return scanComment(repo.connPool.QueryRow(
+		ctx,
+		createComment,
+		arg.PostID,
+		arg.UserID,
+		arg.ParentID,
+		arg.Comment,
+		arg.Status,
+	))
+}
+
+const updateComment = `-- name: UpdateComment :one
+UPDATE "comment"
+SET comment = $2,
+    status = $3
+WHERE comment_id = $1
+RETURNING comment_id, post_id, user_id, parent_id, comment, status, created_at, updated_at
+`
+
+// Update
+// Replaces the text and the status of a comment.
+// @param ctx context.Context
+// @param arg *model.UpdateCommentParams
+// @return model.Comment
+func (repo *Repository) Update(
+	ctx context.Context,
+	arg *model.UpdateCommentParams,
+) (model.Comment, error) {
+	return scanComment(repo.connPool.QueryRow(ctx, updateComment, arg.CommentID, arg.Comment, arg.Status))
+}
+
+const getListComment = `-- name: GetListComment :many
+SELECT comment_id, post_id, user_id, parent_id, comment, status, created_at, updated_at
+FROM "comment"
+WHERE comment_id != 0
+%s
+ORDER BY %s %s
+LIMIT %d OFFSET %d
+`
+
+const getTotalComment = `-- name: GetTotalComment :one
+SELECT COUNT(*)
+FROM "comment"
+WHERE comment_id != 0
+%s
+`
+
+// GetList returns a list of comments whatever their status.
+// @param ctx context.Context
+// @param arg *model.GetListCommentParams
+// @return []model.Comment
+// @return total comment
+// @return error
+func (repo *Repository) GetList(
+	ctx context.Context,
+	arg *model.GetListCommentParams,
+) ([]model.Comment, int64, error) {
+	offset := arg.PageSize * (arg.CurrentPage - 1)
+
+	// Build dynamic filter conditions
+	filterConditions, filterArgs := arg.BuildFilterConditions(arg.Filter)
+
+	// Prepare the main query with dynamic filters
+	query := fmt.Sprintf(
+		getListComment,
+		filterConditions,
+		arg.OrderBy,
+		arg.OrderDirection,
+		arg.PageSize,
+		offset,
+	)
+
+	// Execute the main query
+	rows, err := repo.connPool.Query(ctx, query, filterArgs...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	// Process the results
+	var items []model.Comment
+	for rows.Next() {
+		i, err := scanComment(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	// Build and execute the total count query
+	totalQuery := fmt.Sprintf(getTotalComment, filterConditions)
+	totalRow := repo.connPool.QueryRow(ctx, totalQuery, filterArgs...)
+
+	var count int64
+	err = totalRow.Scan(&count)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, count, nil
+}
+
+const updateCommentStatus = `-- name: UpdateCommentStatus :many
+UPDATE "comment"
+SET status = $2
+WHERE comment_id = ANY($1::bigint[])
+RETURNING comment_id, post_id, user_id, parent_id, comment, status, created_at, updated_at
+`
+
+// UpdateStatus
+// Sets the status of every given comment and returns the comments found.
+// @param ctx context.Context
+// @param commentIDs []int64
+// @param status model.CommentStatus
+// @return []model.Comment
+func (repo *Repository) UpdateStatus(
+	ctx context.Context,
+	commentIDs []int64,
+	status model.CommentStatus,
+) ([]model.Comment, error) {
+	rows, err := repo.connPool.Query(ctx, updateCommentStatus, commentIDs, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []model.Comment
+	for rows.Next() {
+		i, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
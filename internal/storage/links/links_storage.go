@@ -0,0 +1,144 @@
+package links
+
+import (
+	"context"
+
+	categoryModel "github.com/daniel-vuky/go-blog/internal/models/category"
+	"github.com/daniel-vuky/go-blog/internal/storage"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository
+// Wraps the Queries struct from the storage package and a connection pool.
+type Repository struct {
+	*storage.Queries
+	connPool *pgxpool.Pool
+}
+
+// NewLinksRepository
+// Returns a new instance of Repository.
+// @param connPool *pgxpool.Pool
+// @return *Repository
+func NewLinksRepository(connPool *pgxpool.Pool) *Repository {
+	return &Repository{
+		Queries:  storage.New(connPool),
+		connPool: connPool,
+	}
+}
+
+const getPostCategories = `-- name: GetPostCategories :many
+SELECT category.category_id, category.parent_id, category.name, category.url_key, category.short_description, category.description, category.created_at, category.updated_at
+FROM "category"
+JOIN "post_links" ON post_links.category_id = category.category_id
+WHERE post_links.post_id = $1
+ORDER BY category.name, category.category_id
+`
+
+// GetPostCategories
+// Returns the categories a post is assigned to.
+// @param ctx context.Context
+// @param postID int64
+// @return []categoryModel.Category
+func (repo *Repository) GetPostCategories(
+	ctx context.Context,
+	postID int64,
+) ([]categoryModel.Category, error) {
+	rows, err := repo.connPool.Query(ctx, getPostCategories, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []categoryModel.Category
+	for rows.Next() {
+		var i categoryModel.Category
+		if err := rows.Scan(
+			&i.CategoryID,
+			&i.ParentID,
+			&i.Name,
+			&i.UrlKey,
+			&i.ShortDescription,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deletePostLinks = `-- name: DeletePostLinks :exec
+DELETE FROM "post_links"
+WHERE post_id = $1
+`
+
+const addPostLinks = `-- name: AddPostLinks :exec
+INSERT INTO "post_links" (post_id, category_id)
+SELECT $1, unnest($2::bigint[])
+ON CONFLICT (post_id, category_id) DO NOTHING
+`
+
+// SetPostCategories
+// Replaces every category of a post in one transaction.
+// @param ctx context.Context
+// @param postID int64
+// @param categoryIDs []int64
+// @return error
+func (repo *Repository) SetPostCategories(
+	ctx context.Context,
+	postID int64,
+	categoryIDs []int64,
+) error {
+	return pgx.BeginFunc(ctx, repo.connPool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, deletePostLinks, postID); err != nil {
+			return err
+		}
+		if len(categoryIDs) == 0 {
+			return nil
+		}
+		_, err := tx.Exec(ctx, addPostLinks, postID, categoryIDs)
+		return err
+	})
+}
+
+// AddPostCategories
+// Assigns a post to categories, keeping the categories it already has.
+// @param ctx context.Context
+// @param postID int64
+// @param categoryIDs []int64
+// @return error
+func (repo *Repository) AddPostCategories(
+	ctx context.Context,
+	postID int64,
+	categoryIDs []int64,
+) error {
+	_, err := repo.connPool.Exec(ctx, addPostLinks, postID, categoryIDs)
+	return err
+}
+
+const removePostLinks = `-- name: RemovePostLinks :exec
+DELETE FROM "post_links"
+WHERE post_id = $1
+  AND category_id = ANY($2::bigint[])
+`
+
+// RemovePostCategories
+// Removes a post from categories.
+// @param ctx context.Context
+// @param postID int64
+// @param categoryIDs []int64
+// @return error
+func (repo *Repository) RemovePostCategories(
+	ctx context.Context,
+	postID int64,
+	categoryIDs []int64,
+) error {
+	_, err := repo.connPool.Exec(ctx, removePostLinks, postID, categoryIDs)
+	return err
+}
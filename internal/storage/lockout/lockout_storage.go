@@ -0,0 +1,228 @@
+package lockout
+
+import (
+	"context"
+	"time"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/admin"
+	"github.com/daniel-vuky/go-blog/internal/storage"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository
+// Wraps the Queries struct from the storage package and a connection pool.
+type Repository struct {
+	*storage.Queries
+	connPool *pgxpool.Pool
+}
+
+// NewLockoutRepository
+// Returns a new instance of Repository.
+// @param connPool *pgxpool.Pool
+// @return *Repository
+func NewLockoutRepository(connPool *pgxpool.Pool) *Repository {
+	return &Repository{
+		Queries:  storage.New(connPool),
+		connPool: connPool,
+	}
+}
+
+const incrementAdminFailedLogin = `-- name: IncrementAdminFailedLogin :one
+UPDATE admin
+SET failed_login_count = failed_login_count + 1
+WHERE email = $1
+  AND (lock_expires IS NULL OR lock_expires <= NOW())
+RETURNING admin_id, failed_login_count, lock_count
+`
+
+// IncrementAdminFailedLogin
+// Counts a failed login of an admin which is not currently locked.
+// @param ctx context.Context
+// @param email string
+// @return model.LoginFailureCounter
+func (repo *Repository) IncrementAdminFailedLogin(
+	ctx context.Context,
+	email string,
+) (model.LoginFailureCounter, error) {
+	row := repo.connPool.QueryRow(ctx, incrementAdminFailedLogin, email)
+	var i model.LoginFailureCounter
+	err := row.Scan(
+		&i.AdminID,
+		&i.FailedLoginCount,
+		&i.LockCount,
+	)
+	return i, err
+}
+
+const lockAdmin = `-- name: LockAdmin :exec
+UPDATE admin
+SET lock_expires = $2,
+    lock_count = lock_count + 1,
+    failed_login_count = 0
+WHERE admin_id = $1
+`
+
+// LockAdmin
+// Locks an admin until the given time.
+// @param ctx context.Context
+// @param adminID int32
+// @param lockExpires time.Time
+// @return error
+func (repo *Repository) LockAdmin(
+	ctx context.Context,
+	adminID int32,
+	lockExpires time.Time,
+) error {
+	_, err := repo.connPool.Exec(ctx, lockAdmin, adminID, lockExpires)
+	return err
+}
+
+const resetAdminFailedLogin = `-- name: ResetAdminFailedLogin :exec
+UPDATE admin
+SET failed_login_count = 0,
+    lock_count = 0
+WHERE admin_id = $1
+`
+
+// ResetAdminFailedLogin
+// Clears the failed login counters of an admin.
+// @param ctx context.Context
+// @param adminID int32
+// @return error
+func (repo *Repository) ResetAdminFailedLogin(
+	ctx context.Context,
+	adminID int32,
+) error {
+	_, err := repo.connPool.Exec(ctx, resetAdminFailedLogin, adminID)
+	return err
+}
+
+const unlockAdmin = `-- name: UnlockAdmin :one
+UPDATE admin
+SET lock_expires = NULL,
+    failed_login_count = 0,
+    lock_count = 0
+WHERE email = $1
+RETURNING admin_id, role_id, email, hashed_password, firstname, lastname, active, lock_expires, password_changed_at, created_at
+`
+
+// UnlockAdmin
+// Removes the lock and failed login counters of an admin.
+// @param ctx context.Context
+// @param email string
+// @return model.Admin
+func (repo *Repository) UnlockAdmin(
+	ctx context.Context,
+	email string,
+) (model.Admin, error) {
+	row := repo.connPool.QueryRow(ctx, unlockAdmin, email)
+	var i model.Admin
+	err := row.Scan(
+		&i.AdminID,
+		&i.RoleID,
+		&i.Email,
+		&i.HashedPassword,
+		&i.Firstname,
+		&i.Lastname,
+		&i.Active,
+		&i.LockExpires,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getClientIpLockExpires = `-- name: GetClientIpLockExpires :one
+SELECT lock_expires
+FROM login_ip_failures
+WHERE client_ip = $1
+`
+
+// GetClientIpLockExpires
+// Returns the lock expiry of a client ip.
+// @param ctx context.Context
+// @param clientIp string
+// @return pgtype.Timestamptz
+func (repo *Repository) GetClientIpLockExpires(
+	ctx context.Context,
+	clientIp string,
+) (pgtype.Timestamptz, error) {
+	row := repo.connPool.QueryRow(ctx, getClientIpLockExpires, clientIp)
+	var lockExpires pgtype.Timestamptz
+	err := row.Scan(&lockExpires)
+	return lockExpires, err
+}
+
+const incrementClientIpFailedLogin = `-- name: IncrementClientIpFailedLogin :one
+INSERT INTO login_ip_failures (client_ip, failed_count, updated_at)
+VALUES ($1, 1, NOW())
+ON CONFLICT (client_ip) DO UPDATE
+SET failed_count = CASE
+        WHEN login_ip_failures.updated_at < NOW() - ($2::bigint * INTERVAL '1 second') THEN 1
+        ELSE login_ip_failures.failed_count + 1
+    END,
+    updated_at = NOW()
+RETURNING failed_count, lock_count
+`
+
+// IncrementClientIpFailedLogin
+// Counts a failed login from a client ip, restarting the count once the window elapsed.
+// @param ctx context.Context
+// @param clientIp string
+// @param window time.Duration
+// @return model.ClientIpFailureCounter
+func (repo *Repository) IncrementClientIpFailedLogin(
+	ctx context.Context,
+	clientIp string,
+	window time.Duration,
+) (model.ClientIpFailureCounter, error) {
+	row := repo.connPool.QueryRow(ctx, incrementClientIpFailedLogin, clientIp, int64(window.Seconds()))
+	var i model.ClientIpFailureCounter
+	err := row.Scan(
+		&i.FailedCount,
+		&i.LockCount,
+	)
+	return i, err
+}
+
+const lockClientIp = `-- name: LockClientIp :exec
+UPDATE login_ip_failures
+SET lock_expires = $2,
+    lock_count = lock_count + 1,
+    failed_count = 0
+WHERE client_ip = $1
+`
+
+// LockClientIp
+// Locks a client ip until the given time.
+// @param ctx context.Context
+// @param clientIp string
+// @param lockExpires time.Time
+// @return error
+func (repo *Repository) LockClientIp(
+	ctx context.Context,
+	clientIp string,
+	lockExpires time.Time,
+) error {
+	_, err := repo.connPool.Exec(ctx, lockClientIp, clientIp, lockExpires)
+	return err
+}
+
+const resetClientIpFailedLogin = `-- name: ResetClientIpFailedLogin :exec
+DELETE FROM login_ip_failures
+WHERE client_ip = $1
+`
+
+// ResetClientIpFailedLogin
+// Clears the failed login counters of a client ip.
+// @param ctx context.Context
+// @param clientIp string
+// @return error
+func (repo *Repository) ResetClientIpFailedLogin(
+	ctx context.Context,
+	clientIp string,
+) error {
+	_, err := repo.connPool.Exec(ctx, resetClientIpFailedLogin, clientIp)
+	return err
+}
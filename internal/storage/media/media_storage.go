@@ -0,0 +1,265 @@
+package media
+
+import (
+	"context"
+	"fmt"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/media"
+	"github.com/daniel-vuky/go-blog/internal/storage"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository
+// Wraps the Queries struct from the storage package and a connection pool.
+type Repository struct {
+	*storage.Queries
+	connPool *pgxpool.Pool
+}
+
+// NewMediaRepository
+// Returns a new instance of Repository.
+// @param connPool *pgxpool.Pool
+// @return *Repository
+func NewMediaRepository(connPool *pgxpool.Pool) *Repository {
+	return &Repository{
+		Queries:  storage.New(connPool),
+		connPool: connPool,
+	}
+}
+
+// mediaColumns
+// Columns of a media row followed by the number of posts using it
+const mediaColumns = `media_id, file_name, storage_key, content_hash, mime_type, size, admin_id,
+       (SELECT COUNT(*) FROM "post_media" WHERE post_media.media_id = media.media_id) AS usage_count,
+       created_at`
+
+// scanMedia
+// Scans a full media row.
+// @param row pgx.Row
+// @return model.Media, error
+func scanMedia(row pgx.Row) (model.Media, error) {
+	var i model.Media
+	err := row.Scan(
+		&i.MediaID,
+		&i.FileName,
+		&i.StorageKey,
+		&i.ContentHash,
+		&i.MimeType,
+		&i.Size,
+		&i.AdminID,
+		&i.UsageCount,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getMedia = `-- name: GetMedia :one
+SELECT ` + mediaColumns + `
+FROM "media"
+WHERE media_id = $1
+`
+
+// Get
+// Returns a media file by id.
+// @param ctx context.Context
+// @param mediaID int64
+// @return model.Media
+func (repo *Repository) Get(
+	ctx context.Context,
+	mediaID int64,
+) (model.Media, error) {
+	return scanMedia(repo.connPool.QueryRow(ctx, getMedia, mediaID))
+}
+
+const getMediaByHash = `-- name: GetMediaByHash :one
+SELECT ` + mediaColumns + `
+FROM "media"
+WHERE content_hash = $1
+`
+
+// GetByHash
+// Returns the media file having a content hash.
+// @param ctx context.Context
+// @param contentHash string
+// @return model.Media
+func (repo *Repository) GetByHash(
+	ctx context.Context,
+	contentHash string,
+) (model.Media, error) {
+	return scanMedia(repo.connPool.QueryRow(ctx, getMediaByHash, contentHash))
+}
+
+const getListMedia = `-- name: GetListMedia :many
+SELECT ` + mediaColumns + `
+FROM "media"
+WHERE media_id != 0
+%s
+ORDER BY %s %s
+LIMIT %d OFFSET %d
+`
+
+const getTotalMedia = `-- name: GetTotalMedia :one
+SELECT COUNT(*)
+FROM "media"
+WHERE media_id != 0
+%s
+`
+
+// GetList returns a list of media files.
+// @param ctx context.Context
+// @param arg *model.GetListMediaParams
+// @return []model.Media
+// @return total media
+// @return error
+func (repo *Repository) GetList(
+	ctx context.Context,
+	arg *model.GetListMediaParams,
+) ([]model.Media, int64, error) {
+	offset := arg.PageSize * (arg.CurrentPage - 1)
+
+	// Build dynamic filter conditions
+	filterConditions, filterArgs := arg.BuildFilterConditions(arg.Filter)
+
+	// Prepare the main query with dynamic filters
+	query := fmt.Sprintf(
+		getListMedia,
+		filterConditions,
+		arg.OrderBy,
+		arg.OrderDirection,
+		arg.PageSize,
+		offset,
+	)
+
+	// Execute the main query
+	rows, err := repo.connPool.Query(ctx, query, filterArgs...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	var items []model.Media
+	for rows.Next() {
+		i, err := scanMedia(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	// Build and execute the total count query
+	totalQuery := fmt.Sprintf(getTotalMedia, filterConditions)
+	totalRow := repo.connPool.QueryRow(ctx, totalQuery, filterArgs...)
+
+	var count int64
+	err = totalRow.Scan(&count)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, count, nil
+}
+
+const getListMediaPost = `-- name: GetListMediaPost :many
+SELECT post.post_id, post.name
+FROM "post_media"
+JOIN "post" ON post.post_id = post_media.post_id
+WHERE post_media.media_id = $1
+ORDER BY post.post_id
+`
+
+// GetListPost
+// Returns the posts using a media file.
+// @param ctx context.Context
+// @param mediaID int64
+// @return []model.MediaPost
+func (repo *Repository) GetListPost(
+	ctx context.Context,
+	mediaID int64,
+) ([]model.MediaPost, error) {
+	rows, err := repo.connPool.Query(ctx, getListMediaPost, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []model.MediaPost
+	for rows.Next() {
+		var i model.MediaPost
+		if err := rows.Scan(&i.PostID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createMedia = `-- name: CreateMedia :one
+INSERT INTO "media" (file_name, storage_key, content_hash, mime_type, size, admin_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING media_id
+`
+
+const linkMediaPosts = `-- name: LinkMediaPosts :exec
+INSERT INTO "post_media" (post_id, media_id)
+SELECT post_id, $1
+FROM "post_media_key"
+WHERE storage_key = $2
+`
+
+// Create
+// Records an uploaded file, the posts already using its key are linked to it.
+// @param ctx context.Context
+// @param arg *model.CreateMediaParams
+// @return model.Media
+func (repo *Repository) Create(
+	ctx context.Context,
+	arg *model.CreateMediaParams,
+) (model.Media, error) {
+	var i model.Media
+	err := pgx.BeginFunc(ctx, repo.connPool, func(tx pgx.Tx) error {
+		var mediaID int64
+		err := tx.QueryRow(
+			ctx,
+			createMedia,
+			arg.FileName,
+			arg.StorageKey,
+			arg.ContentHash,
+			arg.MimeType,
+			arg.Size,
+			arg.AdminID,
+		).Scan(&mediaID)
+		if err != nil {
+			return err
+		}
+		if _, err = tx.Exec(ctx, linkMediaPosts, mediaID, arg.StorageKey); err != nil {
+			return err
+		}
+		// Read back once the posts are linked, to count them
+		i, err = scanMedia(tx.QueryRow(ctx, getMedia, mediaID))
+		return err
+	})
+	return i, err
+}
+
+const deleteMedia = `-- name: DeleteMedia :one
+DELETE FROM "media"
+WHERE media_id = $1
+RETURNING media_id, file_name, storage_key, content_hash, mime_type, size, admin_id, 0::bigint AS usage_count, created_at
+`
+
+// Delete
+// Deletes a media file, refused by the database while a post uses it.
+// @param ctx context.Context
+// @param mediaID int64
+// @return model.Media
+func (repo *Repository) Delete(
+	ctx context.Context,
+	mediaID int64,
+) (model.Media, error) {
+	return scanMedia(repo.connPool.QueryRow(ctx, deleteMedia, mediaID))
+}
@@ -0,0 +1,126 @@
+package media
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/media"
+	postModel "github.com/daniel-vuky/go-blog/internal/models/post"
+	postStorage "github.com/daniel-vuky/go-blog/internal/storage/post"
+	"github.com/daniel-vuky/go-blog/pkg/config"
+	goRandom "github.com/daniel-vuky/go-random"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+var repository *Repository
+var postRepository *postStorage.Repository
+
+// TestMain
+// Initializes the repositories and closes the connection pool after all tests have run.
+func TestMain(m *testing.M) {
+	loadedConfig, err := config.LoadConfig("../../../")
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+	connPool, err := loadedConfig.ConnectToPgxPool()
+	if err != nil {
+		log.Fatalf("failed to create connection pool: %v", err)
+	}
+	repository = NewMediaRepository(connPool)
+	postRepository = postStorage.NewPostRepository(connPool)
+	code := m.Run()
+	repository.connPool.Close()
+	os.Exit(code)
+}
+
+// randomMediaParams
+// Returns the params of a png file with a random content hash.
+func randomMediaParams() *model.CreateMediaParams {
+	sum := sha256.Sum256([]byte(goRandom.RandomString(32)))
+	contentHash := hex.EncodeToString(sum[:])
+	return &model.CreateMediaParams{
+		FileName:    "cat.png",
+		StorageKey:  contentHash[:2] + "/" + contentHash + ".png",
+		ContentHash: contentHash,
+		MimeType:    "image/png",
+		Size:        64,
+	}
+}
+
+// createPost
+// Creates a draft post with the given thumbnail and content.
+func createPost(t *testing.T, thumbnail string, content string) postModel.Post {
+	createdPost, err := postRepository.Create(context.Background(), &postModel.CreatePostParams{
+		Name:        goRandom.RandomString(12),
+		Content:     pgtype.Text{String: content, Valid: content != ""},
+		UrlKey:      pgtype.Text{String: strings.ToLower(goRandom.RandomString(16)), Valid: true},
+		Thumbnail:   pgtype.Text{String: thumbnail, Valid: thumbnail != ""},
+		CommentMode: postModel.CommentModeOpen,
+	})
+	require.NoError(t, err)
+	return createdPost
+}
+
+// listPostIDs
+// Returns the ids of the posts using a media file.
+func listPostIDs(t *testing.T, mediaID int64) []int64 {
+	posts, err := repository.GetListPost(context.Background(), mediaID)
+	require.NoError(t, err)
+	postIDs := make([]int64, 0, len(posts))
+	for _, post := range posts {
+		postIDs = append(postIDs, post.PostID)
+	}
+	return postIDs
+}
+
+// TestRepository_PostMedia
+// Tests the posts are linked to the files of their thumbnail and their content, and unlinked once they stop using them.
+func TestRepository_PostMedia(t *testing.T) {
+	ctx := context.Background()
+	arg := randomMediaParams()
+	file, err := repository.Create(ctx, arg)
+	require.NoError(t, err)
+	require.Zero(t, file.UsageCount)
+
+	inThumbnail := createPost(t, "/media/"+arg.StorageKey, "")
+	inContent := createPost(t, "", "![cat](/media/"+arg.StorageKey+")")
+	createPost(t, "", "no image")
+	require.ElementsMatch(t, []int64{inThumbnail.PostID, inContent.PostID}, listPostIDs(t, file.MediaID))
+
+	_, err = repository.Delete(ctx, file.MediaID)
+	require.Error(t, err)
+
+	_, err = postRepository.Update(ctx, &postModel.UpdatePostParams{
+		PostID:    inThumbnail.PostID,
+		Thumbnail: pgtype.Text{String: "", Valid: true},
+	})
+	require.NoError(t, err)
+	_, err = postRepository.Update(ctx, &postModel.UpdatePostParams{
+		PostID:  inContent.PostID,
+		Content: pgtype.Text{String: "the cat is gone", Valid: true},
+	})
+	require.NoError(t, err)
+	require.Empty(t, listPostIDs(t, file.MediaID))
+
+	deleted, err := repository.Delete(ctx, file.MediaID)
+	require.NoError(t, err)
+	require.Equal(t, file.MediaID, deleted.MediaID)
+}
+
+// TestRepository_Create_LinksPosts
+// Tests a file uploaded after the posts using it is linked to them.
+func TestRepository_Create_LinksPosts(t *testing.T) {
+	arg := randomMediaParams()
+	post := createPost(t, "", "![cat](/media/"+arg.StorageKey+")")
+
+	file, err := repository.Create(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), file.UsageCount)
+	require.Equal(t, []int64{post.PostID}, listPostIDs(t, file.MediaID))
+}
@@ -0,0 +1,540 @@
+package post
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	mediaModel "github.com/daniel-vuky/go-blog/internal/models/media"
+	model "github.com/daniel-vuky/go-blog/internal/models/post"
+	"github.com/daniel-vuky/go-blog/internal/storage"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository
+// Wraps the Queries struct from the storage package and a connection pool.
+type Repository struct {
+	*storage.Queries
+	connPool *pgxpool.Pool
+}
+
+// NewPostRepository
+// Returns a new instance of Repository.
+// @param connPool *pgxpool.Pool
+// @return *Repository
+func NewPostRepository(connPool *pgxpool.Pool) *Repository {
+	return &Repository{
+		Queries:  storage.New(connPool),
+		connPool: connPool,
+	}
+}
+
+// scanPost
+// Scans a full post row.
+// @param row pgx.Row
+// @return model.Post, error
+func scanPost(row pgx.Row) (model.Post, error) {
+	var i model.Post
+	err := row.Scan(
+		&i.PostID,
+		&i.Name,
+		&i.ShortDescription,
+		&i.Description,
+		&i.Content,
+		&i.UrlKey,
+		&i.Thumbnail,
+		&i.AuthorID,
+		&i.CommentMode,
+		&i.Status,
+		&i.PublishedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getPost = `-- name: GetPost :one
+SELECT post_id, name, short_description, description, content, url_key, thumbnail, author_id, comment_mode, status, published_at, created_at, updated_at
+FROM "post"
+WHERE post_id = $1
+`
+
+// Get
+// Returns a post by id.
+// @param ctx context.Context
+// @param postID int64
+// @return model.Post
+func (repo *Repository) Get(
+	ctx context.Context,
+	postID int64,
+) (model.Post, error) {
+	return scanPost(repo.connPool.QueryRow(ctx, getPost, postID))
+}
+
+const getListPost = `-- name: GetListPost :many
+SELECT post_id, name, short_description, description, content, url_key, thumbnail, author_id, comment_mode, status, published_at, created_at, updated_at
+FROM "post"
+WHERE post_id != 0
+%s
+ORDER BY %s %s
+LIMIT %d OFFSET %d
+`
+
+const getTotalPost = `-- name: GetTotalPost :one
+SELECT COUNT(*)
+FROM "post"
+WHERE post_id != 0
+%s
+`
+
+// GetList returns a list of posts.
+// @param ctx context.Context
+// @param arg *model.GetListPostParams
+// @return []model.Post
+// @return total post
+// @return error
+func (repo *Repository) GetList(
+	ctx context.Context,
+	arg *model.GetListPostParams,
+) ([]model.Post, int64, error) {
+	offset := arg.PageSize * (arg.CurrentPage - 1)
+
+	// Build dynamic filter conditions
+	filterConditions, filterArgs := arg.BuildFilterConditions(arg.Filter)
+
+	// Prepare the main query with dynamic filters
+	query := fmt.Sprintf(
+		getListPost,
+		filterConditions,
+		arg.OrderBy,
+		arg.OrderDirection,
+		arg.PageSize,
+		offset,
+	)
+
+	// Execute the main query
+	rows, err := repo.connPool.Query(ctx, query, filterArgs...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	// Process the results
+	var items []model.Post
+	for rows.Next() {
+		i, err := scanPost(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	// Build and execute the total count query
+	totalQuery := fmt.Sprintf(getTotalPost, filterConditions)
+	totalRow := repo.connPool.QueryRow(ctx, totalQuery, filterArgs...)
+
+	var count int64
+	err = totalRow.Scan(&count)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, count, nil
+}
+
+const createPost = `-- name: CreatePost :one
+INSERT INTO "post"
+    (
+        name,
+        short_description,
+        description,
+        content,
+        url_key,
+        thumbnail,
+        author_id,
+        comment_mode
+    )
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING post_id, name, short_description, description, content, url_key, thumbnail, author_id, comment_mode, status, published_at, created_at, updated_at
+`
+
+// Create
+// Creates a new post with its first revision and links it to the media files it uses.
+// @param ctx context.Context
+// @param arg *model.CreatePostParams
+// @return model.Post
+func (repo *Repository) Create(
+	ctx context.Context,
+	arg *model.CreatePostParams,
+) (model.Post, error) {
+	var i model.Post
+	err := pgx.BeginFunc(ctx, repo.connPool, func(tx pgx.Tx) error {
+		var err error
+		i, err = scanPost(tx.QueryRow(ctx, createPost,
+			arg.Name,
+			arg.ShortDescription,
+			arg.Description,
+			arg.Content,
+			arg.UrlKey,
+			arg.Thumbnail,
+			arg.AuthorID,
+			arg.CommentMode,
+		))
+		if err != nil {
+			return err
+		}
+		if err = syncPostMedia(ctx, tx, &i); err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, createPostRevision, i.PostID, arg.AuthorID, nil)
+		return err
+	})
+	return i, err
+}
+
+const deletePostMediaKey = `-- name: DeletePostMediaKey :exec
+DELETE FROM "post_media_key"
+WHERE post_id = $1
+`
+
+const createPostMediaKey = `-- name: CreatePostMediaKey :exec
+INSERT INTO "post_media_key" (post_id, storage_key)
+SELECT $1, UNNEST($2::varchar[])
+`
+
+const deletePostMedia = `-- name: DeletePostMedia :exec
+DELETE FROM "post_media"
+WHERE post_id = $1
+`
+
+const createPostMedia = `-- name: CreatePostMedia :exec
+INSERT INTO "post_media" (post_id, media_id)
+SELECT $1, media_id
+FROM "media"
+WHERE storage_key = ANY($2::varchar[])
+`
+
+// syncPostMedia
+// Replaces the media keys of a post with the ones of its thumbnail and its content,
+// and links it to the media files of these keys.
+// @param ctx context.Context
+// @param tx pgx.Tx
+// @param post *model.Post
+// @return error
+func syncPostMedia(ctx context.Context, tx pgx.Tx, post *model.Post) error {
+	storageKeys := mediaModel.ExtractStorageKeys(post.Thumbnail.String, post.Content.String)
+	for _, query := range []string{deletePostMediaKey, deletePostMedia} {
+		if _, err := tx.Exec(ctx, query, post.PostID); err != nil {
+			return err
+		}
+	}
+	if len(storageKeys) == 0 {
+		return nil
+	}
+	for _, query := range []string{createPostMediaKey, createPostMedia} {
+		if _, err := tx.Exec(ctx, query, post.PostID, storageKeys); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const updatePost = `-- name: UpdatePost :one
+UPDATE "post"
+SET name = COALESCE($2, name),
+    short_description = COALESCE($3, short_description),
+    description = COALESCE($4, description),
+    content = COALESCE($5, content),
+    url_key = COALESCE($6, url_key),
+    thumbnail = COALESCE($7, thumbnail),
+    comment_mode = COALESCE($8, comment_mode)
+WHERE post_id = $1
+RETURNING post_id, name, short_description, description, content, url_key, thumbnail, author_id, comment_mode, status, published_at, created_at, updated_at
+`
+
+// Update
+// Updates a post, updated_at is maintained by the post_updated_at_trigger.
+// A revision is recorded when the name, the descriptions or the content changed,
+// and the media links follow the thumbnail and the content.
+// @param ctx context.Context
+// @param arg *model.UpdatePostParams
+// @return model.Post
+func (repo *Repository) Update(
+	ctx context.Context,
+	arg *model.UpdatePostParams,
+) (model.Post, error) {
+	var i model.Post
+	err := pgx.BeginFunc(ctx, repo.connPool, func(tx pgx.Tx) error {
+		var err error
+		i, err = scanPost(tx.QueryRow(ctx, updatePost,
+			arg.PostID,
+			arg.Name,
+			arg.ShortDescription,
+			arg.Description,
+			arg.Content,
+			arg.UrlKey,
+			arg.Thumbnail,
+			arg.CommentMode,
+		))
+		if err != nil {
+			return err
+		}
+		if err = syncPostMedia(ctx, tx, &i); err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, createChangedPostRevision, i.PostID, arg.EditorID)
+		return err
+	})
+	return i, err
+}
+
+const deletePost = `-- name: DeletePost :one
+DELETE FROM "post"
+WHERE post_id = $1
+RETURNING post_id, name, short_description, description, content, url_key, thumbnail, author_id, comment_mode, status, published_at, created_at, updated_at
+`
+
+// Delete
+// Deletes a post.
+// @param ctx context.Context
+// @param postID int64
+// @return model.Post
+func (repo *Repository) Delete(
+	ctx context.Context,
+	postID int64,
+) (model.Post, error) {
+	return scanPost(repo.connPool.QueryRow(ctx, deletePost, postID))
+}
+
+const updatePostStatus = `-- name: UpdatePostStatus :one
+UPDATE "post"
+SET status = $2,
+    published_at = $3
+WHERE post_id = $1
+RETURNING post_id, name, short_description, description, content, url_key, thumbnail, author_id, comment_mode, status, published_at, created_at, updated_at
+`
+
+// UpdateStatus
+// Moves a post in the publishing workflow.
+// @param ctx context.Context
+// @param arg *model.UpdatePostStatusParams
+// @return model.Post
+func (repo *Repository) UpdateStatus(
+	ctx context.Context,
+	arg *model.UpdatePostStatusParams,
+) (model.Post, error) {
+	return scanPost(repo.connPool.QueryRow(ctx, updatePostStatus, arg.PostID, arg.Status, arg.PublishedAt))
+}
+
+const publishScheduledPost = `-- name: PublishScheduledPost :many
+UPDATE "post"
+SET status = 'published'
+WHERE status = 'scheduled'
+  AND published_at <= $1
+RETURNING post_id, name, short_description, description, content, url_key, thumbnail, author_id, comment_mode, status, published_at, created_at, updated_at
+`
+
+// PublishScheduled
+// Publishes the scheduled posts whose publication time is reached.
+// @param ctx context.Context
+// @param now time.Time
+// @return []model.Post the published posts
+func (repo *Repository) PublishScheduled(
+	ctx context.Context,
+	now time.Time,
+) ([]model.Post, error) {
+	rows, err := repo.connPool.Query(ctx, publishScheduledPost, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.Post
+	for rows.Next() {
+		i, err := scanPost(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// revisionColumns
+// Columns selected by the revision queries, the name of the admin is read from the admin table
+const revisionColumns = `post_revision.revision_id, post_revision.post_id, post_revision.name,
+       post_revision.short_description, post_revision.description, post_revision.content, post_revision.admin_id,
+       COALESCE(TRIM(CONCAT_WS(' ', admin.firstname, admin.lastname)), '') AS admin_name,
+       post_revision.restored_from, post_revision.created_at`
+
+// scanRevision
+// Scans a revision row selected with revisionColumns.
+// @param row pgx.Row
+// @return model.PostRevision, error
+func scanRevision(row pgx.Row) (model.PostRevision, error) {
+	var i model.PostRevision
+	err := row.Scan(
+		&i.RevisionID,
+		&i.PostID,
+		&i.Name,
+		&i.ShortDescription,
+		&i.Description,
+		&i.Content,
+		&i.AdminID,
+		&i.AdminName,
+		&i.RestoredFrom,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createPostRevision = `-- name: CreatePostRevision :exec
+INSERT INTO "post_revision" (post_id, name, short_description, description, content, admin_id, restored_from)
+SELECT post_id, name, short_description, description, content, NULLIF($2::bigint, 0), $3
+FROM "post"
+WHERE post_id = $1
+`
+
+const createChangedPostRevision = `-- name: CreateChangedPostRevision :exec
+INSERT INTO "post_revision" (post_id, name, short_description, description, content, admin_id)
+SELECT post.post_id, post.name, post.short_description, post.description, post.content, NULLIF($2::bigint, 0)
+FROM "post"
+LEFT JOIN LATERAL (
+    SELECT name, short_description, description, content
+    FROM "post_revision"
+    WHERE post_revision.post_id = post.post_id
+    ORDER BY revision_id DESC
+    LIMIT 1
+) latest ON true
+WHERE post.post_id = $1
+  AND (latest.name IS NULL
+    OR latest.name IS DISTINCT FROM post.name
+    OR latest.short_description IS DISTINCT FROM post.short_description
+    OR latest.description IS DISTINCT FROM post.description
+    OR latest.content IS DISTINCT FROM post.content)
+`
+
+const getPostRevision = `-- name: GetPostRevision :one
+SELECT ` + revisionColumns + `
+FROM "post_revision"
+LEFT JOIN "admin" ON admin.admin_id = post_revision.admin_id
+WHERE post_revision.post_id = $1
+  AND post_revision.revision_id = $2
+`
+
+// GetRevision
+// Returns a revision of a post.
+// @param ctx context.Context
+// @param postID int64
+// @param revisionID int64
+// @return model.PostRevision
+func (repo *Repository) GetRevision(
+	ctx context.Context,
+	postID int64,
+	revisionID int64,
+) (model.PostRevision, error) {
+	return scanRevision(repo.connPool.QueryRow(ctx, getPostRevision, postID, revisionID))
+}
+
+const getListPostRevision = `-- name: GetListPostRevision :many
+SELECT ` + revisionColumns + `
+FROM "post_revision"
+LEFT JOIN "admin" ON admin.admin_id = post_revision.admin_id
+WHERE post_revision.post_id = $1
+ORDER BY post_revision.revision_id DESC
+LIMIT $2 OFFSET $3
+`
+
+const getTotalPostRevision = `-- name: GetTotalPostRevision :one
+SELECT COUNT(*)
+FROM "post_revision"
+WHERE post_id = $1
+`
+
+// GetListRevision
+// Returns the revisions of a post, newest first.
+// @param ctx context.Context
+// @param arg *model.GetListPostRevisionParams
+// @return []model.PostRevision
+// @return total revision
+// @return error
+func (repo *Repository) GetListRevision(
+	ctx context.Context,
+	arg *model.GetListPostRevisionParams,
+) ([]model.PostRevision, int64, error) {
+	offset := arg.PageSize * (arg.CurrentPage - 1)
+	rows, err := repo.connPool.Query(ctx, getListPostRevision, arg.PostID, arg.PageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var items []model.PostRevision
+	for rows.Next() {
+		i, err := scanRevision(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var count int64
+	if err = repo.connPool.QueryRow(ctx, getTotalPostRevision, arg.PostID).Scan(&count); err != nil {
+		return nil, 0, err
+	}
+
+	return items, count, nil
+}
+
+const restorePostRevision = `-- name: RestorePostRevision :one
+UPDATE "post"
+SET name = post_revision.name,
+    short_description = post_revision.short_description,
+    description = post_revision.description,
+    content = post_revision.content
+FROM "post_revision"
+WHERE post.post_id = $1
+  AND post_revision.post_id = post.post_id
+  AND post_revision.revision_id = $2
+RETURNING post.post_id, post.name, post.short_description, post.description, post.content, post.url_key, post.thumbnail,
+          post.author_id, post.comment_mode, post.status, post.published_at, post.created_at, post.updated_at
+`
+
+// RestoreRevision
+// Puts back the name, the descriptions and the content of a revision, recorded as a new revision.
+// @param ctx context.Context
+// @param postID int64
+// @param revisionID int64
+// @param editorID int64
+// @return model.Post
+func (repo *Repository) RestoreRevision(
+	ctx context.Context,
+	postID int64,
+	revisionID int64,
+	editorID int64,
+) (model.Post, error) {
+	var i model.Post
+	err := pgx.BeginFunc(ctx, repo.connPool, func(tx pgx.Tx) error {
+		var err error
+		i, err = scanPost(tx.QueryRow(ctx, restorePostRevision, postID, revisionID))
+		if err != nil {
+			return err
+		}
+		if err = syncPostMedia(ctx, tx, &i); err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, createPostRevision, i.PostID, editorID, revisionID)
+		return err
+	})
+	return i, err
+}
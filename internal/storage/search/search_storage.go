@@ -0,0 +1,161 @@
+package search
+
+import (
+	"context"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/search"
+	"github.com/daniel-vuky/go-blog/internal/storage"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// headlineOptions
+// Options of ts_headline, the matches are wrapped in the markers of the model to be escaped safely
+const headlineOptions = `StartSel="` + model.HighlightStart + `", StopSel="` + model.HighlightStop + `", ` +
+	`MaxFragments=2, MinWords=10, MaxWords=30, FragmentDelimiter=" ... "`
+
+// Repository
+// Wraps the Queries struct from the storage package and a connection pool.
+type Repository struct {
+	*storage.Queries
+	connPool *pgxpool.Pool
+}
+
+// NewSearchRepository
+// Returns a new instance of Repository.
+// @param connPool *pgxpool.Pool
+// @return *Repository
+func NewSearchRepository(connPool *pgxpool.Pool) *Repository {
+	return &Repository{
+		Queries:  storage.New(connPool),
+		connPool: connPool,
+	}
+}
+
+const searchPublishedPost = `-- name: SearchPublishedPost :many
+SELECT ranked.name, ranked.url_key, ranked.short_description, ranked.thumbnail, ranked.author_name,
+       ts_headline(
+         $1::regconfig,
+         CONCAT_WS(' ', ranked.short_description, ranked.description, ranked.content),
+         ranked.query,
+         $3
+       ) AS headline,
+       ranked.rank, ranked.published_at, ranked.updated_at
+FROM (
+  SELECT post.post_id, post.name, post.url_key, post.short_description, post.description, post.content, post.thumbnail,
+         COALESCE(TRIM(CONCAT_WS(' ', admin.firstname, admin.lastname)), '') AS author_name,
+         ts_rank_cd(post.search_vector, search.query) AS rank, search.query,
+         post.published_at, post.updated_at
+  FROM "post"
+  CROSS JOIN to_tsquery($1::regconfig, $2) AS search(query)
+  LEFT JOIN "admin" ON admin.admin_id = post.author_id
+  WHERE post.url_key IS NOT NULL
+    AND post.status = 'published'
+    AND post.search_vector @@ search.query
+  ORDER BY rank DESC, post.published_at DESC, post.post_id DESC
+  LIMIT $4 OFFSET $5
+) ranked
+ORDER BY ranked.rank DESC, ranked.published_at DESC, ranked.post_id DESC
+`
+
+const getTotalSearchPublishedPost = `-- name: GetTotalSearchPublishedPost :one
+SELECT COUNT(*)
+FROM "post"
+WHERE post.url_key IS NOT NULL
+  AND post.status = 'published'
+  AND post.search_vector @@ to_tsquery($1::regconfig, $2)
+`
+
+// SearchPost
+// Returns the published posts matching a text search query, best match first,
+// with a highlighted passage of their text.
+// @param ctx context.Context
+// @param arg *model.SearchPostParams
+// @return []model.Post
+// @return total post
+// @return error
+func (repo *Repository) SearchPost(
+	ctx context.Context,
+	arg *model.SearchPostParams,
+) ([]model.Post, int64, error) {
+	offset := arg.PageSize * (arg.CurrentPage - 1)
+	rows, err := repo.connPool.Query(
+		ctx,
+		searchPublishedPost,
+		arg.Language,
+		arg.Query,
+		headlineOptions,
+		arg.PageSize,
+		offset,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	var items []model.Post
+	for rows.Next() {
+		var i model.Post
+		if err := rows.Scan(
+			&i.Name,
+			&i.UrlKey,
+			&i.ShortDescription,
+			&i.Thumbnail,
+			&i.AuthorName,
+			&i.Headline,
+			&i.Rank,
+			&i.PublishedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var count int64
+	err = repo.connPool.QueryRow(ctx, getTotalSearchPublishedPost, arg.Language, arg.Query).Scan(&count)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, count, nil
+}
+
+const updateSearchLanguage = `-- name: UpdateSearchLanguage :execrows
+UPDATE "search_setting"
+SET language = $1::regconfig
+WHERE language <> $1::regconfig
+`
+
+const rebuildPostSearchVector = `-- name: RebuildPostSearchVector :exec
+UPDATE "post"
+SET search_vector = post_search_vector($1::regconfig, name, short_description, description, content)
+`
+
+// UpdateLanguage
+// Switches the text search configuration of the posts, rebuilding every post vector when it changed.
+// @param ctx context.Context
+// @param language string
+// @return bool whether the language changed
+// @return error
+func (repo *Repository) UpdateLanguage(
+	ctx context.Context,
+	language string,
+) (bool, error) {
+	var changed bool
+	err := pgx.BeginFunc(ctx, repo.connPool, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, updateSearchLanguage, language)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			return nil
+		}
+		changed = true
+		_, err = tx.Exec(ctx, rebuildPostSearchVector, language)
+		return err
+	})
+	return changed, err
+}
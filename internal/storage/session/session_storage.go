@@ -0,0 +1,223 @@
+package session
+
+import (
+	"context"
+	"errors"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/session"
+	"github.com/daniel-vuky/go-blog/internal/repository/session"
+	"github.com/daniel-vuky/go-blog/internal/storage"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository
+// Wraps the Queries struct from the storage package and a connection pool.
+type Repository struct {
+	*storage.Queries
+	connPool *pgxpool.Pool
+}
+
+// NewSessionRepository
+// Returns a new instance of Repository.
+// @param connPool *pgxpool.Pool
+// @return *Repository
+func NewSessionRepository(connPool *pgxpool.Pool) *Repository {
+	return &Repository{
+		Queries:  storage.New(connPool),
+		connPool: connPool,
+	}
+}
+
+// scanRefreshToken
+// Scans a full refresh_tokens row.
+// @param row pgx.Row
+// @return model.RefreshToken, error
+func scanRefreshToken(row pgx.Row) (model.RefreshToken, error) {
+	var i model.RefreshToken
+	err := row.Scan(
+		&i.RefreshTokenID,
+		&i.UserID,
+		&i.RefreshToken,
+		&i.UserAgent,
+		&i.ClientIp,
+		&i.IsBlocked,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+		&i.FamilyID,
+		&i.UsedAt,
+	)
+	return i, err
+}
+
+const getRefreshToken = `-- name: GetRefreshToken :one
+SELECT refresh_token_id, user_id, refresh_token, user_agent, client_ip, is_blocked, expired_at, created_at, family_id, used_at
+FROM refresh_tokens
+WHERE refresh_token = $1
+`
+
+// Get
+// Returns a refresh token by its hash.
+// @param ctx context.Context
+// @param hashedToken string
+// @return model.RefreshToken
+func (repo *Repository) Get(
+	ctx context.Context,
+	hashedToken string,
+) (model.RefreshToken, error) {
+	return scanRefreshToken(repo.connPool.QueryRow(ctx, getRefreshToken, hashedToken))
+}
+
+const getListActiveRefreshTokens = `-- name: GetListActiveRefreshTokens :many
+SELECT refresh_token_id, user_id, refresh_token, user_agent, client_ip, is_blocked, expired_at, created_at, family_id, used_at
+FROM refresh_tokens
+WHERE user_id = $1
+  AND is_blocked = false
+  AND used_at IS NULL
+  AND expired_at > NOW()
+ORDER BY created_at DESC
+`
+
+// GetListActive
+// Returns the usable refresh tokens of a user, one per signed in session.
+// @param ctx context.Context
+// @param userID int64
+// @return []model.RefreshToken
+func (repo *Repository) GetListActive(
+	ctx context.Context,
+	userID int64,
+) ([]model.RefreshToken, error) {
+	rows, err := repo.connPool.Query(ctx, getListActiveRefreshTokens, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []model.RefreshToken{}
+	for rows.Next() {
+		i, err := scanRefreshToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createRefreshToken = `-- name: CreateRefreshToken :one
+INSERT INTO refresh_tokens
+    (
+        user_id,
+        refresh_token,
+        user_agent,
+        client_ip,
+        expired_at,
+        family_id
+    )
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING refresh_token_id, user_id, refresh_token, user_agent, client_ip, is_blocked, expired_at, created_at, family_id, used_at
+`
+
+// Create
+// Stores a new refresh token.
+// @param ctx context.Context
+// @param arg *model.CreateRefreshTokenParams
+// @return model.RefreshToken
+func (repo *Repository) Create(
+	ctx context.Context,
+	arg *model.CreateRefreshTokenParams,
+) (model.RefreshToken, error) {
+	return scanRefreshToken(repo.connPool.QueryRow(ctx, createRefreshToken,
+		arg.UserID,
+		arg.RefreshToken,
+		arg.UserAgent,
+		arg.ClientIp,
+		arg.ExpiredAt,
+		arg.FamilyID,
+	))
+}
+
+const markRefreshTokenUsed = `-- name: MarkRefreshTokenUsed :one
+UPDATE refresh_tokens
+SET used_at = NOW()
+WHERE refresh_token_id = $1
+  AND used_at IS NULL
+  AND is_blocked = false
+RETURNING refresh_token_id
+`
+
+// Rotate
+// Marks a refresh token as used and stores its successor in the same transaction.
+// Returns session.ErrRefreshTokenUsed when the token was already used or blocked meanwhile.
+// @param ctx context.Context
+// @param refreshTokenID int64
+// @param arg *model.CreateRefreshTokenParams
+// @return model.RefreshToken
+func (repo *Repository) Rotate(
+	ctx context.Context,
+	refreshTokenID int64,
+	arg *model.CreateRefreshTokenParams,
+) (model.RefreshToken, error) {
+	var i model.RefreshToken
+	err := pgx.BeginFunc(ctx, repo.connPool, func(tx pgx.Tx) error {
+		var usedTokenID int64
+		if err := tx.QueryRow(ctx, markRefreshTokenUsed, refreshTokenID).Scan(&usedTokenID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return session.ErrRefreshTokenUsed
+			}
+			return err
+		}
+		var err error
+		i, err = scanRefreshToken(tx.QueryRow(ctx, createRefreshToken,
+			arg.UserID,
+			arg.RefreshToken,
+			arg.UserAgent,
+			arg.ClientIp,
+			arg.ExpiredAt,
+			arg.FamilyID,
+		))
+		return err
+	})
+	return i, err
+}
+
+const blockRefreshTokenFamily = `-- name: BlockRefreshTokenFamily :exec
+UPDATE refresh_tokens
+SET is_blocked = true
+WHERE family_id = $1
+`
+
+// BlockFamily
+// Blocks every refresh token descending from the same sign in.
+// @param ctx context.Context
+// @param familyID uuid.UUID
+// @return error
+func (repo *Repository) BlockFamily(
+	ctx context.Context,
+	familyID uuid.UUID,
+) error {
+	_, err := repo.connPool.Exec(ctx, blockRefreshTokenFamily, familyID)
+	return err
+}
+
+const blockUserRefreshTokens = `-- name: BlockUserRefreshTokens :exec
+UPDATE refresh_tokens
+SET is_blocked = true
+WHERE user_id = $1
+`
+
+// BlockUser
+// Blocks every refresh token of a user.
+// @param ctx context.Context
+// @param userID int64
+// @return error
+func (repo *Repository) BlockUser(
+	ctx context.Context,
+	userID int64,
+) error {
+	_, err := repo.connPool.Exec(ctx, blockUserRefreshTokens, userID)
+	return err
+}
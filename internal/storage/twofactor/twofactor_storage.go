@@ -0,0 +1,264 @@
+package twofactor
+
+import (
+	"context"
+	"errors"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/admin"
+	"github.com/daniel-vuky/go-blog/internal/repository/twofactor"
+	"github.com/daniel-vuky/go-blog/internal/storage"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository
+// Wraps the Queries struct from the storage package and a connection pool.
+type Repository struct {
+	*storage.Queries
+	connPool *pgxpool.Pool
+}
+
+// NewTwoFactorRepository
+// Returns a new instance of Repository.
+// @param connPool *pgxpool.Pool
+// @return *Repository
+func NewTwoFactorRepository(connPool *pgxpool.Pool) *Repository {
+	return &Repository{
+		Queries:  storage.New(connPool),
+		connPool: connPool,
+	}
+}
+
+// scanAdminTwoFactor
+// Scans a full admin_two_factor row.
+// @param row pgx.Row
+// @return model.AdminTwoFactor, error
+func scanAdminTwoFactor(row pgx.Row) (model.AdminTwoFactor, error) {
+	var i model.AdminTwoFactor
+	err := row.Scan(
+		&i.AdminID,
+		&i.EncryptedSecret,
+		&i.LastUsedStep,
+		&i.EnabledAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getAdminTwoFactor = `-- name: GetAdminTwoFactor :one
+SELECT admin_id, encrypted_secret, last_used_step, enabled_at, created_at
+FROM admin_two_factor
+WHERE admin_id = $1
+`
+
+// Get
+// Returns the two-factor settings of an admin.
+// @param ctx context.Context
+// @param adminID int32
+// @return model.AdminTwoFactor
+func (repo *Repository) Get(
+	ctx context.Context,
+	adminID int32,
+) (model.AdminTwoFactor, error) {
+	return scanAdminTwoFactor(repo.connPool.QueryRow(ctx, getAdminTwoFactor, adminID))
+}
+
+const countAdminRecoveryCodes = `-- name: CountAdminRecoveryCodes :one
+SELECT COUNT(*)
+FROM admin_recovery_codes
+WHERE admin_id = $1
+  AND used_at IS NULL
+`
+
+// CountRecoveryCodes
+// Returns the amount of unused recovery codes of an admin.
+// @param ctx context.Context
+// @param adminID int32
+// @return int64
+func (repo *Repository) CountRecoveryCodes(
+	ctx context.Context,
+	adminID int32,
+) (int64, error) {
+	var count int64
+	err := repo.connPool.QueryRow(ctx, countAdminRecoveryCodes, adminID).Scan(&count)
+	return count, err
+}
+
+const saveAdminTwoFactorSecret = `-- name: SaveAdminTwoFactorSecret :one
+INSERT INTO admin_two_factor (admin_id, encrypted_secret)
+VALUES ($1, $2)
+ON CONFLICT (admin_id) DO UPDATE
+SET encrypted_secret = EXCLUDED.encrypted_secret,
+    last_used_step = 0,
+    created_at = NOW()
+WHERE admin_two_factor.enabled_at IS NULL
+RETURNING admin_id, encrypted_secret, last_used_step, enabled_at, created_at
+`
+
+// SaveSecret
+// Stores the pending secret of an enrollment, replacing a previous pending one.
+// Returns twofactor.ErrTwoFactorAlreadyEnabled once the enrollment was completed.
+// @param ctx context.Context
+// @param adminID int32
+// @param encryptedSecret string
+// @return model.AdminTwoFactor
+func (repo *Repository) SaveSecret(
+	ctx context.Context,
+	adminID int32,
+	encryptedSecret string,
+) (model.AdminTwoFactor, error) {
+	i, err := scanAdminTwoFactor(repo.connPool.QueryRow(ctx, saveAdminTwoFactorSecret, adminID, encryptedSecret))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return i, twofactor.ErrTwoFactorAlreadyEnabled
+	}
+	return i, err
+}
+
+const enableAdminTwoFactor = `-- name: EnableAdminTwoFactor :exec
+UPDATE admin_two_factor
+SET enabled_at = NOW()
+WHERE admin_id = $1
+`
+
+const useAdminTwoFactorStep = `-- name: UseAdminTwoFactorStep :execrows
+UPDATE admin_two_factor
+SET last_used_step = $2
+WHERE admin_id = $1
+  AND last_used_step < $2
+`
+
+const deleteAdminRecoveryCodes = `-- name: DeleteAdminRecoveryCodes :exec
+DELETE FROM admin_recovery_codes
+WHERE admin_id = $1
+`
+
+const createAdminRecoveryCode = `-- name: CreateAdminRecoveryCode :exec
+INSERT INTO admin_recovery_codes (admin_id, code_hash)
+VALUES ($1, $2)
+`
+
+// Enable
+// Completes an enrollment and stores the recovery codes in the same transaction.
+// @param ctx context.Context
+// @param adminID int32
+// @param step int64
+// @param recoveryCodeHashes []string
+// @return error
+func (repo *Repository) Enable(
+	ctx context.Context,
+	adminID int32,
+	step int64,
+	recoveryCodeHashes []string,
+) error {
+	return pgx.BeginFunc(ctx, repo.connPool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, useAdminTwoFactorStep, adminID, step); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, enableAdminTwoFactor, adminID); err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(ctx, tx, adminID, recoveryCodeHashes)
+	})
+}
+
+// UseStep
+// Records the time step of an accepted code, rejecting steps which are not newer than the last one.
+// @param ctx context.Context
+// @param adminID int32
+// @param step int64
+// @return bool
+func (repo *Repository) UseStep(
+	ctx context.Context,
+	adminID int32,
+	step int64,
+) (bool, error) {
+	result, err := repo.connPool.Exec(ctx, useAdminTwoFactorStep, adminID, step)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+const useAdminRecoveryCode = `-- name: UseAdminRecoveryCode :execrows
+UPDATE admin_recovery_codes
+SET used_at = NOW()
+WHERE admin_id = $1
+  AND code_hash = $2
+  AND used_at IS NULL
+`
+
+// UseRecoveryCode
+// Marks an unused recovery code as used.
+// @param ctx context.Context
+// @param adminID int32
+// @param codeHash string
+// @return bool
+func (repo *Repository) UseRecoveryCode(
+	ctx context.Context,
+	adminID int32,
+	codeHash string,
+) (bool, error) {
+	result, err := repo.connPool.Exec(ctx, useAdminRecoveryCode, adminID, codeHash)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+// ReplaceRecoveryCodes
+// Replaces every recovery code of an admin.
+// @param ctx context.Context
+// @param adminID int32
+// @param recoveryCodeHashes []string
+// @return error
+func (repo *Repository) ReplaceRecoveryCodes(
+	ctx context.Context,
+	adminID int32,
+	recoveryCodeHashes []string,
+) error {
+	return pgx.BeginFunc(ctx, repo.connPool, func(tx pgx.Tx) error {
+		return replaceRecoveryCodes(ctx, tx, adminID, recoveryCodeHashes)
+	})
+}
+
+const deleteAdminTwoFactor = `-- name: DeleteAdminTwoFactor :exec
+DELETE FROM admin_two_factor
+WHERE admin_id = $1
+`
+
+// Delete
+// Removes the two-factor settings and the recovery codes of an admin.
+// @param ctx context.Context
+// @param adminID int32
+// @return error
+func (repo *Repository) Delete(
+	ctx context.Context,
+	adminID int32,
+) error {
+	return pgx.BeginFunc(ctx, repo.connPool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, deleteAdminRecoveryCodes, adminID); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, deleteAdminTwoFactor, adminID)
+		return err
+	})
+}
+
+// replaceRecoveryCodes
+// Deletes the recovery codes of an admin and inserts the new ones.
+// @param ctx context.Context
+// @param tx pgx.Tx
+// @param adminID int32
+// @param recoveryCodeHashes []string
+// @return error
+func replaceRecoveryCodes(ctx context.Context, tx pgx.Tx, adminID int32, recoveryCodeHashes []string) error {
+	if _, err := tx.Exec(ctx, deleteAdminRecoveryCodes, adminID); err != nil {
+		return err
+	}
+	for _, codeHash := range recoveryCodeHashes {
+		if _, err := tx.Exec(ctx, createAdminRecoveryCode, adminID, codeHash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package urlrewrite
+
+import (
+	"context"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/url_rewrite"
+	"github.com/daniel-vuky/go-blog/internal/storage"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository
+// Wraps the Queries struct from the storage package and a connection pool.
+// The rows are written by the url rewrite triggers of the post and category tables.
+type Repository struct {
+	*storage.Queries
+	connPool *pgxpool.Pool
+}
+
+// NewUrlRewriteRepository
+// Returns a new instance of Repository.
+// @param connPool *pgxpool.Pool
+// @return *Repository
+func NewUrlRewriteRepository(connPool *pgxpool.Pool) *Repository {
+	return &Repository{
+		Queries:  storage.New(connPool),
+		connPool: connPool,
+	}
+}
+
+// scanUrlRewrite
+// Scans a full url rewrite row.
+// @param row pgx.Row
+// @return model.UrlRewrite, error
+func scanUrlRewrite(row pgx.Row) (model.UrlRewrite, error) {
+	var i model.UrlRewrite
+	err := row.Scan(
+		&i.UrlRewriteID,
+		&i.EntityType,
+		&i.EntityID,
+		&i.UrlKey,
+		&i.CreatedAt,
+		&i.IsRedirect,
+	)
+	return i, err
+}
+
+const getUrlRewrite = `-- name: GetUrlRewrite :one
+SELECT url_rewrite_id, entity_type, entity_id, url_key, created_at, is_redirect
+FROM "url_rewrite"
+WHERE url_key = $1
+`
+
+// Get
+// Returns the url rewrite of a url key, current or redirected.
+// @param ctx context.Context
+// @param urlKey string
+// @return model.UrlRewrite
+func (repo *Repository) Get(
+	ctx context.Context,
+	urlKey string,
+) (model.UrlRewrite, error) {
+	return scanUrlRewrite(repo.connPool.QueryRow(ctx, getUrlRewrite, urlKey))
+}
+
+const getCanonicalUrlRewrite = `-- name: GetCanonicalUrlRewrite :one
+SELECT url_rewrite_id, entity_type, entity_id, url_key, created_at, is_redirect
+FROM "url_rewrite"
+WHERE entity_type = $1
+  AND entity_id = $2
+  AND NOT is_redirect
+`
+
+// GetCanonical
+// Returns the current url rewrite of an entity.
+// @param ctx context.Context
+// @param entityType model.UrlRewriteEntity
+// @param entityID int64
+// @return model.UrlRewrite
+func (repo *Repository) GetCanonical(
+	ctx context.Context,
+	entityType model.UrlRewriteEntity,
+	entityID int64,
+) (model.UrlRewrite, error) {
+	return scanUrlRewrite(repo.connPool.QueryRow(ctx, getCanonicalUrlRewrite, entityType, entityID))
+}
+
+const getListTakenUrlKey = `-- name: GetListTakenUrlKey :many
+SELECT url_key
+FROM "url_rewrite"
+WHERE (url_key = $1 OR url_key LIKE $1 || '-%')
+  AND NOT (entity_type = $2 AND entity_id = $3)
+UNION
+SELECT url_key
+FROM "post"
+WHERE (url_key = $1 OR url_key LIKE $1 || '-%')
+  AND NOT ($2 = '2' AND post_id = $3)
+UNION
+SELECT url_key
+FROM "category"
+WHERE (url_key = $1 OR url_key LIKE $1 || '-%')
+  AND NOT ($2 = '1' AND category_id = $3)
+`
+
+// GetListTakenUrlKey
+// Returns the url keys equal to the base or starting with the base and a hyphen, current or redirected,
+// which are used by any entity other than the given one.
+// @param ctx context.Context
+// @param base string
+// @param entityType model.UrlRewriteEntity
+// @param entityID int64
+// @return []string
+func (repo *Repository) GetListTakenUrlKey(
+	ctx context.Context,
+	base string,
+	entityType model.UrlRewriteEntity,
+	entityID int64,
+) ([]string, error) {
+	rows, err := repo.connPool.Query(ctx, getListTakenUrlKey, base, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var urlKey string
+		if err := rows.Scan(&urlKey); err != nil {
+			return nil, err
+		}
+		items = append(items, urlKey)
+	}
+	return items, rows.Err()
+}
@@ -0,0 +1,87 @@
+package urlrewrite
+
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	model "github.com/daniel-vuky/go-blog/internal/models/url_rewrite"
+	"github.com/daniel-vuky/go-blog/pkg/config"
+	goRandom "github.com/daniel-vuky/go-random"
+	"github.com/stretchr/testify/require"
+)
+
+var repository *Repository
+
+// TestMain
+// Initializes the repository and closes the connection pool after all tests have run.
+func TestMain(m *testing.M) {
+	loadedConfig, err := config.LoadConfig("../../../")
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+	connPool, err := loadedConfig.ConnectToPgxPool()
+	if err != nil {
+		log.Fatalf("failed to create connection pool: %v", err)
+	}
+	repository = NewUrlRewriteRepository(connPool)
+	code := m.Run()
+	repository.connPool.Close()
+	os.Exit(code)
+}
+
+// createCategory
+// Creates a root category with the given url key.
+func createCategory(t *testing.T, urlKey string) int64 {
+	var categoryID int64
+	err := repository.connPool.QueryRow(context.Background(), `
+		INSERT INTO "category" (name, url_key) VALUES ($1, $2) RETURNING category_id
+	`, goRandom.RandomString(12), urlKey).Scan(&categoryID)
+	require.NoError(t, err)
+	return categoryID
+}
+
+// createPost
+// Creates a draft post with the given url key.
+func createPost(t *testing.T, urlKey string) int64 {
+	var postID int64
+	err := repository.connPool.QueryRow(context.Background(), `
+		INSERT INTO "post" (name, url_key, author_id) VALUES ($1, $2, 0) RETURNING post_id
+	`, goRandom.RandomString(12), urlKey).Scan(&postID)
+	require.NoError(t, err)
+	return postID
+}
+
+// TestRepository_GetListTakenUrlKey
+// Tests the current and the redirected keys of the other entities are taken.
+func TestRepository_GetListTakenUrlKey(t *testing.T) {
+	ctx := context.Background()
+	base := strings.ToLower(goRandom.RandomString(16))
+	categoryID := createCategory(t, base)
+	postID := createPost(t, base+"-2")
+	createPost(t, base+"suffix")
+
+	// The previous key of the post stays taken by its redirect
+	_, err := repository.connPool.Exec(ctx, `UPDATE "post" SET url_key = $2 WHERE post_id = $1`, postID, base+"-3")
+	require.NoError(t, err)
+	rewrite, err := repository.Get(ctx, base+"-2")
+	require.NoError(t, err)
+	require.True(t, rewrite.IsRedirect)
+	canonical, err := repository.GetCanonical(ctx, model.UrlRewriteEntity2, postID)
+	require.NoError(t, err)
+	require.Equal(t, base+"-3", canonical.UrlKey.String)
+
+	taken, err := repository.GetListTakenUrlKey(ctx, base, model.UrlRewriteEntity2, 0)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{base, base + "-2", base + "-3"}, taken)
+
+	taken, err = repository.GetListTakenUrlKey(ctx, base, model.UrlRewriteEntity2, postID)
+	require.NoError(t, err)
+	require.Equal(t, []string{base}, taken)
+
+	taken, err = repository.GetListTakenUrlKey(ctx, base, model.UrlRewriteEntity1, categoryID)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{base + "-2", base + "-3"}, taken)
+}
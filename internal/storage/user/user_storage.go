@@ -0,0 +1,285 @@
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/daniel-vuky/go-blog/internal/common"
+	model "github.com/daniel-vuky/go-blog/internal/models/user"
+	"github.com/daniel-vuky/go-blog/internal/storage"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Repository
+// Wraps the Queries struct from the storage package and a connection pool.
+type Repository struct {
+	*storage.Queries
+	connPool *pgxpool.Pool
+}
+
+// NewUserRepository
+// Returns a new instance of Repository.
+// @param connPool *pgxpool.Pool
+// @return *Repository
+func NewUserRepository(connPool *pgxpool.Pool) *Repository {
+	return &Repository{
+		Queries:  storage.New(connPool),
+		connPool: connPool,
+	}
+}
+
+// scanUser
+// Scans a full user row.
+// @param row pgx.Row
+// @return model.User, error
+func scanUser(row pgx.Row) (model.User, error) {
+	var i model.User
+	err := row.Scan(
+		&i.UserID,
+		&i.Email,
+		&i.Firstname,
+		&i.Lastname,
+		&i.Subscribe,
+		&i.Gender,
+		&i.Dob,
+		&i.HashedPassword,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Active,
+		&i.EmailVerifiedAt,
+	)
+	return i, err
+}
+
+const getUser = `-- name: GetUser :one
+SELECT user_id, email, firstname, lastname, subscribe, gender, dob, hashed_password, password_changed_at, created_at, active, email_verified_at
+FROM "user"
+WHERE email = $1
+`
+
+// Get
+// Returns a user by email.
+// @param ctx context.Context
+// @param email string
+// @return model.User
+func (repo *Repository) Get(
+	ctx context.Context,
+	email string,
+) (model.User, error) {
+	return scanUser(repo.connPool.QueryRow(ctx, getUser, email))
+}
+
+const getUserByID = `-- name: GetUserByID :one
+SELECT user_id, email, firstname, lastname, subscribe, gender, dob, hashed_password, password_changed_at, created_at, active, email_verified_at
+FROM "user"
+WHERE user_id = $1
+`
+
+// GetByID
+// Returns a user by id.
+// @param ctx context.Context
+// @param userID int64
+// @return model.User
+func (repo *Repository) GetByID(
+	ctx context.Context,
+	userID int64,
+) (model.User, error) {
+	return scanUser(repo.connPool.QueryRow(ctx, getUserByID, userID))
+}
+
+const getListUser = `-- name: GetListUser :many
+SELECT user_id, email, firstname, lastname, subscribe, gender, dob, hashed_password, password_changed_at, created_at, active, email_verified_at
+FROM "user"
+WHERE user_id != 0
+%s
+ORDER BY %s %s
+LIMIT %d OFFSET %d
+`
+
+const getTotalUser = `-- name: GetTotalUser :one
+SELECT COUNT(*)
+FROM "user"
+WHERE user_id != 0
+%s
+`
+
+// GetList returns a list of users.
+// @param ctx context.Context
+// @param arg *model.GetListUserParams
+// @return []model.User
+// @return total user
+// @return error
+func (repo *Repository) GetList(
+	ctx context.Context,
+	arg *model.GetListUserParams,
+) ([]model.User, int64, error) {
+	offset := arg.PageSize * (arg.CurrentPage - 1)
+
+	// Build dynamic filter conditions
+	filterConditions, filterArgs := arg.BuildFilterConditions(arg.Filter)
+	if arg.Search != "" {
+		filterArgs = append(filterArgs, "%"+common.EscapeLike(arg.Search)+"%")
+		filterConditions += fmt.Sprintf(
+			" AND (email ILIKE $%[1]d OR firstname ILIKE $%[1]d OR lastname ILIKE $%[1]d)",
+			len(filterArgs),
+		)
+	}
+
+	// Prepare the main query with dynamic filters
+	query := fmt.Sprintf(
+		getListUser,
+		filterConditions,
+		arg.OrderBy,
+		arg.OrderDirection,
+		arg.PageSize,
+		offset,
+	)
+
+	// Execute the main query
+	rows, err := repo.connPool.Query(ctx, query, filterArgs...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	// Process the results
+	var items []model.User
+	for rows.Next() {
+		i, err := scanUser(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	// Build and execute the total count query
+	totalQuery := fmt.Sprintf(getTotalUser, filterConditions)
+	totalRow := repo.connPool.QueryRow(ctx, totalQuery, filterArgs...)
+
+	var count int64
+	err = totalRow.Scan(&count)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, count, nil
+}
+
+const createUser = `-- name: CreateUser :one
+INSERT INTO "user"
+    (
+        email,
+        hashed_password,
+        firstname,
+        lastname,
+        subscribe,
+        gender,
+        dob
+    )
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING user_id, email, firstname, lastname, subscribe, gender, dob, hashed_password, password_changed_at, created_at, active, email_verified_at
+`
+
+// Create
+// Creates a new user.
+// @param ctx context.Context
+// @param arg *model.CreateUserParams
+// @return model.User
+func (repo *Repository) Create(
+	ctx context.Context,
+	arg *model.CreateUserParams,
+) (model.User, error) {
+	return scanUser(repo.connPool.QueryRow(ctx, createUser,
+		arg.Email,
+		arg.HashedPassword,
+		arg.Firstname,
+		arg.Lastname,
+		arg.Subscribe,
+		arg.Gender,
+		arg.Dob,
+	))
+}
+
+const updateUser = `-- name: UpdateUser :one
+UPDATE "user"
+SET hashed_password = COALESCE($2, hashed_password),
+    firstname = COALESCE($3, firstname),
+    lastname = COALESCE($4, lastname),
+    subscribe = COALESCE($5, subscribe),
+    gender = COALESCE($6, gender),
+    dob = COALESCE($7, dob),
+    active = COALESCE($8, active)
+WHERE user_id = $1
+RETURNING user_id, email, firstname, lastname, subscribe, gender, dob, hashed_password, password_changed_at, created_at, active, email_verified_at
+`
+
+// Update
+// Updates a user.
+// @param ctx context.Context
+// @param arg *model.UpdateUserParams
+// @return model.User
+func (repo *Repository) Update(
+	ctx context.Context,
+	arg *model.UpdateUserParams,
+) (model.User, error) {
+	return scanUser(repo.connPool.QueryRow(ctx, updateUser,
+		arg.UserID,
+		arg.HashedPassword,
+		arg.Firstname,
+		arg.Lastname,
+		arg.Subscribe,
+		arg.Gender,
+		arg.Dob,
+		arg.Active,
+	))
+}
+
+const rehashUserPassword = `-- name: RehashUserPassword :exec
+UPDATE "user"
+SET hashed_password = $2
+WHERE user_id = $1
+`
+
+// Rehash
+// Replaces the hash of an unchanged password without touching password_changed_at.
+// @param ctx context.Context
+// @param userID int64
+// @param hashedPassword string
+// @return error
+func (repo *Repository) Rehash(
+	ctx context.Context,
+	userID int64,
+	hashedPassword string,
+) error {
+	return pgx.BeginFunc(ctx, repo.connPool, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, "SELECT set_config('go_blog.password_rehash', 'on', true)")
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, rehashUserPassword, userID, hashedPassword)
+		return err
+	})
+}
+
+const verifyUserEmail = `-- name: VerifyUserEmail :one
+UPDATE "user"
+SET email_verified_at = COALESCE(email_verified_at, NOW())
+WHERE user_id = $1
+RETURNING user_id, email, firstname, lastname, subscribe, gender, dob, hashed_password, password_changed_at, created_at, active, email_verified_at
+`
+
+// VerifyEmail
+// Marks the email of a user as verified, keeping the first verification time.
+// @param ctx context.Context
+// @param userID int64
+// @return model.User
+func (repo *Repository) VerifyEmail(
+	ctx context.Context,
+	userID int64,
+) (model.User, error) {
+	return scanUser(repo.connPool.QueryRow(ctx, verifyUserEmail, userID))
+}
@@ -0,0 +1,19 @@
+package account
+
+import (
+	"context"
+
+	accountModel "github.com/daniel-vuky/go-blog/internal/models/account"
+	userModel "github.com/daniel-vuky/go-blog/internal/models/user"
+)
+
+type Writer interface {
+	ForgotPassword(ctx context.Context, accountType accountModel.AccountType, email string) error
+	ResetPassword(ctx context.Context, plainToken string, newPassword string) error
+	SendEmailVerification(ctx context.Context, siteUser *userModel.User) error
+	VerifyEmail(ctx context.Context, plainToken string) (userModel.User, error)
+}
+
+type UseCase interface {
+	Writer
+}
@@ -7,14 +7,22 @@ import (
 
 type Reader interface {
 	GetAdmin(ctx context.Context, email string) (adminModel.Admin, error)
+	GetAdminByID(ctx context.Context, adminID int32) (adminModel.Admin, error)
 	GetListAdmin(ctx context.Context, arg *adminModel.GetListAdminParams) ([]adminModel.Admin, error)
 	IsAdminActive(ctx context.Context, email string) (bool, error)
+	VerifyAdminPassword(ctx context.Context, email string, plainPassword string) (adminModel.Admin, error)
+	Login(ctx context.Context, email string, plainPassword string, clientIp string) (adminModel.Admin, error)
+	Authenticate(ctx context.Context, email string, plainPassword string, clientIp string) (adminModel.Admin, error)
+	CheckAdminStatus(adminUser *adminModel.Admin) error
 }
 
 type Writer interface {
 	CreateAdmin(ctx context.Context, arg *adminModel.CreateAdminParams) (adminModel.Admin, error)
+	BootstrapAdmin(ctx context.Context, arg *adminModel.CreateAdminParams) (adminModel.Admin, error)
 	DeleteAdmin(ctx context.Context, email string) (adminModel.Admin, error)
 	UpdateAdmin(ctx context.Context, arg *adminModel.UpdateAdminParams) (adminModel.Admin, error)
+	UnlockAdmin(ctx context.Context, email string, actorID int32, clientIp string) (adminModel.Admin, error)
+	CompleteLogin(ctx context.Context, adminID int32, clientIp string)
 }
 
 type UseCase interface {
@@ -0,0 +1,28 @@
+package authorization
+
+import (
+	"context"
+	adminModel "github.com/daniel-vuky/go-blog/internal/models/admin"
+)
+
+type Reader interface {
+	IsAllowed(ctx context.Context, roleID int64, permissionCode string) (bool, error)
+	IsTwoFactorRequired(ctx context.Context, roleID int64) (bool, error)
+	GetRole(ctx context.Context, roleID int64) (adminModel.AuthorizationRole, error)
+	GetListRoles(ctx context.Context) ([]adminModel.AuthorizationRole, error)
+	GetRules(ctx context.Context, roleID int64) ([]adminModel.AuthorizationRule, error)
+}
+
+type Writer interface {
+	InvalidateRole(roleID int64)
+	InvalidateAll()
+	CreateRole(ctx context.Context, arg *adminModel.CreateAuthorizationRoleParams) (adminModel.AuthorizationRole, error)
+	UpdateRole(ctx context.Context, arg *adminModel.UpdateAuthorizationRoleParams) (adminModel.AuthorizationRole, error)
+	DeleteRole(ctx context.Context, roleID int64) (adminModel.AuthorizationRole, error)
+	ReplaceRules(ctx context.Context, arg *adminModel.ReplaceAuthorizationRulesParams) ([]adminModel.AuthorizationRule, error)
+}
+
+type UseCase interface {
+	Reader
+	Writer
+}
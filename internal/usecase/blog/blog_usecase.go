@@ -0,0 +1,21 @@
+package blog
+
+import (
+	"context"
+
+	blogModel "github.com/daniel-vuky/go-blog/internal/models/blog"
+	blogService "github.com/daniel-vuky/go-blog/internal/service/blog"
+)
+
+type Reader interface {
+	GetPost(ctx context.Context, urlKey string) (blogModel.Post, error)
+	GetListPost(ctx context.Context, arg *blogModel.GetListPostParams) (blogService.ListPostResponse, error)
+	GetCategory(ctx context.Context, urlKey string) (blogModel.Category, error)
+	GetListCategory(ctx context.Context) ([]blogModel.Category, error)
+	GetListCategoryPost(ctx context.Context, urlKey string, pageSize int32, currentPage int32) (blogModel.Category, blogService.ListPostResponse, error)
+	GetListComment(ctx context.Context, urlKey string, pageSize int32, currentPage int32) (blogModel.Post, blogService.ListCommentResponse, error)
+}
+
+type UseCase interface {
+	Reader
+}
@@ -0,0 +1,29 @@
+package category
+
+import (
+	"context"
+
+	categoryModel "github.com/daniel-vuky/go-blog/internal/models/category"
+	categoryService "github.com/daniel-vuky/go-blog/internal/service/category"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Reader interface {
+	GetCategory(ctx context.Context, categoryID int64) (categoryModel.Category, error)
+	GetListCategory(ctx context.Context, arg *categoryModel.GetListCategoryParams) (categoryService.ListCategoryResponse, error)
+	GetTree(ctx context.Context, rootID pgtype.Int8) ([]*categoryModel.CategoryNode, error)
+	GetBreadcrumbs(ctx context.Context, categoryID int64) ([]categoryModel.Category, error)
+}
+
+type Writer interface {
+	CreateCategory(ctx context.Context, arg *categoryModel.CreateCategoryParams) (categoryModel.Category, error)
+	UpdateCategory(ctx context.Context, arg *categoryModel.UpdateCategoryParams) (categoryModel.Category, error)
+	MoveCategory(ctx context.Context, categoryID int64, parentID pgtype.Int8) (categoryModel.Category, error)
+	DeleteCategory(ctx context.Context, categoryID int64, reassignTo pgtype.Int8) (categoryModel.Category, error)
+	MergeCategory(ctx context.Context, sourceID int64, targetID int64) (categoryModel.Category, error)
+}
+
+type UseCase interface {
+	Reader
+	Writer
+}
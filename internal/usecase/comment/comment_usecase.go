@@ -0,0 +1,26 @@
+package comment
+
+import (
+	"context"
+
+	commentModel "github.com/daniel-vuky/go-blog/internal/models/comment"
+	commentService "github.com/daniel-vuky/go-blog/internal/service/comment"
+)
+
+type Reader interface {
+	GetComment(ctx context.Context, commentID int64) (commentModel.Comment, error)
+	GetCommentTree(ctx context.Context, postID int64, arg *commentModel.GetCommentTreeParams) (commentService.ListCommentResponse, error)
+	GetReplyTree(ctx context.Context, commentID int64, arg *commentModel.GetCommentTreeParams) (commentService.ListCommentResponse, error)
+	GetListComment(ctx context.Context, arg *commentModel.GetListCommentParams) (commentService.AdminListCommentResponse, error)
+}
+
+type Writer interface {
+	CreateComment(ctx context.Context, arg *commentModel.CreateCommentParams) (commentModel.Comment, error)
+	UpdateComment(ctx context.Context, userID int64, arg *commentModel.UpdateCommentParams) (commentModel.Comment, error)
+	ModerateComments(ctx context.Context, commentIDs []int64, status commentModel.CommentStatus) ([]commentModel.Comment, error)
+}
+
+type UseCase interface {
+	Reader
+	Writer
+}
@@ -0,0 +1,25 @@
+package links
+
+import (
+	"context"
+
+	categoryModel "github.com/daniel-vuky/go-blog/internal/models/category"
+	linksModel "github.com/daniel-vuky/go-blog/internal/models/links"
+	blogService "github.com/daniel-vuky/go-blog/internal/service/blog"
+)
+
+type Reader interface {
+	GetPostCategories(ctx context.Context, postID int64) ([]categoryModel.Category, error)
+	GetListCategoryPost(ctx context.Context, arg *linksModel.GetListCategoryPostParams) (blogService.ListPostResponse, error)
+}
+
+type Writer interface {
+	SetPostCategories(ctx context.Context, postID int64, categoryIDs []int64) ([]categoryModel.Category, error)
+	AddPostCategories(ctx context.Context, postID int64, categoryIDs []int64) ([]categoryModel.Category, error)
+	RemovePostCategories(ctx context.Context, postID int64, categoryIDs []int64) ([]categoryModel.Category, error)
+}
+
+type UseCase interface {
+	Reader
+	Writer
+}
@@ -0,0 +1,22 @@
+package lockout
+
+import (
+	"context"
+	adminModel "github.com/daniel-vuky/go-blog/internal/models/admin"
+)
+
+type Reader interface {
+	CheckClientIp(ctx context.Context, clientIp string) error
+}
+
+type Writer interface {
+	RegisterFailedLogin(ctx context.Context, email string, clientIp string) error
+	RegisterFailedClientLogin(ctx context.Context, clientIp string) error
+	RegisterSuccessfulLogin(ctx context.Context, adminID int32, clientIp string) error
+	UnlockAdmin(ctx context.Context, email string, actorID int32, clientIp string) (adminModel.Admin, error)
+}
+
+type UseCase interface {
+	Reader
+	Writer
+}
@@ -0,0 +1,27 @@
+package media
+
+import (
+	"context"
+	"io"
+
+	mediaModel "github.com/daniel-vuky/go-blog/internal/models/media"
+	mediaService "github.com/daniel-vuky/go-blog/internal/service/media"
+)
+
+type Reader interface {
+	GetMedia(ctx context.Context, mediaID int64) (mediaModel.Media, error)
+	GetListMedia(ctx context.Context, arg *mediaModel.GetListMediaParams) (mediaService.ListMediaResponse, error)
+	OpenFile(ctx context.Context, storageKey string) (io.ReadCloser, string, error)
+	OpenVariant(ctx context.Context, storageKey string, variant mediaService.Variant) (io.ReadCloser, string, error)
+	Srcset(imageUrl string) string
+}
+
+type Writer interface {
+	UploadMedia(ctx context.Context, arg *mediaModel.UploadMediaParams) (mediaModel.Media, bool, error)
+	DeleteMedia(ctx context.Context, mediaID int64) (mediaModel.Media, error)
+}
+
+type UseCase interface {
+	Reader
+	Writer
+}
@@ -0,0 +1,31 @@
+package post
+
+import (
+	"context"
+
+	postModel "github.com/daniel-vuky/go-blog/internal/models/post"
+	postService "github.com/daniel-vuky/go-blog/internal/service/post"
+	"github.com/daniel-vuky/go-blog/pkg/markdown"
+)
+
+type Reader interface {
+	GetPost(ctx context.Context, postID int64) (postModel.Post, error)
+	GetListPost(ctx context.Context, arg *postModel.GetListPostParams) (postService.ListPostResponse, error)
+	GetListPostRevision(ctx context.Context, arg *postModel.GetListPostRevisionParams) (postService.ListPostRevisionResponse, error)
+	GetPostRevisionDiff(ctx context.Context, postID int64, fromRevisionID int64, toRevisionID int64) (postService.PostRevisionDiff, error)
+	PreviewContent(content string) (markdown.Document, error)
+}
+
+type Writer interface {
+	CreatePost(ctx context.Context, arg *postModel.CreatePostParams) (postModel.Post, error)
+	UpdatePost(ctx context.Context, arg *postModel.UpdatePostParams) (postModel.Post, error)
+	DeletePost(ctx context.Context, postID int64) (postModel.Post, error)
+	UpdatePostStatus(ctx context.Context, arg *postModel.UpdatePostStatusParams) (postModel.Post, error)
+	PublishScheduledPosts(ctx context.Context) ([]postModel.Post, error)
+	RestorePostRevision(ctx context.Context, postID int64, revisionID int64, editorID int64) (postModel.Post, error)
+}
+
+type UseCase interface {
+	Reader
+	Writer
+}
@@ -0,0 +1,15 @@
+package search
+
+import (
+	"context"
+
+	searchService "github.com/daniel-vuky/go-blog/internal/service/search"
+)
+
+type Reader interface {
+	SearchPost(ctx context.Context, text string, pageSize int32, currentPage int32) (searchService.ListPostResponse, error)
+}
+
+type UseCase interface {
+	Reader
+}
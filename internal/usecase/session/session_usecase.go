@@ -0,0 +1,24 @@
+package session
+
+import (
+	"context"
+
+	sessionModel "github.com/daniel-vuky/go-blog/internal/models/session"
+	sessionService "github.com/daniel-vuky/go-blog/internal/service/session"
+)
+
+type Reader interface {
+	GetListSessions(ctx context.Context, userID int64) ([]sessionModel.RefreshToken, error)
+}
+
+type Writer interface {
+	CreateSession(ctx context.Context, userID int64, userAgent string, clientIp string) (sessionService.Tokens, error)
+	Refresh(ctx context.Context, refreshToken string, userAgent string, clientIp string) (sessionService.Tokens, error)
+	Logout(ctx context.Context, refreshToken string) error
+	RevokeUserSessions(ctx context.Context, userID int64) error
+}
+
+type UseCase interface {
+	Reader
+	Writer
+}
@@ -0,0 +1,28 @@
+package twofactor
+
+import (
+	"context"
+
+	adminModel "github.com/daniel-vuky/go-blog/internal/models/admin"
+	twoFactorService "github.com/daniel-vuky/go-blog/internal/service/twofactor"
+)
+
+type Reader interface {
+	GetStatus(ctx context.Context, adminID int32) (twoFactorService.Status, error)
+	IsEnabled(ctx context.Context, adminID int32) (bool, error)
+	IsRequired(ctx context.Context, roleID int64) (bool, error)
+	Verify(ctx context.Context, adminUser *adminModel.Admin, code string, clientIp string) error
+}
+
+type Writer interface {
+	Setup(ctx context.Context, adminUser *adminModel.Admin) (twoFactorService.SetupResponse, error)
+	Enable(ctx context.Context, adminID int32, code string) ([]string, error)
+	Disable(ctx context.Context, adminUser *adminModel.Admin, code string) error
+	Reset(ctx context.Context, adminID int32) error
+	RegenerateRecoveryCodes(ctx context.Context, adminID int32, code string) ([]string, error)
+}
+
+type UseCase interface {
+	Reader
+	Writer
+}
@@ -0,0 +1,15 @@
+package urlrewrite
+
+import (
+	"context"
+
+	urlRewriteService "github.com/daniel-vuky/go-blog/internal/service/urlrewrite"
+)
+
+type Reader interface {
+	Resolve(ctx context.Context, path string) (urlRewriteService.Resolution, error)
+}
+
+type UseCase interface {
+	Reader
+}
@@ -0,0 +1,28 @@
+package user
+
+import (
+	"context"
+
+	userModel "github.com/daniel-vuky/go-blog/internal/models/user"
+	userService "github.com/daniel-vuky/go-blog/internal/service/user"
+)
+
+type Reader interface {
+	GetUserByID(ctx context.Context, userID int64) (userModel.User, error)
+	GetUserByEmail(ctx context.Context, email string) (userModel.User, error)
+	GetListUser(ctx context.Context, arg *userModel.GetListUserParams) (userService.ListUserResponse, error)
+}
+
+type Writer interface {
+	Register(ctx context.Context, arg *userModel.CreateUserParams) (userModel.User, error)
+	Login(ctx context.Context, email string, plainPassword string, clientIp string) (userModel.User, error)
+	UpdateProfile(ctx context.Context, arg *userModel.UpdateUserParams, currentPassword string) (userModel.User, error)
+	ResetPassword(ctx context.Context, userID int64, newPassword string) (userModel.User, error)
+	VerifyEmail(ctx context.Context, userID int64) (userModel.User, error)
+	SetUserActive(ctx context.Context, userID int64, active bool) (userModel.User, error)
+}
+
+type UseCase interface {
+	Reader
+	Writer
+}
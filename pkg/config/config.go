@@ -5,10 +5,14 @@ import (
 	"github.com/spf13/viper"
 	"strings"
 	"sync"
+	"time"
 )
 
+// Server
+// Listening port and the proxies whose forwarded client ip headers are trusted
 type Server struct {
-	Port int
+	Port           int
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 type Database struct {
@@ -22,15 +26,143 @@ type Database struct {
 	Timezone string
 }
 
+// Password
+// Hashing algorithm and cost parameters used for stored passwords
+type Password struct {
+	Algorithm     string
+	BcryptCost    int    `mapstructure:"bcrypt_cost"`
+	Argon2Time    uint32 `mapstructure:"argon2_time"`
+	Argon2Memory  uint32 `mapstructure:"argon2_memory"`
+	Argon2Threads uint8  `mapstructure:"argon2_threads"`
+}
+
+// Token
+// Signing settings of issued access tokens and lifetime of refresh tokens
+type Token struct {
+	Type                 string
+	SymmetricKey         string        `mapstructure:"symmetric_key"`
+	AccessTokenDuration  time.Duration `mapstructure:"access_token_duration"`
+	RefreshTokenDuration time.Duration `mapstructure:"refresh_token_duration"`
+}
+
+// Lockout
+// Thresholds and durations of the lockout after repeated failed logins
+type Lockout struct {
+	Threshold    int
+	IpThreshold  int           `mapstructure:"ip_threshold"`
+	IpWindow     time.Duration `mapstructure:"ip_window"`
+	BaseDuration time.Duration `mapstructure:"base_duration"`
+	MaxDuration  time.Duration `mapstructure:"max_duration"`
+}
+
+// Setup
+// Settings of the one-time bootstrap of the first admin
+type Setup struct {
+	Token string
+}
+
+// Mail
+// Driver and credentials of the outgoing mail
+type Mail struct {
+	Driver    string
+	Host      string
+	Port      int
+	Username  string
+	Password  string
+	From      string
+	Directory string
+	// Debug allows the log driver, which prints the messages and their account tokens
+	Debug bool
+}
+
+// AccountToken
+// Signing key, lifetime and resend cooldown of the password reset and email verification tokens
+// and the links sent by email
+type AccountToken struct {
+	SigningKey           string        `mapstructure:"signing_key"`
+	ResetDuration        time.Duration `mapstructure:"reset_duration"`
+	ResetCooldown        time.Duration `mapstructure:"reset_cooldown"`
+	VerificationDuration time.Duration `mapstructure:"verification_duration"`
+	BaseUrl              string        `mapstructure:"base_url"`
+}
+
+// TwoFactor
+// Settings of the TOTP two-factor authentication of admins
+type TwoFactor struct {
+	Issuer            string
+	EncryptionKey     string        `mapstructure:"encryption_key"`
+	ChallengeDuration time.Duration `mapstructure:"challenge_duration"`
+	RecoveryCodes     int           `mapstructure:"recovery_codes"`
+}
+
+// Publishing
+// Interval at which the scheduler publishes the scheduled posts
+type Publishing struct {
+	SchedulerInterval time.Duration `mapstructure:"scheduler_interval"`
+}
+
+// Search
+// Text search configuration of postgres the posts are indexed and searched with, e.g. english or simple
+type Search struct {
+	Language string
+}
+
+// ImagePreset
+// Size of an image variant which may be requested, a zero Width or Height follows the ratio of the image
+type ImagePreset struct {
+	Name   string
+	Width  int
+	Height int
+	Fit    string
+}
+
+// Media
+// Storage driver of the uploaded media and the url the files are served from. Directory is used
+// by the local driver, Endpoint, Region, Bucket and the keys by the S3-compatible driver.
+// Presets are the only image variants served, Srcset names the presets offered to responsive images.
+type Media struct {
+	Driver    string
+	Directory string
+	BaseUrl   string `mapstructure:"base_url"`
+	MaxSize   int64  `mapstructure:"max_size"`
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string `mapstructure:"access_key"`
+	SecretKey string `mapstructure:"secret_key"`
+	Presets   []ImagePreset
+	Srcset    []string
+}
+
 type Config struct {
-	Server   *Server
-	Database *Database
+	Server       *Server
+	Database     *Database
+	Password     *Password
+	Token        *Token
+	Setup        *Setup
+	Lockout      *Lockout
+	Mail         *Mail
+	AccountToken *AccountToken `mapstructure:"account_token"`
+	TwoFactor    *TwoFactor    `mapstructure:"two_factor"`
+	Publishing   *Publishing
+	Search       *Search
+	Media        *Media
 }
 
 var configOnce sync.Once
 var loadedConfig = &Config{
-	Server:   &Server{},
-	Database: &Database{},
+	Server:       &Server{},
+	Database:     &Database{},
+	Password:     &Password{},
+	Token:        &Token{},
+	Setup:        &Setup{},
+	Lockout:      &Lockout{},
+	Mail:         &Mail{},
+	AccountToken: &AccountToken{},
+	TwoFactor:    &TwoFactor{},
+	Publishing:   &Publishing{},
+	Search:       &Search{},
+	Media:        &Media{},
 }
 
 // LoadConfig
@@ -0,0 +1,185 @@
+package diff
+
+import "strings"
+
+// Op is the operation applied to a line to go from the old text to the new text
+type Op string
+
+const (
+	OpEqual  Op = "equal"
+	OpInsert Op = "insert"
+	OpDelete Op = "delete"
+)
+
+// maxEditDistance
+// Number of edited lines above which the changed block is reported as entirely replaced,
+// which keeps the memory used by the search bounded
+const maxEditDistance = 2000
+
+// Line
+// A line of a diff, OldNumber and NewNumber are 1-based and 0 when the line is missing from that side.
+type Line struct {
+	Op        Op     `json:"op"`
+	Text      string `json:"text"`
+	OldNumber int    `json:"old_number,omitempty"`
+	NewNumber int    `json:"new_number,omitempty"`
+}
+
+// Lines
+// Returns the shortest line-level diff turning oldText into newText.
+// @param oldText string
+// @param newText string
+// @return []Line
+func Lines(oldText string, newText string) []Line {
+	a, b := splitLines(oldText), splitLines(newText)
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]Line, 0, len(a)+len(b)-prefix-suffix)
+	for i := 0; i < prefix; i++ {
+		lines = append(lines, Line{Op: OpEqual, Text: a[i], OldNumber: i + 1, NewNumber: i + 1})
+	}
+	for _, line := range shortestEdit(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]) {
+		if line.OldNumber > 0 {
+			line.OldNumber += prefix
+		}
+		if line.NewNumber > 0 {
+			line.NewNumber += prefix
+		}
+		lines = append(lines, line)
+	}
+	for i := suffix; i > 0; i-- {
+		lines = append(lines, Line{Op: OpEqual, Text: a[len(a)-i], OldNumber: len(a) - i + 1, NewNumber: len(b) - i + 1})
+	}
+	return lines
+}
+
+// HasChanges
+// Checks if a diff holds any inserted or deleted line.
+// @param lines []Line
+// @return bool
+func HasChanges(lines []Line) bool {
+	for _, line := range lines {
+		if line.Op != OpEqual {
+			return true
+		}
+	}
+	return false
+}
+
+// splitLines
+// Splits a text into lines, an empty text has no line.
+// @param text string
+// @return []string
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// shortestEdit
+// Myers' algorithm, the furthest reaching paths of every round are kept to walk the edit back.
+// @param a []string
+// @param b []string
+// @return []Line
+func shortestEdit(a []string, b []string) []Line {
+	n, m := len(a), len(b)
+	if n+m == 0 {
+		return nil
+	}
+	offset := n + m
+	v := make([]int, 2*offset+2)
+	var trace [][]int
+	for d := 0; d <= n+m; d++ {
+		if d > maxEditDistance {
+			return replaceAll(a, b)
+		}
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace)
+			}
+		}
+	}
+	return replaceAll(a, b)
+}
+
+// backtrack
+// Walks the edit from the end of both texts back to their start.
+// @param a []string
+// @param b []string
+// @param trace [][]int the furthest reaching x by diagonal at the start of every round
+// @return []Line
+func backtrack(a []string, b []string, trace [][]int) []Line {
+	x, y := len(a), len(b)
+	var reversed []Line
+	for d := len(trace) - 1; d >= 0; d-- {
+		prevX, prevY := 0, 0
+		if d > 0 {
+			k := x - y
+			prevK := k - 1
+			if k == -d || (k != d && trace[d][k-1+d] < trace[d][k+1+d]) {
+				prevK = k + 1
+			}
+			prevX = trace[d][prevK+d]
+			prevY = prevX - prevK
+		}
+		for x > prevX && y > prevY {
+			reversed = append(reversed, Line{Op: OpEqual, Text: a[x-1], OldNumber: x, NewNumber: y})
+			x--
+			y--
+		}
+		if d == 0 {
+			break
+		}
+		if x == prevX {
+			reversed = append(reversed, Line{Op: OpInsert, Text: b[y-1], NewNumber: y})
+		} else {
+			reversed = append(reversed, Line{Op: OpDelete, Text: a[x-1], OldNumber: x})
+		}
+		x, y = prevX, prevY
+	}
+
+	lines := make([]Line, len(reversed))
+	for i, line := range reversed {
+		lines[len(reversed)-1-i] = line
+	}
+	return lines
+}
+
+// replaceAll
+// Reports every old line as deleted and every new line as inserted.
+// @param a []string
+// @param b []string
+// @return []Line
+func replaceAll(a []string, b []string) []Line {
+	lines := make([]Line, 0, len(a)+len(b))
+	for i, text := range a {
+		lines = append(lines, Line{Op: OpDelete, Text: text, OldNumber: i + 1})
+	}
+	for i, text := range b {
+		lines = append(lines, Line{Op: OpInsert, Text: text, NewNumber: i + 1})
+	}
+	return lines
+}
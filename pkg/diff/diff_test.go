@@ -0,0 +1,86 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// rebuild
+// Return the old and the new text described by a diff
+func rebuild(lines []Line) (string, string) {
+	var oldLines, newLines []string
+	for _, line := range lines {
+		if line.Op != OpInsert {
+			oldLines = append(oldLines, line.Text)
+		}
+		if line.Op != OpDelete {
+			newLines = append(newLines, line.Text)
+		}
+	}
+	return strings.Join(oldLines, "\n"), strings.Join(newLines, "\n")
+}
+
+// countChanges
+// Return the number of inserted and deleted lines of a diff
+func countChanges(lines []Line) int {
+	count := 0
+	for _, line := range lines {
+		if line.Op != OpEqual {
+			count++
+		}
+	}
+	return count
+}
+
+// TestLines test the diffs rebuild both texts with the fewest changes
+func TestLines(t *testing.T) {
+	testCases := []struct {
+		oldText string
+		newText string
+		changes int
+	}{
+		{"", "", 0},
+		{"a\nb\nc", "a\nb\nc", 0},
+		{"", "a\nb", 2},
+		{"a\nb", "", 2},
+		{"a\nb\nc", "a\nx\nc", 2},
+		{"a\nb\nc\na\nb\nb\na", "c\nb\na\nb\na\nc", 5},
+		{"one\ntwo\nthree\nfour", "zero\none\nthree\nfour\nfive", 3},
+	}
+	for _, tc := range testCases {
+		lines := Lines(tc.oldText, tc.newText)
+		oldText, newText := rebuild(lines)
+		require.Equal(t, tc.oldText, oldText)
+		require.Equal(t, tc.newText, newText)
+		require.Equal(t, tc.changes, countChanges(lines), fmt.Sprintf("%q -> %q", tc.oldText, tc.newText))
+		require.Equal(t, tc.changes > 0, HasChanges(lines))
+	}
+}
+
+// TestLines_Numbers test the lines keep their number on each side
+func TestLines_Numbers(t *testing.T) {
+	lines := Lines("a\nb\nc\r\n", "a\nx\nc\nd")
+	require.Equal(t, []Line{
+		{Op: OpEqual, Text: "a", OldNumber: 1, NewNumber: 1},
+		{Op: OpDelete, Text: "b", OldNumber: 2},
+		{Op: OpInsert, Text: "x", NewNumber: 2},
+		{Op: OpEqual, Text: "c", OldNumber: 3, NewNumber: 3},
+		{Op: OpInsert, Text: "d", NewNumber: 4},
+	}, lines)
+}
+
+// TestLines_Large test texts differing by more than the edit limit are still rebuilt
+func TestLines_Large(t *testing.T) {
+	var oldLines, newLines []string
+	for i := 0; i < maxEditDistance+10; i++ {
+		oldLines = append(oldLines, fmt.Sprintf("old %d", i))
+		newLines = append(newLines, fmt.Sprintf("new %d", i))
+	}
+	oldText, newText := strings.Join(oldLines, "\n"), strings.Join(newLines, "\n")
+	rebuiltOld, rebuiltNew := rebuild(Lines(oldText, newText))
+	require.Equal(t, oldText, rebuiltOld)
+	require.Equal(t, newText, rebuiltNew)
+}
@@ -0,0 +1,71 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// keySize
+// AES-256 key size in bytes
+const keySize = 32
+
+var ErrInvalidCiphertext = errors.New("ciphertext is invalid")
+
+// AESGCM
+// Encrypts short secrets with AES-256-GCM
+type AESGCM struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCM
+// Create a cipher from a 32 bytes key
+// @param key string
+// @return *AESGCM, error
+func NewAESGCM(key string) (*AESGCM, error) {
+	if len(key) != keySize {
+		return nil, fmt.Errorf("invalid key size: must be exactly %d characters", keySize)
+	}
+	block, err := aes.NewCipher([]byte(key))
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &AESGCM{aead: aead}, nil
+}
+
+// Encrypt
+// Encrypt the plaintext with a random nonce, the nonce is prepended to the encoded result
+// @param plaintext string
+// @return string, error
+func (c *AESGCM) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt
+// Decrypt a value produced by Encrypt
+// @param encoded string
+// @return string, error
+func (c *AESGCM) Decrypt(encoded string) (string, error) {
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < c.aead.NonceSize() {
+		return "", ErrInvalidCiphertext
+	}
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,43 @@
+package encryption
+
+import (
+	"testing"
+
+	goRandom "github.com/daniel-vuky/go-random"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAESGCM_EncryptAndDecrypt test a round trip and tampering detection
+func TestAESGCM_EncryptAndDecrypt(t *testing.T) {
+	c, err := NewAESGCM(goRandom.RandomString(32))
+	require.NoError(t, err)
+
+	plaintext := goRandom.RandomString(20)
+	encrypted, err := c.Encrypt(plaintext)
+	require.NoError(t, err)
+	require.NotContains(t, encrypted, plaintext)
+
+	other, err := c.Encrypt(plaintext)
+	require.NoError(t, err)
+	require.NotEqual(t, encrypted, other)
+
+	decrypted, err := c.Decrypt(encrypted)
+	require.NoError(t, err)
+	require.Equal(t, plaintext, decrypted)
+
+	tampered := []byte(encrypted)
+	tampered[len(tampered)/2] ^= 1
+	_, err = c.Decrypt(string(tampered))
+	require.ErrorIs(t, err, ErrInvalidCiphertext)
+
+	otherKey, err := NewAESGCM(goRandom.RandomString(32))
+	require.NoError(t, err)
+	_, err = otherKey.Decrypt(encrypted)
+	require.ErrorIs(t, err, ErrInvalidCiphertext)
+}
+
+// TestNewAESGCM_InvalidKey test rejecting keys of the wrong size
+func TestNewAESGCM_InvalidKey(t *testing.T) {
+	_, err := NewAESGCM(goRandom.RandomString(16))
+	require.Error(t, err)
+}
@@ -0,0 +1,108 @@
+package filestore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// LocalStorage
+// Storage keeping the files in a directory of the local filesystem
+type LocalStorage struct {
+	directory string
+}
+
+// NewLocalStorage
+// Create a storage keeping the files in the directory
+// @param directory string
+// @return *LocalStorage, error
+func NewLocalStorage(directory string) (*LocalStorage, error) {
+	if directory == "" {
+		return nil, errors.New("media directory is required")
+	}
+	if err := os.MkdirAll(directory, 0o750); err != nil {
+		return nil, err
+	}
+	return &LocalStorage{directory: directory}, nil
+}
+
+// Put
+// Write the file through a temporary file, so a reader never sees a partial file
+// @param ctx context.Context
+// @param key string
+// @param content []byte
+// @param contentType string
+// @return error
+func (storage *LocalStorage) Put(_ context.Context, key string, content []byte, _ string) error {
+	filePath, err := storage.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(filePath), 0o750); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(filePath), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err = file.Write(content); err != nil {
+		file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(file.Name(), 0o640); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), filePath)
+}
+
+// Open
+// Open the file for reading
+// @param ctx context.Context
+// @param key string
+// @return io.ReadCloser, error
+func (storage *LocalStorage) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	filePath, err := storage.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+// Delete
+// Remove the file, a missing file is not an error
+// @param ctx context.Context
+// @param key string
+// @return error
+func (storage *LocalStorage) Delete(_ context.Context, key string) error {
+	filePath, err := storage.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// path
+// Return the path of the file of a key inside the directory
+// @param key string
+// @return string, error
+func (storage *LocalStorage) path(key string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(storage.directory, filepath.FromSlash(key)), nil
+}
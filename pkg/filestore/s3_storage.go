@@ -0,0 +1,234 @@
+package filestore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/daniel-vuky/go-blog/pkg/config"
+)
+
+const (
+	// signingAlgorithm
+	// Algorithm of the AWS signature version 4
+	signingAlgorithm = "AWS4-HMAC-SHA256"
+	// s3Timeout
+	// Longest time a request to the object storage may take
+	s3Timeout = 30 * time.Second
+)
+
+// S3Storage
+// Storage keeping the files in a bucket of an S3-compatible object storage, addressed path-style
+type S3Storage struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	client    *http.Client
+	now       func() time.Time
+}
+
+// NewS3Storage
+// Create a storage keeping the files in the configured bucket
+// @param cfg *config.Media
+// @return *S3Storage, error
+func NewS3Storage(cfg *config.Media) (*S3Storage, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, errors.New("s3 endpoint and bucket are required")
+	}
+	if cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, errors.New("s3 access key and secret key are required")
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(cfg.Endpoint, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, errors.New("s3 endpoint must be an http or https url")
+	}
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	return &S3Storage{
+		endpoint:  endpoint,
+		region:    region,
+		bucket:    cfg.Bucket,
+		accessKey: cfg.AccessKey,
+		secretKey: cfg.SecretKey,
+		client:    &http.Client{Timeout: s3Timeout},
+		now:       time.Now,
+	}, nil
+}
+
+// Put
+// Upload the object
+// @param ctx context.Context
+// @param key string
+// @param content []byte
+// @param contentType string
+// @return error
+func (storage *S3Storage) Put(ctx context.Context, key string, content []byte, contentType string) error {
+	response, err := storage.do(ctx, http.MethodPut, key, content, contentType)
+	if err != nil {
+		return err
+	}
+	return response.Body.Close()
+}
+
+// Open
+// Download the object, the caller closes the returned body
+// @param ctx context.Context
+// @param key string
+// @return io.ReadCloser, error
+func (storage *S3Storage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	response, err := storage.do(ctx, http.MethodGet, key, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	return response.Body, nil
+}
+
+// Delete
+// Delete the object, a missing object is not an error
+// @param ctx context.Context
+// @param key string
+// @return error
+func (storage *S3Storage) Delete(ctx context.Context, key string) error {
+	response, err := storage.do(ctx, http.MethodDelete, key, nil, "")
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return response.Body.Close()
+}
+
+// do
+// Send a signed request for an object, the statuses other than 2xx are returned as errors
+// @param ctx context.Context
+// @param method string
+// @param key string
+// @param content []byte
+// @param contentType string
+// @return *http.Response, error
+func (storage *S3Storage) do(
+	ctx context.Context,
+	method string,
+	key string,
+	content []byte,
+	contentType string,
+) (*http.Response, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+	objectUrl := *storage.endpoint
+	objectUrl.Path = storage.endpoint.Path + "/" + storage.bucket + "/" + key
+	request, err := http.NewRequestWithContext(ctx, method, objectUrl.String(), bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+	signRequest(request, hashHex(content), storage.accessKey, storage.secretKey, storage.region, storage.now())
+
+	response, err := storage.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return response, nil
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	body, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+	return nil, fmt.Errorf("s3 %s %s: %s: %s", method, key, response.Status, strings.TrimSpace(string(body)))
+}
+
+// signRequest
+// Sign the request with the AWS signature version 4, every header already set is signed
+// along with the host, the payload hash and the date
+// @param request *http.Request
+// @param payloadHash string hex encoded sha256 of the body
+// @param accessKey string
+// @param secretKey string
+// @param region string
+// @param now time.Time
+func signRequest(request *http.Request, payloadHash string, accessKey string, secretKey string, region string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	day := amzDate[:8]
+	request.Header.Set("X-Amz-Date", amzDate)
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": request.URL.Host}
+	for name, values := range request.Header {
+		headers[strings.ToLower(name)] = strings.Join(strings.Fields(strings.Join(values, ",")), " ")
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		request.URL.EscapedPath(),
+		request.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := day + "/" + region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{signingAlgorithm, amzDate, scope, hashHex([]byte(canonicalRequest))}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+secretKey), day)
+	signingKey = hmacSHA256(signingKey, region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	request.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signingAlgorithm, accessKey, scope, signedHeaders, signature,
+	))
+}
+
+// hashHex
+// Return the hex encoded sha256 of the data
+// @param data []byte
+// @return string
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256
+// Return the HMAC-SHA256 of the data
+// @param key []byte
+// @param data string
+// @return []byte
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
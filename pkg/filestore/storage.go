@@ -0,0 +1,62 @@
+package filestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/daniel-vuky/go-blog/pkg/config"
+)
+
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+var (
+	ErrUnsupportedDriver = errors.New("unsupported file storage driver")
+	ErrInvalidKey        = errors.New("file key must be a relative path without dot segments")
+	ErrNotFound          = errors.New("file not found")
+)
+
+// Storage
+// Stores the uploaded media files by key
+type Storage interface {
+	Put(ctx context.Context, key string, content []byte, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewStorage
+// Create the storage selected by the configuration
+// @param cfg *config.Media
+// @return Storage, error
+func NewStorage(cfg *config.Media) (Storage, error) {
+	switch cfg.Driver {
+	case DriverLocal, "":
+		return NewLocalStorage(cfg.Directory)
+	case DriverS3:
+		return NewS3Storage(cfg)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedDriver, cfg.Driver)
+	}
+}
+
+// validateKey
+// Reject the keys which could leave the storage root
+// @param key string
+// @return error
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") || path.Clean(key) != key {
+		return ErrInvalidKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "." || segment == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	// Decoders of the uploaded formats
+	_ "image/gif"
+
+	_ "golang.org/x/image/webp"
+
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/draw"
+)
+
+// Fit is how an image is made to match the requested size
+type Fit string
+
+const (
+	// FitCover fills the whole size, cropping what overflows around the center
+	FitCover Fit = "cover"
+	// FitContain fits the image inside the size, keeping all of it
+	FitContain Fit = "contain"
+)
+
+// Format is the encoding of a resized image
+type Format string
+
+const (
+	FormatJPEG Format = "jpeg"
+	FormatPNG  Format = "png"
+	FormatWebP Format = "webp"
+)
+
+const (
+	// MaxPixels
+	// Largest source image decoded, in pixels, which bounds the memory of a resize
+	MaxPixels = 40_000_000
+	// jpegQuality
+	// Quality of the encoded jpeg images
+	jpegQuality = 85
+)
+
+var (
+	ErrInvalidSize   = errors.New("width or height must be set and not negative")
+	ErrInvalidFit    = errors.New("fit must be cover or contain")
+	ErrInvalidFormat = errors.New("format must be jpeg, png or webp")
+	ErrImageTooLarge = errors.New("image has too many pixels to be resized")
+)
+
+// Options
+// Size, fit and format of a resized image, a zero Width or Height follows the ratio of the source.
+type Options struct {
+	Width  int
+	Height int
+	Fit    Fit
+	Format Format
+}
+
+// IsValid
+// Checks the fit is known
+// @return bool
+func (f Fit) IsValid() bool {
+	switch f {
+	case FitCover, FitContain:
+		return true
+	}
+	return false
+}
+
+// IsValid
+// Checks the format is known
+// @return bool
+func (f Format) IsValid() bool {
+	switch f {
+	case FormatJPEG, FormatPNG, FormatWebP:
+		return true
+	}
+	return false
+}
+
+// ContentType
+// Returns the mime type of the format
+// @return string
+func (f Format) ContentType() string {
+	return "image/" + string(f)
+}
+
+// Resize
+// Decodes a jpeg, png, gif or webp image and encodes it resized. An image is never enlarged
+// to fit inside the size, a covered size is always filled.
+// @param src io.Reader
+// @param opts Options
+// @return []byte, error
+func Resize(src io.Reader, opts Options) ([]byte, error) {
+	if opts.Width < 0 || opts.Height < 0 || (opts.Width == 0 && opts.Height == 0) {
+		return nil, ErrInvalidSize
+	}
+	if !opts.Fit.IsValid() {
+		return nil, ErrInvalidFit
+	}
+	if !opts.Format.IsValid() {
+		return nil, ErrInvalidFormat
+	}
+	content, err := io.ReadAll(src)
+	if err != nil {
+		return nil, err
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width*config.Height > MaxPixels {
+		return nil, ErrImageTooLarge
+	}
+	source, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := source.Bounds()
+	crop, width, height := layout(bounds.Dx(), bounds.Dy(), opts)
+	resized := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(resized, resized.Bounds(), source, crop.Add(bounds.Min), draw.Src, nil)
+
+	var buf bytes.Buffer
+	switch opts.Format {
+	case FormatJPEG:
+		err = jpeg.Encode(&buf, flatten(resized), &jpeg.Options{Quality: jpegQuality})
+	case FormatPNG:
+		err = png.Encode(&buf, resized)
+	case FormatWebP:
+		err = nativewebp.Encode(&buf, resized, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encode %s: %w", opts.Format, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// layout
+// Returns the part of the source to scale and the size of the result.
+// @param sourceWidth int
+// @param sourceHeight int
+// @param opts Options
+// @return image.Rectangle, int, int
+func layout(sourceWidth int, sourceHeight int, opts Options) (image.Rectangle, int, int) {
+	crop := image.Rect(0, 0, sourceWidth, sourceHeight)
+	width, height := opts.Width, opts.Height
+	switch {
+	case width == 0:
+		width = max(1, sourceWidth*height/sourceHeight)
+	case height == 0:
+		height = max(1, sourceHeight*width/sourceWidth)
+	}
+
+	if opts.Fit == FitCover {
+		// Keep the centered part of the source having the ratio of the size
+		if sourceWidth*height > sourceHeight*width {
+			cropWidth := max(1, sourceHeight*width/height)
+			offset := (sourceWidth - cropWidth) / 2
+			crop = image.Rect(offset, 0, offset+cropWidth, sourceHeight)
+		} else {
+			cropHeight := max(1, sourceWidth*height/width)
+			offset := (sourceHeight - cropHeight) / 2
+			crop = image.Rect(0, offset, sourceWidth, offset+cropHeight)
+		}
+		return crop, width, height
+	}
+
+	// Scale down to fit both sides, never up
+	if sourceWidth*height > sourceHeight*width {
+		height = max(1, sourceHeight*width/sourceWidth)
+	} else {
+		width = max(1, sourceWidth*height/sourceHeight)
+	}
+	if width > sourceWidth || height > sourceHeight {
+		width, height = sourceWidth, sourceHeight
+	}
+	return crop, width, height
+}
+
+// flatten
+// Draws an image over a white background, jpeg having no transparency
+// @param img image.Image
+// @return image.Image
+func flatten(img image.Image) image.Image {
+	flat := image.NewRGBA(img.Bounds())
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), img, img.Bounds().Min, draw.Over)
+	return flat
+}
@@ -0,0 +1,99 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// encodePNG create a png of the size, its left half red and its right half blue
+func encodePNG(t *testing.T, width int, height int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			c := color.NRGBA{R: 255, A: 255}
+			if x >= width/2 {
+				c = color.NRGBA{B: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// TestLayout test the crop and the size of the result of each fit
+func TestLayout(t *testing.T) {
+	testCases := []struct {
+		name   string
+		width  int
+		height int
+		opts   Options
+		crop   image.Rectangle
+		size   image.Point
+	}{
+		{"cover wide source", 1000, 500, Options{Width: 320, Height: 320, Fit: FitCover}, image.Rect(250, 0, 750, 500), image.Pt(320, 320)},
+		{"cover tall source", 500, 1000, Options{Width: 320, Height: 180, Fit: FitCover}, image.Rect(0, 359, 500, 640), image.Pt(320, 180)},
+		{"cover enlarges", 100, 100, Options{Width: 320, Height: 180, Fit: FitCover}, image.Rect(0, 22, 100, 78), image.Pt(320, 180)},
+		{"contain wide source", 1000, 500, Options{Width: 320, Height: 320, Fit: FitContain}, image.Rect(0, 0, 1000, 500), image.Pt(320, 160)},
+		{"contain width only", 1000, 500, Options{Width: 400, Fit: FitContain}, image.Rect(0, 0, 1000, 500), image.Pt(400, 200)},
+		{"contain never enlarges", 100, 50, Options{Width: 960, Fit: FitContain}, image.Rect(0, 0, 100, 50), image.Pt(100, 50)},
+	}
+	for _, tc := range testCases {
+		crop, width, height := layout(tc.width, tc.height, tc.opts)
+		require.Equal(t, tc.crop, crop, tc.name)
+		require.Equal(t, tc.size, image.Pt(width, height), tc.name)
+	}
+}
+
+// TestResize test resizing into every format
+func TestResize(t *testing.T) {
+	source := encodePNG(t, 400, 200)
+	for _, format := range []Format{FormatJPEG, FormatPNG, FormatWebP} {
+		resized, err := Resize(bytes.NewReader(source), Options{Width: 100, Height: 100, Fit: FitCover, Format: format})
+		require.NoError(t, err, format)
+		img, decodedFormat, err := image.Decode(bytes.NewReader(resized))
+		require.NoError(t, err, format)
+		require.Equal(t, string(format), decodedFormat)
+		require.Equal(t, image.Rect(0, 0, 100, 100), img.Bounds())
+
+		// The centered square keeps both halves
+		r, _, _, _ := img.At(10, 50).RGBA()
+		_, _, b, _ := img.At(90, 50).RGBA()
+		require.Greater(t, r, uint32(0xc000), format)
+		require.Greater(t, b, uint32(0xc000), format)
+	}
+}
+
+// TestResize_Invalid test the options and the sources which are refused
+func TestResize_Invalid(t *testing.T) {
+	source := encodePNG(t, 10, 10)
+	_, err := Resize(bytes.NewReader(source), Options{Fit: FitCover, Format: FormatPNG})
+	require.ErrorIs(t, err, ErrInvalidSize)
+	_, err = Resize(bytes.NewReader(source), Options{Width: 10, Fit: "stretch", Format: FormatPNG})
+	require.ErrorIs(t, err, ErrInvalidFit)
+	_, err = Resize(bytes.NewReader(source), Options{Width: 10, Fit: FitCover, Format: "bmp"})
+	require.ErrorIs(t, err, ErrInvalidFormat)
+	_, err = Resize(bytes.NewReader([]byte("not an image")), Options{Width: 10, Fit: FitCover, Format: FormatPNG})
+	require.Error(t, err)
+
+	// Only the header of a huge png is read before refusing it
+	header := make([]byte, 13)
+	binary.BigEndian.PutUint32(header[0:], 20000)
+	binary.BigEndian.PutUint32(header[4:], 20000)
+	header[8], header[9] = 8, 6
+	chunk := append([]byte("IHDR"), header...)
+	bomb := []byte("\x89PNG\r\n\x1a\n")
+	bomb = binary.BigEndian.AppendUint32(bomb, uint32(len(header)))
+	bomb = append(bomb, chunk...)
+	bomb = binary.BigEndian.AppendUint32(bomb, crc32.ChecksumIEEE(chunk))
+	_, err = Resize(bytes.NewReader(bomb), Options{Width: 10, Fit: FitCover, Format: FormatPNG})
+	require.ErrorIs(t, err, ErrImageTooLarge)
+}
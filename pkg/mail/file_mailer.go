@@ -0,0 +1,77 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FileMailer
+// Mailer writing every message into a directory, one .eml file per message
+type FileMailer struct {
+	from      string
+	directory string
+}
+
+// NewFileMailer
+// Create a mailer writing messages into the directory
+// @param from string
+// @param directory string
+// @return *FileMailer, error
+func NewFileMailer(from string, directory string) (*FileMailer, error) {
+	if directory == "" {
+		return nil, errors.New("mail directory is required")
+	}
+	if err := os.MkdirAll(directory, 0o750); err != nil {
+		return nil, err
+	}
+	return &FileMailer{from: from, directory: directory}, nil
+}
+
+// Send
+// Write the message into a new file
+// @param ctx context.Context
+// @param message *Message
+// @return error
+func (mailer *FileMailer) Send(_ context.Context, message *Message) error {
+	content, err := buildMessage(mailer.from, message)
+	if err != nil {
+		return err
+	}
+	fileName := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102T150405"), uuid.NewString())
+	return os.WriteFile(filepath.Join(mailer.directory, fileName), content, 0o640)
+}
+
+// LogMailer
+// Mailer printing every message to the application log
+type LogMailer struct {
+	from string
+}
+
+// NewLogMailer
+// Create a mailer printing messages to the application log
+// @param from string
+// @return *LogMailer
+func NewLogMailer(from string) *LogMailer {
+	return &LogMailer{from: from}
+}
+
+// Send
+// Print the message to the log
+// @param ctx context.Context
+// @param message *Message
+// @return error
+func (mailer *LogMailer) Send(_ context.Context, message *Message) error {
+	content, err := buildMessage(mailer.from, message)
+	if err != nil {
+		return err
+	}
+	log.Printf("mail to %s:\n%s", message.To, content)
+	return nil
+}
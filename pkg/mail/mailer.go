@@ -0,0 +1,84 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+
+	"github.com/daniel-vuky/go-blog/pkg/config"
+)
+
+const (
+	DriverSMTP = "smtp"
+	DriverFile = "file"
+	DriverLog  = "log"
+)
+
+var (
+	ErrUnsupportedDriver = errors.New("unsupported mail driver")
+	ErrLogDriverDisabled = errors.New("the log mail driver prints account tokens and needs mail.debug")
+	ErrInvalidMessage    = errors.New("mail message must have a recipient and a subject")
+)
+
+// Message
+// Plain text email sent by a Mailer
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer
+// Sends emails
+type Mailer interface {
+	Send(ctx context.Context, message *Message) error
+}
+
+// NewMailer
+// Create the mailer selected by the configuration, smtp when none is set.
+// The log driver is refused unless debug is enabled since it writes the account tokens to the log.
+// @param cfg *config.Mail
+// @return Mailer, error
+func NewMailer(cfg *config.Mail) (Mailer, error) {
+	switch cfg.Driver {
+	case DriverSMTP, "":
+		return NewSMTPMailer(cfg)
+	case DriverFile:
+		return NewFileMailer(cfg.From, cfg.Directory)
+	case DriverLog:
+		if !cfg.Debug {
+			return nil, ErrLogDriverDisabled
+		}
+		return NewLogMailer(cfg.From), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedDriver, cfg.Driver)
+	}
+}
+
+// buildMessage
+// Render the message with its headers in the RFC 5322 format
+// @param from string
+// @param message *Message
+// @return []byte, error
+func buildMessage(from string, message *Message) ([]byte, error) {
+	if message.To == "" || message.Subject == "" {
+		return nil, ErrInvalidMessage
+	}
+	if strings.ContainsAny(message.To+message.Subject, "\r\n") {
+		return nil, ErrInvalidMessage
+	}
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "From: %s\r\n", from)
+	fmt.Fprintf(&buffer, "To: %s\r\n", message.To)
+	fmt.Fprintf(&buffer, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&buffer, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buffer.WriteString("MIME-Version: 1.0\r\n")
+	buffer.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buffer.WriteString("\r\n")
+	buffer.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+	return buffer.Bytes(), nil
+}
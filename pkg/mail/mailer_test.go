@@ -0,0 +1,75 @@
+package mail
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/daniel-vuky/go-blog/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFileMailer_Send test writing messages into a directory
+func TestFileMailer_Send(t *testing.T) {
+	directory := t.TempDir()
+	mailer, err := NewMailer(&config.Mail{Driver: DriverFile, From: "blog@example.com", Directory: directory})
+	require.NoError(t, err)
+
+	err = mailer.Send(context.Background(), &Message{
+		To:      "reader@example.com",
+		Subject: "Reset your password",
+		Body:    "line one\nline two",
+	})
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(directory, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	content, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	require.Contains(t, string(content), "From: blog@example.com\r\n")
+	require.Contains(t, string(content), "To: reader@example.com\r\n")
+	require.Contains(t, string(content), "Subject: Reset your password\r\n")
+	require.Contains(t, string(content), "\r\n\r\nline one\r\nline two")
+}
+
+// TestBuildMessage_HeaderInjection test rejecting headers with line breaks
+func TestBuildMessage_HeaderInjection(t *testing.T) {
+	_, err := buildMessage("blog@example.com", &Message{
+		To:      "reader@example.com\r\nBcc: victim@example.com",
+		Subject: "Hello",
+	})
+	require.ErrorIs(t, err, ErrInvalidMessage)
+
+	_, err = buildMessage("blog@example.com", &Message{To: "reader@example.com"})
+	require.ErrorIs(t, err, ErrInvalidMessage)
+}
+
+// TestNewMailer_InvalidConfig test creating mailers with invalid configuration
+func TestNewMailer_InvalidConfig(t *testing.T) {
+	_, err := NewMailer(&config.Mail{Driver: "pigeon"})
+	require.ErrorIs(t, err, ErrUnsupportedDriver)
+	_, err = NewMailer(&config.Mail{Driver: DriverSMTP})
+	require.Error(t, err)
+	_, err = NewMailer(&config.Mail{Driver: DriverFile})
+	require.Error(t, err)
+}
+
+// TestNewMailer_Default test the smtp driver is used when no driver is set
+func TestNewMailer_Default(t *testing.T) {
+	_, err := NewMailer(&config.Mail{})
+	require.Error(t, err)
+	mailer, err := NewMailer(&config.Mail{Host: "localhost", Port: 1025, From: "blog@example.com"})
+	require.NoError(t, err)
+	require.IsType(t, &SMTPMailer{}, mailer)
+}
+
+// TestNewMailer_LogDriver test the log driver is only allowed in debug
+func TestNewMailer_LogDriver(t *testing.T) {
+	_, err := NewMailer(&config.Mail{Driver: DriverLog, From: "blog@example.com"})
+	require.ErrorIs(t, err, ErrLogDriverDisabled)
+	mailer, err := NewMailer(&config.Mail{Driver: DriverLog, From: "blog@example.com", Debug: true})
+	require.NoError(t, err)
+	require.IsType(t, &LogMailer{}, mailer)
+}
@@ -0,0 +1,62 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+
+	"github.com/daniel-vuky/go-blog/pkg/config"
+)
+
+// SMTPMailer
+// Mailer delivering through an SMTP server
+type SMTPMailer struct {
+	address string
+	from    string
+	auth    smtp.Auth
+}
+
+// NewSMTPMailer
+// Create a mailer delivering through the configured SMTP server
+// @param cfg *config.Mail
+// @return *SMTPMailer, error
+func NewSMTPMailer(cfg *config.Mail) (*SMTPMailer, error) {
+	if cfg.Host == "" || cfg.Port <= 0 {
+		return nil, errors.New("smtp host and port are required")
+	}
+	if cfg.From == "" {
+		return nil, errors.New("mail sender is required")
+	}
+	mailer := &SMTPMailer{
+		address: net.JoinHostPort(cfg.Host, fmt.Sprint(cfg.Port)),
+		from:    cfg.From,
+	}
+	if cfg.Username != "" {
+		mailer.auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	return mailer, nil
+}
+
+// Send
+// Deliver the message to the SMTP server
+// @param ctx context.Context
+// @param message *Message
+// @return error
+func (mailer *SMTPMailer) Send(ctx context.Context, message *Message) error {
+	content, err := buildMessage(mailer.from, message)
+	if err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(mailer.address, mailer.auth, mailer.from, []string{message.To}, content)
+	}()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err = <-done:
+		return err
+	}
+}
@@ -0,0 +1,212 @@
+package markdown
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/daniel-vuky/go-blog/pkg/slug"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// WordsPerMinute
+// Reading speed the reading time is estimated with
+const WordsPerMinute = 200
+
+// renderer
+// Converts the markdown source with the GitHub flavoured extensions, giving every heading an id.
+// Raw html of the source is dropped by the renderer and by the policy.
+var renderer = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+)
+
+// policy
+// Allowlist of the elements and attributes the rendered html may contain
+var policy = newPolicy()
+
+// textPolicy
+// Strips every tag, leaving the text to count the words of
+var textPolicy = bluemonday.StrictPolicy()
+
+// Heading
+// An entry of the table of contents, holding the headings of the level below it.
+type Heading struct {
+	Level    int       `json:"level"`
+	Text     string    `json:"text"`
+	Anchor   string    `json:"anchor"`
+	Children []Heading `json:"children"`
+}
+
+// Document
+// The sanitized html of a markdown source and what was measured on it.
+type Document struct {
+	Html            string    `json:"html"`
+	TableOfContents []Heading `json:"table_of_contents"`
+	WordCount       int       `json:"word_count"`
+	ReadingTime     int       `json:"reading_time"`
+}
+
+// Render
+// Converts a markdown source into sanitized html with its table of contents,
+// word count and reading time in minutes.
+// @param source string
+// @return Document
+// @return error
+func Render(source string) (Document, error) {
+	src := []byte(source)
+	root := renderer.Parser().Parse(
+		text.NewReader(src),
+		parser.WithContext(parser.NewContext(parser.WithIDs(&headingIDs{}))),
+	)
+
+	var headings []ast.Node
+	err := ast.Walk(root, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering && node.Kind() == ast.KindHeading {
+			headings = append(headings, node)
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	if err != nil {
+		return Document{}, err
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Renderer().Render(&buf, src, root); err != nil {
+		return Document{}, err
+	}
+	sanitized := policy.Sanitize(buf.String())
+	wordCount := len(strings.Fields(html.UnescapeString(textPolicy.Sanitize(sanitized))))
+
+	return Document{
+		Html:            sanitized,
+		TableOfContents: tableOfContents(headings, src),
+		WordCount:       wordCount,
+		ReadingTime:     readingTime(wordCount),
+	}, nil
+}
+
+// tableOfContents
+// Nests the headings under the closest previous heading of a lower level.
+// @param headings []ast.Node
+// @param source []byte
+// @return []Heading
+func tableOfContents(headings []ast.Node, source []byte) []Heading {
+	var entries []Heading
+	for _, node := range headings {
+		heading := node.(*ast.Heading)
+		entry := Heading{
+			Level:    heading.Level,
+			Text:     plainText(heading, source),
+			Children: []Heading{},
+		}
+		if id, ok := heading.AttributeString("id"); ok {
+			if anchor, ok := id.([]byte); ok {
+				entry.Anchor = string(anchor)
+			}
+		}
+		entries = appendHeading(entries, entry)
+	}
+	if entries == nil {
+		entries = []Heading{}
+	}
+	return entries
+}
+
+// appendHeading
+// Appends a heading as the last child of the last entry of a lower level, or at the root.
+// @param entries []Heading
+// @param entry Heading
+// @return []Heading
+func appendHeading(entries []Heading, entry Heading) []Heading {
+	if last := len(entries) - 1; last >= 0 && entries[last].Level < entry.Level {
+		entries[last].Children = appendHeading(entries[last].Children, entry)
+		return entries
+	}
+	return append(entries, entry)
+}
+
+// plainText
+// Returns the text of an inline content without its markup.
+// @param node ast.Node
+// @param source []byte
+// @return string
+func plainText(node ast.Node, source []byte) string {
+	var sb strings.Builder
+	_ = ast.Walk(node, func(child ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch child := child.(type) {
+		case *ast.Text:
+			sb.Write(child.Segment.Value(source))
+			if child.SoftLineBreak() || child.HardLineBreak() {
+				sb.WriteByte(' ')
+			}
+		case *ast.String:
+			sb.Write(child.Value)
+		case *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.TrimSpace(sb.String())
+}
+
+// headingIDs
+// Generates the heading anchors as slugs, numbered when a heading text repeats.
+type headingIDs struct {
+	taken []string
+}
+
+// Generate
+// Returns a free anchor for the text of a heading.
+// @param value []byte
+// @param kind ast.NodeKind
+// @return []byte
+func (ids *headingIDs) Generate(value []byte, _ ast.NodeKind) []byte {
+	id := slug.Make(string(value))
+	if id == "" {
+		id = "heading"
+	}
+	id = slug.Unique(id, ids.taken)
+	ids.taken = append(ids.taken, id)
+	return []byte(id)
+}
+
+// Put
+// Marks an anchor set explicitly in the source as taken.
+// @param value []byte
+func (ids *headingIDs) Put(value []byte) {
+	ids.taken = append(ids.taken, string(value))
+}
+
+// readingTime
+// Estimates the minutes needed to read a number of words, at least one minute for any text.
+// @param wordCount int
+// @return int
+func readingTime(wordCount int) int {
+	return (wordCount + WordsPerMinute - 1) / WordsPerMinute
+}
+
+// newPolicy
+// Builds the allowlist of the rendered html: the user generated content policy,
+// the heading anchors, the language of the code blocks and the task list checkboxes.
+// @return *bluemonday.Policy
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("id").Matching(regexp.MustCompile(`^[\w-]+$`)).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#.-]+$`)).OnElements("code")
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	return p
+}
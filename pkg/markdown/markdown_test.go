@@ -0,0 +1,56 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestRender_Sanitize test the scripts, event handlers and dangerous links are removed
+func TestRender_Sanitize(t *testing.T) {
+	doc, err := Render("Hi <script>alert(1)</script> <b onclick=\"x()\">there</b>\n\n" +
+		"[click](javascript:alert(1)) <img src=x onerror=alert(1)> [home](https://example.com)\n\n" +
+		"```go\nfmt.Println(\"<ok>\")\n```\n")
+	require.NoError(t, err)
+	for _, unsafe := range []string{"<script", "onclick", "javascript:", "onerror", "<img"} {
+		require.NotContains(t, doc.Html, unsafe)
+	}
+	require.Contains(t, doc.Html, `<a href="https://example.com" rel="nofollow noopener" target="_blank">home</a>`)
+	require.Contains(t, doc.Html, `<code class="language-go">fmt.Println(&#34;&lt;ok&gt;&#34;)`)
+}
+
+// TestRender_TableOfContents test the headings are nested by level with unique anchors
+func TestRender_TableOfContents(t *testing.T) {
+	doc, err := Render("# Getting *started*\n\n## Install\n\n### On Linux\n\n## Install\n\n# Tiếng Việt\n\n#### Skipped levels\n")
+	require.NoError(t, err)
+	require.Equal(t, []Heading{
+		{Level: 1, Text: "Getting started", Anchor: "getting-started", Children: []Heading{
+			{Level: 2, Text: "Install", Anchor: "install", Children: []Heading{
+				{Level: 3, Text: "On Linux", Anchor: "on-linux", Children: []Heading{}},
+			}},
+			{Level: 2, Text: "Install", Anchor: "install-2", Children: []Heading{}},
+		}},
+		{Level: 1, Text: "Tiếng Việt", Anchor: "tieng-viet", Children: []Heading{
+			{Level: 4, Text: "Skipped levels", Anchor: "skipped-levels", Children: []Heading{}},
+		}},
+	}, doc.TableOfContents)
+	require.Contains(t, doc.Html, `<h2 id="install-2">Install</h2>`)
+}
+
+// TestRender_ReadingTime test the words of the rendered text are counted
+func TestRender_ReadingTime(t *testing.T) {
+	doc, err := Render("")
+	require.NoError(t, err)
+	require.Equal(t, Document{Html: "", TableOfContents: []Heading{}}, doc)
+
+	doc, err = Render("**Hello** _markdown_ `world` & [friends](https://example.com)")
+	require.NoError(t, err)
+	require.Equal(t, 5, doc.WordCount)
+	require.Equal(t, 1, doc.ReadingTime)
+
+	doc, err = Render(strings.Repeat("word ", WordsPerMinute*2+1))
+	require.NoError(t, err)
+	require.Equal(t, WordsPerMinute*2+1, doc.WordCount)
+	require.Equal(t, 3, doc.ReadingTime)
+}
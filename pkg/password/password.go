@@ -0,0 +1,207 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/daniel-vuky/go-blog/pkg/config"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+var (
+	ErrUnsupportedAlgorithm = errors.New("unsupported password hashing algorithm")
+	ErrUnknownHashFormat    = errors.New("unknown password hash format")
+	ErrEmptyPassword        = errors.New("password must not be empty")
+)
+
+// Hasher
+// Hashes and verifies passwords
+type Hasher interface {
+	Hash(password string) (string, error)
+	Verify(hashedPassword string, password string) (bool, error)
+	NeedsRehash(hashedPassword string) bool
+}
+
+// hasher
+// Hasher implementation which writes hashes with the configured algorithm
+// and verifies hashes written by any supported algorithm
+type hasher struct {
+	config *config.Password
+}
+
+// NewHasher
+// Create new password hasher from config
+// @param cfg *config.Password
+// @return Hasher, error
+func NewHasher(cfg *config.Password) (Hasher, error) {
+	switch cfg.Algorithm {
+	case AlgorithmBcrypt:
+		if cfg.BcryptCost < bcrypt.MinCost || cfg.BcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("invalid bcrypt cost %d", cfg.BcryptCost)
+		}
+	case AlgorithmArgon2id:
+		if cfg.Argon2Time == 0 || cfg.Argon2Memory == 0 || cfg.Argon2Threads == 0 {
+			return nil, fmt.Errorf("invalid argon2id parameters")
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, cfg.Algorithm)
+	}
+	return &hasher{config: cfg}, nil
+}
+
+// Hash
+// Hash the password with the configured algorithm
+// @param password string
+// @return string, error
+func (h *hasher) Hash(password string) (string, error) {
+	if password == "" {
+		return "", ErrEmptyPassword
+	}
+	if h.config.Algorithm == AlgorithmBcrypt {
+		hashed, err := bcrypt.GenerateFromPassword([]byte(password), h.config.BcryptCost)
+		if err != nil {
+			return "", fmt.Errorf("failed to hash password: %w", err)
+		}
+		return string(hashed), nil
+	}
+
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+	key := argon2.IDKey(
+		[]byte(password),
+		salt,
+		h.config.Argon2Time,
+		h.config.Argon2Memory,
+		h.config.Argon2Threads,
+		argon2KeyLength,
+	)
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		h.config.Argon2Memory,
+		h.config.Argon2Time,
+		h.config.Argon2Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify
+// Check the password against a hash produced by any supported algorithm
+// @param hashedPassword string
+// @param password string
+// @return bool, error
+func (h *hasher) Verify(hashedPassword string, password string) (bool, error) {
+	switch detectAlgorithm(hashedPassword) {
+	case AlgorithmBcrypt:
+		err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		return err == nil, err
+	case AlgorithmArgon2id:
+		params, salt, key, err := decodeArgon2Hash(hashedPassword)
+		if err != nil {
+			return false, err
+		}
+		otherKey := argon2.IDKey(
+			[]byte(password),
+			salt,
+			params.Argon2Time,
+			params.Argon2Memory,
+			params.Argon2Threads,
+			uint32(len(key)),
+		)
+		return subtle.ConstantTimeCompare(key, otherKey) == 1, nil
+	default:
+		return false, ErrUnknownHashFormat
+	}
+}
+
+// NeedsRehash
+// Report whether the hash was produced with another algorithm or other parameters than configured
+// @param hashedPassword string
+// @return bool
+func (h *hasher) NeedsRehash(hashedPassword string) bool {
+	if detectAlgorithm(hashedPassword) != h.config.Algorithm {
+		return true
+	}
+	if h.config.Algorithm == AlgorithmBcrypt {
+		cost, err := bcrypt.Cost([]byte(hashedPassword))
+		return err != nil || cost != h.config.BcryptCost
+	}
+	params, _, _, err := decodeArgon2Hash(hashedPassword)
+	if err != nil {
+		return true
+	}
+	return params.Argon2Time != h.config.Argon2Time ||
+		params.Argon2Memory != h.config.Argon2Memory ||
+		params.Argon2Threads != h.config.Argon2Threads
+}
+
+// detectAlgorithm
+// Detect the algorithm of a hash from its prefix
+// @param hashedPassword string
+// @return string
+func detectAlgorithm(hashedPassword string) string {
+	switch {
+	case strings.HasPrefix(hashedPassword, "$argon2id$"):
+		return AlgorithmArgon2id
+	case strings.HasPrefix(hashedPassword, "$2a$"),
+		strings.HasPrefix(hashedPassword, "$2b$"),
+		strings.HasPrefix(hashedPassword, "$2y$"):
+		return AlgorithmBcrypt
+	default:
+		return ""
+	}
+}
+
+// decodeArgon2Hash
+// Split an encoded argon2id hash into its parameters, salt and key
+// @param hashedPassword string
+// @return *config.Password, []byte, []byte, error
+func decodeArgon2Hash(hashedPassword string) (*config.Password, []byte, []byte, error) {
+	parts := strings.Split(hashedPassword, "$")
+	if len(parts) != 6 {
+		return nil, nil, nil, ErrUnknownHashFormat
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, nil, nil, ErrUnknownHashFormat
+	}
+	params := &config.Password{Algorithm: AlgorithmArgon2id}
+	_, err := fmt.Sscanf(
+		parts[3],
+		"m=%d,t=%d,p=%d",
+		&params.Argon2Memory,
+		&params.Argon2Time,
+		&params.Argon2Threads,
+	)
+	if err != nil {
+		return nil, nil, nil, ErrUnknownHashFormat
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, ErrUnknownHashFormat
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return nil, nil, nil, ErrUnknownHashFormat
+	}
+	return params, salt, key, nil
+}
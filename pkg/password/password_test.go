@@ -0,0 +1,97 @@
+package password
+
+import (
+	"testing"
+
+	"github.com/daniel-vuky/go-blog/pkg/config"
+	goRandom "github.com/daniel-vuky/go-random"
+	"github.com/stretchr/testify/require"
+)
+
+var bcryptConfig = &config.Password{
+	Algorithm:  AlgorithmBcrypt,
+	BcryptCost: 4,
+}
+
+var argon2Config = &config.Password{
+	Algorithm:     AlgorithmArgon2id,
+	Argon2Time:    1,
+	Argon2Memory:  1024,
+	Argon2Threads: 1,
+}
+
+// TestHasher_HashAndVerify test hashing and verifying with every algorithm
+func TestHasher_HashAndVerify(t *testing.T) {
+	for _, cfg := range []*config.Password{bcryptConfig, argon2Config} {
+		hasher, err := NewHasher(cfg)
+		require.NoError(t, err)
+
+		plain := goRandom.RandomString(12)
+		hashed, err := hasher.Hash(plain)
+		require.NoError(t, err)
+		require.NotEqual(t, plain, hashed)
+		require.False(t, hasher.NeedsRehash(hashed))
+
+		ok, err := hasher.Verify(hashed, plain)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		ok, err = hasher.Verify(hashed, goRandom.RandomString(12))
+		require.NoError(t, err)
+		require.False(t, ok)
+	}
+}
+
+// TestHasher_Hash_EmptyPassword test hashing an empty password
+func TestHasher_Hash_EmptyPassword(t *testing.T) {
+	hasher, err := NewHasher(bcryptConfig)
+	require.NoError(t, err)
+	hashed, err := hasher.Hash("")
+	require.ErrorIs(t, err, ErrEmptyPassword)
+	require.Empty(t, hashed)
+}
+
+// TestHasher_Verify_UnknownFormat test that plaintext values are never accepted as hashes
+func TestHasher_Verify_UnknownFormat(t *testing.T) {
+	hasher, err := NewHasher(argon2Config)
+	require.NoError(t, err)
+	plain := goRandom.RandomString(12)
+	ok, err := hasher.Verify(plain, plain)
+	require.ErrorIs(t, err, ErrUnknownHashFormat)
+	require.False(t, ok)
+}
+
+// TestHasher_NeedsRehash test rehash detection after the configuration changes
+func TestHasher_NeedsRehash(t *testing.T) {
+	bcryptHasher, err := NewHasher(bcryptConfig)
+	require.NoError(t, err)
+	argon2Hasher, err := NewHasher(argon2Config)
+	require.NoError(t, err)
+
+	plain := goRandom.RandomString(12)
+	bcryptHash, err := bcryptHasher.Hash(plain)
+	require.NoError(t, err)
+
+	// A hash from another algorithm is still verifiable but must be upgraded
+	ok, err := argon2Hasher.Verify(bcryptHash, plain)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.True(t, argon2Hasher.NeedsRehash(bcryptHash))
+
+	strongerHasher, err := NewHasher(&config.Password{
+		Algorithm:  AlgorithmBcrypt,
+		BcryptCost: 5,
+	})
+	require.NoError(t, err)
+	require.True(t, strongerHasher.NeedsRehash(bcryptHash))
+}
+
+// TestNewHasher_InvalidConfig test creating a hasher with invalid configuration
+func TestNewHasher_InvalidConfig(t *testing.T) {
+	_, err := NewHasher(&config.Password{Algorithm: "md5"})
+	require.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+	_, err = NewHasher(&config.Password{Algorithm: AlgorithmBcrypt, BcryptCost: 1})
+	require.Error(t, err)
+	_, err = NewHasher(&config.Password{Algorithm: AlgorithmArgon2id})
+	require.Error(t, err)
+}
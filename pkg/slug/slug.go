@@ -0,0 +1,96 @@
+package slug
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLength is the maximum length of a generated slug, numeric suffixes included
+const MaxLength = 200
+
+// transliterations
+// Letters which are not a base letter followed by combining marks once decomposed
+var transliterations = map[rune]string{
+	'đ': "d", 'Đ': "d",
+	'ð': "d", 'Ð': "d",
+	'ß': "ss",
+	'æ': "ae", 'Æ': "ae",
+	'œ': "oe", 'Œ': "oe",
+	'ø': "o", 'Ø': "o",
+	'ł': "l", 'Ł': "l",
+	'þ': "th", 'Þ': "th",
+}
+
+// Make
+// Turns a text into a slug made of lowercase ASCII letters, digits and single hyphens.
+// Diacritics are removed, so "Tiếng Việt" becomes "tieng-viet", other characters separate the words.
+// @param text string
+// @return string
+func Make(text string) string {
+	var builder strings.Builder
+	pendingHyphen := false
+	write := func(s string) {
+		if pendingHyphen && builder.Len() > 0 {
+			builder.WriteByte('-')
+		}
+		pendingHyphen = false
+		builder.WriteString(s)
+	}
+	for _, r := range norm.NFD.String(text) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if replacement, ok := transliterations[r]; ok {
+			write(replacement)
+			continue
+		}
+		r = unicode.ToLower(r)
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			write(string(r))
+			continue
+		}
+		pendingHyphen = true
+	}
+	return truncate(builder.String(), MaxLength)
+}
+
+// Unique
+// Returns the slug when it is free, otherwise the slug followed by the smallest free numeric suffix.
+// @param slug string
+// @param taken []string the keys already used, only slug and slug-N matter
+// @return string
+func Unique(slug string, taken []string) string {
+	used := make(map[string]bool, len(taken))
+	for _, key := range taken {
+		used[key] = true
+	}
+	if !used[slug] {
+		return slug
+	}
+	for n := 2; ; n++ {
+		suffix := "-" + strconv.Itoa(n)
+		candidate := truncate(slug, MaxLength-len(suffix)) + suffix
+		if !used[candidate] {
+			return candidate
+		}
+	}
+}
+
+// truncate
+// Cuts a slug to at most length bytes, preferably between two words.
+// @param slug string
+// @param length int
+// @return string
+func truncate(slug string, length int) string {
+	if len(slug) <= length {
+		return slug
+	}
+	slug = slug[:length]
+	if i := strings.LastIndexByte(slug, '-'); i > 0 {
+		slug = slug[:i]
+	}
+	return strings.TrimRight(slug, "-")
+}
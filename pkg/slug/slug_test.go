@@ -0,0 +1,49 @@
+package slug
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestMake test the transliteration and the hyphenation of the slugs
+func TestMake(t *testing.T) {
+	testCases := []struct {
+		text string
+		slug string
+	}{
+		{"Hello World", "hello-world"},
+		{"  Go 1.22 -- released!  ", "go-1-22-released"},
+		{"Tiếng Việt có dấu", "tieng-viet-co-dau"},
+		{"Đường phố Hà Nội", "duong-pho-ha-noi"},
+		{"Phở bò ưu đãi ở Sài Gòn", "pho-bo-uu-dai-o-sai-gon"},
+		{"Crème brûlée à la française", "creme-brulee-a-la-francaise"},
+		{"Straße & Smørrebrød", "strasse-smorrebrod"},
+		{"日本語", ""},
+		{"", ""},
+	}
+	for _, tc := range testCases {
+		require.Equal(t, tc.slug, Make(tc.text), tc.text)
+	}
+}
+
+// TestMake_Truncate test long texts are cut between two words
+func TestMake_Truncate(t *testing.T) {
+	slug := Make(strings.Repeat("word ", 100))
+	require.LessOrEqual(t, len(slug), MaxLength)
+	require.True(t, strings.HasSuffix(slug, "word"))
+}
+
+// TestUnique test the smallest free suffix is appended
+func TestUnique(t *testing.T) {
+	require.Equal(t, "hello", Unique("hello", nil))
+	require.Equal(t, "hello", Unique("hello", []string{"hello-2"}))
+	require.Equal(t, "hello-2", Unique("hello", []string{"hello"}))
+	require.Equal(t, "hello-4", Unique("hello", []string{"hello", "hello-2", "hello-3", "hello-5"}))
+
+	long := strings.Repeat("a", MaxLength)
+	unique := Unique(long, []string{long})
+	require.LessOrEqual(t, len(unique), MaxLength)
+	require.True(t, strings.HasSuffix(unique, "-2"))
+}
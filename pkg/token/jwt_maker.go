@@ -0,0 +1,58 @@
+package token
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const minSecretKeySize = 32
+
+// JWTMaker
+// Maker signing tokens with HMAC-SHA256 JSON web tokens
+type JWTMaker struct {
+	secretKey string
+}
+
+// NewJWTMaker
+// Create new JWT maker
+// @param secretKey string
+// @return Maker, error
+func NewJWTMaker(secretKey string) (Maker, error) {
+	if len(secretKey) < minSecretKeySize {
+		return nil, fmt.Errorf("invalid key size: must be at least %d characters", minSecretKeySize)
+	}
+	return &JWTMaker{secretKey: secretKey}, nil
+}
+
+// CreateToken
+// Sign the payload
+// @param payload *Payload
+// @return string, error
+func (maker *JWTMaker) CreateToken(payload *Payload) (string, error) {
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
+	return jwtToken.SignedString([]byte(maker.secretKey))
+}
+
+// VerifyToken
+// Check the signature and expiry of the token
+// @param token string
+// @return *Payload, error
+func (maker *JWTMaker) VerifyToken(token string) (*Payload, error) {
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrInvalidToken
+		}
+		return []byte(maker.secretKey), nil
+	}
+	payload := &Payload{}
+	_, err := jwt.ParseWithClaims(token, payload, keyFunc)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+	return payload, nil
+}
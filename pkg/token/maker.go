@@ -0,0 +1,34 @@
+package token
+
+import (
+	"fmt"
+
+	"github.com/daniel-vuky/go-blog/pkg/config"
+)
+
+const (
+	TypeJWT    = "jwt"
+	TypePaseto = "paseto"
+)
+
+// Maker
+// Create and verify signed tokens
+type Maker interface {
+	CreateToken(payload *Payload) (string, error)
+	VerifyToken(token string) (*Payload, error)
+}
+
+// NewMaker
+// Create the token maker selected in config
+// @param cfg *config.Token
+// @return Maker, error
+func NewMaker(cfg *config.Token) (Maker, error) {
+	switch cfg.Type {
+	case TypeJWT:
+		return NewJWTMaker(cfg.SymmetricKey)
+	case TypePaseto:
+		return NewPasetoMaker(cfg.SymmetricKey)
+	default:
+		return nil, fmt.Errorf("unsupported token type %q", cfg.Type)
+	}
+}
@@ -0,0 +1,116 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/daniel-vuky/go-blog/pkg/config"
+	goRandom "github.com/daniel-vuky/go-random"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestMakers
+// Create one maker of every supported type
+func newTestMakers(t *testing.T) []Maker {
+	var makers []Maker
+	for _, tokenType := range []string{TypeJWT, TypePaseto} {
+		maker, err := NewMaker(&config.Token{
+			Type:         tokenType,
+			SymmetricKey: goRandom.RandomString(32),
+		})
+		require.NoError(t, err)
+		makers = append(makers, maker)
+	}
+	return makers
+}
+
+// TestMaker_CreateAndVerify test creating and verifying tokens
+func TestMaker_CreateAndVerify(t *testing.T) {
+	for _, maker := range newTestMakers(t) {
+		payload, err := NewAdminPayload(7, 3, time.Minute)
+		require.NoError(t, err)
+		token, err := maker.CreateToken(payload)
+		require.NoError(t, err)
+		require.NotEmpty(t, token)
+
+		verified, err := maker.VerifyToken(token)
+		require.NoError(t, err)
+		require.Equal(t, payload.ID, verified.ID)
+		require.Equal(t, AudienceAdminAccess, verified.Audience)
+		require.Equal(t, int32(7), verified.AdminID)
+		require.Equal(t, int64(3), verified.RoleID)
+		require.WithinDuration(t, payload.IssuedAt, verified.IssuedAt, time.Second)
+		require.WithinDuration(t, payload.ExpiredAt, verified.ExpiredAt, time.Second)
+	}
+}
+
+// TestMaker_UserPayload test creating and verifying site user tokens
+func TestMaker_UserPayload(t *testing.T) {
+	for _, maker := range newTestMakers(t) {
+		payload, err := NewUserPayload(42, time.Minute)
+		require.NoError(t, err)
+		token, err := maker.CreateToken(payload)
+		require.NoError(t, err)
+
+		verified, err := maker.VerifyToken(token)
+		require.NoError(t, err)
+		require.Equal(t, AudienceUserAccess, verified.Audience)
+		require.Equal(t, int64(42), verified.UserID)
+		require.Zero(t, verified.AdminID)
+	}
+}
+
+// TestMaker_ExpiredToken test verifying an expired token
+func TestMaker_ExpiredToken(t *testing.T) {
+	for _, maker := range newTestMakers(t) {
+		payload, err := NewAdminPayload(7, 3, -time.Minute)
+		require.NoError(t, err)
+		token, err := maker.CreateToken(payload)
+		require.NoError(t, err)
+
+		verified, err := maker.VerifyToken(token)
+		require.ErrorIs(t, err, ErrExpiredToken)
+		require.Nil(t, verified)
+	}
+}
+
+// TestMaker_InvalidToken test verifying tokens signed by another key
+func TestMaker_InvalidToken(t *testing.T) {
+	makers := newTestMakers(t)
+	otherMakers := newTestMakers(t)
+	for i, maker := range makers {
+		payload, err := NewAdminPayload(7, 3, time.Minute)
+		require.NoError(t, err)
+		token, err := otherMakers[i].CreateToken(payload)
+		require.NoError(t, err)
+
+		verified, err := maker.VerifyToken(token)
+		require.ErrorIs(t, err, ErrInvalidToken)
+		require.Nil(t, verified)
+	}
+}
+
+// TestJWTMaker_AlgNone test that unsigned JWTs are rejected
+func TestJWTMaker_AlgNone(t *testing.T) {
+	maker, err := NewJWTMaker(goRandom.RandomString(32))
+	require.NoError(t, err)
+	payload, err := NewAdminPayload(7, 3, time.Minute)
+	require.NoError(t, err)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodNone, payload).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+
+	verified, err := maker.VerifyToken(token)
+	require.ErrorIs(t, err, ErrInvalidToken)
+	require.Nil(t, verified)
+}
+
+// TestNewMaker_InvalidConfig test creating makers with invalid configuration
+func TestNewMaker_InvalidConfig(t *testing.T) {
+	_, err := NewMaker(&config.Token{Type: "saml", SymmetricKey: goRandom.RandomString(32)})
+	require.Error(t, err)
+	_, err = NewMaker(&config.Token{Type: TypeJWT, SymmetricKey: goRandom.RandomString(10)})
+	require.Error(t, err)
+	_, err = NewMaker(&config.Token{Type: TypePaseto, SymmetricKey: goRandom.RandomString(31)})
+	require.Error(t, err)
+}
@@ -0,0 +1,54 @@
+package token
+
+import (
+	"fmt"
+
+	"github.com/aead/chacha20poly1305"
+	"github.com/o1egl/paseto"
+)
+
+// PasetoMaker
+// Maker encrypting tokens with PASETO v2 local
+type PasetoMaker struct {
+	paseto       *paseto.V2
+	symmetricKey []byte
+}
+
+// NewPasetoMaker
+// Create new PASETO maker
+// @param symmetricKey string
+// @return Maker, error
+func NewPasetoMaker(symmetricKey string) (Maker, error) {
+	if len(symmetricKey) != chacha20poly1305.KeySize {
+		return nil, fmt.Errorf("invalid key size: must be exactly %d characters", chacha20poly1305.KeySize)
+	}
+	return &PasetoMaker{
+		paseto:       paseto.NewV2(),
+		symmetricKey: []byte(symmetricKey),
+	}, nil
+}
+
+// CreateToken
+// Encrypt the payload
+// @param payload *Payload
+// @return string, error
+func (maker *PasetoMaker) CreateToken(payload *Payload) (string, error) {
+	return maker.paseto.Encrypt(maker.symmetricKey, payload, nil)
+}
+
+// VerifyToken
+// Decrypt the token and check its expiry
+// @param token string
+// @return *Payload, error
+func (maker *PasetoMaker) VerifyToken(token string) (*Payload, error) {
+	payload := &Payload{}
+	err := maker.paseto.Decrypt(token, maker.symmetricKey, payload, nil)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	err = payload.Valid()
+	if err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
@@ -0,0 +1,137 @@
+package token
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	AudienceAdminAccess    = "admin_access"
+	AudienceAdminTwoFactor = "admin_two_factor"
+	AudienceUserAccess     = "user_access"
+)
+
+var (
+	ErrInvalidToken = errors.New("token is invalid")
+	ErrExpiredToken = errors.New("token has expired")
+)
+
+// Payload
+// Claims carried by every token issued by a Maker
+type Payload struct {
+	ID        uuid.UUID `json:"id"`
+	Audience  string    `json:"audience"`
+	AdminID   int32     `json:"admin_id,omitempty"`
+	RoleID    int64     `json:"role_id,omitempty"`
+	UserID    int64     `json:"user_id,omitempty"`
+	IssuedAt  time.Time `json:"issued_at"`
+	ExpiredAt time.Time `json:"expired_at"`
+}
+
+// newPayload
+// Create payload with a random id valid for the given duration
+// @param audience string
+// @param duration time.Duration
+// @return *Payload, error
+func newPayload(audience string, duration time.Duration) (*Payload, error) {
+	tokenID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &Payload{
+		ID:        tokenID,
+		Audience:  audience,
+		IssuedAt:  now,
+		ExpiredAt: now.Add(duration),
+	}, nil
+}
+
+// NewAdminPayload
+// Create payload of an admin access token
+// @param adminID int32
+// @param roleID int64
+// @param duration time.Duration
+// @return *Payload, error
+func NewAdminPayload(adminID int32, roleID int64, duration time.Duration) (*Payload, error) {
+	payload, err := newPayload(AudienceAdminAccess, duration)
+	if err != nil {
+		return nil, err
+	}
+	payload.AdminID = adminID
+	payload.RoleID = roleID
+	return payload, nil
+}
+
+// NewAdminChallengePayload
+// Create payload of the challenge exchanged for an admin access token once the second factor is verified
+// @param adminID int32
+// @param roleID int64
+// @param duration time.Duration
+// @return *Payload, error
+func NewAdminChallengePayload(adminID int32, roleID int64, duration time.Duration) (*Payload, error) {
+	payload, err := newPayload(AudienceAdminTwoFactor, duration)
+	if err != nil {
+		return nil, err
+	}
+	payload.AdminID = adminID
+	payload.RoleID = roleID
+	return payload, nil
+}
+
+// NewUserPayload
+// Create payload of a site user access token
+// @param userID int64
+// @param duration time.Duration
+// @return *Payload, error
+func NewUserPayload(userID int64, duration time.Duration) (*Payload, error) {
+	payload, err := newPayload(AudienceUserAccess, duration)
+	if err != nil {
+		return nil, err
+	}
+	payload.UserID = userID
+	return payload, nil
+}
+
+// Valid
+// Check if the payload is expired
+// @return error
+func (payload *Payload) Valid() error {
+	if time.Now().After(payload.ExpiredAt) {
+		return ErrExpiredToken
+	}
+	return nil
+}
+
+// GetExpirationTime implements jwt.Claims
+func (payload *Payload) GetExpirationTime() (*jwt.NumericDate, error) {
+	return jwt.NewNumericDate(payload.ExpiredAt), nil
+}
+
+// GetIssuedAt implements jwt.Claims
+func (payload *Payload) GetIssuedAt() (*jwt.NumericDate, error) {
+	return jwt.NewNumericDate(payload.IssuedAt), nil
+}
+
+// GetNotBefore implements jwt.Claims
+func (payload *Payload) GetNotBefore() (*jwt.NumericDate, error) {
+	return nil, nil
+}
+
+// GetIssuer implements jwt.Claims
+func (payload *Payload) GetIssuer() (string, error) {
+	return "", nil
+}
+
+// GetSubject implements jwt.Claims
+func (payload *Payload) GetSubject() (string, error) {
+	return payload.ID.String(), nil
+}
+
+// GetAudience implements jwt.Claims
+func (payload *Payload) GetAudience() (jwt.ClaimStrings, error) {
+	return jwt.ClaimStrings{payload.Audience}, nil
+}